@@ -8,6 +8,7 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 	ethtrie "github.com/ethereum/go-ethereum/trie"
 	"github.com/herdius/herdius-core/accounts/protobuf"
+	"github.com/herdius/herdius-core/asset"
 	"github.com/herdius/herdius-core/blockchain"
 	cryptoAmino "github.com/herdius/herdius-core/crypto/encoding/amino"
 	"github.com/herdius/herdius-core/crypto/secp256k1"
@@ -41,6 +42,7 @@ type Service struct {
 	receiverAddress string
 	extAddress      string
 	txValue         uint64
+	txFee           uint64
 	txLockedAmount  uint64
 	txRedeemAmount  uint64
 }
@@ -105,6 +107,16 @@ func (s *Service) SetTxValue(txValue uint64) {
 	s.txValue = txValue
 }
 
+// TxFee returns transaction fee paid in HER tokens
+func (s *Service) TxFee() uint64 {
+	return s.txFee
+}
+
+// SetTxFee sets transaction fee paid in HER tokens
+func (s *Service) SetTxFee(txFee uint64) {
+	s.txFee = txFee
+}
+
 // TxLockedAmount returns transaction transfer locked amount
 func (s *Service) TxLockedAmount() uint64 {
 	return s.txLockedAmount
@@ -210,14 +222,21 @@ func (s *Service) GetAccountByAddress(address string) (*protobuf.Account, error)
 }
 
 // VerifyAccountBalance verifies if account has enough HER tokens or external asset balances
+// to send the tx value, and enough HER tokens to pay the tx fee
 func (s *Service) VerifyAccountBalance() bool {
 	symbol := strings.ToUpper(s.assetSymbol)
 	// Get the balance of required asset
 	if strings.EqualFold(symbol, "HER") {
-		if s.account.Balance >= s.txValue {
-			return true
+		total := s.txValue + s.txFee
+		if total < s.txValue {
+			return false
 		}
-	} else if s.account != nil && len(s.account.EBalances) > 0 && s.account.EBalances[symbol] != nil {
+		return s.account.GetBalance() >= total
+	}
+	if s.account.GetBalance() < s.txFee {
+		return false
+	}
+	if s.account != nil && len(s.account.EBalances) > 0 && s.account.EBalances[symbol] != nil {
 		lockedAmount := uint64(0)
 		if s.account.LockBalances[symbol] != nil {
 			lockedAmount = s.account.LockBalances[symbol].Asset[s.extAddress]
@@ -282,9 +301,10 @@ func (s *Service) VerifyLockedAmount() bool {
 // VerifyRedeemAmount checks account have proper locked amount for redeeming
 func (s *Service) VerifyRedeemAmount() bool {
 	log.Printf("Account before Redeem: %+v", s.account)
-	if strings.EqualFold(s.assetSymbol, "HBTC") {
-		if s.account != nil && s.account.LockBalances != nil && s.account.LockBalances["BTC"] != nil {
-			if asset := s.account.LockBalances["BTC"].Asset; asset != nil {
+	// A wrapped asset is redeemed from the locked balance of the asset backing it
+	if backing := asset.GetRegistry().Backing(s.assetSymbol); !strings.EqualFold(backing, s.assetSymbol) {
+		if s.account != nil && s.account.LockBalances != nil && s.account.LockBalances[backing] != nil {
+			if asset := s.account.LockBalances[backing].Asset; asset != nil {
 				return s.txRedeemAmount <= asset[s.extAddress]
 			}
 		}
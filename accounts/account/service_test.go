@@ -2,6 +2,7 @@ package account
 
 import (
 	"fmt"
+	"math"
 	"strings"
 	"testing"
 
@@ -28,6 +29,41 @@ func TestVerifyAccountBalanceFalse(t *testing.T) {
 	assert.False(t, accService.VerifyAccountBalance())
 }
 
+func TestVerifyAccountBalanceFee(t *testing.T) {
+	accService := NewAccountService()
+	accService.SetAccount(&protobuf.Account{Balance: 10})
+	accService.SetTxValue(8)
+	accService.SetTxFee(2)
+	accService.SetAssetSymbol("HER")
+	assert.True(t, accService.VerifyAccountBalance())
+
+	accService.SetTxFee(3)
+	assert.False(t, accService.VerifyAccountBalance())
+
+	accService.SetTxValue(math.MaxUint64)
+	assert.False(t, accService.VerifyAccountBalance())
+}
+
+func TestVerifyExternalAssetBalanceFee(t *testing.T) {
+	extAddress := "0xD8f647855876549d2623f52126CE40D053a2ef6A"
+	eBalances := map[string]*protobuf.EBalanceAsset{
+		"ETH": {Asset: map[string]*protobuf.EBalance{
+			extAddress: {Address: extAddress, Balance: 10},
+		}},
+	}
+	accService := NewAccountService()
+	accService.SetAccount(&protobuf.Account{Balance: 1, EBalances: eBalances})
+	accService.SetExtAddress(extAddress)
+	accService.SetTxValue(10)
+	accService.SetTxFee(1)
+	accService.SetAssetSymbol("ETH")
+	assert.True(t, accService.VerifyAccountBalance())
+
+	// The fee is paid in HER, not in the external asset
+	accService.SetTxFee(2)
+	assert.False(t, accService.VerifyAccountBalance())
+}
+
 func TestVerifyExternalAssetBalanceTrue(t *testing.T) {
 	eBalance := &protobuf.EBalance{
 		Address: "0xD8f647855876549d2623f52126CE40D053a2ef6A",
@@ -0,0 +1,317 @@
+package keystore
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/herdius/herdius-core/crypto"
+	cryptoAmino "github.com/herdius/herdius-core/crypto/encoding/amino"
+	"github.com/herdius/herdius-core/crypto/secp256k1"
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/scrypt"
+)
+
+// An encrypted key file holds a private key encrypted with AES-256-GCM under
+// a key derived from a passphrase with scrypt or argon2id:
+//
+//	{
+//	  "version": 2,
+//	  "address": "HHy1CuT3UxCGJ3BHydLEvR5ut5TLFYAEKy",
+//	  "crypto": {
+//	    "cipher": "aes-256-gcm",
+//	    "ciphertext": "<hex>",
+//	    "nonce": "<hex>",
+//	    "kdf": "scrypt",
+//	    "kdfparams": {"salt": "<hex>", "n": 262144, "r": 8, "p": 1, "dklen": 32}
+//	  }
+//	}
+//
+// The argon2id params are salt, time, memory (KiB), threads and dklen. The
+// plaintext is the amino encoding of the private key. The version and the
+// address are authenticated along with it so that neither can be swapped.
+// Version 1 was an unencrypted layout which stored the passphrase.
+
+// Version is the version of the encrypted key file layout
+const Version = 2
+
+// KDFs and cipher of encrypted key files
+const (
+	KDFScrypt   = "scrypt"
+	KDFArgon2id = "argon2id"
+	CipherAES   = "aes-256-gcm"
+)
+
+const (
+	dkLen   = 32
+	saltLen = 32
+)
+
+// Costs of the KDFs for new key files. The limits bound the costs of the key
+// files that are decrypted.
+var (
+	scryptN, scryptR, scryptP               = 1 << 18, 8, 1
+	argon2Time, argon2Memory, argon2Threads = uint32(1), uint32(64 * 1024), uint8(4)
+
+	maxScryptN      = 1 << 22
+	maxScryptR      = 32
+	maxArgon2Time   = uint32(16)
+	maxArgon2Memory = uint32(1024 * 1024)
+)
+
+// ErrDecrypt is returned when a key file can't be decrypted with a passphrase
+var ErrDecrypt = errors.New("could not decrypt key with given passphrase")
+
+// encryptedKeyJSON is the layout of an encrypted key file
+type encryptedKeyJSON struct {
+	Version int        `json:"version"`
+	Address string     `json:"address"`
+	Crypto  CryptoJSON `json:"crypto"`
+}
+
+// CryptoJSON is the encrypted key and how to decrypt it
+type CryptoJSON struct {
+	Cipher     string    `json:"cipher"`
+	CipherText string    `json:"ciphertext"`
+	Nonce      string    `json:"nonce"`
+	KDF        string    `json:"kdf"`
+	KDFParams  KDFParams `json:"kdfparams"`
+}
+
+// KDFParams are the params of the KDF of a key file
+type KDFParams struct {
+	Salt    string `json:"salt"`
+	N       int    `json:"n,omitempty"`
+	R       int    `json:"r,omitempty"`
+	P       int    `json:"p,omitempty"`
+	Time    uint32 `json:"time,omitempty"`
+	Memory  uint32 `json:"memory,omitempty"`
+	Threads uint8  `json:"threads,omitempty"`
+	DKLen   int    `json:"dklen"`
+}
+
+// KeyFile is an encrypted key file of a key store directory
+type KeyFile struct {
+	Address string
+	Path    string
+}
+
+// Address returns the address of privKey, the Herdius address for secp256k1
+// keys and the hex encoded address for the others
+func Address(privKey crypto.PrivKey) string {
+	if pubKey, ok := privKey.PubKey().(secp256k1.PubKeySecp256k1); ok {
+		return pubKey.GetAddress()
+	}
+	return privKey.PubKey().Address().String()
+}
+
+// EncryptKey encrypts privKey with passphrase, deriving the encryption key
+// with kdf
+func EncryptKey(privKey crypto.PrivKey, passphrase, kdf string) ([]byte, error) {
+	// Only secp256k1 keys are registered with amino to be decoded
+	if _, ok := privKey.(secp256k1.PrivKeySecp256k1); !ok {
+		return nil, fmt.Errorf("unsupported key type %T", privKey)
+	}
+	salt := make([]byte, saltLen)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, fmt.Errorf("failed to read random salt: %v", err)
+	}
+	params := KDFParams{Salt: hex.EncodeToString(salt), DKLen: dkLen}
+	switch kdf {
+	case KDFScrypt:
+		params.N, params.R, params.P = scryptN, scryptR, scryptP
+	case KDFArgon2id:
+		params.Time, params.Memory, params.Threads = argon2Time, argon2Memory, argon2Threads
+	default:
+		return nil, fmt.Errorf("unsupported kdf %q", kdf)
+	}
+	derivedKey, err := deriveKey(passphrase, kdf, params)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := newGCM(derivedKey)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("failed to read random nonce: %v", err)
+	}
+	address := Address(privKey)
+	keyJSON := encryptedKeyJSON{
+		Version: Version,
+		Address: address,
+		Crypto: CryptoJSON{
+			Cipher:     CipherAES,
+			CipherText: hex.EncodeToString(gcm.Seal(nil, nonce, privKey.Bytes(), additionalData(Version, address))),
+			Nonce:      hex.EncodeToString(nonce),
+			KDF:        kdf,
+			KDFParams:  params,
+		},
+	}
+	return json.MarshalIndent(keyJSON, "", "  ")
+}
+
+// DecryptKey decrypts the encrypted key file keyjson with passphrase
+func DecryptKey(keyjson []byte, passphrase string) (crypto.PrivKey, error) {
+	var keyJSON encryptedKeyJSON
+	if err := json.Unmarshal(keyjson, &keyJSON); err != nil {
+		return nil, fmt.Errorf("failed to decode key file: %v", err)
+	}
+	if keyJSON.Version != Version {
+		return nil, fmt.Errorf("unsupported key file version %d", keyJSON.Version)
+	}
+	if keyJSON.Crypto.Cipher != CipherAES {
+		return nil, fmt.Errorf("unsupported cipher %q", keyJSON.Crypto.Cipher)
+	}
+	derivedKey, err := deriveKey(passphrase, keyJSON.Crypto.KDF, keyJSON.Crypto.KDFParams)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := newGCM(derivedKey)
+	if err != nil {
+		return nil, err
+	}
+	nonce, err := hex.DecodeString(keyJSON.Crypto.Nonce)
+	if err != nil || len(nonce) != gcm.NonceSize() {
+		return nil, fmt.Errorf("invalid nonce")
+	}
+	cipherText, err := hex.DecodeString(keyJSON.Crypto.CipherText)
+	if err != nil {
+		return nil, fmt.Errorf("invalid ciphertext: %v", err)
+	}
+	plainText, err := gcm.Open(nil, nonce, cipherText, additionalData(keyJSON.Version, keyJSON.Address))
+	if err != nil {
+		return nil, ErrDecrypt
+	}
+	privKey, err := cryptoAmino.PrivKeyFromBytes(plainText)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode private key: %v", err)
+	}
+	if Address(privKey) != keyJSON.Address {
+		return nil, fmt.Errorf("key doesn't match address %v", keyJSON.Address)
+	}
+	return privKey, nil
+}
+
+// IsEncryptedKey reports whether keyjson is an encrypted key file, of any
+// version
+func IsEncryptedKey(keyjson []byte) bool {
+	var keyJSON struct {
+		Crypto *json.RawMessage `json:"crypto"`
+	}
+	return json.Unmarshal(keyjson, &keyJSON) == nil && keyJSON.Crypto != nil
+}
+
+// StoreKey encrypts privKey with passphrase and writes it to dir, in a file
+// named after its address. It returns the path of the file.
+func StoreKey(dir string, privKey crypto.PrivKey, passphrase, kdf string) (string, error) {
+	keyjson, err := EncryptKey(privKey, passphrase, kdf)
+	if err != nil {
+		return "", err
+	}
+	path := filepath.Join(dir, Address(privKey)+".json")
+	if _, err := os.Stat(path); err == nil {
+		return "", fmt.Errorf("key file %v already exists", path)
+	}
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return "", fmt.Errorf("failed to create key store: %v", err)
+	}
+	return path, WriteKeyFile(path, keyjson)
+}
+
+// WriteKeyFile writes keyjson to path, readable by the owner only. The file
+// is replaced at once so that a failed write doesn't lose the previous key.
+func WriteKeyFile(path string, keyjson []byte) error {
+	f, err := ioutil.TempFile(filepath.Dir(path), "."+filepath.Base(path)+".tmp")
+	if err != nil {
+		return fmt.Errorf("failed to write key file: %v", err)
+	}
+	defer os.Remove(f.Name())
+	if _, err := f.Write(keyjson); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to write key file: %v", err)
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to write key file: %v", err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("failed to write key file: %v", err)
+	}
+	if err := os.Chmod(f.Name(), 0600); err != nil {
+		return fmt.Errorf("failed to write key file: %v", err)
+	}
+	return os.Rename(f.Name(), path)
+}
+
+// ListKeys returns the encrypted key files of dir sorted by address
+func ListKeys(dir string) ([]KeyFile, error) {
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read key store: %v", err)
+	}
+	var keys []KeyFile
+	for _, file := range files {
+		if file.IsDir() || strings.HasPrefix(file.Name(), ".") {
+			continue
+		}
+		path := filepath.Join(dir, file.Name())
+		keyjson, err := ioutil.ReadFile(path)
+		if err != nil {
+			continue
+		}
+		var keyJSON encryptedKeyJSON
+		if json.Unmarshal(keyjson, &keyJSON) != nil || keyJSON.Version != Version {
+			continue
+		}
+		keys = append(keys, KeyFile{Address: keyJSON.Address, Path: path})
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i].Address < keys[j].Address })
+	return keys, nil
+}
+
+// additionalData is authenticated along with the encrypted key
+func additionalData(version int, address string) []byte {
+	return []byte(fmt.Sprintf("herdius-key:%d:%s", version, address))
+}
+
+func deriveKey(passphrase, kdf string, params KDFParams) ([]byte, error) {
+	salt, err := hex.DecodeString(params.Salt)
+	if err != nil || len(salt) == 0 {
+		return nil, fmt.Errorf("invalid salt")
+	}
+	if params.DKLen != dkLen {
+		return nil, fmt.Errorf("unsupported derived key length %d", params.DKLen)
+	}
+	switch kdf {
+	case KDFScrypt:
+		if params.N > maxScryptN || params.R > maxScryptR {
+			return nil, fmt.Errorf("invalid scrypt params")
+		}
+		return scrypt.Key([]byte(passphrase), salt, params.N, params.R, params.P, params.DKLen)
+	case KDFArgon2id:
+		if params.Time == 0 || params.Time > maxArgon2Time || params.Memory > maxArgon2Memory || params.Threads == 0 {
+			return nil, fmt.Errorf("invalid argon2id params")
+		}
+		return argon2.IDKey([]byte(passphrase), salt, params.Time, params.Memory, params.Threads, uint32(params.DKLen)), nil
+	}
+	return nil, fmt.Errorf("unsupported kdf %q", kdf)
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,109 @@
+package keystore
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	ed25519 "github.com/herdius/herdius-core/crypto/ed"
+	"github.com/herdius/herdius-core/crypto/secp256k1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// lightKDF lowers the costs of the KDFs for the test
+func lightKDF(t *testing.T) {
+	n, memory := scryptN, argon2Memory
+	scryptN, argon2Memory = 1<<10, 1024
+	t.Cleanup(func() { scryptN, argon2Memory = n, memory })
+}
+
+func TestDecryptKeyFile(t *testing.T) {
+	for _, kdf := range []string{KDFScrypt, KDFArgon2id} {
+		keyjson, err := ioutil.ReadFile("testdata/v2_" + kdf + ".json")
+		require.NoError(t, err)
+		assert.True(t, IsEncryptedKey(keyjson))
+
+		privKey, err := DecryptKey(keyjson, "testpassphrase")
+		require.NoError(t, err, kdf)
+		assert.Equal(t, "HAtmYwUVzcc4cwdJwvLPx4gyGJ5n6Ueh7y", Address(privKey))
+		_, err = DecryptKey(keyjson, "wrong passphrase")
+		assert.Equal(t, ErrDecrypt, err)
+	}
+}
+
+func TestEncryptKey(t *testing.T) {
+	lightKDF(t)
+	for _, kdf := range []string{KDFScrypt, KDFArgon2id} {
+		privKey := secp256k1.GenPrivKey()
+		keyjson, err := EncryptKey(privKey, "passphrase", kdf)
+		require.NoError(t, err)
+		decrypted, err := DecryptKey(keyjson, "passphrase")
+		require.NoError(t, err)
+		assert.True(t, privKey.Equals(decrypted))
+	}
+	_, err := EncryptKey(secp256k1.GenPrivKey(), "passphrase", "pbkdf2")
+	assert.Error(t, err)
+	_, err = EncryptKey(ed25519.GenPrivKey(), "passphrase", KDFScrypt)
+	assert.Error(t, err)
+}
+
+func TestDecryptKeyTampered(t *testing.T) {
+	keyjson, err := ioutil.ReadFile("testdata/v2_scrypt.json")
+	require.NoError(t, err)
+	tamper := func(change func(*encryptedKeyJSON)) []byte {
+		var keyJSON encryptedKeyJSON
+		require.NoError(t, json.Unmarshal(keyjson, &keyJSON))
+		change(&keyJSON)
+		tampered, err := json.Marshal(keyJSON)
+		require.NoError(t, err)
+		return tampered
+	}
+
+	// The address is authenticated with the key
+	_, err = DecryptKey(tamper(func(k *encryptedKeyJSON) { k.Address = "HHy1CuT3UxCGJ3BHydLEvR5ut5TLFYAEKy" }), "testpassphrase")
+	assert.Equal(t, ErrDecrypt, err)
+	_, err = DecryptKey(tamper(func(k *encryptedKeyJSON) { k.Crypto.CipherText = "00" + k.Crypto.CipherText[2:] }), "testpassphrase")
+	assert.Equal(t, ErrDecrypt, err)
+	_, err = DecryptKey(tamper(func(k *encryptedKeyJSON) { k.Version = 1 }), "testpassphrase")
+	assert.Error(t, err)
+	_, err = DecryptKey(tamper(func(k *encryptedKeyJSON) { k.Crypto.KDFParams.N = 1 << 30 }), "testpassphrase")
+	assert.Error(t, err, "costs over the limits aren't derived")
+	_, err = DecryptKey(tamper(func(k *encryptedKeyJSON) { k.Crypto.Cipher = "aes-128-ctr" }), "testpassphrase")
+	assert.Error(t, err)
+
+	legacy, err := ioutil.ReadFile("testdata/v1_test_argon2.json")
+	require.NoError(t, err)
+	_, err = DecryptKey(legacy, "Secret Passphrase")
+	assert.Error(t, err)
+}
+
+func TestStoreAndListKeys(t *testing.T) {
+	lightKDF(t)
+	dir, err := ioutil.TempDir("", "keystore")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	privKey := secp256k1.GenPrivKey()
+	path, err := StoreKey(dir, privKey, "passphrase", KDFScrypt)
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, Address(privKey)+".json"), path)
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+	_, err = StoreKey(dir, privKey, "passphrase", KDFScrypt)
+	assert.Error(t, err, "a key file isn't overwritten")
+
+	other, err := StoreKey(dir, secp256k1.GenPrivKey(), "passphrase", KDFArgon2id)
+	require.NoError(t, err)
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "notes.txt"), []byte("not a key"), 0600))
+
+	keys, err := ListKeys(dir)
+	require.NoError(t, err)
+	require.Len(t, keys, 2)
+	paths := []string{keys[0].Path, keys[1].Path}
+	assert.ElementsMatch(t, []string{path, other}, paths)
+	assert.True(t, keys[0].Address < keys[1].Address)
+}
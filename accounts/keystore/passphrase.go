@@ -0,0 +1,57 @@
+package keystore
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"golang.org/x/crypto/ssh/terminal"
+)
+
+// ReadPassphrase reads the passphrase from the first line of file, or prompts
+// for it on the terminal if file is empty. A new passphrase is prompted for
+// twice. Empty passphrases are rejected.
+func ReadPassphrase(file, prompt string, confirm bool) (string, error) {
+	if len(file) > 0 {
+		content, err := ioutil.ReadFile(file)
+		if err != nil {
+			return "", fmt.Errorf("failed to read passphrase file: %v", err)
+		}
+		passphrase := strings.TrimRight(strings.SplitN(string(content), "\n", 2)[0], "\r")
+		if len(passphrase) == 0 {
+			return "", fmt.Errorf("passphrase file %v is empty", file)
+		}
+		return passphrase, nil
+	}
+	if !terminal.IsTerminal(int(os.Stdin.Fd())) {
+		return "", fmt.Errorf("no passphrase file given and stdin is not a terminal")
+	}
+	passphrase, err := promptPassphrase(prompt)
+	if err != nil {
+		return "", err
+	}
+	if len(passphrase) == 0 {
+		return "", fmt.Errorf("empty passphrase")
+	}
+	if confirm {
+		repeated, err := promptPassphrase("Repeat passphrase: ")
+		if err != nil {
+			return "", err
+		}
+		if repeated != passphrase {
+			return "", fmt.Errorf("passphrases do not match")
+		}
+	}
+	return passphrase, nil
+}
+
+func promptPassphrase(prompt string) (string, error) {
+	fmt.Fprint(os.Stderr, prompt)
+	passphrase, err := terminal.ReadPassword(int(os.Stdin.Fd()))
+	fmt.Fprintln(os.Stderr)
+	if err != nil {
+		return "", fmt.Errorf("failed to read passphrase: %v", err)
+	}
+	return string(passphrase), nil
+}
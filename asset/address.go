@@ -0,0 +1,67 @@
+package asset
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"strings"
+
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcutil"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/mr-tron/base58"
+)
+
+// addressValidators check the addresses of each format
+var addressValidators = map[string]func(string) bool{
+	FormatHerdius:  isHerdiusAddress,
+	FormatBitcoin:  isBitcoinAddress,
+	FormatEthereum: common.IsHexAddress,
+	FormatTezos:    isTezosAddress,
+}
+
+// isHerdiusAddress checks address is the base58check encoding of a version
+// 40 hash, as created by secp256k1.PubKeySecp256k1.GetAddress
+func isHerdiusAddress(address string) bool {
+	raw, err := base58.Decode(address)
+	if err != nil || len(raw) != 25 || raw[0] != 40 {
+		return false
+	}
+	return validChecksum(raw)
+}
+
+// isBitcoinAddress checks address is a bitcoin main or test network address
+func isBitcoinAddress(address string) bool {
+	for _, params := range []*chaincfg.Params{&chaincfg.MainNetParams, &chaincfg.TestNet3Params} {
+		if _, err := btcutil.DecodeAddress(address, params); err == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// isTezosAddress checks address is an implicit (tz1, tz2, tz3) or originated
+// (KT1) tezos account
+func isTezosAddress(address string) bool {
+	prefixed := false
+	for _, prefix := range []string{"tz1", "tz2", "tz3", "KT1"} {
+		prefixed = prefixed || strings.HasPrefix(address, prefix)
+	}
+	if !prefixed {
+		return false
+	}
+	// 3 bytes prefix, 20 bytes hash and 4 bytes checksum
+	raw, err := base58.Decode(address)
+	if err != nil || len(raw) != 27 {
+		return false
+	}
+	return validChecksum(raw)
+}
+
+// validChecksum checks the last 4 bytes of raw are the double sha256 checksum
+// of the others
+func validChecksum(raw []byte) bool {
+	payload, checksum := raw[:len(raw)-4], raw[len(raw)-4:]
+	first := sha256.Sum256(payload)
+	second := sha256.Sum256(first[:])
+	return bytes.Equal(second[:4], checksum)
+}
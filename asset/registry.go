@@ -0,0 +1,186 @@
+// Package asset records the assets the chain supports: how their addresses
+// look, how wrapped assets relate to the assets backing them and which
+// syncers keep their external balances up to date.
+package asset
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Names of the syncers keeping the external balances of an asset up to date
+const (
+	SyncerETH        = "eth"
+	SyncerBTC        = "btc"
+	SyncerBTCTestnet = "btc-testnet"
+	SyncerHBTC       = "hbtc"
+	SyncerHERToken   = "her-token"
+	SyncerTezos      = "tezos"
+)
+
+// Formats of the external addresses of an asset
+const (
+	FormatHerdius  = "herdius"
+	FormatBitcoin  = "bitcoin"
+	FormatEthereum = "ethereum"
+	FormatTezos    = "tezos"
+)
+
+var (
+	// ErrUnknownAsset is returned for a symbol missing from the registry
+	ErrUnknownAsset = errors.New("unknown asset")
+	// ErrInvalidAddress is returned for an address not in the format of its asset
+	ErrInvalidAddress = errors.New("invalid address")
+)
+
+// Asset describes an asset the chain supports
+type Asset struct {
+	Symbol   string
+	Network  string
+	Decimals int
+	// Parent is the symbol of the asset backing a wrapped asset, e.g. BTC for HBTC
+	Parent string
+	// Host is the symbol of the asset whose first address of an account holds
+	// the asset, e.g. ETH for the HBTC token. Empty if the asset has its own addresses.
+	Host string
+	// AddressFormat is the format of the asset's addresses, unchecked if empty
+	AddressFormat string
+	// Syncers are the names of the syncers of the asset's external balances
+	Syncers []string
+	// External reports whether External txs can be sent for the asset
+	External bool
+}
+
+// Registry holds the supported assets by symbol
+type Registry struct {
+	assets map[string]Asset
+}
+
+// NewRegistry creates a registry of assets. The parent and host of each asset
+// have to be registered too.
+func NewRegistry(assets ...Asset) (*Registry, error) {
+	r := &Registry{assets: make(map[string]Asset, len(assets))}
+	for _, a := range assets {
+		if len(a.Symbol) == 0 {
+			return nil, fmt.Errorf("asset has no symbol")
+		}
+		a.Symbol = strings.ToUpper(a.Symbol)
+		a.Parent = strings.ToUpper(a.Parent)
+		a.Host = strings.ToUpper(a.Host)
+		if _, ok := r.assets[a.Symbol]; ok {
+			return nil, fmt.Errorf("asset %v registered twice", a.Symbol)
+		}
+		if _, ok := addressValidators[a.AddressFormat]; !ok && len(a.AddressFormat) > 0 {
+			return nil, fmt.Errorf("asset %v has unknown address format %v", a.Symbol, a.AddressFormat)
+		}
+		r.assets[a.Symbol] = a
+	}
+	for _, a := range r.assets {
+		for _, related := range []string{a.Parent, a.Host} {
+			if _, ok := r.assets[related]; !ok && len(related) > 0 {
+				return nil, fmt.Errorf("asset %v refers to unknown asset %v", a.Symbol, related)
+			}
+		}
+	}
+	return r, nil
+}
+
+// Get returns the asset of symbol
+func (r *Registry) Get(symbol string) (Asset, bool) {
+	a, ok := r.assets[strings.ToUpper(symbol)]
+	return a, ok
+}
+
+// Assets returns the registered assets sorted by symbol
+func (r *Registry) Assets() []Asset {
+	assets := make([]Asset, 0, len(r.assets))
+	for _, a := range r.assets {
+		assets = append(assets, a)
+	}
+	sort.Slice(assets, func(i, j int) bool { return assets[i].Symbol < assets[j].Symbol })
+	return assets
+}
+
+// IsExternal reports whether External txs can be sent for the asset of symbol
+func (r *Registry) IsExternal(symbol string) bool {
+	a, ok := r.Get(symbol)
+	return ok && a.External
+}
+
+// Backing returns the symbol of the asset backing symbol, which is symbol
+// itself unless it is a wrapped asset
+func (r *Registry) Backing(symbol string) string {
+	if a, ok := r.Get(symbol); ok && len(a.Parent) > 0 {
+		return a.Parent
+	}
+	return strings.ToUpper(symbol)
+}
+
+// Wrapped returns the asset wrapping the asset of symbol, if any
+func (r *Registry) Wrapped(symbol string) (Asset, bool) {
+	symbol = strings.ToUpper(symbol)
+	for _, a := range r.Assets() {
+		if a.Parent == symbol {
+			return a, true
+		}
+	}
+	return Asset{}, false
+}
+
+// ValidateAddress checks address is in the format of the asset of symbol
+func (r *Registry) ValidateAddress(symbol, address string) error {
+	a, ok := r.Get(symbol)
+	if !ok {
+		return fmt.Errorf("%w: %v", ErrUnknownAsset, symbol)
+	}
+	validate := addressValidators[a.AddressFormat]
+	if validate == nil {
+		return nil
+	}
+	if !validate(address) {
+		return fmt.Errorf("%w: %v is not a %v address", ErrInvalidAddress, address, a.Symbol)
+	}
+	return nil
+}
+
+// DefaultAssets are the assets supported unless configured otherwise
+func DefaultAssets() []Asset {
+	return []Asset{
+		{Symbol: "HER", Network: "Herdius", Decimals: 18, AddressFormat: FormatHerdius, Syncers: []string{SyncerHERToken}},
+		{Symbol: "BTC", Network: "Bitcoin", Decimals: 8, AddressFormat: FormatBitcoin, Syncers: []string{SyncerBTC, SyncerBTCTestnet}, External: true},
+		{Symbol: "ETH", Network: "Ethereum", Decimals: 18, AddressFormat: FormatEthereum, Syncers: []string{SyncerETH}, External: true},
+		{Symbol: "HBTC", Network: "Ethereum", Decimals: 8, Parent: "BTC", Host: "ETH", AddressFormat: FormatEthereum, Syncers: []string{SyncerHBTC}, External: true},
+		{Symbol: "XTZ", Network: "Tezos", Decimals: 6, AddressFormat: FormatTezos, Syncers: []string{SyncerTezos}, External: true},
+	}
+}
+
+var (
+	registryMutex sync.RWMutex
+	registry      *Registry
+)
+
+func init() {
+	r, err := NewRegistry(DefaultAssets()...)
+	if err != nil {
+		panic(err)
+	}
+	registry = r
+}
+
+// GetRegistry returns the registry of the assets the node supports
+func GetRegistry() *Registry {
+	registryMutex.RLock()
+	defer registryMutex.RUnlock()
+	return registry
+}
+
+// SetRegistry sets the registry of the assets the node supports. Every node
+// of the chain has to use the same registry.
+func SetRegistry(r *Registry) {
+	registryMutex.Lock()
+	defer registryMutex.Unlock()
+	registry = r
+}
@@ -0,0 +1,71 @@
+package asset
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/herdius/herdius-core/crypto/secp256k1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewRegistry(t *testing.T) {
+	r, err := NewRegistry(DefaultAssets()...)
+	require.NoError(t, err)
+
+	btc, ok := r.Get("btc")
+	require.True(t, ok)
+	assert.Equal(t, 8, btc.Decimals)
+	assert.True(t, r.IsExternal("HBTC"))
+	assert.False(t, r.IsExternal("HER"))
+	assert.False(t, r.IsExternal("DOGE"))
+
+	assert.Equal(t, "BTC", r.Backing("hbtc"))
+	assert.Equal(t, "ETH", r.Backing("ETH"))
+	wrapped, ok := r.Wrapped("BTC")
+	require.True(t, ok)
+	assert.Equal(t, "HBTC", wrapped.Symbol)
+	assert.Equal(t, "ETH", wrapped.Host)
+	_, ok = r.Wrapped("ETH")
+	assert.False(t, ok)
+
+	_, err = NewRegistry(Asset{Symbol: "HBTC", Parent: "BTC"})
+	assert.Error(t, err, "the parent has to be registered")
+	_, err = NewRegistry(Asset{Symbol: "BTC"}, Asset{Symbol: "btc"})
+	assert.Error(t, err, "symbols are case insensitive")
+	_, err = NewRegistry(Asset{Symbol: "BTC", AddressFormat: "unknown"})
+	assert.Error(t, err)
+}
+
+func TestValidateAddress(t *testing.T) {
+	r, err := NewRegistry(append(DefaultAssets(), Asset{Symbol: "ANY"})...)
+	require.NoError(t, err)
+	herAddress := secp256k1.GenPrivKey().PubKey().GetAddress()
+
+	tests := []struct {
+		symbol, address string
+		valid           bool
+	}{
+		{"HER", herAddress, true},
+		{"HER", herAddress[:len(herAddress)-1], false},
+		{"BTC", "1A1zP1eP5QGefi2DMPTfTL5SLmv7DivfNa", true},
+		{"BTC", "mipcBbFg9gMiCh81Kj8tqqdgoZub1ZJRfn", true},
+		{"BTC", "1A1zP1eP5QGefi2DMPTfTL5SLmv7DivfNb", false},
+		{"ETH", "0xD8f647855876549d2623f52126CE40D053a2ef6A", true},
+		{"HBTC", "0xD8f647855876549d2623f52126CE40D053a2ef6A", true},
+		{"ETH", "0xD8f647855876549d2623f52126CE40D053a2ef", false},
+		{"XTZ", "tz1KqTpEZ7Yob7QbPE4Hy4Wo8fHG8LhKxZSx", true},
+		{"XTZ", "tz1KqTpEZ7Yob7QbPE4Hy4Wo8fHG8LhKxZSy", false},
+		{"XTZ", "0xD8f647855876549d2623f52126CE40D053a2ef6A", false},
+		{"ANY", "anything", true},
+	}
+	for _, tc := range tests {
+		err := r.ValidateAddress(tc.symbol, tc.address)
+		if tc.valid {
+			assert.NoError(t, err, "%v %v", tc.symbol, tc.address)
+		} else {
+			assert.True(t, errors.Is(err, ErrInvalidAddress), "%v %v", tc.symbol, tc.address)
+		}
+	}
+	assert.True(t, errors.Is(r.ValidateAddress("DOGE", "address"), ErrUnknownAsset))
+}
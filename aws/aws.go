@@ -1,7 +1,6 @@
 package aws
 
 import (
-	"bytes"
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
@@ -10,14 +9,11 @@ import (
 	"path/filepath"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
-	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/session"
-	"github.com/aws/aws-sdk-go/service/s3"
-	"github.com/aws/aws-sdk-go/service/s3/s3manager"
 	"github.com/dgraph-io/badger"
-	"github.com/spf13/viper"
 	amino "github.com/tendermint/go-amino"
 
 	"github.com/herdius/herdius-core/blockchain"
@@ -25,90 +21,105 @@ import (
 	"github.com/herdius/herdius-core/config"
 	cryptoAmino "github.com/herdius/herdius-core/crypto/encoding/amino"
 	"github.com/herdius/herdius-core/libs/common"
+	"github.com/herdius/herdius-core/storage/blobstore"
 )
 
 // BackuperI ....
 type BackuperI interface {
 	TryBackupBaseBlock(*protobuf.BaseBlock, *protobuf.BaseBlock) (bool, error)
 	BackupNeededBaseBlocks(*protobuf.BaseBlock) error
-	backupBlock(*s3manager.Uploader, *protobuf.BaseBlock) (*s3manager.UploadOutput, error)
-	backupStateDB(*s3manager.Uploader, int64) error
-	findBlockInS3(*s3.S3, *protobuf.BaseBlock) (bool, error)
 }
 
-// Backuper ...
+// Backuper backs up base blocks and the state db to a blob store
 type Backuper struct {
-	Session      *session.Session
-	Bucket       string
+	Store        blobstore.BlobStore
 	StateDirPath string
-	BackupPath   string
-	timeStamp    string
-	objectTags   string
 }
 
-// NewBackuper creates a standard AWS SDK session
-func NewBackuper(env string) BackuperI {
-	bucket := config.GetConfiguration(env).S3Bucket
-	sdp := config.GetConfiguration(env).StateDBPath
-	sess := session.New()
-	return &Backuper{
-		Bucket:       bucket,
-		Session:      sess,
-		StateDirPath: sdp,
+// NewBlobStore returns the blob store backups of env are kept in: the
+// backupdir directory if it is configured, the S3 backup bucket otherwise
+func NewBlobStore(env string) (blobstore.BlobStore, error) {
+	detail := config.GetConfiguration(env)
+	if detail == nil {
+		return nil, fmt.Errorf("no configuration for env %v", env)
+	}
+	if len(detail.BackupDir) > 0 {
+		return blobstore.NewLocalStore(detail.BackupDir)
+	}
+	return blobstore.NewS3Store(session.New(), detail.S3Bucket), nil
+}
+
+// NewBackuper creates a Backuper backing up to the blob store of env
+func NewBackuper(env string) (BackuperI, error) {
+	store, err := NewBlobStore(env)
+	if err != nil {
+		return nil, fmt.Errorf("cannot open backup store: %v", err)
 	}
+	return &Backuper{
+		Store:        store,
+		StateDirPath: config.GetConfiguration(env).StateDBPath,
+	}, nil
+}
+
+// BlockKey is the key a base block is backed up under
+func BlockKey(baseBlock *protobuf.BaseBlock) string {
+	var blockHash common.HexBytes = baseBlock.GetHeader().GetBlock_ID().GetBlockHash()
+	return fmt.Sprintf("%v/blocks/%v", baseBlock.GetHeader().GetHeight(), blockHash)
+}
+
+// BlocksPrefix prefixes the keys of the base blocks backed up at height
+func BlocksPrefix(height int64) string {
+	return fmt.Sprintf("%v/blocks/", height)
+}
+
+// StatePrefix prefixes the keys of the state db files backed up at height
+func StatePrefix(height int64) string {
+	return fmt.Sprintf("%v/statedb/", height)
 }
 
 // TryBackupBaseBlock takes a single block, returns true if able and successfully backup, false if business logic makes backup
 // not useful, and errors if attempted backup fails
 func (b *Backuper) TryBackupBaseBlock(lastBlock, baseBlock *protobuf.BaseBlock) (bool, error) {
-	svc := s3.New(b.Session)
-	found, err := b.findBlockInS3(svc, lastBlock)
+	found, err := b.Store.Exists(BlockKey(lastBlock))
 	if err != nil {
-		return false, fmt.Errorf("failure searching S3 for previous block backup: %v", err)
+		return false, fmt.Errorf("failure searching backup for previous block: %v", err)
 	}
 	if !found {
 		return false, nil
 	}
 
-	uploader := s3manager.NewUploader(b.Session)
-	res, err := b.backupBlock(uploader, baseBlock)
+	tags, err := b.backupBlock(baseBlock)
 	if err != nil {
-		return false, fmt.Errorf("could not backup new base block to S3: %v", err)
+		return false, fmt.Errorf("could not backup new base block: %v", err)
 	}
-	log.Println("Uploaded base block file to S3:", res.Location)
+	log.Println("Backed up base block:", BlockKey(baseBlock))
 
-	err = b.backupStateDB(uploader, baseBlock.Header.Height)
+	err = b.backupStateDB(baseBlock.Header.Height, tags)
 	if err != nil {
-		return false, fmt.Errorf("could not backup State DB to S3: %v", err)
+		return false, fmt.Errorf("could not backup State DB: %v", err)
 	}
 	return true, nil
 }
 
-// BackupNeededBaseBlocks iteratively goes through the entire blockchain and pushes up the contents of each block into S3
+// BackupNeededBaseBlocks iteratively goes through the entire blockchain and backs up each block not yet in the store
 func (b *Backuper) BackupNeededBaseBlocks(newBlock *protobuf.BaseBlock) error {
 	cdc := amino.NewCodec()
 	cryptoAmino.RegisterAmino(cdc)
 
-	viper.SetConfigName("config")   // Config file name without extension
-	viper.AddConfigPath("./config") // Path to config file
-	err := viper.ReadInConfig()
-	if err != nil {
-		return fmt.Errorf("config file not found: %v", err)
-	}
-
 	bDB := blockchain.GetBlockchainDb()
 
-	svc := s3.New(b.Session)
-	uploader := s3manager.NewUploader(b.Session)
-	res, err := b.backupBlock(uploader, newBlock)
+	tags, err := b.backupBlock(newBlock)
 	if err != nil {
-		return fmt.Errorf("while trying to backup all needed base blocks, could not backup new base blocks to S3: %v", err)
+		return fmt.Errorf("while trying to backup all needed base blocks, could not backup new base block: %v", err)
 	}
-	log.Println("Block backed up to S3:", res.Location)
+	log.Println("Block backed up:", BlockKey(newBlock))
 
-	var blockHash common.HexBytes
-	added, failed, maxThread := 0, 0, 200
-	height := int64(0)
+	var (
+		mu                sync.Mutex
+		wg                sync.WaitGroup
+		added, failed     int
+		height, maxThread = newBlock.GetHeader().GetHeight(), 200
+	)
 	sem := make(chan struct{}, maxThread)
 
 	err = bDB.GetBadgerDB().View(func(txn *badger.Txn) error {
@@ -118,6 +129,9 @@ func (b *Backuper) BackupNeededBaseBlocks(newBlock *protobuf.BaseBlock) error {
 		defer it.Close()
 		for it.Rewind(); it.Valid(); it.Next() {
 			item := it.Item()
+			if blockchain.IsIndexKey(item.Key()) {
+				continue
+			}
 			v, err := item.Value()
 			if err != nil {
 				return fmt.Errorf("cannot retrieve item value: %v", err)
@@ -127,206 +141,103 @@ func (b *Backuper) BackupNeededBaseBlocks(newBlock *protobuf.BaseBlock) error {
 			if err != nil {
 				return fmt.Errorf("cannot unmarshal db block into struct block: %v", err)
 			}
-			blockHash = block.Header.Block_ID.BlockHash
 
 			sem <- struct{}{}
-			go func(blockHash common.HexBytes) {
-				defer func() { <-sem }()
-				found, err := b.findBlockInS3(svc, block)
+			wg.Add(1)
+			go func(block *protobuf.BaseBlock) {
+				defer func() { <-sem; wg.Done() }()
+				key := BlockKey(block)
+				found, err := b.Store.Exists(key)
 				if err != nil {
 					log.Println("Nonfatal: while attempting full chain backup, error while searching for block", err)
+					mu.Lock()
 					failed++
+					mu.Unlock()
 					return
 				}
 				if found {
-					log.Printf("Block found in s3 while backing up entire chain: %v", b.BackupPath)
+					log.Printf("Block found in backup while backing up entire chain: %v", key)
 					return
 				}
-				log.Printf("Block not found in S3, backing up: %v", b.BackupPath)
-				res, err := b.backupBlock(uploader, block)
-				if err != nil {
-					log.Println("Nonfatal: could not backup base block to S3:", err)
+				log.Printf("Block not found in backup, backing up: %v", key)
+				if _, err := b.backupBlock(block); err != nil {
+					log.Println("Nonfatal: could not backup base block:", err)
+					mu.Lock()
 					failed++
+					mu.Unlock()
 					return
 				}
+				log.Println("Block backed up:", key)
+				mu.Lock()
 				if block.Header.Height > height {
 					height = block.Header.Height
 				}
-				log.Println("Block backed up to S3:", res.Location)
 				added++
-			}(blockHash)
+				mu.Unlock()
+			}(block)
 		}
 		return nil
 	})
-	for i := 0; i < cap(sem); i++ {
-		sem <- struct{}{}
+	wg.Wait()
+	if err != nil {
+		return fmt.Errorf("failed to iterate chain: %v", err)
 	}
 	log.Printf("Finished backing up all blocks; added blocks: %v, chain height: %v, blocks failed to backup: %v", added, height, failed)
-	err = b.backupStateDB(uploader, height)
+	err = b.backupStateDB(height, tags)
 	if err != nil {
-		return fmt.Errorf("Nonfatal: could not backup state DB to S3: %v", err)
+		return fmt.Errorf("Nonfatal: could not backup state DB: %v", err)
 	}
 	return nil
 }
 
-// findInS3 searches for a given baseBlock in S3 in the given bucket
-func (b *Backuper) findBlockInS3(svc *s3.S3, baseBlock *protobuf.BaseBlock) (bool, error) {
-	var blockHash common.HexBytes
-	blockHashBz := baseBlock.GetHeader().GetBlock_ID().GetBlockHash()
-	blockHash = blockHashBz
-	blockHeight := strconv.FormatInt(baseBlock.Header.Height, 10)
-	b.BackupPath = fmt.Sprintf("%v/blocks/%v", blockHeight, blockHash)
-	search := &s3.ListObjectsV2Input{
-		Bucket: aws.String(b.Bucket),
-		Prefix: aws.String(b.BackupPath),
-	}
-	result, err := svc.ListObjectsV2(search)
-	if err != nil {
-		return false, fmt.Errorf("could not list previous block in S3: %v", err)
-	}
-	if len(result.Contents) <= 0 {
-		return false, nil
-	}
-	return true, nil
-}
-
-// backupBloc backs up a single baseBlock to S3 in the given Backuper.Bucket
-func (b *Backuper) backupBlock(uploader *s3manager.Uploader, baseBlock *protobuf.BaseBlock) (result *s3manager.UploadOutput, err error) {
-	fileName := "tmpfile.txt"
-
-	tmpFile, err := ioutil.TempFile("", fileName)
-	if err != nil {
-		return nil, fmt.Errorf("cannot create tmpfile: %v ", err)
-	}
-	defer os.Remove(tmpFile.Name())
-
+// backupBlock backs up a single baseBlock and returns the tags it was stored with
+func (b *Backuper) backupBlock(baseBlock *protobuf.BaseBlock) (map[string]string, error) {
 	bBlock, err := json.Marshal(baseBlock)
 	if err != nil {
 		return nil, fmt.Errorf("cannot convert baseBlock to json: %v", err)
 	}
 
-	_, err = tmpFile.Write(bBlock)
-	if err != nil {
-		return nil, fmt.Errorf("cannot write to: tmpfile %v: %v", fileName, err)
+	var blockHash common.HexBytes = baseBlock.GetHeader().GetBlock_ID().GetBlockHash()
+	tags := map[string]string{
+		"height":    strconv.FormatInt(baseBlock.GetHeader().GetHeight(), 10),
+		"timestamp": strconv.FormatInt(time.Now().Unix(), 10),
+		"blockhash": blockHash.String(),
 	}
-	defer func() {
-		errF := tmpFile.Close()
-		if err != nil && errF != nil {
-			err = fmt.Errorf("%v: %v", err, errF)
-		}
-		if errF != nil {
-			err = fmt.Errorf("cannot close tmpfile %v: %v", fileName, errF)
-		}
-	}()
-	_, err = tmpFile.Seek(0, 0)
-	if err != nil {
-		return nil, fmt.Errorf("cannot seek file %v: %v", fileName, err)
-	}
-
-	heightStr := strconv.Itoa(int(baseBlock.Header.Height))
-	var blockHash common.HexBytes
-	blockHash = baseBlock.GetHeader().GetBlock_ID().GetBlockHash()
-	b.BackupPath = fmt.Sprintf("%v/blocks/%v", heightStr, blockHash)
-	b.timeStamp = strconv.Itoa(int(time.Now().Unix()))
-	b.objectTags = b.setObjectTags(heightStr, blockHash)
-
-	result, err = uploader.Upload(&s3manager.UploadInput{
-		Bucket:               aws.String(b.Bucket),
-		Key:                  aws.String(b.BackupPath),
-		Body:                 tmpFile,
-		ServerSideEncryption: aws.String("AES256"),
-		Tagging:              aws.String(b.objectTags),
-	})
-	if err != nil {
-		return nil, fmt.Errorf("failed to upload file to S3: %v", err)
+	if err := b.Store.Put(BlockKey(baseBlock), bBlock, tags); err != nil {
+		return nil, err
 	}
-	return result, err
+	return tags, nil
 }
 
-func (b *Backuper) backupStateDB(uploader *s3manager.Uploader, height int64) error {
-	w := walker{
-		uploader:     uploader,
-		uploadBucket: b.Bucket,
-		objectTags:   b.objectTags,
-		objectHeight: height,
-	}
-
-	err := filepath.Walk(b.StateDirPath, w.walk)
+// backupStateDB backs up the files of the state db under the current manifest of height
+func (b *Backuper) backupStateDB(height int64, tags map[string]string) error {
+	current, err := ioutil.ReadFile(filepath.Join(b.StateDirPath, "CURRENT"))
 	if err != nil {
-		return fmt.Errorf("couldn't walk dir: %v", err)
+		return fmt.Errorf("couldn't read CURRENT statedb file: %v", err)
 	}
-	log.Printf("State DB files uploaded: [%+v]", strings.Join(w.files, ", "))
-
-	return nil
-}
-
-func (b *Backuper) setObjectTags(height string, blockHash common.HexBytes) string {
-	return fmt.Sprintf("height=%v&timestamp=%v&blockhash=%v", height, b.timeStamp, blockHash)
-}
+	prefix := StatePrefix(height) + strings.TrimSpace(string(current)) + "/"
 
-type walker struct {
-	files        []string
-	uploader     *s3manager.Uploader
-	uploadPath   string
-	uploadBucket string
-	objectTags   string
-	objectHeight int64
-}
-
-func (w *walker) walk(path string, info os.FileInfo, err error) error {
-	if err != nil {
-		return fmt.Errorf("err walking (%q): %v", path, err)
-	}
-	if info.IsDir() {
+	var files []string
+	err = filepath.Walk(b.StateDirPath, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return fmt.Errorf("err walking (%q): %v", path, err)
+		}
+		if info.IsDir() {
+			return nil
+		}
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("couldn't read from file (%q): %v", path, err)
+		}
+		if err := b.Store.Put(prefix+info.Name(), data, tags); err != nil {
+			return fmt.Errorf("couldn't backup file (%q): %v", path, err)
+		}
+		files = append(files, path)
 		return nil
-	}
-	w.files = append(w.files, path)
-	f, err := os.Open(path)
-	if err != nil {
-		return fmt.Errorf("couldn't open file %v: %v", path, err)
-	}
-	defer f.Close()
-
-	fileInfo, err := f.Stat()
-	if err != nil {
-		return fmt.Errorf("couldn't get file info (%q): %v", path, err)
-	}
-	buffer := make([]byte, fileInfo.Size())
-	_, err = f.Read(buffer)
-	if err != nil {
-		return fmt.Errorf("couldn't read from file (%q): %v", path, err)
-	}
-	err = w.setUploadPath(fileInfo.Name())
-	if err != nil {
-		return fmt.Errorf("couldn't set uploadPath (%q): %v", path, err)
-	}
-
-	_, err = w.uploader.Upload(&s3manager.UploadInput{
-		Bucket:               aws.String(w.uploadBucket),
-		Key:                  aws.String(w.uploadPath),
-		Body:                 bytes.NewReader(buffer),
-		ServerSideEncryption: aws.String("AES256"),
-		Tagging:              aws.String(w.objectTags),
 	})
 	if err != nil {
-		return fmt.Errorf("couldn't upload file (%q) to S3: %v", path, err)
-	}
-	return nil
-}
-
-func (w *walker) setUploadPath(fileName string) error {
-	currentPath := fmt.Sprintf("herdius/statedb/CURRENT")
-	cur, err := os.Open(currentPath)
-	if err != nil {
-		return fmt.Errorf("couldn't open CURRENT statedb file: %v", err)
-	}
-	defer cur.Close()
-	contents, err := ioutil.ReadAll(cur)
-	if err != nil {
-		return fmt.Errorf("couldn't read contents from CURRENT statedb file: %v", err)
+		return fmt.Errorf("couldn't walk dir: %v", err)
 	}
-	curStr := string(contents)
-
-	w.uploadPath = fmt.Sprintf("%v/statedb/%v/%v", w.objectHeight, curStr, fileName)
+	log.Printf("State DB files backed up: [%+v]", strings.Join(files, ", "))
 	return nil
 }
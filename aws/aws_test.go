@@ -1,11 +1,19 @@
 package aws
 
 import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
 	"testing"
 
 	gomock "github.com/golang/mock/gomock"
-		"github.com/stretchr/testify/assert"
-			"github.com/herdius/herdius-core/aws/aws_mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/herdius/herdius-core/aws/aws_mocks"
+	"github.com/herdius/herdius-core/blockchain/protobuf"
+	"github.com/herdius/herdius-core/storage/blobstore"
 )
 
 func TestTryBackupBaseBlock(t *testing.T) {
@@ -28,3 +36,54 @@ func TestTryBackupBaseBlock(t *testing.T) {
 	assert.NoError(t, err)
 
 }
+
+func testBlock(height int64, hash byte) *protobuf.BaseBlock {
+	return &protobuf.BaseBlock{
+		Header: &protobuf.BaseHeader{
+			Height:   height,
+			Block_ID: &protobuf.BlockID{BlockHash: []byte{hash}},
+		},
+	}
+}
+
+func TestTryBackupBaseBlockToStore(t *testing.T) {
+	stateDir, err := ioutil.TempDir(os.TempDir(), "statedb_test_")
+	require.Nil(t, err)
+	defer os.RemoveAll(stateDir)
+	require.Nil(t, ioutil.WriteFile(filepath.Join(stateDir, "CURRENT"), []byte("MANIFEST-000002\n"), 0644))
+	require.Nil(t, ioutil.WriteFile(filepath.Join(stateDir, "MANIFEST-000002"), []byte("manifest"), 0644))
+	require.Nil(t, ioutil.WriteFile(filepath.Join(stateDir, "000001.log"), []byte("log"), 0644))
+
+	store := blobstore.NewMemStore()
+	b := &Backuper{Store: store, StateDirPath: stateDir}
+	lastBlock, baseBlock := testBlock(1, 0xA1), testBlock(2, 0xB2)
+
+	succ, err := b.TryBackupBaseBlock(lastBlock, baseBlock)
+	require.Nil(t, err)
+	assert.False(t, succ, "previous block is not backed up")
+	keys, err := store.List("")
+	require.Nil(t, err)
+	assert.Empty(t, keys)
+
+	require.Nil(t, store.Put(BlockKey(lastBlock), []byte("{}"), nil))
+	succ, err = b.TryBackupBaseBlock(lastBlock, baseBlock)
+	require.Nil(t, err)
+	assert.True(t, succ)
+
+	assert.Equal(t, "2/blocks/B2", BlockKey(baseBlock))
+	data, err := store.Get("2/blocks/B2")
+	require.Nil(t, err)
+	backedUp := &protobuf.BaseBlock{}
+	require.Nil(t, json.Unmarshal(data, backedUp))
+	assert.Equal(t, baseBlock.Header.Height, backedUp.Header.Height)
+	assert.Equal(t, "2", store.Tags("2/blocks/B2")["height"])
+	assert.Equal(t, "B2", store.Tags("2/blocks/B2")["blockhash"])
+
+	keys, err = store.List(StatePrefix(2))
+	require.Nil(t, err)
+	assert.Equal(t, []string{
+		"2/statedb/MANIFEST-000002/000001.log",
+		"2/statedb/MANIFEST-000002/CURRENT",
+		"2/statedb/MANIFEST-000002/MANIFEST-000002",
+	}, keys)
+}
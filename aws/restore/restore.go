@@ -3,73 +3,84 @@ package restore
 import (
 	"encoding/json"
 	"fmt"
+	"io/ioutil"
 	"log"
 	"os"
-	"strings"
+	"path"
+	"path/filepath"
 
+	"github.com/herdius/herdius-core/aws"
 	"github.com/herdius/herdius-core/blockchain"
 	"github.com/herdius/herdius-core/blockchain/protobuf"
 	"github.com/herdius/herdius-core/config"
-
-	"github.com/aws/aws-sdk-go/aws"
-	"github.com/aws/aws-sdk-go/aws/session"
-	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/herdius/herdius-core/storage/blobstore"
+	"github.com/herdius/herdius-core/storage/state/statedb"
 )
 
+// RestorerI restores the local chain and state db from a backup
 type RestorerI interface {
 	Restore() error
-	testCompleteChainRemote() (bool, error)
+	verifiedChain() ([]*protobuf.BaseBlock, string, error)
 	clearOld() error
-	downloadChain() (*[]protobuf.BaseBlock, error)
-	replayChain(*[]protobuf.BaseBlock) error
+	downloadChain() ([]*protobuf.BaseBlock, error)
+	replayChain([]*protobuf.BaseBlock) error
 }
 
+// Restorer restores the chain and state db from a blob store
 type Restorer struct {
 	statePath       string
 	chainPath       string
-	s3bucket        string
+	blockPath       string
 	heightToRestore int
-	s3              *s3.S3
+	store           blobstore.BlobStore
 }
 
-func NewRestorer(env string, height int) RestorerI {
+// NewRestorer creates a Restorer restoring from the backup store of env
+func NewRestorer(env string, height int) (RestorerI, error) {
 	detail := config.GetConfiguration(env)
-	s := s3.New(session.New())
+	store, err := aws.NewBlobStore(env)
+	if err != nil {
+		return nil, fmt.Errorf("cannot open backup store: %v", err)
+	}
+	return NewRestorerWithStore(store, detail.ChainDBPath, detail.BlockDBPath, detail.StateDBPath, height), nil
+}
+
+// NewRestorerWithStore creates a Restorer restoring the blocks up to height
+// to the chain and block dbs at chainPath and blockPath, and the state db to
+// statePath from store
+func NewRestorerWithStore(store blobstore.BlobStore, chainPath, blockPath, statePath string, height int) RestorerI {
 	return Restorer{
-		statePath:       detail.StateDBPath,
-		chainPath:       detail.ChainDBPath,
-		s3bucket:        detail.S3Bucket,
+		statePath:       statePath,
+		chainPath:       chainPath,
+		blockPath:       blockPath,
 		heightToRestore: height,
-		s3:              s,
+		store:           store,
 	}
 }
 
-// Restore retrieves and procceses an entire blockchain stored in S3
-// into the Supervisor's local blockchain and statedb
+// Restore retrieves the chain up to heightToRestore and the state db backed
+// up at that height, verifies them and replaces the Supervisor's local
+// blockchain and statedb with them. The local dbs are left untouched unless
+// the whole chain links up and the state db matches its last block.
 func (r Restorer) Restore() error {
-	succ, err := r.testCompleteChainRemote()
-	if err != nil {
-		return fmt.Errorf("restore failed while trying to test remote chain: %v", err)
+	blocks, stateDir, err := r.verifiedChain()
+	if stateDir != "" {
+		defer os.RemoveAll(stateDir)
 	}
-	if !succ {
-		return fmt.Errorf("could not restore chain from backup, specified chain in S3 is invalid")
+	if err != nil {
+		return fmt.Errorf("restore failed while verifying backup: %v", err)
 	}
 
 	err = r.clearOld()
 	if err != nil {
 		return fmt.Errorf("restore failed while trying to clean old chain: %v", err)
 	}
-
-	err = r.downloadState()
-	if err != nil {
-		return fmt.Errorf("restore failed while trying to download state db: %v", err)
-	}
-
-	blocks, err := r.downloadChain()
+	err = os.Rename(stateDir, r.statePath)
 	if err != nil {
-		return fmt.Errorf("restore failed while trying to download backed up chain: %v", err)
+		return fmt.Errorf("restore failed while trying to move state db into place: %v", err)
 	}
 
+	blockchain.LoadDB()
 	err = r.replayChain(blocks)
 	if err != nil {
 		return fmt.Errorf("restore failed while trying to replay chain: %v", err)
@@ -78,160 +89,101 @@ func (r Restorer) Restore() error {
 	return nil
 }
 
-func (r Restorer) testCompleteChainRemote() (bool, error) {
-	listParams := &s3.ListObjectsV2Input{
-		Bucket:  aws.String(r.s3bucket),
-		Prefix:  aws.String("0/blocks/"),
-		MaxKeys: aws.Int64(1),
-	}
-	listResult, err := r.s3.ListObjectsV2(listParams)
+// verifiedChain downloads and verifies the backed up chain, and the state db
+// into a temporary dir next to statePath it returns. The dir is returned
+// along with any error so the caller can remove it.
+func (r Restorer) verifiedChain() ([]*protobuf.BaseBlock, string, error) {
+	blocks, err := r.downloadChain()
 	if err != nil {
-		return false, fmt.Errorf("failed to retrieve list of S3 objects: %v", err)
+		return nil, "", fmt.Errorf("failed to download backed up chain: %v", err)
 	}
-	if len(listResult.Contents) != 1 {
-		return false, fmt.Errorf("failed to find base block in S3 (block height = 0)")
-	}
-	log.Printf("root base block: %+v", *listResult.Contents[0].Key)
-	key := *listResult.Contents[0].Key
-	downloadParams := &s3.GetObjectInput{
-		Bucket: aws.String(r.s3bucket),
-		Key:    aws.String(key),
+	if err := blockchain.VerifyChain(blocks); err != nil {
+		return nil, "", fmt.Errorf("backed up chain is invalid: %v", err)
 	}
 
-	for i := 0; i < r.heightToRestore; i++ {
-		downloadParams.Key = aws.String(key)
-		downResult, err := r.s3.GetObject(downloadParams)
-		if err != nil {
-			return false, fmt.Errorf("failed to download S3 objects (height=%v, key=%v): %v", i, key, err)
-		}
-
-		key, err = r.getKeyFromDownload(i+1, downResult)
-		if err != nil {
-			return false, fmt.Errorf("failed to get key from prior block download (height=%v): %v", i, err)
-		}
-
+	stateDir := r.statePath + ".restore"
+	if err := os.RemoveAll(stateDir); err != nil {
+		return nil, "", fmt.Errorf("failed to clear state dir: %v", err)
+	}
+	if err := r.downloadState(stateDir); err != nil {
+		return nil, stateDir, fmt.Errorf("failed to download state db: %v", err)
 	}
-	return true, nil
+	lastBlock := blocks[len(blocks)-1]
+	if err := statedb.VerifyStateDB(stateDir, lastBlock.GetHeader().GetStateRoot()); err != nil {
+		return nil, stateDir, fmt.Errorf("backed up state db doesn't match block %d: %v", lastBlock.GetHeader().GetHeight(), err)
+	}
+	log.Printf("verified backed up chain up to height %d and its state db", lastBlock.GetHeader().GetHeight())
+	return blocks, stateDir, nil
 }
 
 func (r Restorer) clearOld() error {
-	err := os.RemoveAll(r.chainPath)
-	if err != nil {
-		return fmt.Errorf("failed to clear old chain path: %v", err)
-	}
-	err = os.RemoveAll(r.statePath)
-	if err != nil {
-		return fmt.Errorf("failed to clear old state path: %v", err)
+	for _, dir := range []string{r.chainPath, r.blockPath, r.statePath} {
+		if err := os.RemoveAll(dir); err != nil {
+			return fmt.Errorf("failed to clear old %v: %v", dir, err)
+		}
 	}
 	return nil
 }
 
-func (r Restorer) downloadState() (downloadErr error) {
-
-	pre := fmt.Sprintf("%v/statedb/MANIFEST", r.heightToRestore)
-	listParams := &s3.ListObjectsV2Input{
-		Bucket: aws.String(r.s3bucket),
-		Prefix: aws.String(pre),
-	}
-	listResult, err := r.s3.ListObjectsV2(listParams)
+// downloadState downloads the state db backed up at heightToRestore into dir
+func (r Restorer) downloadState(dir string) error {
+	pre := aws.StatePrefix(int64(r.heightToRestore)) + "MANIFEST"
+	keys, err := r.store.List(pre)
 	if err != nil {
-		return fmt.Errorf("failed to retrieve list of S3 objects: %v", err)
-	}
-	if len(listResult.Contents) <= 1 {
-		return fmt.Errorf("failed to find state db in S3 (key = %v)", pre)
+		return fmt.Errorf("failed to retrieve list of backed up objects: %v", err)
 	}
-	downloadParams := &s3.GetObjectInput{
-		Bucket: aws.String(r.s3bucket),
+	if len(keys) <= 1 {
+		return fmt.Errorf("failed to find state db in backup (key = %v)", pre)
 	}
-	err = os.Mkdir(r.statePath, 0777)
+	err = os.MkdirAll(dir, 0777)
 	if err != nil {
 		return fmt.Errorf("failed to create state dir: %v", err)
 	}
-	for _, obj := range listResult.Contents {
-		downloadParams.Key = obj.Key
-		stateFile, err := r.s3.GetObject(downloadParams)
-		if err != nil {
-			return fmt.Errorf("failed to download S3 objects (height=%v, key=%v): %v", r.heightToRestore, obj.Key, err)
-		}
-		f := strings.Split(*obj.Key, "/")
-		fileName := f[len(f)-1]
-		path, ok := os.LookupEnv("GOPATH")
-		if !ok {
-			return fmt.Errorf("failed to get statePath, GOPATH env var unset")
-		}
-		file, err := os.Create(fmt.Sprintf("%v/src/github.com/herdius/herdius-core/%v/%v", path, r.statePath, fileName))
+	for _, key := range keys {
+		body, err := r.store.Get(key)
 		if err != nil {
-			return fmt.Errorf("failed to create state file %v: %v", fileName, err)
+			return fmt.Errorf("failed to download backed up objects (height=%v, key=%v): %v", r.heightToRestore, key, err)
 		}
-		defer func() {
-			err := file.Close()
-			if err != nil {
-				downloadErr = fmt.Errorf("couldn't close state files: %v", err)
-			}
-		}()
-		body := make([]byte, *stateFile.ContentLength)
-		_, err = stateFile.Body.Read(body)
+		fileName := path.Base(key)
+		err = ioutil.WriteFile(filepath.Join(dir, fileName), body, 0644)
 		if err != nil {
-			return fmt.Errorf("couldn't read statefile body: %v", err)
+			return fmt.Errorf("failed to write state file %v: %v", fileName, err)
 		}
-		file.Write(body)
 		log.Printf("successfully wrote to %v", fileName)
 	}
 	return nil
 }
 
-func (r Restorer) downloadChain() (*[]protobuf.BaseBlock, error) {
-	listParams := &s3.ListObjectsV2Input{
-		Bucket:  aws.String(r.s3bucket),
-		Prefix:  aws.String("0/blocks/"),
-		MaxKeys: aws.Int64(1),
-	}
-	listResult, err := r.s3.ListObjectsV2(listParams)
-	if err != nil {
-		return nil, fmt.Errorf("failed to retrieve list of S3 objects: %v", err)
-	}
-	if len(listResult.Contents) != 1 {
-		return nil, fmt.Errorf("failed to find base block in S3 (block height = 0)")
-	}
-	log.Printf("root base block: %+v", *listResult.Contents[0].Key)
-
-	key := *listResult.Contents[0].Key
-	downloadParams := &s3.GetObjectInput{
-		Bucket: aws.String(r.s3bucket),
-		Key:    aws.String(key),
-	}
-	baseBlocks := &[]protobuf.BaseBlock{}
+// downloadChain downloads the blocks from the genesis block up to heightToRestore
+func (r Restorer) downloadChain() ([]*protobuf.BaseBlock, error) {
+	var baseBlocks []*protobuf.BaseBlock
 
-	for i := 0; i < r.heightToRestore; i++ {
-		downloadParams.Key = aws.String(key)
-		downResult, err := r.s3.GetObject(downloadParams)
+	for i := 0; i <= r.heightToRestore; i++ {
+		key, err := r.blockKey(i)
 		if err != nil {
-			return nil, fmt.Errorf("failed to download S3 objects (height=%v, key=%v): %v", i, key, err)
+			return nil, err
 		}
-
-		baseBlock := protobuf.BaseBlock{}
-		dec := json.NewDecoder(downResult.Body)
-		err = dec.Decode(&baseBlock)
+		body, err := r.store.Get(key)
 		if err != nil {
-			return nil, fmt.Errorf("failed to unmarshal S3 object into baseblock (height=%v, key=%v): %v", i, key, err)
+			return nil, fmt.Errorf("failed to download backed up objects (height=%v, key=%v): %v", i, key, err)
 		}
-		*baseBlocks = append(*baseBlocks, baseBlock)
 
-		key, err = r.getKeyFromDownload(i+1, downResult)
+		baseBlock := &protobuf.BaseBlock{}
+		err = json.Unmarshal(body, baseBlock)
 		if err != nil {
-			return nil, fmt.Errorf("failed to get key from prior block download (height=%v): %v", i, err)
+			return nil, fmt.Errorf("failed to unmarshal backed up object into baseblock (height=%v, key=%v): %v", i, key, err)
 		}
-
+		baseBlocks = append(baseBlocks, baseBlock)
 	}
 	return baseBlocks, nil
 }
 
-func (r Restorer) replayChain(blocks *[]protobuf.BaseBlock) error {
-	log.Println("replaying chain, number of blocks:", len(*blocks))
+func (r Restorer) replayChain(blocks []*protobuf.BaseBlock) error {
+	log.Println("replaying chain, number of blocks:", len(blocks))
 	chain := blockchain.Service{}
-	for _, block := range *blocks {
+	for _, block := range blocks {
 		log.Printf("content: %+v", block.Header.Block_ID.BlockHash)
-		err := chain.AddBaseBlock(&block)
+		err := chain.AddBaseBlock(block)
 		if err != nil {
 			return fmt.Errorf("couldn't add base block to chain: %v", err)
 		}
@@ -239,20 +191,14 @@ func (r Restorer) replayChain(blocks *[]protobuf.BaseBlock) error {
 	return nil
 }
 
-func (r Restorer) getKeyFromDownload(i int, obj *s3.GetObjectOutput) (string, error) {
-	listParams := &s3.ListObjectsV2Input{
-		Bucket:  aws.String(r.s3bucket),
-		Prefix:  aws.String(fmt.Sprintf("%v/blocks/", i)),
-		MaxKeys: aws.Int64(1),
-	}
-	listResult, err := r.s3.ListObjectsV2(listParams)
+// blockKey returns the key of the base block backed up at height
+func (r Restorer) blockKey(height int) (string, error) {
+	keys, err := r.store.List(aws.BlocksPrefix(int64(height)))
 	if err != nil {
-		return "", fmt.Errorf("failed to retrieve list of S3 objects: %v", err)
+		return "", fmt.Errorf("failed to retrieve list of backed up objects: %v", err)
 	}
-	if len(listResult.Contents) != 1 {
-		return "", fmt.Errorf("failed to find base block in S3 (block height = 0)")
+	if len(keys) == 0 {
+		return "", fmt.Errorf("failed to find base block in backup (block height = %v)", height)
 	}
-
-	return *listResult.Contents[0].Key, nil
-
+	return keys[0], nil
 }
@@ -0,0 +1,376 @@
+package blockchain
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/dgraph-io/badger"
+
+	"github.com/herdius/herdius-core/blockchain/protobuf"
+	pluginproto "github.com/herdius/herdius-core/hbi/protobuf"
+	cmn "github.com/herdius/herdius-core/libs/common"
+	"github.com/herdius/herdius-core/p2p/log"
+	"github.com/herdius/herdius-core/supervisor/transaction"
+)
+
+// Secondary tx indexes live in the chain db next to the blocks, under
+// indexPrefix, so they are written in the same badger transaction as the
+// block they point into:
+//
+//	index/tx/<txID>                                  -> location
+//	index/addr/<address>/<height>/<pos>              -> location
+//	index/asset/<symbol>/<address>/<height>/<pos>    -> location
+//	index/type/<type>/<height>/<pos>                 -> location
+//
+// Heights and positions are zero padded so that prefix scans return txs in
+// chain order. Addresses, symbols and types are lower cased since queries
+// match them case insensitively.
+var (
+	indexPrefix   = []byte("index/")
+	lastBlockKey  = []byte("LastBlock")
+	txIndexPrefix = "index/tx/"
+)
+
+// txLocation points at a tx inside a base block
+type txLocation struct {
+	BlockHash cmn.HexBytes `json:"block_hash"`
+	Height    int64        `json:"height"`
+	Index     int          `json:"index"`
+}
+
+// blockTx is a tx of a base block as stored and decoded
+type blockTx struct {
+	raw []byte
+	tx  *pluginproto.Tx
+}
+
+// IsIndexKey reports whether key of the chain db holds an index entry rather
+// than a block.
+func IsIndexKey(key []byte) bool {
+	return bytes.HasPrefix(key, indexPrefix)
+}
+
+func txKey(txID string) []byte {
+	return []byte(txIndexPrefix + txID)
+}
+
+func addressPrefix(address string) []byte {
+	return []byte(fmt.Sprintf("index/addr/%s/", strings.ToLower(address)))
+}
+
+func assetPrefix(symbol, address string) []byte {
+	return []byte(fmt.Sprintf("index/asset/%s/%s/", strings.ToLower(symbol), strings.ToLower(address)))
+}
+
+func typePrefix(txType string, height int64) []byte {
+	return []byte(fmt.Sprintf("index/type/%s/%020d/", strings.ToLower(txType), height))
+}
+
+func positionKey(prefix []byte, height int64, pos int) []byte {
+	return append(append([]byte{}, prefix...), fmt.Sprintf("%020d/%06d", height, pos)...)
+}
+
+// blockTxs decodes all the txs of a base block, including the ones carried in
+// child blocks, in the order they are indexed. Txs that fail to decode keep
+// their position with a nil tx.
+func blockTxs(bb *protobuf.BaseBlock) []blockTx {
+	txs := make([]blockTx, 0, len(bb.GetTxsData().GetTx()))
+	for _, txbz := range bb.GetTxsData().GetTx() {
+		var tx pluginproto.Tx
+		if err := cdc.UnmarshalJSON(txbz, &tx); err != nil {
+			log.Printf("Failed to Unmarshal tx: %v", err)
+			txs = append(txs, blockTx{raw: txbz})
+			continue
+		}
+		txs = append(txs, blockTx{raw: txbz, tx: &tx})
+	}
+
+	if len(bb.GetChildBlock()) == 0 {
+		return txs
+	}
+	var cbs []*protobuf.ChildBlock
+	if err := cdc.UnmarshalJSON(bb.GetChildBlock(), &cbs); err != nil {
+		log.Printf("Failed to Unmarshal child block array: %v", err)
+		return txs
+	}
+	for _, cb := range cbs {
+		for _, txbz := range cb.GetTxsData().GetTx() {
+			var txT transaction.Tx
+			if err := cdc.UnmarshalJSON(txbz, &txT); err != nil {
+				log.Printf("Failed to Unmarshal tx: %v", err)
+				txs = append(txs, blockTx{raw: txbz})
+				continue
+			}
+			tx, err := transaction.ToProto(txT)
+			if err != nil {
+				log.Printf("Error converting Transation to Proto tx: %v", err)
+				txs = append(txs, blockTx{raw: txbz})
+				continue
+			}
+			txs = append(txs, blockTx{raw: txbz, tx: &tx})
+		}
+	}
+	return txs
+}
+
+// BlockTxs decodes all the txs of a base block, including the ones carried in
+// child blocks. Txs that fail to decode are left out.
+func BlockTxs(bb *protobuf.BaseBlock) []*pluginproto.Tx {
+	var txs []*pluginproto.Tx
+	for _, btx := range blockTxs(bb) {
+		if btx.tx != nil {
+			txs = append(txs, btx.tx)
+		}
+	}
+	return txs
+}
+
+// indexBlock writes the index entries of all the txs in bb to txn
+func indexBlock(txn *badger.Txn, bb *protobuf.BaseBlock) error {
+	height := bb.GetHeader().GetHeight()
+	for pos, btx := range blockTxs(bb) {
+		tx := btx.tx
+		if tx == nil {
+			continue
+		}
+		loc, err := cdc.MarshalJSON(txLocation{
+			BlockHash: bb.GetHeader().GetBlock_ID().GetBlockHash(),
+			Height:    height,
+			Index:     pos,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to Marshal tx location: %v", err)
+		}
+
+		keys := [][]byte{
+			txKey(TxIDWithoutStatus(tx)),
+			append(typePrefix(tx.Type, height), fmt.Sprintf("%06d", pos)...),
+		}
+		for _, address := range []string{tx.SenderAddress, tx.RecieverAddress} {
+			if len(address) == 0 {
+				continue
+			}
+			keys = append(keys, positionKey(addressPrefix(address), height, pos))
+			if tx.Asset != nil {
+				keys = append(keys, positionKey(assetPrefix(tx.Asset.Symbol, address), height, pos))
+			}
+		}
+		for _, key := range keys {
+			if err := txn.Set(key, loc); err != nil {
+				return fmt.Errorf("failed to store tx index: %v", err)
+			}
+		}
+	}
+	return nil
+}
+
+// decodedBlock is a base block loaded from the chain db along with its txs
+// and receipts
+type decodedBlock struct {
+	bb       *protobuf.BaseBlock
+	txs      []blockTx
+	receipts map[string]*pluginproto.Receipt
+}
+
+func newDecodedBlock(bb *protobuf.BaseBlock) *decodedBlock {
+	receipts := make(map[string]*pluginproto.Receipt, len(bb.GetReceipts()))
+	for _, receiptbz := range bb.GetReceipts() {
+		receipt := &pluginproto.Receipt{}
+		if err := cdc.UnmarshalJSON(receiptbz, receipt); err != nil {
+			log.Printf("Failed to Unmarshal receipt: %v", err)
+			continue
+		}
+		if len(receipt.TxId) > 0 {
+			receipts[receipt.TxId] = receipt
+		}
+	}
+	return &decodedBlock{bb: bb, txs: blockTxs(bb), receipts: receipts}
+}
+
+// getTxByLocation loads the tx pointed at by loc and its block, using blocks
+// as a cache of already loaded blocks.
+func getTxByLocation(txn *badger.Txn, loc txLocation, blocks map[string]*decodedBlock) (*decodedBlock, *blockTx, error) {
+	block, ok := blocks[string(loc.BlockHash)]
+	if !ok {
+		item, err := txn.Get(loc.BlockHash)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get block %v: %v", loc.BlockHash, err)
+		}
+		v, err := item.Value()
+		if err != nil {
+			return nil, nil, err
+		}
+		bb := &protobuf.BaseBlock{}
+		if err := cdc.UnmarshalJSON(v, bb); err != nil {
+			return nil, nil, fmt.Errorf("failed to Unmarshal Base Block: %v", err)
+		}
+		block = newDecodedBlock(bb)
+		blocks[string(loc.BlockHash)] = block
+	}
+
+	if loc.Index < 0 || loc.Index >= len(block.txs) || block.txs[loc.Index].tx == nil {
+		return nil, nil, fmt.Errorf("tx %d not found in block %v", loc.Index, loc.BlockHash)
+	}
+	return block, &block.txs[loc.Index], nil
+}
+
+// lookupTx finds a tx by its id through the tx index. It returns a nil tx if
+// the id is not indexed.
+func lookupTx(id string) (*decodedBlock, *blockTx, error) {
+	var (
+		block *decodedBlock
+		btx   *blockTx
+	)
+	err := badgerDB.GetBadgerDB().View(func(txn *badger.Txn) error {
+		item, err := txn.Get(txKey(id))
+		if err == badger.ErrKeyNotFound {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		v, err := item.Value()
+		if err != nil {
+			return err
+		}
+		var loc txLocation
+		if err := cdc.UnmarshalJSON(v, &loc); err != nil {
+			return fmt.Errorf("failed to Unmarshal tx location: %v", err)
+		}
+		block, btx, err = getTxByLocation(txn, loc, make(map[string]*decodedBlock))
+		return err
+	})
+	return block, btx, err
+}
+
+// lookupTxs returns the details of the txs indexed under prefix in chain
+// order. With dedupe set, a tx id is only returned once.
+func lookupTxs(prefix []byte, dedupe bool) ([]*pluginproto.TxDetailResponse, error) {
+	txDetails := make([]*pluginproto.TxDetailResponse, 0)
+	err := badgerDB.GetBadgerDB().View(func(txn *badger.Txn) error {
+		opts := badger.DefaultIteratorOptions
+		opts.PrefetchSize = 10
+		it := txn.NewIterator(opts)
+		defer it.Close()
+
+		blocks := make(map[string]*decodedBlock)
+		duplicateTxTracker := make(map[string]bool)
+		for it.Seek(prefix); it.ValidForPrefix(prefix); it.Next() {
+			v, err := it.Item().Value()
+			if err != nil {
+				return err
+			}
+			var loc txLocation
+			if err := cdc.UnmarshalJSON(v, &loc); err != nil {
+				return fmt.Errorf("failed to Unmarshal tx location: %v", err)
+			}
+			block, btx, err := getTxByLocation(txn, loc, blocks)
+			if err != nil {
+				return err
+			}
+			txDetail := newTxDetail(block, btx.tx)
+			if dedupe && duplicateTxTracker[txDetail.TxId] {
+				continue
+			}
+			duplicateTxTracker[txDetail.TxId] = true
+			txDetails = append(txDetails, txDetail)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return txDetails, nil
+}
+
+// newTxDetail returns the details of tx of block along with its receipt
+func newTxDetail(block *decodedBlock, tx *pluginproto.Tx) *pluginproto.TxDetailResponse {
+	txID := TxIDWithoutStatus(tx)
+	return &pluginproto.TxDetailResponse{
+		Tx:      tx,
+		TxId:    txID,
+		BlockId: uint64(block.bb.GetHeader().GetHeight()),
+		CreationDt: &pluginproto.Timestamp{
+			Seconds: block.bb.GetHeader().GetTime().GetSeconds(),
+			Nanos:   block.bb.GetHeader().GetTime().GetNanos(),
+		},
+		Receipt: block.receipts[txID],
+	}
+}
+
+// RebuildIndexes drops all the tx indexes of the chain db and rebuilds them
+// from the stored blocks. It returns the number of blocks indexed.
+func (s *Service) RebuildIndexes() (int, error) {
+	bdb := badgerDB.GetBadgerDB()
+
+	var indexKeys, blockKeys [][]byte
+	err := bdb.View(func(txn *badger.Txn) error {
+		opts := badger.DefaultIteratorOptions
+		opts.PrefetchValues = false
+		it := txn.NewIterator(opts)
+		defer it.Close()
+		for it.Rewind(); it.Valid(); it.Next() {
+			key := it.Item().KeyCopy(nil)
+			switch {
+			case IsIndexKey(key):
+				indexKeys = append(indexKeys, key)
+			case !bytes.Equal(key, lastBlockKey):
+				blockKeys = append(blockKeys, key)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to list chain db keys: %v", err)
+	}
+
+	// Delete the old entries in as few transactions as badger allows
+	txn := bdb.NewTransaction(true)
+	for _, key := range indexKeys {
+		err := txn.Delete(key)
+		if err == badger.ErrTxnTooBig {
+			if err = txn.Commit(nil); err != nil {
+				return 0, fmt.Errorf("failed to delete old indexes: %v", err)
+			}
+			txn = bdb.NewTransaction(true)
+			err = txn.Delete(key)
+		}
+		if err != nil {
+			txn.Discard()
+			return 0, fmt.Errorf("failed to delete old indexes: %v", err)
+		}
+	}
+	if err := txn.Commit(nil); err != nil {
+		return 0, fmt.Errorf("failed to delete old indexes: %v", err)
+	}
+
+	indexed := 0
+	for _, key := range blockKeys {
+		isBlock := false
+		err := bdb.Update(func(txn *badger.Txn) error {
+			item, err := txn.Get(key)
+			if err != nil {
+				return err
+			}
+			v, err := item.Value()
+			if err != nil {
+				return err
+			}
+			bb := &protobuf.BaseBlock{}
+			if err := cdc.UnmarshalJSON(v, bb); err != nil {
+				log.Printf("Skipping undecodable chain db entry %X: %v", key, err)
+				return nil
+			}
+			isBlock = true
+			return indexBlock(txn, bb)
+		})
+		if err != nil {
+			return indexed, fmt.Errorf("failed to index block %X: %v", key, err)
+		}
+		if isBlock {
+			indexed++
+		}
+	}
+	return indexed, nil
+}
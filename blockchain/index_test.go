@@ -0,0 +1,188 @@
+package blockchain
+
+import (
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/dgraph-io/badger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/herdius/herdius-core/blockchain/protobuf"
+	"github.com/herdius/herdius-core/crypto/secp256k1"
+	pluginproto "github.com/herdius/herdius-core/hbi/protobuf"
+	txbyte "github.com/herdius/herdius-core/tx"
+)
+
+// loadTestDBs loads the chain and block height dbs in temp dirs and returns
+// a func closing and removing them.
+func loadTestDBs(t *testing.T) func() {
+	dirname, err := ioutil.TempDir(os.TempDir(), "badgerdb_test_")
+	require.Nil(t, err)
+	LoadDBTest(dirname)
+	blockDirName, err := ioutil.TempDir(os.TempDir(), "badgerdb_test_")
+	require.Nil(t, err)
+	LoadBlockDBTest(blockDirName)
+	return func() {
+		badgerDB.Close()
+		blockHeightHashDB.Close()
+		os.RemoveAll(dirname)
+		os.RemoveAll(blockDirName)
+	}
+}
+
+// createIndexedBlocks creates blocks 1 to n holding perBlock distinct txs of
+// privKey each
+func createIndexedBlocks(t *testing.T, privKey secp256k1.PrivKeySecp256k1, n, perBlock int) []*protobuf.BaseBlock {
+	blocks := make([]*protobuf.BaseBlock, 0, n)
+	for h := 1; h <= n; h++ {
+		var txs txbyte.Txs
+		for i := 0; i < perBlock; i++ {
+			tx := getTx(i, privKey)
+			tx.Asset.Nonce = uint64(h*perBlock + i)
+			if i%2 == 1 {
+				tx.Type = "Lock"
+				tx.Asset.Symbol = "ETH"
+			}
+			txbz, err := cdc.MarshalJSON(tx)
+			require.Nil(t, err)
+			txs = append(txs, txbz)
+		}
+		blocks = append(blocks, createBlock(int64(h), txs, t))
+	}
+	return blocks
+}
+
+func TestGetTxByID(t *testing.T) {
+	defer loadTestDBs(t)()
+
+	privKey := secp256k1.GenPrivKey()
+	blocks := createIndexedBlocks(t, privKey, 3, 4)
+	for _, bb := range blocks {
+		require.Nil(t, (&Service{}).AddBaseBlock(bb))
+	}
+
+	txSrv := TxService{}
+	txs, err := txSrv.GetTxs(privKey.PubKey().GetAddress())
+	require.Nil(t, err)
+	require.Equal(t, 12, len(txs.GetTxs()))
+
+	want := txs.GetTxs()[6]
+	got, err := txSrv.GetTx(want.TxId)
+	require.Nil(t, err)
+	assert.Equal(t, want.TxId, got.TxId)
+	assert.Equal(t, uint64(2), got.BlockId)
+	assert.Equal(t, want.Tx.Asset.Nonce, got.Tx.Asset.Nonce)
+
+	txbz, err := (&Service{}).GetTx(want.TxId)
+	require.Nil(t, err)
+	assert.Equal(t, []byte(blocks[1].GetTxsData().GetTx()[2]), txbz)
+
+	missing, err := txSrv.GetTx("HTx0000")
+	require.Nil(t, err)
+	assert.Empty(t, missing.TxId)
+	_, err = (&Service{}).GetTx("HTx0000")
+	assert.Error(t, err)
+}
+
+func TestGetTxsByIndexOrderAndCase(t *testing.T) {
+	defer loadTestDBs(t)()
+
+	privKey := secp256k1.GenPrivKey()
+	blocks := createIndexedBlocks(t, privKey, 3, 4)
+	// Add out of order, queries still return txs in chain order
+	for _, i := range []int{2, 0, 1} {
+		require.Nil(t, (&Service{}).AddBaseBlock(blocks[i]))
+	}
+
+	txSrv := TxService{}
+	txs, err := txSrv.GetTxs(strings.ToUpper(privKey.PubKey().GetAddress()))
+	require.Nil(t, err)
+	require.Equal(t, 12, len(txs.GetTxs()))
+	for i, tx := range txs.GetTxs() {
+		assert.Equal(t, uint64(i/4+1), tx.BlockId)
+	}
+
+	ethTxs, err := txSrv.GetTxsByAssetAndAddress("eth", privKey.PubKey().GetAddress())
+	require.Nil(t, err)
+	assert.Equal(t, 6, len(ethTxs.GetTxs()))
+
+	locked, err := txSrv.GetLockedTxsByBlockNumber(3)
+	require.Nil(t, err)
+	assert.Equal(t, 2, len(locked.GetTxs()))
+	redeemed, err := txSrv.GetRedeemTxsByBlockNumber(3)
+	require.Nil(t, err)
+	assert.Equal(t, 0, len(redeemed.GetTxs()))
+	_, err = txSrv.GetLockedTxsByBlockNumber(4)
+	assert.Error(t, err)
+}
+
+func TestRebuildIndexes(t *testing.T) {
+	defer loadTestDBs(t)()
+
+	privKey := secp256k1.GenPrivKey()
+	address := privKey.PubKey().GetAddress()
+	// Store blocks the way they were stored before they were indexed, along
+	// with a stale index entry
+	for _, bb := range createIndexedBlocks(t, privKey, 3, 2) {
+		bbbz, err := cdc.MarshalJSON(bb)
+		require.Nil(t, err)
+		badgerDB.Set(bb.GetHeader().GetBlock_ID().GetBlockHash(), bbbz)
+		badgerDB.Set(lastBlockKey, bbbz)
+	}
+	staleKey := positionKey(addressPrefix(address), 9, 0)
+	badgerDB.Set(staleKey, []byte("{}"))
+
+	txSrv := TxService{}
+	_, err := txSrv.GetTxs(address)
+	require.Error(t, err, "stale index entry should not resolve")
+
+	for i := 0; i < 2; i++ {
+		indexed, err := (&Service{}).RebuildIndexes()
+		require.Nil(t, err)
+		assert.Equal(t, 3, indexed)
+
+		txs, err := txSrv.GetTxs(address)
+		require.Nil(t, err)
+		assert.Equal(t, 6, len(txs.GetTxs()))
+	}
+
+	err = badgerDB.GetBadgerDB().View(func(txn *badger.Txn) error {
+		_, err := txn.Get(staleKey)
+		return err
+	})
+	assert.Equal(t, badger.ErrKeyNotFound, err)
+}
+
+func TestGetTxReceipt(t *testing.T) {
+	defer loadTestDBs(t)()
+
+	privKey := secp256k1.GenPrivKey()
+	bb := createIndexedBlocks(t, privKey, 1, 2)[0]
+	var tx pluginproto.Tx
+	require.Nil(t, cdc.UnmarshalJSON(bb.GetTxsData().GetTx()[1], &tx))
+	receipt := &pluginproto.Receipt{
+		TxId:      TxIDWithoutStatus(&tx),
+		Status:    "failed",
+		Code:      8,
+		Message:   "insufficient balance",
+		StateRoot: []byte{1, 2, 3},
+	}
+	receiptbz, err := cdc.MarshalJSON(receipt)
+	require.Nil(t, err)
+	bb.Receipts = [][]byte{receiptbz}
+	require.Nil(t, (&Service{}).AddBaseBlock(bb))
+
+	txSrv := TxService{}
+	txDetail, err := txSrv.GetTx(receipt.TxId)
+	require.Nil(t, err)
+	assert.Equal(t, receipt, txDetail.Receipt)
+
+	txs, err := txSrv.GetTxsByHeight(1)
+	require.Nil(t, err)
+	require.Equal(t, 2, len(txs.GetTxs()))
+	assert.Nil(t, txs.GetTxs()[0].Receipt)
+	assert.Equal(t, receipt, txs.GetTxs()[1].Receipt)
+}
@@ -95,7 +95,9 @@ type Header struct {
 	// Merkle root hash of the transactions
 	RootHash []byte `protobuf:"bytes,8,opt,name=rootHash,proto3" json:"rootHash,omitempty"`
 	// Child block ID having hash
-	BlockID              *BlockID `protobuf:"bytes,9,opt,name=blockID,proto3" json:"blockID,omitempty"`
+	BlockID *BlockID `protobuf:"bytes,9,opt,name=blockID,proto3" json:"blockID,omitempty"`
+	// State root the transactions were verified against
+	StateRoot            []byte   `protobuf:"bytes,10,opt,name=stateRoot,proto3" json:"stateRoot,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -189,6 +191,13 @@ func (m *Header) GetBlockID() *BlockID {
 	return nil
 }
 
+func (m *Header) GetStateRoot() []byte {
+	if m != nil {
+		return m.StateRoot
+	}
+	return nil
+}
+
 type BlockID struct {
 	BlockHash            []byte   `protobuf:"bytes,1,opt,name=blockHash,proto3" json:"blockHash,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
@@ -283,7 +292,7 @@ func (m *ChildBlock) GetLastVotes() *VoteCommit {
 	return nil
 }
 
-//Transactions Data
+// Transactions Data
 type TxsData struct {
 	Tx                   [][]byte `protobuf:"bytes,1,rep,name=tx,proto3" json:"tx,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
@@ -436,11 +445,14 @@ func (m *VoteInfo) GetSignature() []byte {
 }
 
 type ChildBlockMessage struct {
-	Vote                 *VoteInfo   `protobuf:"bytes,1,opt,name=vote,proto3" json:"vote,omitempty"`
-	ChildBlock           *ChildBlock `protobuf:"bytes,2,opt,name=childBlock,proto3" json:"childBlock,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}    `json:"-"`
-	XXX_unrecognized     []byte      `json:"-"`
-	XXX_sizecache        int32       `json:"-"`
+	Vote       *VoteInfo   `protobuf:"bytes,1,opt,name=vote,proto3" json:"vote,omitempty"`
+	ChildBlock *ChildBlock `protobuf:"bytes,2,opt,name=childBlock,proto3" json:"childBlock,omitempty"`
+	// Merkle proofs of the accounts touched by the child block
+	// against the header's state root
+	AccountProofs        []*AccountProof `protobuf:"bytes,3,rep,name=accountProofs,proto3" json:"accountProofs,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}        `json:"-"`
+	XXX_unrecognized     []byte          `json:"-"`
+	XXX_sizecache        int32           `json:"-"`
 }
 
 func (m *ChildBlockMessage) Reset()         { *m = ChildBlockMessage{} }
@@ -482,6 +494,61 @@ func (m *ChildBlockMessage) GetChildBlock() *ChildBlock {
 	return nil
 }
 
+func (m *ChildBlockMessage) GetAccountProofs() []*AccountProof {
+	if m != nil {
+		return m.AccountProofs
+	}
+	return nil
+}
+
+// AccountProof is a merkle proof of an account in the state trie
+type AccountProof struct {
+	Address              string   `protobuf:"bytes,1,opt,name=address,proto3" json:"address,omitempty"`
+	Proof                [][]byte `protobuf:"bytes,2,rep,name=proof,proto3" json:"proof,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *AccountProof) Reset()         { *m = AccountProof{} }
+func (m *AccountProof) String() string { return proto.CompactTextString(m) }
+func (*AccountProof) ProtoMessage()    {}
+func (*AccountProof) Descriptor() ([]byte, []int) {
+	return fileDescriptor_bb17ef3f514bfe54, []int{8}
+}
+
+func (m *AccountProof) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AccountProof.Unmarshal(m, b)
+}
+func (m *AccountProof) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AccountProof.Marshal(b, m, deterministic)
+}
+func (m *AccountProof) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AccountProof.Merge(m, src)
+}
+func (m *AccountProof) XXX_Size() int {
+	return xxx_messageInfo_AccountProof.Size(m)
+}
+func (m *AccountProof) XXX_DiscardUnknown() {
+	xxx_messageInfo_AccountProof.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AccountProof proto.InternalMessageInfo
+
+func (m *AccountProof) GetAddress() string {
+	if m != nil {
+		return m.Address
+	}
+	return ""
+}
+
+func (m *AccountProof) GetProof() [][]byte {
+	if m != nil {
+		return m.Proof
+	}
+	return nil
+}
+
 type VoteCommit struct {
 	BlockID              *BlockID    `protobuf:"bytes,1,opt,name=blockID,proto3" json:"blockID,omitempty"`
 	Vote                 []*VoteInfo `protobuf:"bytes,2,rep,name=vote,proto3" json:"vote,omitempty"`
@@ -494,7 +561,7 @@ func (m *VoteCommit) Reset()         { *m = VoteCommit{} }
 func (m *VoteCommit) String() string { return proto.CompactTextString(m) }
 func (*VoteCommit) ProtoMessage()    {}
 func (*VoteCommit) Descriptor() ([]byte, []int) {
-	return fileDescriptor_bb17ef3f514bfe54, []int{8}
+	return fileDescriptor_bb17ef3f514bfe54, []int{9}
 }
 
 func (m *VoteCommit) XXX_Unmarshal(b []byte) error {
@@ -552,7 +619,7 @@ func (m *Message) Reset()         { *m = Message{} }
 func (m *Message) String() string { return proto.CompactTextString(m) }
 func (*Message) ProtoMessage()    {}
 func (*Message) Descriptor() ([]byte, []int) {
-	return fileDescriptor_bb17ef3f514bfe54, []int{9}
+	return fileDescriptor_bb17ef3f514bfe54, []int{10}
 }
 
 func (m *Message) XXX_Unmarshal(b []byte) error {
@@ -632,7 +699,7 @@ func (m *Ping) Reset()         { *m = Ping{} }
 func (m *Ping) String() string { return proto.CompactTextString(m) }
 func (*Ping) ProtoMessage()    {}
 func (*Ping) Descriptor() ([]byte, []int) {
-	return fileDescriptor_bb17ef3f514bfe54, []int{10}
+	return fileDescriptor_bb17ef3f514bfe54, []int{11}
 }
 
 func (m *Ping) XXX_Unmarshal(b []byte) error {
@@ -663,7 +730,7 @@ func (m *Pong) Reset()         { *m = Pong{} }
 func (m *Pong) String() string { return proto.CompactTextString(m) }
 func (*Pong) ProtoMessage()    {}
 func (*Pong) Descriptor() ([]byte, []int) {
-	return fileDescriptor_bb17ef3f514bfe54, []int{11}
+	return fileDescriptor_bb17ef3f514bfe54, []int{12}
 }
 
 func (m *Pong) XXX_Unmarshal(b []byte) error {
@@ -695,7 +762,7 @@ func (m *LookupNodeRequest) Reset()         { *m = LookupNodeRequest{} }
 func (m *LookupNodeRequest) String() string { return proto.CompactTextString(m) }
 func (*LookupNodeRequest) ProtoMessage()    {}
 func (*LookupNodeRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_bb17ef3f514bfe54, []int{12}
+	return fileDescriptor_bb17ef3f514bfe54, []int{13}
 }
 
 func (m *LookupNodeRequest) XXX_Unmarshal(b []byte) error {
@@ -734,7 +801,7 @@ func (m *LookupNodeResponse) Reset()         { *m = LookupNodeResponse{} }
 func (m *LookupNodeResponse) String() string { return proto.CompactTextString(m) }
 func (*LookupNodeResponse) ProtoMessage()    {}
 func (*LookupNodeResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_bb17ef3f514bfe54, []int{13}
+	return fileDescriptor_bb17ef3f514bfe54, []int{14}
 }
 
 func (m *LookupNodeResponse) XXX_Unmarshal(b []byte) error {
@@ -773,7 +840,7 @@ func (m *Bytes) Reset()         { *m = Bytes{} }
 func (m *Bytes) String() string { return proto.CompactTextString(m) }
 func (*Bytes) ProtoMessage()    {}
 func (*Bytes) Descriptor() ([]byte, []int) {
-	return fileDescriptor_bb17ef3f514bfe54, []int{14}
+	return fileDescriptor_bb17ef3f514bfe54, []int{15}
 }
 
 func (m *Bytes) XXX_Unmarshal(b []byte) error {
@@ -812,7 +879,7 @@ func (m *ConnectionMessage) Reset()         { *m = ConnectionMessage{} }
 func (m *ConnectionMessage) String() string { return proto.CompactTextString(m) }
 func (*ConnectionMessage) ProtoMessage()    {}
 func (*ConnectionMessage) Descriptor() ([]byte, []int) {
-	return fileDescriptor_bb17ef3f514bfe54, []int{15}
+	return fileDescriptor_bb17ef3f514bfe54, []int{16}
 }
 
 func (m *ConnectionMessage) XXX_Unmarshal(b []byte) error {
@@ -851,7 +918,7 @@ func (m *ClientRequest) Reset()         { *m = ClientRequest{} }
 func (m *ClientRequest) String() string { return proto.CompactTextString(m) }
 func (*ClientRequest) ProtoMessage()    {}
 func (*ClientRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_bb17ef3f514bfe54, []int{16}
+	return fileDescriptor_bb17ef3f514bfe54, []int{17}
 }
 
 func (m *ClientRequest) XXX_Unmarshal(b []byte) error {
@@ -890,7 +957,7 @@ func (m *ClientResponse) Reset()         { *m = ClientResponse{} }
 func (m *ClientResponse) String() string { return proto.CompactTextString(m) }
 func (*ClientResponse) ProtoMessage()    {}
 func (*ClientResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_bb17ef3f514bfe54, []int{17}
+	return fileDescriptor_bb17ef3f514bfe54, []int{18}
 }
 
 func (m *ClientResponse) XXX_Unmarshal(b []byte) error {
@@ -935,7 +1002,7 @@ func (m *Timestamp) Reset()         { *m = Timestamp{} }
 func (m *Timestamp) String() string { return proto.CompactTextString(m) }
 func (*Timestamp) ProtoMessage()    {}
 func (*Timestamp) Descriptor() ([]byte, []int) {
-	return fileDescriptor_bb17ef3f514bfe54, []int{18}
+	return fileDescriptor_bb17ef3f514bfe54, []int{19}
 }
 
 func (m *Timestamp) XXX_Unmarshal(b []byte) error {
@@ -971,22 +1038,29 @@ func (m *Timestamp) GetNanos() int64 {
 }
 
 type BaseBlock struct {
-	Header               *BaseHeader `protobuf:"bytes,1,opt,name=header,proto3" json:"header,omitempty"`
-	ChildBlock           []byte      `protobuf:"bytes,2,opt,name=child_block,json=childBlock,proto3" json:"child_block,omitempty"`
-	Validator            []byte      `protobuf:"bytes,3,opt,name=validator,proto3" json:"validator,omitempty"`
-	NextValidator        []byte      `protobuf:"bytes,4,opt,name=next_validator,json=nextValidator,proto3" json:"next_validator,omitempty"`
-	VoteCommits          []byte      `protobuf:"bytes,5,opt,name=vote_commits,json=voteCommits,proto3" json:"vote_commits,omitempty"`
-	TxsData              *TxsData    `protobuf:"bytes,6,opt,name=txsData,proto3" json:"txsData,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}    `json:"-"`
-	XXX_unrecognized     []byte      `json:"-"`
-	XXX_sizecache        int32       `json:"-"`
+	Header        *BaseHeader `protobuf:"bytes,1,opt,name=header,proto3" json:"header,omitempty"`
+	ChildBlock    []byte      `protobuf:"bytes,2,opt,name=child_block,json=childBlock,proto3" json:"child_block,omitempty"`
+	Validator     []byte      `protobuf:"bytes,3,opt,name=validator,proto3" json:"validator,omitempty"`
+	NextValidator []byte      `protobuf:"bytes,4,opt,name=next_validator,json=nextValidator,proto3" json:"next_validator,omitempty"`
+	VoteCommits   []byte      `protobuf:"bytes,5,opt,name=vote_commits,json=voteCommits,proto3" json:"vote_commits,omitempty"`
+	TxsData       *TxsData    `protobuf:"bytes,6,opt,name=txsData,proto3" json:"txsData,omitempty"`
+	// Encoded receipts of the transactions in the block
+	Receipts [][]byte `protobuf:"bytes,7,rep,name=receipts,proto3" json:"receipts,omitempty"`
+	// Validator groups the child blocks were sent to
+	ValidatorGroups []*ValidatorGroup `protobuf:"bytes,8,rep,name=validator_groups,json=validatorGroups,proto3" json:"validator_groups,omitempty"`
+	// Accounts written to the state for the external balance updates of
+	// the syncer, before the transactions were applied
+	AccountUpdates       []*AccountUpdate `protobuf:"bytes,9,rep,name=account_updates,json=accountUpdates,proto3" json:"account_updates,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}         `json:"-"`
+	XXX_unrecognized     []byte           `json:"-"`
+	XXX_sizecache        int32            `json:"-"`
 }
 
 func (m *BaseBlock) Reset()         { *m = BaseBlock{} }
 func (m *BaseBlock) String() string { return proto.CompactTextString(m) }
 func (*BaseBlock) ProtoMessage()    {}
 func (*BaseBlock) Descriptor() ([]byte, []int) {
-	return fileDescriptor_bb17ef3f514bfe54, []int{19}
+	return fileDescriptor_bb17ef3f514bfe54, []int{20}
 }
 
 func (m *BaseBlock) XXX_Unmarshal(b []byte) error {
@@ -1049,6 +1123,132 @@ func (m *BaseBlock) GetTxsData() *TxsData {
 	return nil
 }
 
+func (m *BaseBlock) GetReceipts() [][]byte {
+	if m != nil {
+		return m.Receipts
+	}
+	return nil
+}
+
+func (m *BaseBlock) GetValidatorGroups() []*ValidatorGroup {
+	if m != nil {
+		return m.ValidatorGroups
+	}
+	return nil
+}
+
+func (m *BaseBlock) GetAccountUpdates() []*AccountUpdate {
+	if m != nil {
+		return m.AccountUpdates
+	}
+	return nil
+}
+
+// AccountUpdate is an encoded account written to the state
+type AccountUpdate struct {
+	Address              string   `protobuf:"bytes,1,opt,name=address,proto3" json:"address,omitempty"`
+	Account              []byte   `protobuf:"bytes,2,opt,name=account,proto3" json:"account,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *AccountUpdate) Reset()         { *m = AccountUpdate{} }
+func (m *AccountUpdate) String() string { return proto.CompactTextString(m) }
+func (*AccountUpdate) ProtoMessage()    {}
+func (*AccountUpdate) Descriptor() ([]byte, []int) {
+	return fileDescriptor_bb17ef3f514bfe54, []int{21}
+}
+
+func (m *AccountUpdate) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AccountUpdate.Unmarshal(m, b)
+}
+func (m *AccountUpdate) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AccountUpdate.Marshal(b, m, deterministic)
+}
+func (m *AccountUpdate) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AccountUpdate.Merge(m, src)
+}
+func (m *AccountUpdate) XXX_Size() int {
+	return xxx_messageInfo_AccountUpdate.Size(m)
+}
+func (m *AccountUpdate) XXX_DiscardUnknown() {
+	xxx_messageInfo_AccountUpdate.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AccountUpdate proto.InternalMessageInfo
+
+func (m *AccountUpdate) GetAddress() string {
+	if m != nil {
+		return m.Address
+	}
+	return ""
+}
+
+func (m *AccountUpdate) GetAccount() []byte {
+	if m != nil {
+		return m.Account
+	}
+	return nil
+}
+
+// ValidatorGroup is a group of validators and the child block sent to them
+type ValidatorGroup struct {
+	Validators     []string `protobuf:"bytes,1,rep,name=validators,proto3" json:"validators,omitempty"`
+	ChildBlockHash []byte   `protobuf:"bytes,2,opt,name=child_block_hash,json=childBlockHash,proto3" json:"child_block_hash,omitempty"`
+	// Whether the child block got the votes of over 2/3 of the group's stake
+	Committed            bool     `protobuf:"varint,3,opt,name=committed,proto3" json:"committed,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ValidatorGroup) Reset()         { *m = ValidatorGroup{} }
+func (m *ValidatorGroup) String() string { return proto.CompactTextString(m) }
+func (*ValidatorGroup) ProtoMessage()    {}
+func (*ValidatorGroup) Descriptor() ([]byte, []int) {
+	return fileDescriptor_bb17ef3f514bfe54, []int{22}
+}
+
+func (m *ValidatorGroup) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ValidatorGroup.Unmarshal(m, b)
+}
+func (m *ValidatorGroup) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ValidatorGroup.Marshal(b, m, deterministic)
+}
+func (m *ValidatorGroup) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ValidatorGroup.Merge(m, src)
+}
+func (m *ValidatorGroup) XXX_Size() int {
+	return xxx_messageInfo_ValidatorGroup.Size(m)
+}
+func (m *ValidatorGroup) XXX_DiscardUnknown() {
+	xxx_messageInfo_ValidatorGroup.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ValidatorGroup proto.InternalMessageInfo
+
+func (m *ValidatorGroup) GetValidators() []string {
+	if m != nil {
+		return m.Validators
+	}
+	return nil
+}
+
+func (m *ValidatorGroup) GetChildBlockHash() []byte {
+	if m != nil {
+		return m.ChildBlockHash
+	}
+	return nil
+}
+
+func (m *ValidatorGroup) GetCommitted() bool {
+	if m != nil {
+		return m.Committed
+	}
+	return false
+}
+
 type BaseHeader struct {
 	LastBlockID *BlockID `protobuf:"bytes,1,opt,name=lastBlockID,proto3" json:"lastBlockID,omitempty"`
 	// Base block ID having hash
@@ -1067,8 +1267,14 @@ type BaseHeader struct {
 	// Holds the global state trie created by encoded herdius accounts
 	StateRoot []byte `protobuf:"bytes,9,opt,name=state_root,json=stateRoot,proto3" json:"state_root,omitempty"`
 	// Merkle root hash of the transactions in SingularBlock
-	RootHash             []byte   `protobuf:"bytes,10,opt,name=rootHash,proto3" json:"rootHash,omitempty"`
-	TotalTxs             uint64   `protobuf:"varint,11,opt,name=total_txs,json=totalTxs,proto3" json:"total_txs,omitempty"`
+	RootHash []byte `protobuf:"bytes,10,opt,name=rootHash,proto3" json:"rootHash,omitempty"`
+	TotalTxs uint64 `protobuf:"varint,11,opt,name=total_txs,json=totalTxs,proto3" json:"total_txs,omitempty"`
+	// HER fees collected from the transactions in the block
+	Fees uint64 `protobuf:"varint,12,opt,name=fees,proto3" json:"fees,omitempty"`
+	// Simple Merkle root of the transaction receipts
+	ReceiptRoot []byte `protobuf:"bytes,13,opt,name=receipt_root,json=receiptRoot,proto3" json:"receipt_root,omitempty"`
+	// Simple Merkle root of the validator groups
+	ValidatorGroupsHash  []byte   `protobuf:"bytes,14,opt,name=validator_groups_hash,json=validatorGroupsHash,proto3" json:"validator_groups_hash,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -1078,7 +1284,7 @@ func (m *BaseHeader) Reset()         { *m = BaseHeader{} }
 func (m *BaseHeader) String() string { return proto.CompactTextString(m) }
 func (*BaseHeader) ProtoMessage()    {}
 func (*BaseHeader) Descriptor() ([]byte, []int) {
-	return fileDescriptor_bb17ef3f514bfe54, []int{20}
+	return fileDescriptor_bb17ef3f514bfe54, []int{23}
 }
 
 func (m *BaseHeader) XXX_Unmarshal(b []byte) error {
@@ -1176,6 +1382,27 @@ func (m *BaseHeader) GetTotalTxs() uint64 {
 	return 0
 }
 
+func (m *BaseHeader) GetFees() uint64 {
+	if m != nil {
+		return m.Fees
+	}
+	return 0
+}
+
+func (m *BaseHeader) GetReceiptRoot() []byte {
+	if m != nil {
+		return m.ReceiptRoot
+	}
+	return nil
+}
+
+func (m *BaseHeader) GetValidatorGroupsHash() []byte {
+	if m != nil {
+		return m.ValidatorGroupsHash
+	}
+	return nil
+}
+
 func init() {
 	proto.RegisterType((*ID)(nil), "protobuf.ID")
 	proto.RegisterType((*Header)(nil), "protobuf.Header")
@@ -1185,6 +1412,7 @@ func init() {
 	proto.RegisterType((*Validator)(nil), "protobuf.Validator")
 	proto.RegisterType((*VoteInfo)(nil), "protobuf.VoteInfo")
 	proto.RegisterType((*ChildBlockMessage)(nil), "protobuf.ChildBlockMessage")
+	proto.RegisterType((*AccountProof)(nil), "protobuf.AccountProof")
 	proto.RegisterType((*VoteCommit)(nil), "protobuf.VoteCommit")
 	proto.RegisterType((*Message)(nil), "protobuf.Message")
 	proto.RegisterType((*Ping)(nil), "protobuf.Ping")
@@ -1197,80 +1425,94 @@ func init() {
 	proto.RegisterType((*ClientResponse)(nil), "protobuf.ClientResponse")
 	proto.RegisterType((*Timestamp)(nil), "protobuf.Timestamp")
 	proto.RegisterType((*BaseBlock)(nil), "protobuf.BaseBlock")
+	proto.RegisterType((*AccountUpdate)(nil), "protobuf.AccountUpdate")
+	proto.RegisterType((*ValidatorGroup)(nil), "protobuf.ValidatorGroup")
 	proto.RegisterType((*BaseHeader)(nil), "protobuf.BaseHeader")
 }
 
 func init() { proto.RegisterFile("stream.proto", fileDescriptor_bb17ef3f514bfe54) }
 
 var fileDescriptor_bb17ef3f514bfe54 = []byte{
-	// 1091 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x8c, 0x56, 0xcd, 0x6e, 0xdb, 0x46,
-	0x10, 0x0e, 0x25, 0x59, 0x12, 0x47, 0xb4, 0x11, 0x6f, 0x83, 0x80, 0x4d, 0x62, 0xc5, 0x65, 0xd3,
-	0xc6, 0x6d, 0x13, 0x25, 0x70, 0x8a, 0xfe, 0xa0, 0x37, 0xc9, 0x68, 0x6d, 0xb4, 0x09, 0x8c, 0x85,
-	0x91, 0x2b, 0xb1, 0x22, 0xd7, 0x14, 0x61, 0x91, 0xcb, 0x70, 0x97, 0xae, 0x7c, 0xeb, 0x3b, 0xf4,
-	0xd0, 0x57, 0xe8, 0x63, 0xf4, 0x18, 0xa0, 0x97, 0x1e, 0x7b, 0x2a, 0x62, 0x9f, 0x7a, 0x6b, 0x1f,
-	0xa1, 0xd8, 0x1f, 0xfe, 0x48, 0xb5, 0x9d, 0x9c, 0xc4, 0x99, 0xf9, 0x76, 0x77, 0x7e, 0xbe, 0x99,
-	0x11, 0x38, 0x5c, 0xe4, 0x94, 0x24, 0xa3, 0x2c, 0x67, 0x82, 0xa1, 0xbe, 0xfa, 0x99, 0x16, 0xc7,
-	0x77, 0x1e, 0x47, 0xb1, 0x98, 0x15, 0xd3, 0x51, 0xc0, 0x92, 0x27, 0x11, 0x8b, 0xd8, 0x93, 0xd2,
-	0xa2, 0x24, 0x25, 0xa8, 0x2f, 0x7d, 0xd0, 0x7b, 0x0e, 0xad, 0x83, 0x3d, 0xb4, 0x05, 0x90, 0x15,
-	0xd3, 0x79, 0x1c, 0xf8, 0x27, 0xf4, 0xcc, 0xb5, 0xb6, 0xad, 0x1d, 0x07, 0xdb, 0x5a, 0xf3, 0x3d,
-	0x3d, 0x43, 0x2e, 0xf4, 0x48, 0x18, 0xe6, 0x94, 0x73, 0xb7, 0xb5, 0x6d, 0xed, 0xd8, 0xb8, 0x14,
-	0xd1, 0x06, 0xb4, 0xe2, 0xd0, 0x6d, 0xab, 0x03, 0xad, 0x38, 0xf4, 0x7e, 0x6f, 0x41, 0x77, 0x9f,
-	0x92, 0x90, 0xe6, 0xe8, 0x29, 0x38, 0xbc, 0xc8, 0x68, 0x7e, 0x1a, 0x73, 0x96, 0x1f, 0xec, 0xa9,
-	0x5b, 0x07, 0xbb, 0xce, 0xa8, 0xf4, 0x67, 0x74, 0xb0, 0x87, 0x97, 0x10, 0xe8, 0x19, 0x0c, 0xe6,
-	0x84, 0x8b, 0xf1, 0x9c, 0x05, 0x27, 0x07, 0x7b, 0xea, 0xa9, 0xc1, 0xee, 0x66, 0x7d, 0xc0, 0x18,
-	0x70, 0x13, 0x85, 0x6e, 0x43, 0x37, 0x2d, 0x92, 0xa3, 0x05, 0x57, 0x5e, 0xb4, 0xb1, 0x91, 0xd0,
-	0x1d, 0xe8, 0x0b, 0x26, 0xc8, 0x5c, 0x5a, 0x3a, 0xca, 0x52, 0xc9, 0xf2, 0xcc, 0x8c, 0xc6, 0xd1,
-	0x4c, 0xb8, 0x6b, 0xfa, 0x8c, 0x96, 0xd0, 0x43, 0xe8, 0x88, 0x38, 0xa1, 0x6e, 0x57, 0xbd, 0xfc,
-	0x5e, 0xfd, 0xf2, 0x51, 0x9c, 0x50, 0x2e, 0x48, 0x92, 0x61, 0x05, 0x40, 0xf7, 0xc0, 0xe6, 0x71,
-	0x94, 0x12, 0x51, 0xe4, 0xd4, 0xed, 0xe9, 0x74, 0x55, 0x0a, 0xf9, 0x74, 0xce, 0x98, 0xd8, 0x27,
-	0x7c, 0xe6, 0xf6, 0x95, 0xb1, 0x92, 0xd1, 0x67, 0xd0, 0x9b, 0x9a, 0xf8, 0xec, 0xab, 0xe2, 0x2b,
-	0x11, 0xde, 0x43, 0xe8, 0x95, 0x61, 0xde, 0x03, 0x5b, 0x69, 0xd5, 0xa5, 0xa6, 0x40, 0x95, 0xc2,
-	0xfb, 0xc5, 0x02, 0x98, 0xcc, 0xe2, 0x79, 0xa8, 0xe0, 0x68, 0x47, 0xc6, 0x27, 0x8b, 0x60, 0x92,
-	0x7e, 0xb3, 0x7e, 0x43, 0x17, 0x07, 0x1b, 0xbb, 0x74, 0x47, 0x2c, 0xf8, 0x1e, 0x11, 0xe4, 0xff,
-	0xe9, 0x3e, 0xd2, 0x06, 0x5c, 0x22, 0xd0, 0x2e, 0xd8, 0x32, 0xf3, 0x2f, 0x99, 0xa0, 0x3a, 0xdb,
-	0x83, 0xdd, 0x5b, 0x35, 0x5c, 0xaa, 0x27, 0x2c, 0x49, 0x62, 0x81, 0x6b, 0x98, 0xf7, 0x3e, 0xf4,
-	0xcc, 0x3d, 0x92, 0x2b, 0x62, 0xe1, 0x5a, 0xdb, 0x6d, 0xc9, 0x15, 0xb1, 0xf0, 0x66, 0x60, 0xbf,
-	0x24, 0xf3, 0x38, 0x24, 0x82, 0xe5, 0x4d, 0x8a, 0x59, 0xcb, 0x14, 0xdb, 0x82, 0x5e, 0x56, 0x4c,
-	0x15, 0x31, 0xa5, 0x8b, 0xce, 0xb8, 0xf3, 0xfa, 0xaf, 0xfb, 0x37, 0x70, 0x37, 0x2b, 0xa6, 0x92,
-	0x9b, 0x9e, 0xec, 0x04, 0x72, 0x12, 0xa7, 0x51, 0xc6, 0x7e, 0xa4, 0xb9, 0x61, 0xc1, 0x92, 0xce,
-	0xfb, 0xd9, 0x82, 0xbe, 0x74, 0xe7, 0x20, 0x3d, 0x66, 0xe8, 0x4b, 0xb0, 0x4f, 0xcb, 0x67, 0x4d,
-	0x7e, 0x1a, 0x95, 0xae, 0x3c, 0x32, 0xcf, 0xd4, 0x58, 0xf4, 0x14, 0x6e, 0xc9, 0x1a, 0xd3, 0xd0,
-	0x0f, 0x8a, 0x3c, 0xa7, 0xa9, 0xf0, 0x55, 0x01, 0x94, 0x57, 0x7d, 0x8c, 0xb4, 0x6d, 0xa2, 0x4d,
-	0xba, 0x0e, 0x4b, 0x34, 0x69, 0xaf, 0xd0, 0xc4, 0x7b, 0x05, 0x9b, 0x75, 0xcd, 0x9e, 0x53, 0xce,
-	0x49, 0x44, 0xd1, 0xc7, 0xd0, 0x39, 0x65, 0x82, 0x1a, 0xc7, 0xd0, 0x72, 0x7a, 0xa5, 0xff, 0x58,
-	0xd9, 0xd1, 0xe7, 0x00, 0x41, 0x75, 0xd8, 0xd4, 0xae, 0x51, 0x8c, 0xfa, 0x62, 0xdc, 0xc0, 0x79,
-	0x04, 0xa0, 0x2e, 0x53, 0x93, 0x8b, 0xd6, 0xdb, 0xb8, 0x58, 0x39, 0xd6, 0xda, 0x6e, 0x5f, 0xe7,
-	0x98, 0xf7, 0xb7, 0x05, 0xbd, 0x32, 0x18, 0x17, 0x7a, 0x89, 0xfe, 0x34, 0x94, 0x2d, 0x45, 0xf4,
-	0x00, 0xba, 0x9c, 0xa6, 0x92, 0xa1, 0xad, 0x4b, 0xc6, 0x82, 0xb1, 0x5d, 0x9f, 0x3f, 0xf4, 0x21,
-	0xac, 0xe7, 0xf4, 0x55, 0x41, 0xb9, 0xf0, 0x53, 0x96, 0x06, 0x54, 0xb5, 0x79, 0x07, 0x3b, 0x46,
-	0xf9, 0x42, 0xea, 0x24, 0xc8, 0xbc, 0x69, 0x40, 0x6b, 0x1a, 0x64, 0x94, 0x1a, 0xb4, 0x05, 0x90,
-	0xd3, 0x6c, 0x7e, 0xe6, 0x1f, 0xcf, 0x49, 0xa4, 0xba, 0xbf, 0x8f, 0x6d, 0xa5, 0xf9, 0x76, 0x4e,
-	0x22, 0x39, 0x2e, 0x58, 0x16, 0xb0, 0x50, 0xb7, 0xfa, 0x3a, 0x36, 0x92, 0xd7, 0x85, 0xce, 0x61,
-	0x9c, 0x46, 0xea, 0x97, 0xa5, 0x91, 0xf7, 0x35, 0x6c, 0xfe, 0xc0, 0xd8, 0x49, 0x91, 0xbd, 0x60,
-	0x21, 0xc5, 0xda, 0x0b, 0x19, 0xa9, 0x20, 0x79, 0x44, 0xc5, 0xa5, 0x03, 0xd0, 0xd8, 0xbc, 0xaf,
-	0x00, 0x35, 0x8f, 0xf2, 0x8c, 0xa5, 0x9c, 0x22, 0x0f, 0xd6, 0x32, 0x4a, 0x73, 0xae, 0x9a, 0x66,
-	0xf5, 0xa8, 0x36, 0x79, 0x77, 0x61, 0x6d, 0x7c, 0x26, 0x28, 0x47, 0x08, 0x3a, 0xa1, 0xec, 0x63,
-	0x9d, 0x69, 0xf5, 0xed, 0x3d, 0x86, 0xcd, 0x09, 0x4b, 0x53, 0x1a, 0x88, 0x98, 0xa5, 0x57, 0x54,
-	0xc5, 0xae, 0xaa, 0xe2, 0x7d, 0x02, 0xeb, 0x93, 0x79, 0x4c, 0x53, 0x51, 0x3a, 0x7f, 0x35, 0xf4,
-	0x53, 0xd8, 0x28, 0xa1, 0xc6, 0xd9, 0xab, 0xb1, 0xdf, 0x80, 0x5d, 0x0d, 0x50, 0x09, 0xe3, 0x34,
-	0x60, 0x69, 0xa8, 0x1b, 0xbd, 0x8d, 0x4b, 0x11, 0xdd, 0x82, 0xb5, 0x94, 0xa4, 0x4c, 0xef, 0x98,
-	0x36, 0xd6, 0x82, 0xf7, 0x8f, 0x05, 0xf6, 0x98, 0x70, 0xaa, 0x3b, 0xea, 0xd1, 0xca, 0x64, 0x6b,
-	0x50, 0x5e, 0x82, 0x56, 0xa6, 0xdb, 0x7d, 0x18, 0x28, 0xf2, 0x37, 0x1a, 0xd5, 0x69, 0xf6, 0x83,
-	0x24, 0x58, 0x3d, 0x0b, 0x0c, 0xc1, 0xea, 0x86, 0xff, 0x08, 0x36, 0x52, 0xba, 0x10, 0x7e, 0x0d,
-	0xe9, 0x28, 0xc8, 0xba, 0xd4, 0xd6, 0xa3, 0xeb, 0x03, 0x70, 0x24, 0xf3, 0xfd, 0x40, 0x75, 0x15,
-	0x57, 0x0c, 0x73, 0xf0, 0xe0, 0xb4, 0x6a, 0x34, 0xde, 0x1c, 0xb3, 0xdd, 0xb7, 0x8d, 0x59, 0xef,
-	0xb7, 0x36, 0x40, 0x1d, 0xcc, 0xea, 0x56, 0xb4, 0xde, 0x69, 0x2b, 0x3e, 0x82, 0xbe, 0x8a, 0xd9,
-	0xbf, 0x6e, 0x8f, 0x56, 0xbd, 0x5d, 0xef, 0xc3, 0xf6, 0xd2, 0x3e, 0x1c, 0x01, 0xaa, 0x62, 0xff,
-	0x2e, 0x67, 0x45, 0xa6, 0xb6, 0x8f, 0x4e, 0xc2, 0x25, 0x16, 0xf4, 0x05, 0xdc, 0x5e, 0x4a, 0x4d,
-	0x7d, 0x46, 0xe7, 0xe4, 0x0a, 0xeb, 0xbb, 0xef, 0xdd, 0x07, 0xb0, 0x21, 0xa3, 0xf4, 0x55, 0xbe,
-	0x67, 0xf2, 0x62, 0xbd, 0x7c, 0x9d, 0x72, 0xe1, 0xa8, 0xeb, 0x76, 0xe0, 0x66, 0xa3, 0xec, 0x1a,
-	0xa7, 0xf7, 0xf0, 0x46, 0x5d, 0x7b, 0x85, 0xdc, 0x02, 0xe0, 0x82, 0x08, 0xea, 0xcb, 0xfd, 0xac,
-	0x16, 0xb2, 0x9c, 0x30, 0x52, 0x83, 0x19, 0x13, 0x4b, 0x8b, 0x1c, 0x56, 0x16, 0xf9, 0x5d, 0xb0,
-	0xd5, 0xff, 0x09, 0x5f, 0x2c, 0xb8, 0x3b, 0x50, 0x43, 0xa5, 0xfa, 0x83, 0x31, 0x9e, 0xfc, 0x79,
-	0x3e, 0xbc, 0xf1, 0xe6, 0x7c, 0x68, 0xfd, 0x7b, 0x3e, 0xb4, 0x7e, 0xba, 0x18, 0x5a, 0xbf, 0x5e,
-	0x0c, 0xad, 0xd7, 0x17, 0x43, 0xeb, 0x8f, 0x8b, 0xa1, 0xf5, 0xe6, 0x62, 0x68, 0xc1, 0x66, 0xc0,
-	0x92, 0xd1, 0x8c, 0xe6, 0x61, 0x5c, 0x70, 0x1d, 0xef, 0xd8, 0xd9, 0xd7, 0xe2, 0xa1, 0x94, 0x0e,
-	0xad, 0x69, 0x57, 0xa9, 0x9f, 0xfd, 0x17, 0x00, 0x00, 0xff, 0xff, 0xef, 0x28, 0x48, 0x04, 0xea,
-	0x09, 0x00, 0x00,
+	// 1290 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x8c, 0x56, 0x4d, 0x93, 0xdb, 0x44,
+	0x13, 0x8e, 0x6c, 0xaf, 0x6d, 0xb5, 0x65, 0x27, 0x3b, 0xc9, 0x9b, 0x57, 0x24, 0x59, 0x67, 0x11,
+	0x81, 0x2c, 0x90, 0x6c, 0x52, 0x0e, 0xc5, 0x47, 0x41, 0x51, 0x60, 0x6f, 0x41, 0xb6, 0x20, 0xa9,
+	0xad, 0xa9, 0x90, 0xab, 0x4a, 0x96, 0x66, 0x65, 0xd5, 0xda, 0x1a, 0xa1, 0x19, 0x2d, 0xde, 0x1b,
+	0xbf, 0x01, 0x0e, 0xfc, 0x05, 0xaa, 0x38, 0xf0, 0x37, 0x72, 0xe4, 0xc8, 0x89, 0x4a, 0xf6, 0x04,
+	0x37, 0x7e, 0x02, 0x35, 0x1f, 0xfa, 0x72, 0xbc, 0x9b, 0x9c, 0xec, 0xee, 0x7e, 0x46, 0xdd, 0x9a,
+	0xe7, 0xe9, 0x56, 0x83, 0xc5, 0x78, 0x4a, 0xbc, 0xc5, 0x6e, 0x92, 0x52, 0x4e, 0x51, 0x57, 0xfe,
+	0x4c, 0xb3, 0xc3, 0x6b, 0x77, 0xc3, 0x88, 0xcf, 0xb2, 0xe9, 0xae, 0x4f, 0x17, 0xf7, 0x42, 0x1a,
+	0xd2, 0x7b, 0x79, 0x44, 0x5a, 0xd2, 0x90, 0xff, 0xd4, 0x41, 0xe7, 0x11, 0x34, 0xf6, 0xf7, 0xd0,
+	0x16, 0x40, 0x92, 0x4d, 0xe7, 0x91, 0xef, 0x1e, 0x91, 0x13, 0xdb, 0xd8, 0x36, 0x76, 0x2c, 0x6c,
+	0x2a, 0xcf, 0x37, 0xe4, 0x04, 0xd9, 0xd0, 0xf1, 0x82, 0x20, 0x25, 0x8c, 0xd9, 0x8d, 0x6d, 0x63,
+	0xc7, 0xc4, 0xb9, 0x89, 0x06, 0xd0, 0x88, 0x02, 0xbb, 0x29, 0x0f, 0x34, 0xa2, 0xc0, 0xf9, 0xa7,
+	0x01, 0xed, 0x87, 0xc4, 0x0b, 0x48, 0x8a, 0xee, 0x83, 0xc5, 0xb2, 0x84, 0xa4, 0xc7, 0x11, 0xa3,
+	0xe9, 0xfe, 0x9e, 0x7c, 0x6a, 0x6f, 0x64, 0xed, 0xe6, 0xf5, 0xec, 0xee, 0xef, 0xe1, 0x1a, 0x02,
+	0x3d, 0x80, 0xde, 0xdc, 0x63, 0x7c, 0x3c, 0xa7, 0xfe, 0xd1, 0xfe, 0x9e, 0x4c, 0xd5, 0x1b, 0x6d,
+	0x96, 0x07, 0x74, 0x00, 0x57, 0x51, 0xe8, 0x2a, 0xb4, 0xe3, 0x6c, 0xf1, 0x64, 0xc9, 0x64, 0x15,
+	0x4d, 0xac, 0x2d, 0x74, 0x0d, 0xba, 0x9c, 0x72, 0x6f, 0x2e, 0x22, 0x2d, 0x19, 0x29, 0x6c, 0x71,
+	0x66, 0x46, 0xa2, 0x70, 0xc6, 0xed, 0x0d, 0x75, 0x46, 0x59, 0xe8, 0x36, 0xb4, 0x78, 0xb4, 0x20,
+	0x76, 0x5b, 0x66, 0xbe, 0x5c, 0x66, 0x7e, 0x12, 0x2d, 0x08, 0xe3, 0xde, 0x22, 0xc1, 0x12, 0x80,
+	0x6e, 0x80, 0xc9, 0xa2, 0x30, 0xf6, 0x78, 0x96, 0x12, 0xbb, 0xa3, 0xae, 0xab, 0x70, 0x88, 0xd4,
+	0x29, 0xa5, 0xfc, 0xa1, 0xc7, 0x66, 0x76, 0x57, 0x06, 0x0b, 0x1b, 0xbd, 0x0f, 0x9d, 0xa9, 0x7e,
+	0x3f, 0xf3, 0xac, 0xf7, 0xcb, 0x11, 0x32, 0x0d, 0xf7, 0x38, 0xc1, 0x94, 0x72, 0x1b, 0x74, 0x9a,
+	0xdc, 0xe1, 0xdc, 0x86, 0xce, 0xb8, 0x04, 0xca, 0x33, 0x32, 0xa5, 0xa6, 0xaf, 0x70, 0x38, 0xbf,
+	0x18, 0x00, 0x93, 0x59, 0x34, 0x0f, 0x24, 0x1c, 0xed, 0x88, 0xb7, 0x17, 0x14, 0x69, 0x4a, 0x2e,
+	0x95, 0x15, 0x28, 0xea, 0xb0, 0x8e, 0x8b, 0x62, 0xf9, 0x92, 0xed, 0x79, 0xdc, 0x7b, 0x99, 0x8c,
+	0x27, 0x2a, 0x80, 0x73, 0x04, 0x1a, 0x81, 0x29, 0x78, 0x79, 0x4a, 0x39, 0x51, 0x5c, 0xf4, 0x46,
+	0x57, 0x4a, 0xb8, 0x70, 0x4f, 0xe8, 0x62, 0x11, 0x71, 0x5c, 0xc2, 0x9c, 0x37, 0xa0, 0xa3, 0x9f,
+	0x23, 0x94, 0xc4, 0x97, 0xb6, 0xb1, 0xdd, 0x14, 0x4a, 0xe2, 0x4b, 0x67, 0x06, 0xe6, 0x53, 0x6f,
+	0x1e, 0x05, 0x1e, 0xa7, 0x69, 0x55, 0x80, 0x46, 0x5d, 0x80, 0x5b, 0xd0, 0x49, 0xb2, 0xa9, 0x94,
+	0xad, 0x28, 0xd1, 0x1a, 0xb7, 0x9e, 0xfd, 0x75, 0xf3, 0x02, 0x6e, 0x27, 0xd9, 0x54, 0x28, 0xd7,
+	0x11, 0x7d, 0xe2, 0x1d, 0x45, 0x71, 0x98, 0xd0, 0x1f, 0x48, 0xaa, 0x35, 0x52, 0xf3, 0x39, 0x3f,
+	0x1b, 0xd0, 0x15, 0xe5, 0xec, 0xc7, 0x87, 0x14, 0x7d, 0x04, 0xe6, 0x71, 0x9e, 0xd6, 0x36, 0x56,
+	0x75, 0x50, 0x54, 0xa4, 0xd3, 0x94, 0x58, 0x74, 0x1f, 0xae, 0x08, 0x05, 0x90, 0xc0, 0xf5, 0xb3,
+	0x34, 0x25, 0x31, 0x77, 0x25, 0x01, 0xb2, 0xaa, 0x2e, 0x46, 0x2a, 0x36, 0x51, 0x21, 0xc5, 0x43,
+	0x4d, 0x44, 0xcd, 0x15, 0x11, 0x39, 0xbf, 0x1b, 0xb0, 0x59, 0x92, 0xf6, 0x88, 0x30, 0xe6, 0x85,
+	0x04, 0xbd, 0x03, 0xad, 0x63, 0xca, 0x89, 0xae, 0x0c, 0xd5, 0xef, 0x57, 0xbc, 0x00, 0x96, 0x71,
+	0xf4, 0x01, 0x80, 0x5f, 0x1c, 0xb6, 0x1b, 0xab, 0x6c, 0x94, 0x0f, 0xc6, 0x15, 0x1c, 0xfa, 0x0c,
+	0xfa, 0x9e, 0xef, 0xd3, 0x2c, 0xe6, 0x07, 0x29, 0xa5, 0x87, 0x82, 0xc6, 0xe6, 0x4e, 0x6f, 0x74,
+	0xb5, 0x3c, 0xf8, 0x65, 0x25, 0x8c, 0xeb, 0x60, 0xe7, 0x73, 0xb0, 0xaa, 0xe1, 0x73, 0x48, 0xbb,
+	0x02, 0x1b, 0x89, 0x80, 0xd8, 0x0d, 0x49, 0xb7, 0x32, 0x1c, 0x0f, 0xa0, 0x54, 0x49, 0xb5, 0x51,
+	0x8c, 0x57, 0x36, 0x4a, 0x7e, 0x2d, 0x8d, 0xed, 0xe6, 0x79, 0xd7, 0xe2, 0xfc, 0x6d, 0x40, 0x27,
+	0xbf, 0x4a, 0x1b, 0x3a, 0x0b, 0xf5, 0x57, 0x77, 0x4c, 0x6e, 0xa2, 0x5b, 0xd0, 0x66, 0x24, 0x16,
+	0x0d, 0xd2, 0x58, 0x33, 0xb3, 0x74, 0xec, 0x7c, 0xfa, 0xd0, 0x5b, 0xd0, 0x4f, 0xc9, 0xf7, 0x19,
+	0x61, 0xdc, 0x8d, 0x69, 0xec, 0x13, 0x39, 0x83, 0x5a, 0xd8, 0xd2, 0xce, 0xc7, 0xc2, 0x27, 0x40,
+	0x3a, 0xa7, 0x06, 0x6d, 0x28, 0x90, 0x76, 0x2a, 0xd0, 0x16, 0x40, 0x4a, 0x92, 0xf9, 0x89, 0x7b,
+	0x38, 0xf7, 0x42, 0x39, 0x9a, 0xba, 0xd8, 0x94, 0x9e, 0xaf, 0xe6, 0x5e, 0x28, 0x66, 0x19, 0x4d,
+	0x7c, 0x1a, 0xa8, 0x39, 0xd4, 0xc7, 0xda, 0x72, 0xda, 0xd0, 0x3a, 0x88, 0xe2, 0x50, 0xfe, 0xd2,
+	0x38, 0x74, 0x3e, 0x81, 0xcd, 0x6f, 0x29, 0x3d, 0xca, 0x92, 0xc7, 0x34, 0x20, 0x58, 0x55, 0x21,
+	0xde, 0x94, 0x7b, 0x69, 0x48, 0xf8, 0xda, 0xe9, 0xac, 0x63, 0xce, 0xc7, 0x80, 0xaa, 0x47, 0x59,
+	0x42, 0x63, 0x46, 0x90, 0x03, 0x1b, 0x09, 0x21, 0x29, 0x93, 0x3d, 0xbb, 0x7a, 0x54, 0x85, 0x9c,
+	0xeb, 0xb0, 0x31, 0x3e, 0xe1, 0x84, 0x21, 0x04, 0xad, 0x40, 0x8c, 0x11, 0x75, 0xd3, 0xf2, 0xbf,
+	0x73, 0x17, 0x36, 0x27, 0x34, 0x8e, 0x89, 0xcf, 0x23, 0x1a, 0x9f, 0xc1, 0x8a, 0x59, 0xb0, 0xe2,
+	0xbc, 0x0b, 0xfd, 0xc9, 0x3c, 0x22, 0x31, 0xcf, 0x8b, 0x3f, 0x1b, 0xfa, 0x1e, 0x0c, 0x72, 0xa8,
+	0x2e, 0xf6, 0x6c, 0xec, 0xa7, 0x60, 0x16, 0xd3, 0x5d, 0xc0, 0x18, 0xf1, 0x69, 0x1c, 0x28, 0xc9,
+	0x36, 0x71, 0x6e, 0x0a, 0xc9, 0xc6, 0x5e, 0x4c, 0xd5, 0x07, 0xb0, 0x89, 0x95, 0xe1, 0xfc, 0xd4,
+	0x04, 0x73, 0xec, 0x31, 0xa2, 0xda, 0xe7, 0xce, 0xca, 0x60, 0xad, 0x34, 0x9c, 0x00, 0xad, 0x0c,
+	0xd7, 0x9b, 0xd0, 0x93, 0xad, 0x57, 0x99, 0x13, 0x56, 0xad, 0x1b, 0x6f, 0x54, 0x47, 0x91, 0x16,
+	0x58, 0xe1, 0x40, 0x6f, 0xc3, 0x20, 0x26, 0x4b, 0xee, 0x96, 0x90, 0x96, 0x84, 0xf4, 0x85, 0xb7,
+	0x9c, 0x9c, 0x6f, 0x82, 0x25, 0x94, 0xef, 0xfa, 0xb2, 0xab, 0x98, 0x54, 0x98, 0x85, 0x7b, 0xc7,
+	0x45, 0xa3, 0xb1, 0xea, 0x94, 0x6f, 0xbf, 0x72, 0xca, 0x8b, 0x6f, 0x1b, 0xf1, 0x49, 0x94, 0x70,
+	0x66, 0x77, 0x64, 0xf7, 0x16, 0x36, 0x9a, 0xc0, 0xa5, 0xa2, 0x1a, 0x37, 0x4c, 0x69, 0x96, 0x30,
+	0xbb, 0x2b, 0xc5, 0x61, 0xaf, 0x19, 0xa1, 0x5f, 0x0b, 0x00, 0xbe, 0x78, 0x5c, 0xb3, 0x19, 0xfa,
+	0x02, 0x2e, 0xea, 0xb1, 0xe2, 0x66, 0x49, 0xe0, 0x89, 0x8f, 0x89, 0x29, 0x9f, 0xf1, 0xff, 0x97,
+	0xa6, 0xd0, 0x77, 0x32, 0x8e, 0x07, 0x5e, 0xd5, 0x64, 0xce, 0x04, 0xfa, 0x35, 0xc0, 0x39, 0x83,
+	0x48, 0x44, 0x14, 0x54, 0xdf, 0x7f, 0x6e, 0x3a, 0x4b, 0x18, 0xd4, 0x2b, 0x45, 0x43, 0x80, 0xa2,
+	0x56, 0x25, 0x7a, 0x13, 0x57, 0x3c, 0x68, 0x07, 0x2e, 0x55, 0xf8, 0x74, 0x67, 0xe2, 0x53, 0xac,
+	0x1e, 0x3a, 0x28, 0x49, 0x95, 0x3b, 0xc0, 0x0d, 0x30, 0x15, 0x1d, 0x9c, 0xa8, 0xdd, 0xa9, 0x8b,
+	0x4b, 0x87, 0xf3, 0x5b, 0x0b, 0xa0, 0x94, 0xcb, 0xea, 0x52, 0x64, 0xbc, 0xd6, 0x52, 0x74, 0x07,
+	0xba, 0xaa, 0x8a, 0xf3, 0xd6, 0xa8, 0x62, 0x7a, 0x96, 0xeb, 0x50, 0xb3, 0xb6, 0x0e, 0xed, 0x02,
+	0xaa, 0xb3, 0x23, 0xd7, 0x0b, 0x25, 0xb3, 0x35, 0x11, 0xf4, 0x21, 0x5c, 0xad, 0x89, 0xaf, 0x3c,
+	0xa3, 0x54, 0x77, 0x46, 0xf4, 0xf5, 0xd7, 0xae, 0x5b, 0x30, 0x10, 0x6f, 0xe9, 0x4a, 0x45, 0xcb,
+	0x0b, 0x56, 0xbb, 0x97, 0x95, 0x6f, 0x14, 0xf2, 0x71, 0xeb, 0x88, 0xe8, 0xae, 0x25, 0x62, 0x0b,
+	0x40, 0xae, 0x53, 0x6e, 0x4a, 0x29, 0xb7, 0x4d, 0x3d, 0xc3, 0xf3, 0x05, 0xab, 0xb6, 0xc7, 0xc1,
+	0xca, 0x1e, 0x77, 0x1d, 0x4c, 0xb9, 0x4e, 0xba, 0x7c, 0xc9, 0xec, 0x9e, 0x1c, 0xdb, 0xe5, 0x7e,
+	0x89, 0xa0, 0x75, 0x48, 0x08, 0xb3, 0x2d, 0xe9, 0x97, 0xff, 0x45, 0x23, 0xea, 0x46, 0x51, 0xd9,
+	0xfa, 0xaa, 0x11, 0xb5, 0x4f, 0xe6, 0x1b, 0xc1, 0xff, 0x56, 0xfb, 0x47, 0x55, 0x3f, 0x90, 0xd8,
+	0xcb, 0x2b, 0xad, 0x22, 0xea, 0x18, 0x4f, 0xfe, 0x7c, 0x31, 0xbc, 0xf0, 0xfc, 0xc5, 0xd0, 0xf8,
+	0xf7, 0xc5, 0xd0, 0xf8, 0xf1, 0x74, 0x68, 0xfc, 0x7a, 0x3a, 0x34, 0x9e, 0x9d, 0x0e, 0x8d, 0x3f,
+	0x4e, 0x87, 0xc6, 0xf3, 0xd3, 0xa1, 0x01, 0x9b, 0x3e, 0x5d, 0xec, 0xce, 0x48, 0x1a, 0x44, 0x19,
+	0x53, 0x57, 0x3b, 0xb6, 0x1e, 0x2a, 0xf3, 0x40, 0x58, 0x07, 0xc6, 0xb4, 0x2d, 0xdd, 0x0f, 0xfe,
+	0x1b, 0x00, 0xbe, 0xfb, 0x73, 0xea, 0x54, 0x0c, 0x00, 0x00,
 }
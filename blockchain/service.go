@@ -2,25 +2,23 @@ package blockchain
 
 import (
 	"encoding/base64"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
 	"strconv"
-	"strings"
 	"time"
 
 	"github.com/dgraph-io/badger"
 	"github.com/spf13/viper"
 
 	"github.com/herdius/herdius-core/blockchain/protobuf"
-	"github.com/herdius/herdius-core/crypto/herhash"
 	pluginproto "github.com/herdius/herdius-core/hbi/protobuf"
 	cmn "github.com/herdius/herdius-core/libs/common"
 	"github.com/herdius/herdius-core/p2p/key"
 	"github.com/herdius/herdius-core/p2p/log"
 	"github.com/herdius/herdius-core/storage/db"
 	"github.com/herdius/herdius-core/storage/state/statedb"
-	"github.com/herdius/herdius-core/supervisor/transaction"
 )
 
 // ServiceI is blockchain service interface
@@ -94,11 +92,10 @@ func (s *Service) CreateOrLoadGenesisBlock() (*protobuf.BaseBlock, error) {
 		StateRoot: root,
 	}
 
-	blockIDBz, err := cdc.MarshalJSON(blockID)
+	blockhash, err := GenesisBlockHash()
 	if err != nil {
-		return nil, fmt.Errorf("failed to Marshal Block ID: %v", err)
+		return nil, err
 	}
-	blockhash := herhash.Sum(blockIDBz)
 	header.Block_ID.BlockHash = blockhash
 
 	genesisBlock = &protobuf.BaseBlock{
@@ -111,7 +108,7 @@ func (s *Service) CreateOrLoadGenesisBlock() (*protobuf.BaseBlock, error) {
 		return nil, fmt.Errorf("failed to Marshal Base Block: %v", err)
 	}
 	badgerDB.Set(blockhash, gbbz)
-	badgerDB.Set([]byte("LastBlock"), gbbz)
+	badgerDB.Set(lastBlockKey, gbbz)
 
 	return genesisBlock, nil
 }
@@ -181,6 +178,9 @@ func (s *Service) GetBlockByHeight(height int64) (*protobuf.BaseBlock, error) {
 		defer it.Close()
 		for it.Rewind(); it.Valid(); it.Next() {
 			item := it.Item()
+			if IsIndexKey(item.Key()) {
+				continue
+			}
 			v, err := item.Value()
 			if err != nil {
 				return err
@@ -212,7 +212,8 @@ func (s *Service) GetBlockByHeight(height int64) (*protobuf.BaseBlock, error) {
 	return lastBlock, nil
 }
 
-// AddBaseBlock adds base block to blockchain db
+// AddBaseBlock adds base block to blockchain db. The block and its tx
+// indexes are written in a single transaction.
 func (s *Service) AddBaseBlock(bb *protobuf.BaseBlock) error {
 	blockhash := bb.GetHeader().GetBlock_ID().GetBlockHash()
 	bbbz, err := cdc.MarshalJSON(bb)
@@ -220,15 +221,25 @@ func (s *Service) AddBaseBlock(bb *protobuf.BaseBlock) error {
 		return fmt.Errorf(fmt.Sprintf("Failed to Marshal Block ID: %v.", err))
 	}
 
-	badgerDB.Set(blockhash, bbbz)
-	badgerDB.Set([]byte("LastBlock"), bbbz)
+	err = badgerDB.GetBadgerDB().Update(func(txn *badger.Txn) error {
+		if err := txn.Set(blockhash, bbbz); err != nil {
+			return err
+		}
+		if err := txn.Set(lastBlockKey, bbbz); err != nil {
+			return err
+		}
+		return indexBlock(txn, bb)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to store base block: %v", err)
+	}
 	blockHeightHashDB.Set([]byte(strconv.FormatInt(bb.Header.GetHeight(), 10)), blockhash)
 	return nil
 }
 
 // GetLastBlock ...
 func (s *Service) GetLastBlock() *protobuf.BaseBlock {
-	bbbz := badgerDB.Get([]byte("LastBlock"))
+	bbbz := badgerDB.Get(lastBlockKey)
 	bb := &protobuf.BaseBlock{}
 	if len(bbbz) == 0 {
 		bb, err := s.CreateOrLoadGenesisBlock()
@@ -245,40 +256,17 @@ func (s *Service) GetLastBlock() *protobuf.BaseBlock {
 	return bb
 }
 
-// GetTx searches a transaction against a tx id in blockchain
-// TODO: This will be completed in another PR since account
-// since account registeration needs to be completed and
-// I would like to complete the implementation once the
-// account registeration works.
+// GetTx searches a transaction against a tx id in blockchain and returns
+// the tx as it is stored in its block
 func (s *Service) GetTx(txID string) ([]byte, error) {
-	err := badgerDB.GetBadgerDB().View(func(txn *badger.Txn) error {
-		opts := badger.DefaultIteratorOptions
-		opts.PrefetchSize = 10
-		it := txn.NewIterator(opts)
-		defer it.Close()
-		for it.Rewind(); it.Valid(); it.Next() {
-			item := it.Item()
-			v, err := item.Value()
-			if err != nil {
-				return err
-			}
-			lb := &protobuf.BaseBlock{}
-			err = cdc.UnmarshalJSON(v, lb)
-			if err != nil {
-				return nil
-			}
-
-			if lb.GetChildBlock() != nil && len(lb.GetChildBlock()) > 0 {
-				// Check transactions in child blocks
-			}
-
-		}
-		return nil
-	})
+	_, btx, err := lookupTx(txID)
 	if err != nil {
-		return []byte{0}, fmt.Errorf(fmt.Sprintf("Failed to find the tx: %v.", err))
+		return nil, fmt.Errorf("failed to find the tx: %v", err)
+	}
+	if btx == nil {
+		return nil, fmt.Errorf("tx %s not found", txID)
 	}
-	return []byte{0}, nil
+	return btx.raw, nil
 }
 
 // TxServiceI is transaction service interface over blockchain
@@ -292,452 +280,86 @@ type TxServiceI interface {
 // TxService ...
 type TxService struct{}
 
+// ErrBlockNotFound is returned when no block is stored at the requested height
+var ErrBlockNotFound = errors.New("block not found")
+
 var (
 	_ TxServiceI = (*TxService)(nil)
 )
 
 // GetTx ...
 func (t *TxService) GetTx(id string) (*pluginproto.TxDetailResponse, error) {
-	txDetailRes := &pluginproto.TxDetailResponse{}
-	err := badgerDB.GetBadgerDB().View(func(txn *badger.Txn) error {
-		opts := badger.DefaultIteratorOptions
-		opts.PrefetchSize = 10
-		it := txn.NewIterator(opts)
-		defer it.Close()
-		for it.Rewind(); it.Valid(); it.Next() {
-			item := it.Item()
-			v, err := item.Value()
-			if err != nil {
-				return err
-			}
-
-			var baseBlock protobuf.BaseBlock
-			err = cdc.UnmarshalJSON(v, &baseBlock)
-			if err != nil {
-				return nil
-			}
-
-			// Check if base block has an transaction in it
-			if baseBlock.GetTxsData() != nil &&
-				len(baseBlock.GetTxsData().GetTx()) > 0 {
-
-				// Get all the transaction from the base block
-				txs := baseBlock.GetTxsData().GetTx()
-				for _, txbz := range txs {
-					var tx pluginproto.Tx
-					err := cdc.UnmarshalJSON(txbz, &tx)
-					if err != nil {
-						log.Printf("Failed to Unmarshal tx: %v", err)
-						continue
-					}
-					txID := getTxIDWithoutStatus(&tx)
-					if txID == id {
-						txDetailRes.Tx = &tx
-						txDetailRes.BlockId = uint64(baseBlock.GetHeader().GetHeight())
-
-						ts := &pluginproto.Timestamp{
-							Seconds: baseBlock.GetHeader().Time.Seconds,
-							Nanos:   baseBlock.GetHeader().Time.Nanos,
-						}
-						txDetailRes.CreationDt = ts
-						txDetailRes.TxId = txID
-						break
-					}
-				}
-			} else if len(baseBlock.GetChildBlock()) > 0 {
-				var cbs []*protobuf.ChildBlock
-				err := cdc.UnmarshalJSON(baseBlock.GetChildBlock(), &cbs)
-				if err != nil {
-					log.Printf("Failed to Unmarshal child block array: %v", err)
-					continue
-				}
-				for _, cb := range cbs {
-					// Get all the transaction from the child block
-					txs := cb.GetTxsData().GetTx()
-					for _, txbz := range txs {
-						var txT transaction.Tx
-						err := cdc.UnmarshalJSON(txbz, &txT)
-						if err != nil {
-							log.Printf("Failed to Unmarshal tx: %v", err)
-							continue
-						}
-						tx, err := transactiontoProto(txT)
-						if err != nil {
-							log.Printf("Error converting Transation to Proto tx: %v", err)
-							continue
-						}
-						txID := getTxIDWithoutStatus(&tx)
-						if txID == id {
-							txDetailRes.Tx = &tx
-							txDetailRes.BlockId = uint64(baseBlock.GetHeader().GetHeight())
-
-							ts := &pluginproto.Timestamp{
-								Seconds: baseBlock.GetHeader().Time.Seconds,
-								Nanos:   baseBlock.GetHeader().Time.Nanos,
-							}
-							txDetailRes.CreationDt = ts
-							txDetailRes.TxId = txID
-							break
-						}
-					}
-				}
-			}
-		}
-		return nil
-	})
+	block, btx, err := lookupTx(id)
 	if err != nil {
-		log.Error().Msgf("Failed to get blocks due to: %v", err.Error())
-		return nil, fmt.Errorf("failed to get blocks due to: %v", err.Error())
+		log.Error().Msgf("Failed to get tx due to: %v", err.Error())
+		return nil, fmt.Errorf("failed to get tx due to: %v", err.Error())
+	}
+	if btx == nil {
+		return &pluginproto.TxDetailResponse{}, nil
 	}
-	return txDetailRes, nil
+	return newTxDetail(block, btx.tx), nil
 }
 
 // GetTxs : Get all the txs by account address
 func (t *TxService) GetTxs(address string) (*pluginproto.TxsResponse, error) {
-
-	txDetails := make([]*pluginproto.TxDetailResponse, 0)
-
-	err := badgerDB.GetBadgerDB().View(func(txn *badger.Txn) error {
-		opts := badger.DefaultIteratorOptions
-		opts.PrefetchSize = 10
-		it := txn.NewIterator(opts)
-		defer it.Close()
-		var duplicateTxTracker map[string]uint8
-		duplicateTxTracker = make(map[string]uint8)
-		for it.Rewind(); it.Valid(); it.Next() {
-			item := it.Item()
-			v, err := item.Value()
-			if err != nil {
-				return err
-			}
-
-			var baseBlock protobuf.BaseBlock
-			err = cdc.UnmarshalJSON(v, &baseBlock)
-			if err != nil {
-				return nil
-			}
-
-			// Check if base block has an transaction in it
-			if baseBlock.GetTxsData() != nil &&
-				len(baseBlock.GetTxsData().GetTx()) > 0 {
-
-				// Get all the transaction from the base block
-				txs := baseBlock.GetTxsData().GetTx()
-
-				for _, txbz := range txs {
-					var tx pluginproto.Tx
-					err := cdc.UnmarshalJSON(txbz, &tx)
-					if err != nil {
-						log.Printf("Failed to Unmarshal tx: %v", err)
-						continue
-					}
-					if strings.EqualFold(address, tx.SenderAddress) ||
-						strings.EqualFold(address, tx.RecieverAddress) {
-						txDetailRes := &pluginproto.TxDetailResponse{}
-						txDetailRes.Tx = &tx
-						txDetailRes.BlockId = uint64(baseBlock.GetHeader().GetHeight())
-						ts := &pluginproto.Timestamp{
-							Seconds: baseBlock.GetHeader().Time.Seconds,
-							Nanos:   baseBlock.GetHeader().Time.Nanos,
-						}
-						txDetailRes.CreationDt = ts
-						txID := getTxIDWithoutStatus(&tx)
-						txDetailRes.TxId = txID
-
-						txDetails = append(txDetails, txDetailRes)
-					}
-				}
-			} else if len(baseBlock.GetChildBlock()) > 0 {
-				var cbs []*protobuf.ChildBlock
-				err := cdc.UnmarshalJSON(baseBlock.GetChildBlock(), &cbs)
-				if err != nil {
-					log.Printf("Failed to Unmarshal child block array: %v", err)
-					continue
-				}
-				for _, cb := range cbs {
-					// Get all the transaction from the child block
-					txs := cb.GetTxsData().GetTx()
-					for _, txbz := range txs {
-						var txT transaction.Tx
-						err := cdc.UnmarshalJSON(txbz, &txT)
-						if err != nil {
-							log.Printf("Failed to Unmarshal tx: %v", err)
-							continue
-						}
-						tx, err := transactiontoProto(txT)
-						if err != nil {
-							log.Printf("Error converting Transation to Proto tx: %v", err)
-							continue
-						}
-						if strings.EqualFold(address, tx.SenderAddress) ||
-							strings.EqualFold(address, tx.RecieverAddress) {
-							txDetailRes := &pluginproto.TxDetailResponse{}
-							txDetailRes.Tx = &tx
-							txDetailRes.BlockId = uint64(baseBlock.GetHeader().GetHeight())
-							ts := &pluginproto.Timestamp{
-								Seconds: baseBlock.GetHeader().Time.Seconds,
-								Nanos:   baseBlock.GetHeader().Time.Nanos,
-							}
-							txDetailRes.CreationDt = ts
-							txID := getTxIDWithoutStatus(&tx)
-							if duplicateTxTracker[txID] == 1 {
-								continue
-							}
-							duplicateTxTracker[txID] = 1
-							txDetailRes.TxId = txID
-
-							txDetails = append(txDetails, txDetailRes)
-						}
-					}
-				}
-			}
-		}
-		return nil
-	})
+	txDetails, err := lookupTxs(addressPrefix(address), false)
 	if err != nil {
-		log.Error().Msgf("Failed to get blocks due to: %v", err.Error())
-		return nil, fmt.Errorf("failed to get blocks due to: %v", err.Error())
-	}
-	txs := &pluginproto.TxsResponse{
-		Txs: txDetails,
+		log.Error().Msgf("Failed to get txs due to: %v", err.Error())
+		return nil, fmt.Errorf("failed to get txs due to: %v", err.Error())
 	}
-	return txs, nil
+	return &pluginproto.TxsResponse{Txs: txDetails}, nil
 }
 
 // GetTxsByAssetAndAddress : Get all the txs by account address and asset name
 func (t *TxService) GetTxsByAssetAndAddress(assetName, address string) (*pluginproto.TxsResponse, error) {
-	txDetails := make([]*pluginproto.TxDetailResponse, 0)
-
-	err := badgerDB.GetBadgerDB().View(func(txn *badger.Txn) error {
-		opts := badger.DefaultIteratorOptions
-		opts.PrefetchSize = 10
-		it := txn.NewIterator(opts)
-		defer it.Close()
-
-		var duplicateTxTracker map[string]uint8
-		duplicateTxTracker = make(map[string]uint8)
-		for it.Rewind(); it.Valid(); it.Next() {
-			item := it.Item()
-			v, err := item.Value()
-			if err != nil {
-				return err
-			}
-
-			var baseBlock protobuf.BaseBlock
-			err = cdc.UnmarshalJSON(v, &baseBlock)
-			if err != nil {
-				return nil
-			}
-
-			// Check if base block has an transaction in it
-			if baseBlock.GetTxsData() != nil &&
-				len(baseBlock.GetTxsData().GetTx()) > 0 {
-
-				// Get all the transaction from the base block
-				txs := baseBlock.GetTxsData().GetTx()
-
-				for _, txbz := range txs {
-					var tx pluginproto.Tx
-					err := cdc.UnmarshalJSON(txbz, &tx)
-					if err != nil {
-						log.Printf("Failed to Unmarshal tx: %v", err)
-						continue
-					}
-					if strings.EqualFold(assetName, tx.Asset.Symbol) {
-						if strings.EqualFold(address, tx.SenderAddress) ||
-							strings.EqualFold(address, tx.RecieverAddress) {
-							txDetailRes := &pluginproto.TxDetailResponse{}
-							txDetailRes.Tx = &tx
-							txDetailRes.BlockId = uint64(baseBlock.GetHeader().GetHeight())
-							ts := &pluginproto.Timestamp{
-								Seconds: baseBlock.GetHeader().Time.Seconds,
-								Nanos:   baseBlock.GetHeader().Time.Nanos,
-							}
-							txDetailRes.CreationDt = ts
-							txID := getTxIDWithoutStatus(&tx)
-							//Remove duplicate transactions from the array
-							//TODO: needs to be fixed
-							if duplicateTxTracker[txID] == 1 {
-								continue
-							}
-							duplicateTxTracker[txID] = 1
-							txDetailRes.TxId = txID
-
-							txDetails = append(txDetails, txDetailRes)
-						}
-					}
+	txDetails, err := lookupTxs(assetPrefix(assetName, address), true)
+	if err != nil {
+		log.Error().Msgf("Failed to get txs due to: %v", err.Error())
+		return nil, fmt.Errorf("failed to get txs due to: %v", err.Error())
+	}
+	return &pluginproto.TxsResponse{Txs: txDetails}, nil
+}
 
-				}
-			} else if len(baseBlock.GetChildBlock()) > 0 {
-				var cbs []*protobuf.ChildBlock
-				err := cdc.UnmarshalJSON(baseBlock.GetChildBlock(), &cbs)
-				if err != nil {
-					log.Printf("Failed to Unmarshal child block array: %v", err)
-					continue
-				}
-				for _, cb := range cbs {
-					// Get all the transaction from the child block
-					txs := cb.GetTxsData().GetTx()
-					for _, txbz := range txs {
-						var txT transaction.Tx
-						err := cdc.UnmarshalJSON(txbz, &txT)
-						if err != nil {
-							log.Printf("Failed to Unmarshal tx: %v", err)
-							continue
-						}
-						tx, err := transactiontoProto(txT)
-						if err != nil {
-							log.Printf("Error converting Transation to Proto tx: %v", err)
-							continue
-						}
-						if strings.EqualFold(assetName, tx.Asset.Symbol) {
-							if strings.EqualFold(address, tx.SenderAddress) ||
-								strings.EqualFold(address, tx.RecieverAddress) {
-								txDetailRes := &pluginproto.TxDetailResponse{}
-								txDetailRes.Tx = &tx
-								txDetailRes.BlockId = uint64(baseBlock.GetHeader().GetHeight())
-								ts := &pluginproto.Timestamp{
-									Seconds: baseBlock.GetHeader().Time.Seconds,
-									Nanos:   baseBlock.GetHeader().Time.Nanos,
-								}
-								txDetailRes.CreationDt = ts
-								txID := getTxIDWithoutStatus(&tx)
-								//Remove duplicate transactions from the array
-								//TODO: needs to be fixed
-								if duplicateTxTracker[txID] == 1 {
-									continue
-								}
-								duplicateTxTracker[txID] = 1
-								txDetailRes.TxId = txID
-
-								txDetails = append(txDetails, txDetailRes)
-							}
-						}
-					}
-				}
-			}
+// getTxsByTypeAndBlockNumber returns all the txs of txType in a block
+func (t *TxService) getTxsByTypeAndBlockNumber(txType string, blockNumber int64) ([]*pluginproto.TxDetailResponse, error) {
+	found := false
+	err := blockHeightHashDB.GetBadgerDB().View(func(txn *badger.Txn) error {
+		_, err := txn.Get([]byte(strconv.FormatInt(blockNumber, 10)))
+		if err == badger.ErrKeyNotFound {
+			return nil
 		}
-		return nil
+		found = err == nil
+		return err
 	})
 	if err != nil {
-		log.Error().Msgf("Failed to get blocks due to: %v", err.Error())
-		return nil, fmt.Errorf("failed to get blocks due to: %v", err.Error())
+		return nil, fmt.Errorf(fmt.Sprintf("Failed to find the block: %v.", err))
+	}
+	if !found {
+		return nil, fmt.Errorf("%w: block number %d", ErrBlockNotFound, blockNumber)
 	}
-	txs := &pluginproto.TxsResponse{
-		Txs: txDetails,
+
+	txs, err := lookupTxs(typePrefix(txType, blockNumber), false)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get %s txs: %v", txType, err)
 	}
 	return txs, nil
 }
 
 // GetLockedTxsByBlockNumber returns a list of all locked txs in a block
 func (t *TxService) GetLockedTxsByBlockNumber(blockNumber int64) (*pluginproto.TxLockedResponse, error) {
-
-	txs := make([]*pluginproto.TxDetailResponse, 0)
-
-	err := badgerDB.GetBadgerDB().View(func(txn *badger.Txn) error {
-		opts := badger.DefaultIteratorOptions
-		opts.PrefetchSize = 10
-		it := txn.NewIterator(opts)
-		defer it.Close()
-		for it.Rewind(); it.Valid(); it.Next() {
-			item := it.Item()
-			v, err := item.Value()
-			if err != nil {
-				return err
-			}
-			baseBlock := &protobuf.BaseBlock{}
-			if err := cdc.UnmarshalJSON(v, baseBlock); err != nil {
-				return err
-			}
-
-			if blockNumber == baseBlock.GetHeader().GetHeight() {
-				// Check if base block has an transaction in it
-				if baseBlock.GetTxsData() != nil {
-					for _, txbz := range baseBlock.GetTxsData().GetTx() {
-						var tx pluginproto.Tx
-						err := cdc.UnmarshalJSON(txbz, &tx)
-						if err != nil {
-							return err
-						}
-						if strings.EqualFold("lock", tx.Type) {
-							txDetailRes := &pluginproto.TxDetailResponse{}
-							txDetailRes.Tx = &tx
-							txDetailRes.BlockId = uint64(baseBlock.GetHeader().GetHeight())
-							ts := &pluginproto.Timestamp{
-								Seconds: baseBlock.GetHeader().Time.Seconds,
-								Nanos:   baseBlock.GetHeader().Time.Nanos,
-							}
-							txDetailRes.CreationDt = ts
-							txDetailRes.TxId = getTxIDWithoutStatus(&tx)
-							txs = append(txs, txDetailRes)
-						}
-					}
-				}
-				return nil
-			}
-		}
-		return fmt.Errorf("block number %d not found", blockNumber)
-	})
+	txs, err := t.getTxsByTypeAndBlockNumber("lock", blockNumber)
 	if err != nil {
-		return nil, fmt.Errorf(fmt.Sprintf("Failed to find the block: %v.", err))
+		return nil, err
 	}
-
 	return &pluginproto.TxLockedResponse{Txs: txs}, nil
 }
 
-// GetRedeemTxsByBlockNumber returns a list of all locked txs in a block
+// GetRedeemTxsByBlockNumber returns a list of all redeem txs in a block
 func (t *TxService) GetRedeemTxsByBlockNumber(blockNumber int64) (*pluginproto.TxRedeemResponse, error) {
-
-	txs := make([]*pluginproto.TxDetailResponse, 0)
-
-	err := badgerDB.GetBadgerDB().View(func(txn *badger.Txn) error {
-		opts := badger.DefaultIteratorOptions
-		opts.PrefetchSize = 10
-		it := txn.NewIterator(opts)
-		defer it.Close()
-		for it.Rewind(); it.Valid(); it.Next() {
-			item := it.Item()
-			v, err := item.Value()
-			if err != nil {
-				return err
-			}
-			baseBlock := &protobuf.BaseBlock{}
-			if err := cdc.UnmarshalJSON(v, baseBlock); err != nil {
-				return err
-			}
-
-			if blockNumber == baseBlock.GetHeader().GetHeight() {
-				// Check if base block has an transaction in it
-				if baseBlock.GetTxsData() != nil {
-					for _, txbz := range baseBlock.GetTxsData().GetTx() {
-						var tx pluginproto.Tx
-						err := cdc.UnmarshalJSON(txbz, &tx)
-						if err != nil {
-							return err
-						}
-						if strings.EqualFold("redeem", tx.Type) {
-							txDetailRes := &pluginproto.TxDetailResponse{}
-							txDetailRes.Tx = &tx
-							txDetailRes.BlockId = uint64(baseBlock.GetHeader().GetHeight())
-							ts := &pluginproto.Timestamp{
-								Seconds: baseBlock.GetHeader().Time.Seconds,
-								Nanos:   baseBlock.GetHeader().Time.Nanos,
-							}
-							txDetailRes.CreationDt = ts
-							txDetailRes.TxId = getTxIDWithoutStatus(&tx)
-							txs = append(txs, txDetailRes)
-						}
-					}
-				}
-				return nil
-			}
-		}
-		return fmt.Errorf("block number %d not found", blockNumber)
-	})
+	txs, err := t.getTxsByTypeAndBlockNumber("redeem", blockNumber)
 	if err != nil {
-		return nil, fmt.Errorf(fmt.Sprintf("Failed to find the block: %v.", err))
+		return nil, err
 	}
-
 	return &pluginproto.TxRedeemResponse{Txs: txs}, nil
 }
 
@@ -752,6 +374,9 @@ func (t *TxService) GetTxsByHeight(height int64) (*pluginproto.TxsResponse, erro
 			item *badger.Item
 		)
 		item, err = txn.Get([]byte(strconv.FormatInt(height, 10)))
+		if err == badger.ErrKeyNotFound {
+			return fmt.Errorf("%w: block number %d", ErrBlockNotFound, height)
+		}
 		if err != nil {
 			return err
 		}
@@ -783,6 +408,7 @@ func (t *TxService) GetTxsByHeight(height int64) (*pluginproto.TxsResponse, erro
 		}
 
 		if baseBlock.GetTxsData() != nil {
+			block := newDecodedBlock(baseBlock)
 			txs = make([]*pluginproto.TxDetailResponse, len(baseBlock.GetTxsData().GetTx()))
 			for i, txbz := range baseBlock.GetTxsData().GetTx() {
 				var tx pluginproto.Tx
@@ -790,16 +416,7 @@ func (t *TxService) GetTxsByHeight(height int64) (*pluginproto.TxsResponse, erro
 				if err != nil {
 					return err
 				}
-				txDetailRes := &pluginproto.TxDetailResponse{}
-				txDetailRes.Tx = &tx
-				txDetailRes.BlockId = uint64(baseBlock.GetHeader().GetHeight())
-				ts := &pluginproto.Timestamp{
-					Seconds: baseBlock.GetHeader().Time.Seconds,
-					Nanos:   baseBlock.GetHeader().Time.Nanos,
-				}
-				txDetailRes.CreationDt = ts
-				txDetailRes.TxId = getTxIDWithoutStatus(&tx)
-				txs[i] = txDetailRes
+				txs[i] = newTxDetail(block, &tx)
 			}
 		}
 		return nil
@@ -869,61 +486,12 @@ func GetBlockchainDb() db.DB {
 	return badgerDB
 }
 
-// getTxIDWithoutStatus creates TxID without the status
-func getTxIDWithoutStatus(tx *pluginproto.Tx) string {
+// TxIDWithoutStatus creates the TxID of tx without its status, the id the tx
+// is indexed and its receipt recorded under
+func TxIDWithoutStatus(tx *pluginproto.Tx) string {
 	txWithOutStatus := *tx
 	txWithOutStatus.Status = ""
 	txbzWithOutStatus, _ := cdc.MarshalJSON(txWithOutStatus)
 	txID := cmn.CreateTxID(txbzWithOutStatus)
 	return txID
 }
-
-func transactiontoProto(txValue transaction.Tx) (tx pluginproto.Tx, err error) {
-	val := uint64(0)
-	if txValue.Asset.Value != "" {
-		val, err = strconv.ParseUint(txValue.Asset.Value, 10, 64)
-		if err != nil {
-			err = fmt.Errorf("Failed to parse transaction value: %v", err)
-			return
-		}
-	}
-	fee := uint64(0)
-	if txValue.Asset.Fee != "" {
-		fee, err = strconv.ParseUint(txValue.Asset.Fee, 10, 64)
-		if err != nil {
-			err = fmt.Errorf("Failed to parse transaction fee: %v", err)
-			return
-		}
-	}
-	nonc := uint64(0)
-	if txValue.Asset.Nonce != "" {
-		nonc, err = strconv.ParseUint(txValue.Asset.Nonce, 10, 64)
-		if err != nil {
-			err = fmt.Errorf("Failed to parse transaction nonce: %v", err)
-			return
-		}
-	}
-	asset := &pluginproto.Asset{
-		Category:              txValue.Asset.Category,
-		Symbol:                txValue.Asset.Symbol,
-		Network:               txValue.Asset.Network,
-		Value:                 val,
-		Fee:                   fee,
-		Nonce:                 nonc,
-		ExternalSenderAddress: txValue.Asset.ExternalSenderAddress,
-		LockedAmount:          txValue.Asset.LockedAmount,
-		RedeemedAmount:        txValue.Asset.RedeemedAmount,
-	}
-	tx = pluginproto.Tx{
-		SenderAddress:   txValue.SenderAddress,
-		SenderPubkey:    txValue.SenderPubKey,
-		RecieverAddress: txValue.ReceiverAddress,
-		Asset:           asset,
-		Message:         txValue.Message,
-		Type:            txValue.Type,
-		Sign:            txValue.Signature,
-		Status:          txValue.Status,
-	}
-
-	return
-}
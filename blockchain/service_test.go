@@ -3,7 +3,6 @@ package blockchain
 import (
 	"io/ioutil"
 	"os"
-	"strconv"
 	"testing"
 	"time"
 
@@ -27,6 +26,12 @@ func TestGetTxsByAddress(t *testing.T) {
 	LoadDBTest(dirname)
 	defer os.RemoveAll(dirname)
 	defer badgerDB.Close() // Close the db to release the lock
+
+	blockDirName, err := ioutil.TempDir(os.TempDir(), "badgerdb_test_")
+	require.Nil(t, err)
+	LoadBlockDBTest(blockDirName)
+	defer os.RemoveAll(blockDirName)
+	defer blockHeightHashDB.Close()
 	require.Nil(t, err)
 
 	privKey := secp256k1.GenPrivKey()
@@ -43,6 +48,12 @@ func TestGetTxsByAddressAndAsset(t *testing.T) {
 	LoadDBTest(dirname)
 	defer os.RemoveAll(dirname)
 	defer badgerDB.Close() // Close the db to release the lock
+
+	blockDirName, err := ioutil.TempDir(os.TempDir(), "badgerdb_test_")
+	require.Nil(t, err)
+	LoadBlockDBTest(blockDirName)
+	defer os.RemoveAll(blockDirName)
+	defer blockHeightHashDB.Close()
 	require.Nil(t, err)
 
 	privKey := secp256k1.GenPrivKey()
@@ -74,13 +85,7 @@ func addBlocks(privKey secp256k1.PrivKeySecp256k1, t *testing.T) {
 	assert.Equal(t, 5, len(txsBatch1))
 	// Block 1
 	baseBlock1 := createBlock(1, txsBatch1, t)
-	blockhash := baseBlock1.GetHeader().GetBlock_ID().GetBlockHash()
-	bbbz1, err := cdc.MarshalJSON(baseBlock1)
-	require.Nil(t, err)
-	badgerDB.Set(blockhash, bbbz1)
-	if blockHeightHashDB != nil {
-		blockHeightHashDB.Set([]byte(strconv.FormatInt(1, 10)), blockhash)
-	}
+	require.Nil(t, (&Service{}).AddBaseBlock(baseBlock1))
 
 	for i := 6; i <= 10; i++ {
 		tx := getTx(i, privKey)
@@ -91,13 +96,7 @@ func addBlocks(privKey secp256k1.PrivKeySecp256k1, t *testing.T) {
 	assert.Equal(t, 5, len(txsBatch2))
 	// Block 2
 	baseBlock2 := createBlock(2, txsBatch2, t)
-	blockhash = baseBlock2.GetHeader().GetBlock_ID().GetBlockHash()
-	bbbz2, err := cdc.MarshalJSON(baseBlock2)
-	require.Nil(t, err)
-	badgerDB.Set(blockhash, bbbz2)
-	if blockHeightHashDB != nil {
-		blockHeightHashDB.Set([]byte(strconv.FormatInt(2, 10)), blockhash)
-	}
+	require.Nil(t, (&Service{}).AddBaseBlock(baseBlock2))
 
 	for i := 11; i <= 15; i++ {
 		tx := getTx(i, privKey)
@@ -108,14 +107,7 @@ func addBlocks(privKey secp256k1.PrivKeySecp256k1, t *testing.T) {
 	assert.Equal(t, 5, len(txsBatch3))
 	// Block 3
 	baseBlock3 := createBlock(3, txsBatch3, t)
-	blockhash = baseBlock3.GetHeader().GetBlock_ID().GetBlockHash()
-	bbbz3, err := cdc.MarshalJSON(baseBlock3)
-	require.Nil(t, err)
-	badgerDB.Set(blockhash, bbbz3)
-
-	if blockHeightHashDB != nil {
-		blockHeightHashDB.Set([]byte(strconv.FormatInt(3, 10)), blockhash)
-	}
+	require.Nil(t, (&Service{}).AddBaseBlock(baseBlock3))
 
 	for i := 16; i <= 20; i++ {
 		tx := getTx(i, privKey)
@@ -126,13 +118,7 @@ func addBlocks(privKey secp256k1.PrivKeySecp256k1, t *testing.T) {
 	assert.Equal(t, 5, len(txsBatch4))
 	// Block 4
 	baseBlock4 := createBlock(4, txsBatch4, t)
-	blockhash = baseBlock4.GetHeader().GetBlock_ID().GetBlockHash()
-	bbbz4, err := cdc.MarshalJSON(baseBlock4)
-	require.Nil(t, err)
-	badgerDB.Set(blockhash, bbbz4)
-	if blockHeightHashDB != nil {
-		blockHeightHashDB.Set([]byte(strconv.FormatInt(4, 10)), blockhash)
-	}
+	require.Nil(t, (&Service{}).AddBaseBlock(baseBlock4))
 }
 
 func getTx(nonce int, privKey secp256k1.PrivKeySecp256k1) pluginproto.Tx {
@@ -203,10 +189,7 @@ func addBlocksWithLockedTxs(privKey secp256k1.PrivKeySecp256k1, t *testing.T) in
 	assert.Equal(t, 10, len(txsBatch))
 
 	baseBlock := createBlock(1, txsBatch, t)
-	blockhash := baseBlock.GetHeader().GetBlock_ID().GetBlockHash()
-	bbbz, err := cdc.MarshalJSON(baseBlock)
-	require.Nil(t, err)
-	badgerDB.Set(blockhash, bbbz)
+	require.Nil(t, (&Service{}).AddBaseBlock(baseBlock))
 	return baseBlock.GetHeader().GetHeight()
 }
 
@@ -217,6 +200,12 @@ func TestGetLockedTxsByBlockNumber(t *testing.T) {
 	defer os.RemoveAll(dirname)
 	defer badgerDB.Close() // Close the db to release the lock
 
+	blockDirName, err := ioutil.TempDir(os.TempDir(), "badgerdb_test_")
+	require.Nil(t, err)
+	LoadBlockDBTest(blockDirName)
+	defer os.RemoveAll(blockDirName)
+	defer blockHeightHashDB.Close()
+
 	privKey := secp256k1.GenPrivKey()
 	blockNumber := addBlocksWithLockedTxs(privKey, t)
 	txSrv := TxService{}
@@ -244,10 +233,7 @@ func addBlocksWithRedeemTxs(privKey secp256k1.PrivKeySecp256k1, t *testing.T) in
 	assert.Equal(t, 10, len(txsBatch))
 
 	baseBlock := createBlock(1, txsBatch, t)
-	blockhash := baseBlock.GetHeader().GetBlock_ID().GetBlockHash()
-	bbbz, err := cdc.MarshalJSON(baseBlock)
-	require.Nil(t, err)
-	badgerDB.Set(blockhash, bbbz)
+	require.Nil(t, (&Service{}).AddBaseBlock(baseBlock))
 	return baseBlock.GetHeader().GetHeight()
 }
 func TestGetRedeemTxsByBlockNumber(t *testing.T) {
@@ -257,6 +243,12 @@ func TestGetRedeemTxsByBlockNumber(t *testing.T) {
 	defer os.RemoveAll(dirname)
 	defer badgerDB.Close() // Close the db to release the lock
 
+	blockDirName, err := ioutil.TempDir(os.TempDir(), "badgerdb_test_")
+	require.Nil(t, err)
+	LoadBlockDBTest(blockDirName)
+	defer os.RemoveAll(blockDirName)
+	defer blockHeightHashDB.Close()
+
 	privKey := secp256k1.GenPrivKey()
 	blockNumber := addBlocksWithRedeemTxs(privKey, t)
 	txSrv := TxService{}
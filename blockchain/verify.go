@@ -0,0 +1,114 @@
+package blockchain
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/herdius/herdius-core/blockchain/protobuf"
+	"github.com/herdius/herdius-core/crypto/herhash"
+	"github.com/herdius/herdius-core/crypto/merkle"
+	cmn "github.com/herdius/herdius-core/libs/common"
+)
+
+// HeaderHash returns the hash identifying header, the hash of the header
+// with an empty block ID
+func HeaderHash(header *protobuf.BaseHeader) ([]byte, error) {
+	h := *header
+	h.Block_ID = &protobuf.BlockID{}
+	bz, err := cdc.MarshalJSON(&h)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal base header: %v", err)
+	}
+	return herhash.Sum(bz), nil
+}
+
+// ValidatorGroupsHash returns the simple merkle root of validator groups
+func ValidatorGroupsHash(groups []*protobuf.ValidatorGroup) ([]byte, error) {
+	groupsBz := make([][]byte, 0, len(groups))
+	for _, group := range groups {
+		bz, err := cdc.MarshalJSON(group)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal validator group: %v", err)
+		}
+		groupsBz = append(groupsBz, bz)
+	}
+	return merkle.SimpleHashFromByteSlices(groupsBz), nil
+}
+
+// GenesisBlockHash returns the hash of the genesis block
+func GenesisBlockHash() ([]byte, error) {
+	bz, err := cdc.MarshalJSON(&protobuf.BlockID{BlockHash: []byte{0}})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal genesis block ID: %v", err)
+	}
+	return herhash.Sum(bz), nil
+}
+
+// VerifyBlock checks that block hashes to its block ID, that its txs and
+// receipts match the roots in its header and that it follows prev. A nil
+// prev verifies block as the genesis block.
+func VerifyBlock(block, prev *protobuf.BaseBlock) error {
+	header := block.GetHeader()
+	if header == nil {
+		return fmt.Errorf("block has no header")
+	}
+	var blockHash cmn.HexBytes = header.GetBlock_ID().GetBlockHash()
+
+	if prev == nil {
+		if header.GetHeight() != 0 {
+			return fmt.Errorf("chain starts at height %d, not at genesis", header.GetHeight())
+		}
+		genesisHash, err := GenesisBlockHash()
+		if err != nil {
+			return err
+		}
+		if !bytes.Equal(blockHash, genesisHash) {
+			return fmt.Errorf("genesis block hash %v doesn't match %v", blockHash, cmn.HexBytes(genesisHash))
+		}
+		return nil
+	}
+
+	if header.GetHeight() != prev.GetHeader().GetHeight()+1 {
+		return fmt.Errorf("block %v at height %d doesn't follow height %d", blockHash, header.GetHeight(), prev.GetHeader().GetHeight())
+	}
+	var prevHash cmn.HexBytes = prev.GetHeader().GetBlock_ID().GetBlockHash()
+	if !bytes.Equal(header.GetLastBlockID().GetBlockHash(), prevHash) {
+		return fmt.Errorf("block %d links to %v, not to previous block %v", header.GetHeight(),
+			cmn.HexBytes(header.GetLastBlockID().GetBlockHash()), prevHash)
+	}
+	hash, err := HeaderHash(header)
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(hash, blockHash) {
+		return fmt.Errorf("block %d hashes to %v, not to its block ID %v", header.GetHeight(), cmn.HexBytes(hash), blockHash)
+	}
+	if block.GetTxsData() != nil {
+		if root := merkle.SimpleHashFromByteSlices(block.GetTxsData().GetTx()); !bytes.Equal(root, header.GetRootHash()) {
+			return fmt.Errorf("txs of block %d don't match its root hash", header.GetHeight())
+		}
+	}
+	if root := merkle.SimpleHashFromByteSlices(block.GetReceipts()); !bytes.Equal(root, header.GetReceiptRoot()) {
+		return fmt.Errorf("receipts of block %d don't match its receipt root", header.GetHeight())
+	}
+	root, err := ValidatorGroupsHash(block.GetValidatorGroups())
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(root, header.GetValidatorGroupsHash()) {
+		return fmt.Errorf("validator groups of block %d don't match their hash", header.GetHeight())
+	}
+	return nil
+}
+
+// VerifyChain verifies blocks as a chain starting at the genesis block
+func VerifyChain(blocks []*protobuf.BaseBlock) error {
+	var prev *protobuf.BaseBlock
+	for _, block := range blocks {
+		if err := VerifyBlock(block, prev); err != nil {
+			return err
+		}
+		prev = block
+	}
+	return nil
+}
@@ -0,0 +1,104 @@
+package blockchain
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/herdius/herdius-core/blockchain/protobuf"
+	"github.com/herdius/herdius-core/crypto/merkle"
+)
+
+// createChain creates a genesis block followed by n blocks the way the
+// supervisor does, and round trips them through JSON like backups do
+func createChain(t *testing.T, n int) []*protobuf.BaseBlock {
+	genesisHash, err := GenesisBlockHash()
+	require.Nil(t, err)
+	blocks := []*protobuf.BaseBlock{{
+		Header: &protobuf.BaseHeader{
+			Block_ID:  &protobuf.BlockID{BlockHash: genesisHash},
+			Time:      &protobuf.Timestamp{Seconds: 1500000000, Nanos: 1500000000000000000},
+			StateRoot: []byte{0xAA},
+		},
+	}}
+	for h := 1; h <= n; h++ {
+		txs := [][]byte{[]byte("tx-a"), []byte{byte(h)}}
+		receipts := [][]byte{[]byte("receipt-a"), []byte{byte(h), 1}}
+		groups := []*protobuf.ValidatorGroup{
+			{Validators: []string{"validator-a", "validator-b"}, ChildBlockHash: []byte{byte(h), 2}},
+		}
+		groupsHash, err := ValidatorGroupsHash(groups)
+		require.Nil(t, err)
+		header := &protobuf.BaseHeader{
+			Block_ID:            &protobuf.BlockID{},
+			LastBlockID:         blocks[h-1].GetHeader().GetBlock_ID(),
+			Height:              int64(h),
+			StateRoot:           []byte{byte(h)},
+			Time:                &protobuf.Timestamp{Seconds: 1500000000 + int64(h), Nanos: 1500000000000000000 + int64(h)},
+			RootHash:            merkle.SimpleHashFromByteSlices(txs),
+			TotalTxs:            uint64(len(txs)),
+			ReceiptRoot:         merkle.SimpleHashFromByteSlices(receipts),
+			ValidatorGroupsHash: groupsHash,
+		}
+		hash, err := HeaderHash(header)
+		require.Nil(t, err)
+		header.GetBlock_ID().BlockHash = hash
+		blocks = append(blocks, &protobuf.BaseBlock{
+			Header:          header,
+			TxsData:         &protobuf.TxsData{Tx: txs},
+			Receipts:        receipts,
+			ValidatorGroups: groups,
+		})
+	}
+
+	restored := make([]*protobuf.BaseBlock, 0, len(blocks))
+	for _, block := range blocks {
+		bz, err := json.Marshal(block)
+		require.Nil(t, err)
+		restoredBlock := &protobuf.BaseBlock{}
+		require.Nil(t, json.Unmarshal(bz, restoredBlock))
+		restored = append(restored, restoredBlock)
+	}
+	return restored
+}
+
+func TestVerifyChain(t *testing.T) {
+	require.Nil(t, VerifyChain(createChain(t, 3)))
+
+	cases := map[string]func(blocks []*protobuf.BaseBlock){
+		"missing genesis": func(blocks []*protobuf.BaseBlock) {
+			blocks[0] = blocks[1]
+		},
+		"tampered genesis": func(blocks []*protobuf.BaseBlock) {
+			blocks[0].Header.Block_ID.BlockHash = []byte{1}
+		},
+		"gap": func(blocks []*protobuf.BaseBlock) {
+			blocks[2] = blocks[3]
+		},
+		"tampered header": func(blocks []*protobuf.BaseBlock) {
+			blocks[2].Header.StateRoot = []byte{0xFF}
+		},
+		"tampered block id": func(blocks []*protobuf.BaseBlock) {
+			blocks[3].Header.Block_ID.BlockHash = []byte{0xFF}
+		},
+		"broken link": func(blocks []*protobuf.BaseBlock) {
+			blocks[2].Header.LastBlockID.BlockHash = []byte{0xFF}
+		},
+		"tampered tx": func(blocks []*protobuf.BaseBlock) {
+			blocks[1].TxsData.Tx[0] = []byte("tx-b")
+		},
+		"dropped receipt": func(blocks []*protobuf.BaseBlock) {
+			blocks[3].Receipts = blocks[3].Receipts[:1]
+		},
+		"tampered validator group": func(blocks []*protobuf.BaseBlock) {
+			blocks[2].ValidatorGroups[0].Validators[1] = "validator-c"
+		},
+	}
+	for name, tamper := range cases {
+		blocks := createChain(t, 3)
+		tamper(blocks)
+		assert.Error(t, VerifyChain(blocks), name)
+	}
+}
@@ -20,8 +20,11 @@ func init() {
 	cryptoAmino.RegisterAmino(cdc)
 }
 
-// LoadDB loads databases used by blockchain
+// LoadDB loads databases used by blockchain, unless they are loaded already
 func LoadDB() {
+	if badgerDB != nil && blockHeightHashDB != nil {
+		return
+	}
 	var (
 		dir, dbName           string
 		blockDir, blockDBName string
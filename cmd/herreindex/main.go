@@ -0,0 +1,26 @@
+// Command herreindex rebuilds the tx indexes of an existing chain db. Run it
+// from the repository root while the supervisor is stopped.
+package main
+
+import (
+	nlog "log"
+
+	"github.com/herdius/herdius-core/blockchain"
+	"github.com/herdius/herdius-core/p2p/log"
+)
+
+func init() {
+	nlog.SetFlags(nlog.LstdFlags | nlog.Lshortfile)
+}
+
+func main() {
+	blockchain.LoadDB()
+	defer blockchain.GetBlockchainDb().Close()
+
+	blockchainSvc := &blockchain.Service{}
+	indexed, err := blockchainSvc.RebuildIndexes()
+	if err != nil {
+		log.Fatal().Err(err).Msg("failed to rebuild tx indexes")
+	}
+	log.Info().Msgf("Rebuilt tx indexes of %d blocks", indexed)
+}
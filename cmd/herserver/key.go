@@ -0,0 +1,203 @@
+package main
+
+import (
+	"encoding/hex"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/herdius/herdius-core/accounts/keystore"
+	"github.com/herdius/herdius-core/crypto/secp256k1"
+	p2pkey "github.com/herdius/herdius-core/p2p/key"
+)
+
+const defaultKeystoreDir = "./keystore"
+
+const keyUsage = `Usage: herserver key <command> [flags] [args]
+
+Commands:
+  new                          create a key in the key store
+  import <file>                encrypt a plaintext node key file or hex encoded private key into the key store
+  export <keyfile>             print the plaintext node key of an encrypted key file
+  list                         list the keys of the key store
+  change-password <keyfile>    encrypt a key file with a new passphrase
+
+Run herserver key <command> -h for the flags of a command.
+`
+
+// keyCommand runs the key subcommand of args and returns the exit code
+func keyCommand(args []string) int {
+	if len(args) == 0 {
+		fmt.Fprint(os.Stderr, keyUsage)
+		return 2
+	}
+	var err error
+	switch args[0] {
+	case "new":
+		err = keyNew(args[1:])
+	case "import":
+		err = keyImport(args[1:])
+	case "export":
+		err = keyExport(args[1:])
+	case "list":
+		err = keyList(args[1:])
+	case "change-password":
+		err = keyChangePassword(args[1:])
+	default:
+		fmt.Fprint(os.Stderr, keyUsage)
+		return 2
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		return 1
+	}
+	return 0
+}
+
+func keyNew(args []string) error {
+	flags := flag.NewFlagSet("key new", flag.ExitOnError)
+	dir := flags.String("keystore", defaultKeystoreDir, "key store directory")
+	kdf := flags.String("kdf", keystore.KDFScrypt, "key derivation function, scrypt or argon2id")
+	passphraseFile := flags.String("passphrasefile", "", "file holding the passphrase (prompted for if empty)")
+	flags.Parse(args)
+
+	passphrase, err := keystore.ReadPassphrase(*passphraseFile, "Passphrase: ", true)
+	if err != nil {
+		return err
+	}
+	privKey := secp256k1.GenPrivKey()
+	path, err := keystore.StoreKey(*dir, privKey, passphrase, *kdf)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Address: %v\nKey file: %v\n", keystore.Address(privKey), path)
+	return nil
+}
+
+func keyImport(args []string) error {
+	flags := flag.NewFlagSet("key import", flag.ExitOnError)
+	dir := flags.String("keystore", defaultKeystoreDir, "key store directory")
+	kdf := flags.String("kdf", keystore.KDFScrypt, "key derivation function, scrypt or argon2id")
+	passphraseFile := flags.String("passphrasefile", "", "file holding the passphrase (prompted for if empty)")
+	flags.Parse(args)
+	if flags.NArg() != 1 {
+		return fmt.Errorf("usage: herserver key import [flags] <file>")
+	}
+
+	privKey, err := readPlainKey(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+	passphrase, err := keystore.ReadPassphrase(*passphraseFile, "Passphrase: ", true)
+	if err != nil {
+		return err
+	}
+	path, err := keystore.StoreKey(*dir, privKey, passphrase, *kdf)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Address: %v\nKey file: %v\n", keystore.Address(privKey), path)
+	return nil
+}
+
+func keyExport(args []string) error {
+	flags := flag.NewFlagSet("key export", flag.ExitOnError)
+	passphraseFile := flags.String("passphrasefile", "", "file holding the passphrase (prompted for if empty)")
+	flags.Parse(args)
+	if flags.NArg() != 1 {
+		return fmt.Errorf("usage: herserver key export [flags] <keyfile>")
+	}
+
+	keyjson, err := ioutil.ReadFile(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+	passphrase, err := keystore.ReadPassphrase(*passphraseFile, "Passphrase: ", false)
+	if err != nil {
+		return err
+	}
+	privKey, err := keystore.DecryptKey(keyjson, passphrase)
+	if err != nil {
+		return err
+	}
+	nodeKeyJSON, err := cdc.MarshalJSON(&p2pkey.NodeKey{PrivKey: privKey})
+	if err != nil {
+		return err
+	}
+	fmt.Fprintln(os.Stderr, "Warning: the node key below is not encrypted")
+	fmt.Println(string(nodeKeyJSON))
+	return nil
+}
+
+func keyList(args []string) error {
+	flags := flag.NewFlagSet("key list", flag.ExitOnError)
+	dir := flags.String("keystore", defaultKeystoreDir, "key store directory")
+	flags.Parse(args)
+
+	keys, err := keystore.ListKeys(*dir)
+	if err != nil {
+		return err
+	}
+	for _, key := range keys {
+		fmt.Printf("%v %v\n", key.Address, key.Path)
+	}
+	return nil
+}
+
+func keyChangePassword(args []string) error {
+	flags := flag.NewFlagSet("key change-password", flag.ExitOnError)
+	kdf := flags.String("kdf", keystore.KDFScrypt, "key derivation function, scrypt or argon2id")
+	passphraseFile := flags.String("passphrasefile", "", "file holding the current passphrase (prompted for if empty)")
+	newPassphraseFile := flags.String("newpassphrasefile", "", "file holding the new passphrase (prompted for if empty)")
+	flags.Parse(args)
+	if flags.NArg() != 1 {
+		return fmt.Errorf("usage: herserver key change-password [flags] <keyfile>")
+	}
+
+	path := flags.Arg(0)
+	keyjson, err := ioutil.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	passphrase, err := keystore.ReadPassphrase(*passphraseFile, "Current passphrase: ", false)
+	if err != nil {
+		return err
+	}
+	privKey, err := keystore.DecryptKey(keyjson, passphrase)
+	if err != nil {
+		return err
+	}
+	newPassphrase, err := keystore.ReadPassphrase(*newPassphraseFile, "New passphrase: ", true)
+	if err != nil {
+		return err
+	}
+	keyjson, err = keystore.EncryptKey(privKey, newPassphrase, *kdf)
+	if err != nil {
+		return err
+	}
+	return keystore.WriteKeyFile(path, keyjson)
+}
+
+// readPlainKey reads a plaintext node key file or a hex encoded secp256k1
+// private key from path
+func readPlainKey(path string) (secp256k1.PrivKeySecp256k1, error) {
+	var privKey secp256k1.PrivKeySecp256k1
+	if nodeKey, err := p2pkey.LoadNodeKey(path); err == nil {
+		if key, ok := nodeKey.PrivKey.(secp256k1.PrivKeySecp256k1); ok {
+			return key, nil
+		}
+		return privKey, fmt.Errorf("unsupported key type %T", nodeKey.PrivKey)
+	}
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return privKey, err
+	}
+	raw, err := hex.DecodeString(strings.TrimSpace(string(content)))
+	if err != nil || len(raw) != len(privKey) {
+		return privKey, fmt.Errorf("%v is neither a node key file nor a hex encoded private key", path)
+	}
+	copy(privKey[:], raw)
+	return privKey, nil
+}
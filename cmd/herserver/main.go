@@ -4,12 +4,16 @@ import (
 	"context"
 	"flag"
 	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
 	"strconv"
 
 	nlog "log"
 	"strings"
 	"time"
 
+	"github.com/herdius/herdius-core/asset"
 	"github.com/herdius/herdius-core/aws/restore"
 	"github.com/herdius/herdius-core/blockchain"
 	"github.com/herdius/herdius-core/blockchain/protobuf"
@@ -20,13 +24,15 @@ import (
 	protoplugin "github.com/herdius/herdius-core/hbi/protobuf"
 	cmn "github.com/herdius/herdius-core/libs/common"
 	"github.com/herdius/herdius-core/p2p/crypto"
-	keystore "github.com/herdius/herdius-core/p2p/key"
+	p2pkey "github.com/herdius/herdius-core/p2p/key"
 	"github.com/herdius/herdius-core/p2p/log"
 	"github.com/herdius/herdius-core/p2p/network"
 	"github.com/herdius/herdius-core/p2p/network/discovery"
+	"github.com/herdius/herdius-core/p2p/network/reputation"
 	"github.com/herdius/herdius-core/p2p/types/opcode"
 	external "github.com/herdius/herdius-core/storage/exbalance"
 	syncer "github.com/herdius/herdius-core/syncer"
+	"github.com/herdius/herdius-core/transition"
 	"github.com/herdius/herdius-core/types"
 
 	sup "github.com/herdius/herdius-core/supervisor/service"
@@ -51,9 +57,6 @@ func init() {
 	nlog.SetFlags(nlog.LstdFlags | nlog.Lshortfile)
 	supsvc = &sup.Supervisor{}
 	supsvc.SetWriteMutex()
-	supsvc.ValidatorChildblock = make(map[string]*blockProtobuf.BlockID, 0)
-	supsvc.ChildBlock = make([]*blockProtobuf.ChildBlock, 0)
-	supsvc.VoteInfoData = make(map[string][]*blockProtobuf.VoteInfo, 0)
 
 	// Register Amino service for message (en/de) coding
 	cryptoAmino.RegisterAmino(cdc)
@@ -95,14 +98,24 @@ func (state *HerdiusMessagePlugin) Receive(ctx *network.PluginContext) error {
 }
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "key" {
+		os.Exit(keyCommand(os.Args[2:]))
+	}
+
 	// process other flags
 	peersFlag := flag.String("peers", "", "peers to connect to")
 	groupSizeFlag := flag.Int("groupsize", 3, "# of peers in a validator group")
 	portFlag := flag.Int("port", 0, "port to bind validator to")
 	envFlag := flag.String("env", "dev", "environment to build network and run process for")
 	waitTimeFlag := flag.Int("waitTime", 15, "time to wait before the Memory Pool is flushed to a new block")
-	restoreFlag := flag.Bool("restore", false, "restore blockchain from S3")
-	backupFlag := flag.Bool("backup", false, "backup blockchain to S3")
+	restoreFlag := flag.Bool("restore", false, "restore blockchain from backup (S3, or backupdir if configured)")
+	backupFlag := flag.Bool("backup", false, "backup blockchain to S3, or backupdir if configured")
+	verifyStateFlag := flag.Bool("verifystate", false, "verify the whole state trie of the last block at startup and rebuild it if it is incomplete")
+	httpFlag := flag.String("http", "", "address to serve the HTTP/JSON API on, e.g. :8080 (disabled if empty)")
+	banDurationFlag := flag.Duration("banduration", 24*time.Hour, "how long peers that keep misbehaving are banned for")
+	nodeKeyFlag := flag.String("nodekey", "", "node key file (defaults to <ip>_<port>_sk_peer_id.json in the node key dir)")
+	passphraseFlag := flag.String("passphrase", "", "passphrase of the encrypted node key")
+	passphraseFileFlag := flag.String("passphrasefile", "", "file holding the passphrase of the encrypted node key")
 
 	flag.Parse()
 
@@ -112,6 +125,8 @@ func main() {
 	waitTime := *waitTimeFlag
 	restr := *restoreFlag
 	backup := *backupFlag
+	httpAddress := *httpFlag
+	banDuration := *banDurationFlag
 	cfg := config.GetConfiguration(env)
 	peers := []string{}
 	if len(*peersFlag) > 0 {
@@ -122,11 +137,26 @@ func main() {
 		port = cfg.SelfBroadcastPort
 	}
 
+	transition.Configure(cfg.ChainID, cfg.LegacySignHeight)
+
+	// Every node of the chain has to support the same assets
+	if len(cfg.Assets) > 0 {
+		registry, err := asset.NewRegistry(cfg.Assets...)
+		if err != nil {
+			log.Fatal().Err(err).Msg("failed to load the configured assets")
+		}
+		asset.SetRegistry(registry)
+	}
+
 	// Generate or Load Keys
 	nodeAddress := cfg.SelfBroadcastIP + "_" + strconv.Itoa(port)
-	nodekey, err := keystore.LoadOrGenNodeKey(nodeKeydir + nodeAddress + "_sk_peer_id.json")
+	nodeKeyPath := *nodeKeyFlag
+	if len(nodeKeyPath) == 0 {
+		nodeKeyPath = nodeKeydir + nodeAddress + "_sk_peer_id.json"
+	}
+	nodekey, err := p2pkey.UnlockNodeKey(nodeKeyPath, *passphraseFlag, *passphraseFileFlag)
 	if err != nil {
-		log.Error().Msgf("Failed to create or load node key: %v", err)
+		log.Fatal().Err(err).Msg("Failed to create or load node key")
 	}
 	privKey := nodekey.PrivKey
 	pubKey := privKey.PubKey()
@@ -161,6 +191,13 @@ func main() {
 	opcode.RegisterMessageType(types.OpcodeTxRedeemResponse, &protoplugin.TxRedeemResponse{})
 	opcode.RegisterMessageType(types.OpcodeTxsByBlockHeightRequest, &protoplugin.TxsByBlockHeightRequest{})
 	opcode.RegisterMessageType(types.OpcodeLastBlockRequest, &protoplugin.LastBlockRequest{})
+	opcode.RegisterMessageType(types.OpcodeSubscribeRequest, &protoplugin.SubscribeRequest{})
+	opcode.RegisterMessageType(types.OpcodeSubscribeResponse, &protoplugin.SubscribeResponse{})
+	opcode.RegisterMessageType(types.OpcodeUnsubscribeRequest, &protoplugin.UnsubscribeRequest{})
+	opcode.RegisterMessageType(types.OpcodeNotification, &protoplugin.Notification{})
+	opcode.RegisterMessageType(types.OpcodeTxAnnouncement, &protoplugin.TxAnnouncement{})
+	opcode.RegisterMessageType(types.OpcodeTxFetchRequest, &protoplugin.TxFetchRequest{})
+	opcode.RegisterMessageType(types.OpcodeTxFetchResponse, &protoplugin.TxFetchResponse{})
 
 	address := cfg.ConstructTCPAddress()
 	builder := network.NewBuilderWithOptions(network.Address(address))
@@ -168,14 +205,24 @@ func main() {
 
 	builder.SetAddress(network.FormatAddress(cfg.Protocol, cfg.SelfBroadcastIP, uint16(port)))
 
-	// Register peer discovery plugin.
-	builder.AddPlugin(new(discovery.Plugin))
+	// Register peer discovery plugin, persisting the routing table.
+	builder.AddPlugin(&discovery.Plugin{
+		RoutesPath: filepath.Join(cfg.RoutingTableDir, nodeAddress+"_routes.json"),
+		SeedNodes:  cfg.SeedNodes,
+	})
+
+	// Disconnect and ban peers that keep misbehaving.
+	builder.AddPlugin(reputation.New(reputation.WithBanDuration(banDuration), reputation.WithBanListPath(cfg.BanListPath)))
 
 	// Add custom Herdius plugin.
 	builder.AddPlugin(new(HerdiusMessagePlugin))
 	builder.AddPlugin(new(message.BlockMessagePlugin))
 	builder.AddPlugin(new(message.AccountMessagePlugin))
 	builder.AddPlugin(new(message.TransactionMessagePlugin))
+	// Gossip the txs accepted from clients to the other nodes.
+	builder.AddPlugin(new(message.TxGossipPlugin))
+	subscriptions := message.NewSubscriptionPlugin()
+	builder.AddPlugin(subscriptions)
 
 	net, err := builder.Build()
 	if err != nil {
@@ -197,16 +244,33 @@ func main() {
 	var stateRoot []byte
 	accountStorage = external.New()
 	if restr {
-		log.Info().Msg("Restore value true: proceeding to restore from AWS S3")
-		r := restore.NewRestorer(env, 3)
-		if err := r.Restore(); err != nil {
-			log.Error().Err(err).Msg("failed to restore from aws s3")
+		log.Info().Msg("Restore value true: proceeding to restore from backup")
+		r, err := restore.NewRestorer(env, 3)
+		if err != nil {
+			log.Error().Err(err).Msg("failed to restore from backup")
+		} else if err := r.Restore(); err != nil {
+			log.Error().Err(err).Msg("failed to restore from backup")
 		}
 	}
 	blockchain.LoadDB()
 	sup.LoadStateDB(accountStorage)
 	blockchainSvc := &blockchain.Service{}
 
+	if len(httpAddress) > 0 {
+		go func() {
+			srv := &http.Server{
+				Addr:         httpAddress,
+				Handler:      message.NewGateway(net.ID.Address),
+				ReadTimeout:  10 * time.Second,
+				WriteTimeout: 30 * time.Second,
+			}
+			log.Info().Msgf("Serving HTTP API on %v", httpAddress)
+			if err := srv.ListenAndServe(); err != nil {
+				log.Error().Err(err).Msg("HTTP API server stopped")
+			}
+		}()
+	}
+
 	lastBlock := blockchainSvc.GetLastBlock()
 
 	go syncer.SyncAllAccounts(accountStorage, env)
@@ -232,6 +296,14 @@ func main() {
 	supsvc.SetWaitTime(waitTime)
 	supsvc.SetNoOfPeersInGroup(noOfPeersInGroup)
 	supsvc.SetBackup(backup)
+	supsvc.SetVerifyState(*verifyStateFlag)
+	supsvc.SetRewardAddress(pubKey.GetAddress())
+
+	// The state db has to hold the state of the last block before any block
+	// is created on top of it
+	if err := supsvc.CheckState(blockchainSvc); err != nil {
+		log.Fatal().Err(err).Msg("state db doesn't match the chain, restore it from backup with -restore")
+	}
 
 	go func() {
 		for {
@@ -259,10 +331,11 @@ func main() {
 			continue
 		}
 
-		if err := blockchainSvc.AddBaseBlock(baseBlock); err != nil {
+		if err := supsvc.CommitBlock(lastBlock, baseBlock, blockchainSvc); err != nil {
 			log.Error().Err(err).Msg("Failed to Add Base Block")
 			continue
 		}
+		subscriptions.Publish(baseBlock)
 
 		var (
 			pbbh cmn.HexBytes = baseBlock.Header.LastBlockID.BlockHash
@@ -0,0 +1,166 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	nlog "log"
+	"path/filepath"
+	"strconv"
+
+	"github.com/herdius/herdius-core/asset"
+	blockProtobuf "github.com/herdius/herdius-core/blockchain/protobuf"
+	"github.com/herdius/herdius-core/config"
+	"github.com/herdius/herdius-core/hbi/message"
+	protoplugin "github.com/herdius/herdius-core/hbi/protobuf"
+	cmn "github.com/herdius/herdius-core/libs/common"
+	"github.com/herdius/herdius-core/p2p/crypto"
+	keystore "github.com/herdius/herdius-core/p2p/key"
+	"github.com/herdius/herdius-core/p2p/log"
+	"github.com/herdius/herdius-core/p2p/network"
+	"github.com/herdius/herdius-core/p2p/network/discovery"
+	"github.com/herdius/herdius-core/p2p/types/opcode"
+	"github.com/herdius/herdius-core/transition"
+	"github.com/herdius/herdius-core/types"
+	val "github.com/herdius/herdius-core/validator/service"
+)
+
+var (
+	nodeKeydir = "./cmd/testdata/secp205k1Accts/"
+)
+
+var (
+	valsvc            *val.Validator
+	supervisorAddress string
+)
+
+// ValidatorMessagePlugin verifies and votes on the child blocks sent by the supervisor.
+type ValidatorMessagePlugin struct{ *network.Plugin }
+
+func init() {
+	nlog.SetFlags(nlog.LstdFlags | nlog.Lshortfile)
+}
+
+// Receive handles each received message from the supervisor
+func (state *ValidatorMessagePlugin) Receive(ctx *network.PluginContext) error {
+	switch msg := ctx.Message().(type) {
+	case *blockProtobuf.ChildBlockMessage:
+		sender := ctx.Sender().Address
+		if sender != supervisorAddress {
+			log.Warn().Msgf("<%s> Ignoring child block from unknown supervisor", sender)
+			return nil
+		}
+		var cbhash cmn.HexBytes = msg.GetChildBlock().GetHeader().GetBlockID().GetBlockHash()
+		reply := valsvc.ProcessChildBlock(msg)
+		if reply.GetVote().GetSignedCurrentBlock() {
+			log.Info().Msgf("<%s> Child block verified and signed: %v", sender, cbhash)
+		} else {
+			log.Info().Msgf("<%s> Child block rejected: %v", sender, cbhash)
+		}
+		if err := ctx.Reply(network.WithSignMessage(context.Background(), true), reply); err != nil {
+			return fmt.Errorf("failed to reply to supervisor: %v", err)
+		}
+	case *blockProtobuf.ConnectionMessage:
+		log.Info().Msgf("<%s> %s", ctx.Sender().Address, msg.Message)
+	}
+	return nil
+}
+
+func main() {
+	supervisorFlag := flag.String("supervisor", "", "address of the supervisor to validate child blocks for")
+	portFlag := flag.Int("port", 0, "port to bind validator to")
+	envFlag := flag.String("env", "dev", "environment to build network and run process for")
+	nodeKeyFlag := flag.String("nodekey", "", "node key file (defaults to <ip>_<port>_sk_peer_id.json in the node key dir)")
+	passphraseFlag := flag.String("passphrase", "", "passphrase of the encrypted node key")
+	passphraseFileFlag := flag.String("passphrasefile", "", "file holding the passphrase of the encrypted node key")
+
+	flag.Parse()
+
+	port := *portFlag
+	env := *envFlag
+	cfg := config.GetConfiguration(env)
+
+	if len(*supervisorFlag) == 0 {
+		log.Fatal().Msg("supervisor address is required")
+		return
+	}
+	var err error
+	supervisorAddress, err = network.ToUnifiedAddress(*supervisorFlag)
+	if err != nil {
+		log.Fatal().Err(err).Msg("invalid supervisor address")
+		return
+	}
+
+	if port == 0 {
+		port = cfg.SelfBroadcastPort
+	}
+
+	transition.Configure(cfg.ChainID, cfg.LegacySignHeight)
+
+	// Validators apply txs by the same assets as the supervisor
+	if len(cfg.Assets) > 0 {
+		registry, err := asset.NewRegistry(cfg.Assets...)
+		if err != nil {
+			log.Fatal().Err(err).Msg("failed to load the configured assets")
+		}
+		asset.SetRegistry(registry)
+	}
+
+	// Generate or Load Keys
+	nodeAddress := cfg.SelfBroadcastIP + "_" + strconv.Itoa(port)
+	nodeKeyPath := *nodeKeyFlag
+	if len(nodeKeyPath) == 0 {
+		nodeKeyPath = nodeKeydir + nodeAddress + "_sk_peer_id.json"
+	}
+	nodekey, err := keystore.UnlockNodeKey(nodeKeyPath, *passphraseFlag, *passphraseFileFlag)
+	if err != nil {
+		log.Fatal().Msgf("Failed to create or load node key: %v", err)
+		return
+	}
+	privKey := nodekey.PrivKey
+	pubKey := privKey.PubKey()
+	keys := &crypto.KeyPair{
+		PublicKey:  pubKey.Bytes(),
+		PrivateKey: privKey.Bytes(),
+		PrivKey:    privKey,
+		PubKey:     pubKey,
+	}
+
+	opcode.RegisterMessageType(types.OpcodeChildBlockMessage, &blockProtobuf.ChildBlockMessage{})
+	opcode.RegisterMessageType(types.OpcodeConnectionMessage, &blockProtobuf.ConnectionMessage{})
+	opcode.RegisterMessageType(types.OpcodePing, &blockProtobuf.Ping{})
+	opcode.RegisterMessageType(types.OpcodePong, &blockProtobuf.Pong{})
+	opcode.RegisterMessageType(types.OpcodeTxRequest, &protoplugin.TxRequest{})
+	opcode.RegisterMessageType(types.OpcodeTxResponse, &protoplugin.TxResponse{})
+
+	address := network.FormatAddress(cfg.Protocol, cfg.SelfBroadcastIP, uint16(port))
+	builder := network.NewBuilderWithOptions(network.Address(address))
+	builder.SetKeys(keys)
+	builder.SetAddress(address)
+
+	// Register peer discovery plugin, persisting the routing table.
+	builder.AddPlugin(&discovery.Plugin{
+		RoutesPath: filepath.Join(cfg.RoutingTableDir, nodeAddress+"_routes.json"),
+		SeedNodes:  cfg.SeedNodes,
+	})
+
+	// Add validator plugin.
+	builder.AddPlugin(new(ValidatorMessagePlugin))
+
+	// Accept txs from clients on behalf of the supervisor.
+	builder.AddPlugin(&message.TxForwardPlugin{Supervisor: supervisorAddress})
+
+	net, err := builder.Build()
+	if err != nil {
+		log.Fatal().Err(err).Msg("failed to build network")
+		return
+	}
+	valsvc = val.NewValidator(keys, net.Address)
+
+	go net.Listen()
+	defer net.Close()
+
+	log.Info().Msgf("Validator connecting to supervisor: %v", supervisorAddress)
+	c := new(network.ConnTester)
+	c.IsConnected(net, []string{supervisorAddress})
+}
@@ -6,10 +6,13 @@ import (
 	"os"
 	"sync"
 
+	"github.com/herdius/herdius-core/asset"
 	"github.com/spf13/viper"
 )
 
 type detail struct {
+	ChainID           string //ID of the chain txs have to be signed for
+	LegacySignHeight  int64  //Height from which legacy signed txs are rejected, 0 to reject them in every block
 	SelfBroadcastIP   string //The IP to broadcast to network which host can accept traffic
 	SelfBroadcastPort int    //The Port to broadcast to network which host can accept traffic
 	Protocol          string //Only `tcp` supported at the moment
@@ -20,6 +23,11 @@ type detail struct {
 	LevelDB           string
 	NodeKeyDir        string
 	S3Bucket          string
+	BackupDir         string        // Directory to back up to instead of S3, e.g. an NFS mount
+	BanListPath       string        // File the banned peers are persisted to
+	RoutingTableDir   string        // Directory the DHT routing tables are persisted to
+	SeedNodes         []string      // Addresses of nodes to bootstrap peer discovery from
+	Assets            []asset.Asset // Supported assets, the defaults if none are configured
 }
 
 // GetConfiguration ...
@@ -39,6 +47,8 @@ func GetConfiguration(env string) *detail {
 			log.Printf("Config file not found: %v", err)
 		} else {
 			configuration = &detail{
+				ChainID:           viper.GetString(fmt.Sprint(env, ".chainid")),
+				LegacySignHeight:  viper.GetInt64(fmt.Sprint(env, ".legacysignheight")),
 				SelfBroadcastIP:   viper.GetString(fmt.Sprint(env, ".selfbroadcastip")),
 				SelfBroadcastPort: viper.GetInt(fmt.Sprint(env, ".selfbroadcastport")),
 				Protocol:          viper.GetString(fmt.Sprint(env, ".protocol")),
@@ -49,6 +59,13 @@ func GetConfiguration(env string) *detail {
 				LevelDB:           viper.GetString(fmt.Sprint(env, ".leveldb")),
 				NodeKeyDir:        viper.GetString(fmt.Sprint(env, ".nodekeydir")),
 				S3Bucket:          viper.GetString(fmt.Sprint(env, ".s3backupbucket")),
+				BackupDir:         viper.GetString(fmt.Sprint(env, ".backupdir")),
+				BanListPath:       viper.GetString(fmt.Sprint(env, ".banlistpath")),
+				RoutingTableDir:   viper.GetString(fmt.Sprint(env, ".routingtabledir")),
+				SeedNodes:         viper.GetStringSlice(fmt.Sprint(env, ".seednodes")),
+			}
+			if err := viper.UnmarshalKey(fmt.Sprint(env, ".assets"), &configuration.Assets); err != nil {
+				log.Printf("Failed to read the configured assets: %v", err)
 			}
 		}
 	})
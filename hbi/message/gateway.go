@@ -0,0 +1,271 @@
+package message
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/golang/protobuf/jsonpb"
+	"github.com/golang/protobuf/proto"
+
+	"github.com/herdius/herdius-core/blockchain"
+	protoplugin "github.com/herdius/herdius-core/hbi/protobuf"
+	plog "github.com/herdius/herdius-core/p2p/log"
+	"github.com/herdius/herdius-core/storage/mempool"
+)
+
+// maxRequestBodySize limits the size of tx submit and update requests
+const maxRequestBodySize = 1 << 20
+
+// Gateway serves the HBI requests over HTTP with JSON bodies. Each endpoint
+// maps onto one of the p2p requests and is answered by the same handler:
+//
+//	GET    /v1/accounts/{address}               AccountRequest
+//	GET    /v1/accounts/{address}/txs           TxsByAddressRequest
+//	GET    /v1/accounts/{address}/txs/{asset}   TxsByAssetAndAddressRequest
+//	GET    /v1/blocks/latest                    LastBlockRequest
+//	GET    /v1/blocks/{height}                  BlockHeightRequest
+//	GET    /v1/blocks/{height}/txs              TxsByBlockHeightRequest
+//	GET    /v1/blocks/{height}/txs/locked       TxLockedRequest
+//	GET    /v1/blocks/{height}/txs/redeemed     TxRedeemRequest
+//	GET    /v1/txs/{id}                         TxDetailRequest
+//	POST   /v1/txs                              TxRequest
+//	PUT    /v1/txs/{id}                         TxUpdateRequest
+//	DELETE /v1/txs/{id}                         TxDeleteRequest
+//
+// Messages are encoded with the protobuf JSON mapping using the field names
+// of hbi/protobuf/service.proto.
+type Gateway struct {
+	supervisorAddress string
+	marshaler         jsonpb.Marshaler
+	unmarshaler       jsonpb.Unmarshaler
+}
+
+// NewGateway returns the HTTP gateway of the supervisor at supervisorAddress
+func NewGateway(supervisorAddress string) *Gateway {
+	return &Gateway{
+		supervisorAddress: supervisorAddress,
+		marshaler:         jsonpb.Marshaler{OrigName: true, EmitDefaults: true},
+	}
+}
+
+// ServeHTTP routes the request to its handler
+func (g *Gateway) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	path := strings.Trim(r.URL.Path, "/")
+	if !strings.HasPrefix(path, "v1/") {
+		g.writeError(w, http.StatusNotFound, fmt.Errorf("unknown endpoint: %s", r.URL.Path))
+		return
+	}
+	parts := strings.Split(strings.TrimPrefix(path, "v1/"), "/")
+	switch parts[0] {
+	case "accounts":
+		g.serveAccounts(w, r, parts[1:])
+	case "blocks":
+		g.serveBlocks(w, r, parts[1:])
+	case "txs":
+		g.serveTxs(w, r, parts[1:])
+	default:
+		g.writeError(w, http.StatusNotFound, fmt.Errorf("unknown endpoint: %s", r.URL.Path))
+	}
+}
+
+func (g *Gateway) serveAccounts(w http.ResponseWriter, r *http.Request, parts []string) {
+	if len(parts) == 0 || len(parts) > 3 || len(parts[0]) == 0 || (len(parts) > 1 && parts[1] != "txs") {
+		g.writeError(w, http.StatusNotFound, fmt.Errorf("unknown endpoint: %s", r.URL.Path))
+		return
+	}
+	if !g.allowMethods(w, r, http.MethodGet) {
+		return
+	}
+	address := parts[0]
+	switch len(parts) {
+	case 1:
+		accountRes, err := accountResponse(address)
+		if err != nil {
+			g.writeError(w, statusOf(err), err)
+			return
+		}
+		if accountRes == nil {
+			g.writeError(w, http.StatusNotFound, fmt.Errorf("account %s not found", address))
+			return
+		}
+		g.write(w, http.StatusOK, accountRes)
+	case 2:
+		txs, err := txsByAddress(address)
+		g.writeResult(w, txs, err)
+	case 3:
+		txs, err := txsByAssetAndAddress(parts[2], address)
+		g.writeResult(w, txs, err)
+	}
+}
+
+func (g *Gateway) serveBlocks(w http.ResponseWriter, r *http.Request, parts []string) {
+	if len(parts) == 0 || len(parts) > 3 || (len(parts) > 1 && parts[1] != "txs") {
+		g.writeError(w, http.StatusNotFound, fmt.Errorf("unknown endpoint: %s", r.URL.Path))
+		return
+	}
+	if !g.allowMethods(w, r, http.MethodGet) {
+		return
+	}
+	blockchainSvc := &blockchain.Service{}
+	if parts[0] == "latest" && len(parts) == 1 {
+		g.write(w, http.StatusOK, blockResponse(blockchainSvc.GetLastBlock(), g.supervisorAddress))
+		return
+	}
+	height, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil || height < 0 {
+		g.writeError(w, http.StatusBadRequest, fmt.Errorf("invalid block height: %s", parts[0]))
+		return
+	}
+
+	txSvc := &blockchain.TxService{}
+	switch {
+	case len(parts) == 1:
+		block, err := blockchainSvc.GetBlockByHeight(height)
+		if err != nil {
+			g.writeError(w, statusOf(err), err)
+			return
+		}
+		if block.GetHeader() == nil {
+			g.writeError(w, http.StatusNotFound, fmt.Errorf("%w: block number %d", blockchain.ErrBlockNotFound, height))
+			return
+		}
+		g.write(w, http.StatusOK, blockResponse(block, g.supervisorAddress))
+	case len(parts) == 2:
+		txs, err := txSvc.GetTxsByHeight(height)
+		g.writeResult(w, txs, err)
+	case parts[2] == "locked":
+		txs, err := txSvc.GetLockedTxsByBlockNumber(height)
+		g.writeResult(w, txs, err)
+	case parts[2] == "redeemed":
+		txs, err := txSvc.GetRedeemTxsByBlockNumber(height)
+		g.writeResult(w, txs, err)
+	default:
+		g.writeError(w, http.StatusNotFound, fmt.Errorf("unknown endpoint: %s", r.URL.Path))
+	}
+}
+
+func (g *Gateway) serveTxs(w http.ResponseWriter, r *http.Request, parts []string) {
+	if len(parts) == 0 {
+		if !g.allowMethods(w, r, http.MethodPost) {
+			return
+		}
+		req := &protoplugin.TxRequest{}
+		if !g.readBody(w, r, req) {
+			return
+		}
+		txRes, err := submitTx(req.GetTx())
+		g.writeWithError(w, txRes, err)
+		return
+	}
+	if len(parts) > 1 {
+		g.writeError(w, http.StatusNotFound, fmt.Errorf("unknown endpoint: %s", r.URL.Path))
+		return
+	}
+
+	id := parts[0]
+	switch r.Method {
+	case http.MethodGet:
+		txSvc := &blockchain.TxService{}
+		txDetailRes, err := txSvc.GetTx(id)
+		if err != nil {
+			g.writeError(w, statusOf(err), err)
+			return
+		}
+		if len(txDetailRes.GetTxId()) == 0 {
+			g.writeError(w, http.StatusNotFound, fmt.Errorf("%w: %s", errTxNotFound, id))
+			return
+		}
+		g.write(w, http.StatusOK, txDetailRes)
+	case http.MethodPut:
+		req := &protoplugin.TxUpdateRequest{}
+		if !g.readBody(w, r, req) {
+			return
+		}
+		txUpdateRes, err := updateTx(id, req.GetTx())
+		g.writeWithError(w, txUpdateRes, err)
+	case http.MethodDelete:
+		txDeleteRes, err := deleteTx(id)
+		g.writeWithError(w, txDeleteRes, err)
+	default:
+		g.allowMethods(w, r, http.MethodGet, http.MethodPut, http.MethodDelete)
+	}
+}
+
+// allowMethods replies with 405 and returns false unless the request method is one of methods
+func (g *Gateway) allowMethods(w http.ResponseWriter, r *http.Request, methods ...string) bool {
+	for _, method := range methods {
+		if r.Method == method {
+			return true
+		}
+	}
+	w.Header().Set("Allow", strings.Join(methods, ", "))
+	g.writeError(w, http.StatusMethodNotAllowed, fmt.Errorf("method %s not allowed", r.Method))
+	return false
+}
+
+// readBody decodes the JSON request body into msg, replying with 400 if it can't
+func (g *Gateway) readBody(w http.ResponseWriter, r *http.Request, msg proto.Message) bool {
+	body := http.MaxBytesReader(w, r.Body, maxRequestBodySize)
+	if err := g.unmarshaler.Unmarshal(body, msg); err != nil {
+		g.writeError(w, http.StatusBadRequest, fmt.Errorf("invalid request body: %v", err))
+		return false
+	}
+	return true
+}
+
+// writeResult writes msg, or the error if err is not nil
+func (g *Gateway) writeResult(w http.ResponseWriter, msg proto.Message, err error) {
+	if err != nil {
+		g.writeError(w, statusOf(err), err)
+		return
+	}
+	g.write(w, http.StatusOK, msg)
+}
+
+// writeWithError writes msg with the status of err. The message of requests
+// that change the memory pool tells the client why they failed.
+func (g *Gateway) writeWithError(w http.ResponseWriter, msg proto.Message, err error) {
+	status := http.StatusOK
+	if err != nil {
+		status = statusOf(err)
+	}
+	g.write(w, status, msg)
+}
+
+func (g *Gateway) write(w http.ResponseWriter, status int, msg proto.Message) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := g.marshaler.Marshal(w, msg); err != nil {
+		plog.Error().Msgf("Failed to write HTTP response: %v", err)
+	}
+}
+
+func (g *Gateway) writeError(w http.ResponseWriter, status int, err error) {
+	if status >= http.StatusInternalServerError {
+		plog.Error().Msgf("HTTP request failed: %v", err)
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+}
+
+// statusOf returns the HTTP status of a failed request
+func statusOf(err error) int {
+	switch {
+	case errors.Is(err, errTxNotFound), errors.Is(err, blockchain.ErrBlockNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, mempool.ErrTxDrained):
+		return http.StatusConflict
+	case errors.Is(err, mempool.ErrMemPoolFull):
+		return http.StatusServiceUnavailable
+	case errors.Is(err, errTxRejected), errors.Is(err, mempool.ErrTxExists),
+		errors.Is(err, mempool.ErrNonceExists), errors.Is(err, mempool.ErrNonceTooLow):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,77 @@
+package message
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/herdius/herdius-core/blockchain"
+	"github.com/herdius/herdius-core/storage/mempool"
+)
+
+func serve(method, path, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	rec := httptest.NewRecorder()
+	NewGateway("tcp://127.0.0.1:3000").ServeHTTP(rec, req)
+	return rec
+}
+
+func TestGatewayRouting(t *testing.T) {
+	cases := []struct {
+		method, path, body string
+		status             int
+	}{
+		{http.MethodGet, "/", "", http.StatusNotFound},
+		{http.MethodGet, "/v1/unknown", "", http.StatusNotFound},
+		{http.MethodGet, "/v1/accounts", "", http.StatusNotFound},
+		{http.MethodGet, "/v1/accounts/HHy1/balances", "", http.StatusNotFound},
+		{http.MethodPost, "/v1/accounts/HHy1", "", http.StatusMethodNotAllowed},
+		{http.MethodGet, "/v1/blocks/abc", "", http.StatusBadRequest},
+		{http.MethodGet, "/v1/blocks/-1", "", http.StatusBadRequest},
+		{http.MethodGet, "/v1/blocks/1/txs/unknown", "", http.StatusNotFound},
+		{http.MethodDelete, "/v1/blocks/latest", "", http.StatusMethodNotAllowed},
+		{http.MethodGet, "/v1/txs", "", http.StatusMethodNotAllowed},
+		{http.MethodPost, "/v1/txs/HTx1", "", http.StatusMethodNotAllowed},
+		{http.MethodGet, "/v1/txs/HTx1/detail", "", http.StatusNotFound},
+		{http.MethodPost, "/v1/txs", "not json", http.StatusBadRequest},
+		{http.MethodPut, "/v1/txs/HTx1", `{"tx": 1}`, http.StatusBadRequest},
+	}
+	for _, c := range cases {
+		rec := serve(c.method, c.path, c.body)
+		assert.Equal(t, c.status, rec.Code, "%s %s", c.method, c.path)
+		assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+		assert.Contains(t, rec.Body.String(), `"error"`)
+	}
+}
+
+func TestGatewayTxNotInMemPool(t *testing.T) {
+	rec := serve(http.MethodDelete, "/v1/txs/HTx-missing", "")
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"status":false`)
+	assert.Contains(t, rec.Body.String(), `"error":"Unable to find Tx (id: HTx-missing) in memory pool"`)
+
+	rec = serve(http.MethodPut, "/v1/txs/HTx-missing", `{"tx": {"sender_address": "HHy1"}}`)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	assert.Contains(t, rec.Body.String(), "does not exist in memory pool")
+}
+
+func TestStatusOf(t *testing.T) {
+	cases := map[error]int{
+		fmt.Errorf("%w: bad nonce", errTxRejected):                    http.StatusBadRequest,
+		fmt.Errorf("failed: %w", mempool.ErrNonceExists):              http.StatusBadRequest,
+		fmt.Errorf("%w: id", errTxNotFound):                           http.StatusNotFound,
+		&txNotFoundError{"Unable to find Tx"}:                         http.StatusNotFound,
+		fmt.Errorf("%w: block number 9", blockchain.ErrBlockNotFound): http.StatusNotFound,
+		fmt.Errorf("failed: %w", mempool.ErrTxDrained):                http.StatusConflict,
+		fmt.Errorf("failed: %w", mempool.ErrMemPoolFull):              http.StatusServiceUnavailable,
+		errors.New("db closed"):                                       http.StatusInternalServerError,
+	}
+	for err, status := range cases {
+		assert.Equal(t, status, statusOf(err), err.Error())
+	}
+}
@@ -0,0 +1,230 @@
+package message
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+
+	protoplugin "github.com/herdius/herdius-core/hbi/protobuf"
+	cmn "github.com/herdius/herdius-core/libs/common"
+	plog "github.com/herdius/herdius-core/p2p/log"
+	"github.com/herdius/herdius-core/p2p/network"
+	"github.com/herdius/herdius-core/p2p/types/lru"
+)
+
+const (
+	defaultTxGossipFanout    = 3
+	defaultTxGossipCacheSize = 10000
+	txFetchTimeout           = 10 * time.Second
+)
+
+// TxGossipPluginID is used to check existence of the tx gossip plugin
+var TxGossipPluginID = (*TxGossipPlugin)(nil)
+
+// TxGossipPlugin propagates txs between nodes. The ids of the txs a node
+// accepts are announced to random peers, which fetch the txs they don't know
+// of from the announcer, then accept and announce them in turn.
+type TxGossipPlugin struct {
+	*network.Plugin
+
+	// Fanout is the number of peers a tx is announced to
+	Fanout int
+	// CacheSize is the number of tx ids and txs the node remembers
+	CacheSize int
+
+	// accept validates a tx and adds it to the memory pool
+	accept func(*protoplugin.Tx) (*protoplugin.TxResponse, error)
+	// known holds the ids of the txs the node accepted and announced. Known
+	// txs aren't fetched anymore.
+	known *lru.Cache
+	// txs holds the txs accepted by the node, served to its peers
+	txs *lru.Cache
+
+	// inFlight holds the ids of the txs being fetched. A tx is only fetched
+	// from one announcer at a time, and from the next announcer should the
+	// fetch fail or the tx be rejected.
+	mu       sync.Mutex
+	inFlight map[string]bool
+}
+
+// Startup sets the defaults of unset fields
+func (p *TxGossipPlugin) Startup(net *network.Network) {
+	if p.Fanout <= 0 {
+		p.Fanout = defaultTxGossipFanout
+	}
+	if p.CacheSize <= 0 {
+		p.CacheSize = defaultTxGossipCacheSize
+	}
+	if p.accept == nil {
+		p.accept = submitTx
+	}
+	p.known = lru.NewCache(p.CacheSize)
+	p.txs = lru.NewCache(p.CacheSize)
+	p.inFlight = make(map[string]bool)
+}
+
+// Receive handles tx announcements and fetch requests
+func (p *TxGossipPlugin) Receive(ctx *network.PluginContext) error {
+	switch msg := ctx.Message().(type) {
+	case *protoplugin.TxAnnouncement:
+		return p.fetch(ctx.Client(), msg.GetTxIds())
+
+	case *protoplugin.TxFetchRequest:
+		res := &protoplugin.TxFetchResponse{}
+		for _, id := range msg.GetTxIds() {
+			if tx, ok := p.txs.Peek(id); ok {
+				res.Txs = append(res.Txs, tx.(*protoplugin.Tx))
+			}
+		}
+		if err := ctx.Reply(network.WithSignMessage(context.Background(), true), res); err != nil {
+			return fmt.Errorf("failed to reply to tx fetch request: %v", err)
+		}
+	}
+	return nil
+}
+
+// Announce announces a tx accepted by the node to random peers
+func (p *TxGossipPlugin) Announce(net *network.Network, id string, tx *protoplugin.Tx) {
+	p.markKnown(id)
+	p.txs.Get(id, func() (interface{}, error) {
+		return proto.Clone(tx), nil
+	})
+	net.BroadcastRandomly(network.WithSignMessage(context.Background(), true), &protoplugin.TxAnnouncement{TxIds: []string{id}}, p.Fanout)
+}
+
+// markKnown records id as known to the node
+func (p *TxGossipPlugin) markKnown(id string) {
+	p.known.Get(id, func() (interface{}, error) {
+		return struct{}{}, nil
+	})
+}
+
+// claim returns true if id is neither known nor being fetched, marking it in
+// flight
+func (p *TxGossipPlugin) claim(id string) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if _, ok := p.known.Peek(id); ok || p.inFlight[id] {
+		return false
+	}
+	p.inFlight[id] = true
+	return true
+}
+
+// release ends the fetches of ids. Those which weren't accepted, and so
+// marked known, may be fetched again from a later announcer.
+func (p *TxGossipPlugin) release(ids []string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for _, id := range ids {
+		delete(p.inFlight, id)
+	}
+}
+
+// fetch requests the txs of ids unknown to the node from client, then accepts
+// and announces them
+func (p *TxGossipPlugin) fetch(client *network.PeerClient, ids []string) error {
+	requested := make(map[string]bool)
+	var unknown []string
+	for _, id := range ids {
+		if p.claim(id) {
+			requested[id] = true
+			unknown = append(unknown, id)
+		}
+	}
+	if len(unknown) == 0 {
+		return nil
+	}
+	defer p.release(unknown)
+
+	ctx, cancel := context.WithTimeout(network.WithSignMessage(context.Background(), true), txFetchTimeout)
+	defer cancel()
+	reply, err := client.Request(ctx, &protoplugin.TxFetchRequest{TxIds: unknown})
+	if err != nil {
+		return fmt.Errorf("failed to fetch txs from %v: %v", client.Address, err)
+	}
+	res, ok := reply.(*protoplugin.TxFetchResponse)
+	if !ok {
+		return fmt.Errorf("unexpected response to tx fetch request: %T", reply)
+	}
+
+	for _, tx := range res.GetTxs() {
+		id, err := txID(tx)
+		if err != nil || !requested[id] {
+			plog.Warn().Msgf("<%s> Peer sent a tx which wasn't requested", client.Address)
+			continue
+		}
+		delete(requested, id)
+		if _, err := p.accept(tx); err != nil {
+			plog.Info().Msgf("Gossiped tx %v rejected: %v", id, err)
+			continue
+		}
+		p.Announce(client.Network, id, tx)
+	}
+	return nil
+}
+
+// txID returns the id of tx, as returned to the client which sent it
+func txID(tx *protoplugin.Tx) (string, error) {
+	txbz, err := cdc.MarshalJSON(tx)
+	if err != nil {
+		return "", err
+	}
+	return cmn.CreateTxID(txbz), nil
+}
+
+// announceTx announces a tx accepted from a client, should the tx gossip
+// plugin be registered
+func announceTx(net *network.Network, id string, tx *protoplugin.Tx) {
+	if plugin, ok := net.Plugin(TxGossipPluginID); ok {
+		plugin.(*TxGossipPlugin).Announce(net, id, tx)
+	}
+}
+
+// TxForwardPlugin lets a node without a memory pool, like a validator, accept
+// txs from clients by forwarding them to its supervisor, which replies.
+type TxForwardPlugin struct {
+	*network.Plugin
+
+	// Supervisor is the address of the supervisor txs are forwarded to
+	Supervisor string
+}
+
+// Receive forwards tx requests to the supervisor
+func (p *TxForwardPlugin) Receive(ctx *network.PluginContext) error {
+	msg, ok := ctx.Message().(*protoplugin.TxRequest)
+	if !ok {
+		return nil
+	}
+
+	txRes, err := p.forward(ctx.Network(), msg)
+	if err != nil {
+		txRes = &protoplugin.TxResponse{Status: "failed", Message: err.Error()}
+	}
+	if errRep := ctx.Reply(network.WithSignMessage(context.Background(), true), txRes); errRep != nil {
+		return fmt.Errorf("Failed to reply to client :%v", errRep)
+	}
+	return err
+}
+
+// forward sends a tx request to the supervisor and returns its response
+func (p *TxForwardPlugin) forward(net *network.Network, req *protoplugin.TxRequest) (*protoplugin.TxResponse, error) {
+	supervisor, err := net.Client(p.Supervisor)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to the supervisor: %v", err)
+	}
+	ctx, cancel := context.WithTimeout(network.WithSignMessage(context.Background(), true), txFetchTimeout)
+	defer cancel()
+	reply, err := supervisor.Request(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to forward tx to the supervisor: %v", err)
+	}
+	txRes, ok := reply.(*protoplugin.TxResponse)
+	if !ok {
+		return nil, fmt.Errorf("unexpected response to tx request: %T", reply)
+	}
+	return txRes, nil
+}
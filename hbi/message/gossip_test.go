@@ -0,0 +1,247 @@
+package message
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	protoplugin "github.com/herdius/herdius-core/hbi/protobuf"
+	"github.com/herdius/herdius-core/p2p/crypto/ed25519"
+	"github.com/herdius/herdius-core/p2p/network"
+	"github.com/herdius/herdius-core/p2p/network/transport"
+	"github.com/herdius/herdius-core/p2p/types/opcode"
+	"github.com/herdius/herdius-core/types"
+)
+
+func init() {
+	opcode.RegisterMessageType(types.OpcodeTxRequest, &protoplugin.TxRequest{})
+	opcode.RegisterMessageType(types.OpcodeTxResponse, &protoplugin.TxResponse{})
+	opcode.RegisterMessageType(types.OpcodeTxAnnouncement, &protoplugin.TxAnnouncement{})
+	opcode.RegisterMessageType(types.OpcodeTxFetchRequest, &protoplugin.TxFetchRequest{})
+	opcode.RegisterMessageType(types.OpcodeTxFetchResponse, &protoplugin.TxFetchResponse{})
+}
+
+// acceptor records the txs accepted by a node, rejecting txs from "rejected"
+type acceptor struct {
+	mutex    sync.Mutex
+	accepted []string
+	rejected int
+}
+
+func (a *acceptor) accept(tx *protoplugin.Tx) (*protoplugin.TxResponse, error) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	if tx.GetSenderAddress() == "rejected" {
+		a.rejected++
+		return nil, errTxRejected
+	}
+	a.accepted = append(a.accepted, tx.GetSenderAddress())
+	return &protoplugin.TxResponse{Status: "success"}, nil
+}
+
+func (a *acceptor) counts() (int, int) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	return len(a.accepted), a.rejected
+}
+
+func newMemoryNode(t *testing.T, mem *transport.MemoryNetwork, port uint16, plugins ...network.PluginInterface) *network.Network {
+	builder := network.NewBuilder()
+	builder.ClearTransportLayers()
+	builder.RegisterTransportLayer("tcp", mem.Transport())
+	builder.SetKeys(ed25519.RandomKeyPair())
+	builder.SetAddress(network.FormatAddress("tcp", "127.0.0.1", port))
+	for _, plugin := range plugins {
+		require.NoError(t, builder.AddPlugin(plugin))
+	}
+	node, err := builder.Build()
+	require.NoError(t, err)
+	go node.Listen()
+	node.BlockUntilListening()
+	return node
+}
+
+// connect connects a and b both ways. Peers handle messages once they
+// received one.
+func connect(t *testing.T, a, b *network.Network) {
+	for _, pair := range [][2]*network.Network{{a, b}, {b, a}} {
+		client, err := pair[0].Client(pair[1].Address)
+		require.NoError(t, err)
+		require.NoError(t, client.Tell(context.Background(), &protoplugin.TxAnnouncement{}))
+	}
+	for _, pair := range [][2]*network.Network{{a, b}, {b, a}} {
+		client, err := pair[0].Client(pair[1].Address)
+		require.NoError(t, err)
+		require.True(t, waitUntil(func() bool { return client.IsIncomingReady() }))
+	}
+}
+
+// waitUntil polls cond for at most 5 seconds
+func waitUntil(cond func() bool) bool {
+	deadline := time.Now().Add(5 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			return false
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	return true
+}
+
+func TestTxGossip(t *testing.T) {
+	mem := transport.NewMemoryNetwork(1)
+	var nodes []*network.Network
+	var plugins []*TxGossipPlugin
+	var acceptors []*acceptor
+	for i := 0; i < 3; i++ {
+		acceptor := new(acceptor)
+		plugin := &TxGossipPlugin{accept: acceptor.accept}
+		node := newMemoryNode(t, mem, uint16(3000+i), plugin)
+		defer node.Close()
+		nodes = append(nodes, node)
+		plugins = append(plugins, plugin)
+		acceptors = append(acceptors, acceptor)
+	}
+	// The first node only reaches the last one through the second
+	connect(t, nodes[0], nodes[1])
+	connect(t, nodes[1], nodes[2])
+
+	tx := &protoplugin.Tx{SenderAddress: "sender", Asset: &protoplugin.Asset{Symbol: "HER", Value: 1}}
+	id, err := txID(tx)
+	require.NoError(t, err)
+	plugins[0].Announce(nodes[0], id, tx)
+	require.True(t, waitUntil(func() bool {
+		accepted, _ := acceptors[2].counts()
+		return accepted == 1
+	}), "the tx should reach the last node")
+	time.Sleep(200 * time.Millisecond)
+	for i, acceptor := range acceptors {
+		accepted, _ := acceptor.counts()
+		if i == 0 {
+			assert.Equal(t, 0, accepted, "the announcer doesn't fetch its own tx back")
+		} else {
+			assert.Equal(t, 1, accepted, "each node accepts the tx once")
+		}
+	}
+	served, ok := plugins[2].txs.Peek(id)
+	require.True(t, ok)
+	assert.Equal(t, "sender", served.(*protoplugin.Tx).GetSenderAddress())
+
+	// A rejected tx isn't announced further
+	tx = &protoplugin.Tx{SenderAddress: "rejected", Asset: &protoplugin.Asset{Symbol: "HER", Value: 1}}
+	id, err = txID(tx)
+	require.NoError(t, err)
+	plugins[0].Announce(nodes[0], id, tx)
+	require.True(t, waitUntil(func() bool {
+		_, rejected := acceptors[1].counts()
+		return rejected == 1
+	}))
+	time.Sleep(200 * time.Millisecond)
+	_, rejected := acceptors[2].counts()
+	assert.Equal(t, 0, rejected)
+}
+
+// withholdingPlugin announces txs but doesn't serve them
+type withholdingPlugin struct {
+	*network.Plugin
+
+	mutex   sync.Mutex
+	fetches int
+}
+
+func (p *withholdingPlugin) Receive(ctx *network.PluginContext) error {
+	if _, ok := ctx.Message().(*protoplugin.TxFetchRequest); ok {
+		p.mutex.Lock()
+		p.fetches++
+		p.mutex.Unlock()
+		return ctx.Reply(context.Background(), &protoplugin.TxFetchResponse{})
+	}
+	return nil
+}
+
+func (p *withholdingPlugin) fetchCount() int {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	return p.fetches
+}
+
+func TestTxGossipWithheldTx(t *testing.T) {
+	mem := transport.NewMemoryNetwork(1)
+	accepted := new(acceptor)
+	plugin := &TxGossipPlugin{accept: accepted.accept}
+	node := newMemoryNode(t, mem, 3000, plugin)
+	defer node.Close()
+	withholding := new(withholdingPlugin)
+	withholder := newMemoryNode(t, mem, 3001, withholding)
+	defer withholder.Close()
+	serving := &TxGossipPlugin{accept: new(acceptor).accept}
+	server := newMemoryNode(t, mem, 3002, serving)
+	defer server.Close()
+	connect(t, node, withholder)
+	connect(t, node, server)
+
+	tx := &protoplugin.Tx{SenderAddress: "sender", Asset: &protoplugin.Asset{Symbol: "HER", Value: 1}}
+	id, err := txID(tx)
+	require.NoError(t, err)
+
+	// The tx the withholder announced but didn't serve isn't known
+	client, err := withholder.Client(node.Address)
+	require.NoError(t, err)
+	require.NoError(t, client.Tell(context.Background(), &protoplugin.TxAnnouncement{TxIds: []string{id}}))
+	require.True(t, waitUntil(func() bool {
+		plugin.mu.Lock()
+		defer plugin.mu.Unlock()
+		return withholding.fetchCount() == 1 && !plugin.inFlight[id]
+	}))
+	_, known := plugin.known.Peek(id)
+	assert.False(t, known)
+
+	// so it is fetched from the next announcer
+	serving.Announce(server, id, tx)
+	require.True(t, waitUntil(func() bool {
+		count, _ := accepted.counts()
+		return count == 1
+	}), "the tx should be fetched from the peer serving it")
+	_, known = plugin.known.Peek(id)
+	assert.True(t, known)
+}
+
+// supervisorPlugin replies to tx requests like a supervisor accepting them
+type supervisorPlugin struct{ *network.Plugin }
+
+func (p *supervisorPlugin) Receive(ctx *network.PluginContext) error {
+	if msg, ok := ctx.Message().(*protoplugin.TxRequest); ok {
+		return ctx.Reply(context.Background(), &protoplugin.TxResponse{TxId: msg.GetTx().GetSenderAddress(), Status: "success"})
+	}
+	return nil
+}
+
+func TestTxForward(t *testing.T) {
+	mem := transport.NewMemoryNetwork(1)
+	supervisor := newMemoryNode(t, mem, 3000, new(supervisorPlugin))
+	defer supervisor.Close()
+	validator := newMemoryNode(t, mem, 3001, &TxForwardPlugin{Supervisor: supervisor.Address})
+	defer validator.Close()
+	client := newMemoryNode(t, mem, 3002)
+	defer client.Close()
+
+	peer, err := client.Client(validator.Address)
+	require.NoError(t, err)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	reply, err := peer.Request(ctx, &protoplugin.TxRequest{Tx: &protoplugin.Tx{SenderAddress: "sender"}})
+	require.NoError(t, err)
+	require.IsType(t, &protoplugin.TxResponse{}, reply)
+	assert.Equal(t, "success", reply.(*protoplugin.TxResponse).GetStatus())
+	assert.Equal(t, "sender", reply.(*protoplugin.TxResponse).GetTxId())
+
+	// Without a supervisor the tx fails
+	mem.Partition([]int{3000})
+	reply, err = peer.Request(ctx, &protoplugin.TxRequest{Tx: &protoplugin.Tx{SenderAddress: "sender"}})
+	require.NoError(t, err)
+	assert.Equal(t, "failed", reply.(*protoplugin.TxResponse).GetStatus())
+}
@@ -0,0 +1,281 @@
+package message
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+
+	"github.com/herdius/herdius-core/accounts/account"
+	"github.com/herdius/herdius-core/asset"
+	"github.com/herdius/herdius-core/blockchain"
+	"github.com/herdius/herdius-core/blockchain/protobuf"
+	protoplugin "github.com/herdius/herdius-core/hbi/protobuf"
+	cmn "github.com/herdius/herdius-core/libs/common"
+	plog "github.com/herdius/herdius-core/p2p/log"
+	"github.com/herdius/herdius-core/storage/mempool"
+)
+
+// The handlers below serve the HBI requests independent of the transport,
+// so the p2p plugins and the HTTP gateway answer them the same way.
+
+var (
+	// errTxRejected is returned when a tx fails validation
+	errTxRejected = errors.New("tx rejected")
+	// errTxNotFound is returned when a tx is not in the memory pool
+	errTxNotFound = errors.New("tx not found")
+)
+
+// txNotFoundError is errTxNotFound with the message sent back to clients
+type txNotFoundError struct {
+	msg string
+}
+
+func (e *txNotFoundError) Error() string { return e.msg }
+
+func (e *txNotFoundError) Unwrap() error { return errTxNotFound }
+
+// accountResponse returns the account at address, or nil if it is not registered
+func accountResponse(address string) (*protoplugin.AccountResponse, error) {
+	accountSvc := &account.Service{}
+	account, err := accountSvc.GetAccountByAddress(address)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve the account: %v", err)
+	}
+	if account == nil {
+		return nil, nil
+	}
+
+	eBalances := make(map[string]*protoplugin.EBalanceAsset)
+	for asset, assetAccount := range account.EBalances {
+		eBalances[asset] = &protoplugin.EBalanceAsset{}
+		eBalances[asset].Asset = make(map[string]*protoplugin.EBalance)
+		for _, eb := range assetAccount.Asset {
+			eBalanceRes := &protoplugin.EBalance{
+				Address:         eb.Address,
+				Balance:         eb.Balance,
+				LastBlockHeight: eb.LastBlockHeight,
+				Nonce:           eb.Nonce,
+			}
+			eBalances[asset].Asset[eb.Address] = eBalanceRes
+		}
+	}
+	return &protoplugin.AccountResponse{
+		Address:              address,
+		Nonce:                account.Nonce,
+		Balance:              account.Balance,
+		StorageRoot:          account.StorageRoot,
+		PublicKey:            account.PublicKey,
+		EBalances:            eBalances,
+		Erc20Address:         account.Erc20Address,
+		ExternalNonce:        account.ExternalNonce,
+		LastBlockHeight:      account.LastBlockHeight,
+		FirstExternalAddress: account.FirstExternalAddress,
+	}, nil
+}
+
+// blockResponse summarizes block for clients. It returns an empty response
+// if the block has no header.
+func blockResponse(block *protobuf.BaseBlock, supervisorAddress string) *protoplugin.BlockResponse {
+	if block.GetHeader() == nil {
+		return &protoplugin.BlockResponse{}
+	}
+	return &protoplugin.BlockResponse{
+		BlockHeight: block.GetHeader().GetHeight(),
+		TotalTxs:    block.GetHeader().GetTotalTxs(),
+		Time: &protoplugin.Timestamp{
+			Nanos:   block.GetHeader().GetTime().GetNanos(),
+			Seconds: block.GetHeader().GetTime().GetSeconds(),
+		},
+		SupervisorAddress: supervisorAddress,
+	}
+}
+
+// txsByAddress returns the txs of the account at address. Unregistered
+// accounts have no txs. The response is never nil.
+func txsByAddress(address string) (*protoplugin.TxsResponse, error) {
+	if ok, err := isRegistered(address); err != nil || !ok {
+		return &protoplugin.TxsResponse{}, err
+	}
+	txSvc := &blockchain.TxService{}
+	txs, err := txSvc.GetTxs(address)
+	if err != nil {
+		return &protoplugin.TxsResponse{}, err
+	}
+	return txs, nil
+}
+
+// txsByAssetAndAddress returns the txs of asset of the account at address
+func txsByAssetAndAddress(asset, address string) (*protoplugin.TxsResponse, error) {
+	if ok, err := isRegistered(address); err != nil || !ok {
+		return &protoplugin.TxsResponse{}, err
+	}
+	txSvc := &blockchain.TxService{}
+	txs, err := txSvc.GetTxsByAssetAndAddress(asset, address)
+	if err != nil {
+		return &protoplugin.TxsResponse{}, err
+	}
+	return txs, nil
+}
+
+func isRegistered(address string) (bool, error) {
+	accSrv := account.NewAccountService()
+	account, err := accSrv.GetAccountByAddress(address)
+	if err != nil {
+		return false, fmt.Errorf("couldn't find the account due to: %v", err)
+	}
+	return account != nil && strings.EqualFold(account.Address, address), nil
+}
+
+// submitTx validates tx against the sender's account and adds it to the
+// memory pool. A rejected tx gets a failed response along with the error.
+func submitTx(tx *protoplugin.Tx) (*protoplugin.TxResponse, error) {
+	failed := func(pending, queue int, msg string, err error) (*protoplugin.TxResponse, error) {
+		return &protoplugin.TxResponse{
+			TxId: "", Status: "failed", Queued: int64(queue), Pending: int64(pending),
+			Message: msg,
+		}, err
+	}
+	reject := func(msg string) (*protoplugin.TxResponse, error) {
+		return failed(0, 0, msg, fmt.Errorf("%w: %s", errTxRejected, msg))
+	}
+	if tx == nil || tx.Asset == nil {
+		return reject("tx has no asset")
+	}
+
+	accSrv := account.NewAccountService()
+	accSrv.SetReceiverAddress(tx.RecieverAddress)
+	accSrv.SetAssetSymbol(tx.Asset.Symbol)
+	accSrv.SetExtAddress(tx.Asset.ExternalSenderAddress)
+	accSrv.SetTxValue(tx.Asset.Value)
+	accSrv.SetTxFee(tx.Asset.Fee)
+	accSrv.SetTxLockedAmount(tx.Asset.LockedAmount)
+	accSrv.SetTxRedeemAmount(tx.Asset.RedeemedAmount)
+	account, err := accSrv.GetAccountByAddress(tx.GetSenderAddress())
+	if err != nil {
+		return failed(0, 0, "Couldn't find the account due to : "+err.Error(),
+			fmt.Errorf("couldn't find the account due to: %v", err))
+	}
+
+	//Check Tx.Nonce > account.Nonce
+	if account != nil && !accSrv.VerifyAccountNonce(account, tx.GetAsset().Nonce) {
+		txNonce := strconv.FormatUint(tx.GetAsset().Nonce, 10)
+		accountNonce := strconv.FormatUint(account.Nonce, 10)
+		return reject("Transaction nonce " + txNonce + " should be greater than account nonce " + accountNonce)
+	}
+	accSrv.SetAccount(account)
+
+	// Check if tx is of type account update
+	// and verify external address exists
+	if strings.EqualFold(tx.Type, Update.String()) {
+		if !strings.EqualFold(tx.Asset.Symbol, "HER") {
+			if err := asset.GetRegistry().ValidateAddress(tx.Asset.Symbol, tx.Asset.ExternalSenderAddress); err != nil {
+				return reject(err.Error())
+			}
+		}
+		if accSrv.AccountExternalAddressExist() {
+			return reject("External account existed: " + tx.Asset.ExternalSenderAddress)
+		}
+		if accSrv.AccountEBalancePerAssetReachLimit() {
+			return reject("Account reached number of addresses limit")
+		}
+	}
+
+	// Check if tx is of type lock
+	// verify if external account address doesn't exists
+	// verify if receiver address is herdius zero address
+	if strings.EqualFold(tx.Type, Lock.String()) {
+		if !accSrv.AccountExternalAddressExist() {
+			return reject("External address does not exist: " + tx.Asset.ExternalSenderAddress)
+		}
+		if !accSrv.IsHerdiusZeroAddress() {
+			return reject("Incorrect herdius zero address: " + tx.RecieverAddress)
+		}
+		if !accSrv.VerifyLockedAmount() {
+			return reject("Account does not have enough locked amount")
+		}
+	}
+
+	// Check if asset has enough balance for transfers
+	// account.Balance >= Tx.Value + Tx.Fee
+	if balanceChecked(tx.Type) && !accSrv.VerifyAccountBalance() {
+		return reject("Not enough balance: " + strconv.FormatUint(tx.GetAsset().Value, 10))
+	}
+
+	// Add Tx to Mempool
+	mp := mempool.GetMemPool()
+	txbz, err := cdc.MarshalJSON(tx)
+	if err != nil {
+		return reject("Incorrect Transaction format : " + tx.GetSenderAddress())
+	}
+	log.Println("Add tx to mempool")
+	pending, queue, err := mp.AddTx(tx, accSrv)
+	if err != nil {
+		msg := "Failed to add tx to memory pool: " + err.Error()
+		if errors.Is(err, mempool.ErrMemPoolFull) {
+			return failed(pending, queue, msg, fmt.Errorf("failed to add tx to memory pool: %w", err))
+		}
+		return failed(pending, queue, msg, fmt.Errorf("%w: %s", errTxRejected, msg))
+	}
+	plog.Info().Msgf("Remaining mempool pending, queue: %+v %+v", pending, queue)
+
+	// Create the Transaction ID
+	txID := cmn.CreateTxID(txbz)
+	plog.Info().Msgf("Tx ID : %v", txID)
+	return &protoplugin.TxResponse{
+		TxId: txID, Status: "success", Queued: int64(queue), Pending: int64(pending),
+	}, nil
+}
+
+// balanceChecked reports whether the value and fee of txs of txType have to
+// be covered by the balance of the sender. An account update registers an
+// external address which has no balance yet, and locks and redeems are
+// checked against their own amounts.
+func balanceChecked(txType string) bool {
+	for _, t := range []TxType{Update, Lock, Redeem} {
+		if strings.EqualFold(txType, t.String()) {
+			return false
+		}
+	}
+	return true
+}
+
+// updateTx replaces the tx with id in the memory pool with newTx
+func updateTx(id string, newTx *protoplugin.Tx) (*protoplugin.TxUpdateResponse, error) {
+	log.Println("Processing request to update Tx, ID:", id)
+	updatedID, updatedTx, err := putTxUpdateRequest(id, newTx)
+	if err != nil {
+		return &protoplugin.TxUpdateResponse{Error: err.Error(), Status: false}, err
+	}
+	return &protoplugin.TxUpdateResponse{Status: true, TxId: updatedID, Tx: updatedTx}, nil
+}
+
+// deleteTx removes the tx with id from the memory pool
+func deleteTx(id string) (*protoplugin.TxUpdateResponse, error) {
+	mp := mempool.GetMemPool()
+	if !mp.DeleteTx(id) {
+		err := &txNotFoundError{fmt.Sprintf("Unable to find Tx (id: %v) in memory pool", id)}
+		return &protoplugin.TxUpdateResponse{Status: false, Error: err.Error()}, err
+	}
+	return &protoplugin.TxUpdateResponse{TxId: id, Status: true}, nil
+}
+
+// putTxUpdateRequest upates the Tx with the input string. After updating, calculates new Tx ID
+func putTxUpdateRequest(id string, newTx *protoplugin.Tx) (string, *protoplugin.Tx, error) {
+	mp := mempool.GetMemPool()
+	origTx := mp.GetTx(id)
+	if origTx == nil {
+		return "", nil, &txNotFoundError{fmt.Sprintf("requested Tx (id: %v) does not exist in memory pool; it may have been flushed from the memory pool into a block", id)}
+	}
+	updatedTx, err := mp.UpdateTx(id, newTx)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to update Tx in MemPool with new values: %w", err)
+	}
+	updatedBz, err := cdc.MarshalJSON(updatedTx)
+	if err != nil {
+		return "", nil, fmt.Errorf("could not marshal updated transaction back into memory pool: %v", err)
+	}
+	newID := cmn.CreateTxID(updatedBz)
+	return newID, updatedTx, nil
+}
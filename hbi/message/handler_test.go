@@ -0,0 +1,30 @@
+package message
+
+import (
+	"testing"
+
+	"github.com/herdius/herdius-core/accounts/account"
+	"github.com/herdius/herdius-core/accounts/protobuf"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBalanceCheckedNewExternalAddress(t *testing.T) {
+	// An account without any ETH address registers its first one
+	accSrv := account.NewAccountService()
+	accSrv.SetAccount(&protobuf.Account{Address: "HHy1CuT3UxCGJ3BHydLEvR5ut5TLFYAEKy", Balance: 10})
+	accSrv.SetAssetSymbol("ETH")
+	accSrv.SetExtAddress("0xD8f647855876549d2623f52126CE40D053a2ef6A")
+	accSrv.SetTxFee(1)
+	assert.False(t, accSrv.AccountExternalAddressExist())
+	assert.False(t, balanceChecked("update"))
+	assert.False(t, balanceChecked(Update.String()))
+
+	// A transfer of ETH it doesn't hold is rejected
+	accSrv.SetTxValue(5)
+	assert.True(t, balanceChecked("transfer"))
+	assert.True(t, balanceChecked(""))
+	assert.False(t, accSrv.VerifyAccountBalance())
+
+	assert.False(t, balanceChecked(Lock.String()))
+	assert.False(t, balanceChecked(Redeem.String()))
+}
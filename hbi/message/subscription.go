@@ -0,0 +1,371 @@
+package message
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/gogo/protobuf/proto"
+
+	"github.com/herdius/herdius-core/blockchain"
+	"github.com/herdius/herdius-core/blockchain/protobuf"
+	protoplugin "github.com/herdius/herdius-core/hbi/protobuf"
+	plog "github.com/herdius/herdius-core/p2p/log"
+	"github.com/herdius/herdius-core/p2p/network"
+)
+
+const (
+	// maxSubscriptionsPerPeer limits the subscriptions a single peer may hold
+	maxSubscriptionsPerPeer = 100
+	// outboxSize bounds the notifications waiting to be sent to a peer
+	outboxSize = 64
+)
+
+// subscriber is the peer a subscription notifies
+type subscriber interface {
+	Tell(ctx context.Context, message proto.Message) error
+}
+
+type subscription struct {
+	id      uint64
+	peer    subscriber
+	topic   protoplugin.SubscriptionTopic
+	address string
+	txID    string
+}
+
+// outbox sends the notifications of a peer in order, away from the block
+// loop, so that a slow peer holds up neither the others nor new blocks
+type outbox struct {
+	peer  subscriber
+	queue chan *protoplugin.Notification
+	done  chan struct{} // closed when the peer is removed
+	idle  chan struct{} // closed once the peer has no subscriptions left
+}
+
+// pending is a notification to be sent through an outbox
+type pending struct {
+	outbox       *outbox
+	notification *protoplugin.Notification
+}
+
+// SubscriptionPlugin keeps the subscriptions of peers to new blocks, the txs
+// of an address and the status of a tx, and notifies them as blocks are added.
+// Subscriptions of a peer are removed when it disconnects.
+type SubscriptionPlugin struct {
+	*network.Plugin
+
+	supervisorAddress string
+
+	mu       sync.Mutex
+	nextID   uint64
+	subs     map[uint64]*subscription
+	outboxes map[subscriber]*outbox
+}
+
+// NewSubscriptionPlugin creates a SubscriptionPlugin without subscriptions
+func NewSubscriptionPlugin() *SubscriptionPlugin {
+	return &SubscriptionPlugin{
+		subs:     make(map[uint64]*subscription),
+		outboxes: make(map[subscriber]*outbox),
+	}
+}
+
+// Startup records the address of the supervisor sent along with blocks
+func (s *SubscriptionPlugin) Startup(net *network.Network) {
+	s.supervisorAddress = net.ID.Address
+}
+
+// Receive handles subscribe and unsubscribe requests
+func (s *SubscriptionPlugin) Receive(ctx *network.PluginContext) error {
+	switch msg := ctx.Message().(type) {
+	case *protoplugin.SubscribeRequest:
+		res, notification := s.subscribe(ctx.Client(), msg)
+		if err := ctx.Reply(network.WithSignMessage(context.Background(), true), res); err != nil {
+			return fmt.Errorf("failed to reply to client: %v", err)
+		}
+		if notification != nil {
+			return ctx.Client().Tell(network.WithSignMessage(context.Background(), true), notification)
+		}
+	case *protoplugin.UnsubscribeRequest:
+		res := s.unsubscribe(ctx.Client(), msg.GetSubscriptionId())
+		if err := ctx.Reply(network.WithSignMessage(context.Background(), true), res); err != nil {
+			return fmt.Errorf("failed to reply to client: %v", err)
+		}
+	}
+	return nil
+}
+
+// PeerDisconnect removes the subscriptions of client
+func (s *SubscriptionPlugin) PeerDisconnect(client *network.PeerClient) {
+	s.removePeer(client)
+}
+
+// subscribe adds the subscription of peer. A TX_STATUS subscription to a tx
+// already in a block isn't added, the returned notification holds its status.
+func (s *SubscriptionPlugin) subscribe(peer subscriber, req *protoplugin.SubscribeRequest) (*protoplugin.SubscribeResponse, *protoplugin.Notification) {
+	failed := func(format string, args ...interface{}) (*protoplugin.SubscribeResponse, *protoplugin.Notification) {
+		return &protoplugin.SubscribeResponse{Status: false, Error: fmt.Sprintf(format, args...)}, nil
+	}
+	sub := &subscription{peer: peer, topic: req.GetTopic()}
+	switch req.GetTopic() {
+	case protoplugin.SubscriptionTopic_BLOCKS:
+	case protoplugin.SubscriptionTopic_ADDRESS_TXS:
+		if len(req.GetAddress()) == 0 {
+			return failed("address required to subscribe to %v", req.GetTopic())
+		}
+		sub.address = req.GetAddress()
+	case protoplugin.SubscriptionTopic_TX_STATUS:
+		if len(req.GetTxId()) == 0 {
+			return failed("tx id required to subscribe to %v", req.GetTopic())
+		}
+		sub.txID = req.GetTxId()
+	default:
+		return failed("unknown subscription topic: %v", req.GetTopic())
+	}
+
+	// A tx already in a block won't change its status anymore
+	var (
+		included      *protoplugin.TxDetailResponse
+		includedBlock *protoplugin.BlockResponse
+	)
+	if sub.topic == protoplugin.SubscriptionTopic_TX_STATUS {
+		included, includedBlock = s.includedTx(sub.txID)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	count := 0
+	for _, other := range s.subs {
+		if other.peer == peer {
+			count++
+		}
+	}
+	if count >= maxSubscriptionsPerPeer {
+		return failed("peer reached the limit of %d subscriptions", maxSubscriptionsPerPeer)
+	}
+	s.nextID++
+	sub.id = s.nextID
+	res := &protoplugin.SubscribeResponse{SubscriptionId: sub.id, Status: true}
+	if included != nil {
+		return res, &protoplugin.Notification{
+			SubscriptionId: sub.id,
+			Block:          includedBlock,
+			Txs:            []*protoplugin.TxDetailResponse{included},
+		}
+	}
+	s.subs[sub.id] = sub
+	s.openOutboxLocked(peer)
+	return res, nil
+}
+
+// openOutboxLocked starts the outbox of peer if it has none
+func (s *SubscriptionPlugin) openOutboxLocked(peer subscriber) {
+	if _, ok := s.outboxes[peer]; ok {
+		return
+	}
+	ob := &outbox{
+		peer:  peer,
+		queue: make(chan *protoplugin.Notification, outboxSize),
+		done:  make(chan struct{}),
+		idle:  make(chan struct{}),
+	}
+	s.outboxes[peer] = ob
+	go s.send(ob)
+}
+
+// includedTx returns the details of the tx with id and its block if it is in a block
+func (s *SubscriptionPlugin) includedTx(id string) (*protoplugin.TxDetailResponse, *protoplugin.BlockResponse) {
+	txSvc := &blockchain.TxService{}
+	txDetail, err := txSvc.GetTx(id)
+	if err != nil || len(txDetail.GetTxId()) == 0 {
+		return nil, nil
+	}
+	blockSvc := &blockchain.Service{}
+	block, err := blockSvc.GetBlockByHeight(int64(txDetail.GetBlockId()))
+	if err != nil {
+		plog.Error().Msgf("Failed to get block %d of tx %s: %v", txDetail.GetBlockId(), id, err)
+	}
+	return txDetail, blockResponse(block, s.supervisorAddress)
+}
+
+// unsubscribe removes the subscription with id if it belongs to peer
+func (s *SubscriptionPlugin) unsubscribe(peer subscriber, id uint64) *protoplugin.SubscribeResponse {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	sub, ok := s.subs[id]
+	if !ok || sub.peer != peer {
+		return &protoplugin.SubscribeResponse{SubscriptionId: id, Status: false, Error: fmt.Sprintf("no subscription with id %d", id)}
+	}
+	delete(s.subs, id)
+	s.releaseOutboxLocked(peer)
+	return &protoplugin.SubscribeResponse{SubscriptionId: id, Status: true}
+}
+
+// removePeer removes the subscriptions and the outbox of peer
+func (s *SubscriptionPlugin) removePeer(peer subscriber) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.removePeerLocked(peer)
+}
+
+// dropOutbox removes the subscriptions of the peer of ob, unless the peer
+// subscribed anew since ob was removed
+func (s *SubscriptionPlugin) dropOutbox(ob *outbox) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.outboxes[ob.peer] == ob {
+		s.removePeerLocked(ob.peer)
+	}
+}
+
+func (s *SubscriptionPlugin) removePeerLocked(peer subscriber) {
+	for id, sub := range s.subs {
+		if sub.peer == peer {
+			delete(s.subs, id)
+		}
+	}
+	if ob, ok := s.outboxes[peer]; ok {
+		close(ob.done)
+		delete(s.outboxes, peer)
+	}
+}
+
+// releaseOutboxLocked removes the outbox of peer once it has no
+// subscriptions left. The notifications queued in it are still sent.
+func (s *SubscriptionPlugin) releaseOutboxLocked(peer subscriber) {
+	for _, sub := range s.subs {
+		if sub.peer == peer {
+			return
+		}
+	}
+	if ob, ok := s.outboxes[peer]; ok {
+		close(ob.idle)
+		delete(s.outboxes, peer)
+	}
+}
+
+// send sends the notifications queued in ob until the outbox is removed, or
+// until the queue is empty once the outbox is released. A peer that can't be
+// reached loses its subscriptions.
+func (s *SubscriptionPlugin) send(ob *outbox) {
+	for {
+		select {
+		case notification := <-ob.queue:
+			if !s.tell(ob, notification) {
+				return
+			}
+		case <-ob.done:
+			return
+		case <-ob.idle:
+			for {
+				select {
+				case notification := <-ob.queue:
+					if !s.tell(ob, notification) {
+						return
+					}
+				default:
+					return
+				}
+			}
+		}
+	}
+}
+
+func (s *SubscriptionPlugin) tell(ob *outbox, notification *protoplugin.Notification) bool {
+	if err := ob.peer.Tell(network.WithSignMessage(context.Background(), true), notification); err != nil {
+		plog.Error().Msgf("Failed to notify subscription %d, removing the subscriptions of the peer: %v", notification.SubscriptionId, err)
+		s.dropOutbox(ob)
+		return false
+	}
+	return true
+}
+
+// Publish notifies the subscribers of block. It is called once block has
+// been added to the chain.
+func (s *SubscriptionPlugin) Publish(block *protobuf.BaseBlock) {
+	var txs []*protoplugin.TxDetailResponse
+	if s.hasTxSubscriptions() {
+		txSvc := &blockchain.TxService{}
+		txsRes, err := txSvc.GetTxsByHeight(block.GetHeader().GetHeight())
+		if err != nil {
+			plog.Error().Msgf("Failed to get txs of block %d for subscribers: %v", block.GetHeader().GetHeight(), err)
+		}
+		txs = txsRes.GetTxs()
+	}
+	s.notify(blockResponse(block, s.supervisorAddress), txs)
+}
+
+func (s *SubscriptionPlugin) hasTxSubscriptions() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, sub := range s.subs {
+		if sub.topic != protoplugin.SubscriptionTopic_BLOCKS {
+			return true
+		}
+	}
+	return false
+}
+
+// notify queues the notifications of a block with txs to the outboxes of the
+// subscribers. TX_STATUS subscriptions end once notified, and the outbox of a
+// peer left without subscriptions is released. A peer whose outbox is full
+// can't keep up and loses its subscriptions.
+func (s *SubscriptionPlugin) notify(block *protoplugin.BlockResponse, txs []*protoplugin.TxDetailResponse) {
+	var (
+		queued []pending
+		ended  []subscriber
+	)
+	s.mu.Lock()
+	for id, sub := range s.subs {
+		var matched []*protoplugin.TxDetailResponse
+		switch sub.topic {
+		case protoplugin.SubscriptionTopic_ADDRESS_TXS:
+			for _, tx := range txs {
+				if strings.EqualFold(tx.GetTx().GetSenderAddress(), sub.address) ||
+					strings.EqualFold(tx.GetTx().GetRecieverAddress(), sub.address) {
+					matched = append(matched, tx)
+				}
+			}
+		case protoplugin.SubscriptionTopic_TX_STATUS:
+			for _, tx := range txs {
+				if tx.GetTxId() == sub.txID {
+					matched = append(matched, tx)
+				}
+			}
+		}
+		if sub.topic != protoplugin.SubscriptionTopic_BLOCKS && len(matched) == 0 {
+			continue
+		}
+
+		queued = append(queued, pending{
+			outbox:       s.outboxes[sub.peer],
+			notification: &protoplugin.Notification{SubscriptionId: id, Block: block, Txs: matched},
+		})
+		if sub.topic == protoplugin.SubscriptionTopic_TX_STATUS {
+			delete(s.subs, id)
+			ended = append(ended, sub.peer)
+		}
+	}
+	s.mu.Unlock()
+
+	for _, p := range queued {
+		select {
+		case p.outbox.queue <- p.notification:
+		case <-p.outbox.done:
+		default:
+			plog.Error().Msgf("Notifications of subscription %d are backed up, removing the subscriptions of the peer", p.notification.SubscriptionId)
+			s.dropOutbox(p.outbox)
+		}
+	}
+
+	// Released once their last notifications are queued
+	if len(ended) > 0 {
+		s.mu.Lock()
+		for _, peer := range ended {
+			s.releaseOutboxLocked(peer)
+		}
+		s.mu.Unlock()
+	}
+}
@@ -0,0 +1,231 @@
+package message
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	protoplugin "github.com/herdius/herdius-core/hbi/protobuf"
+)
+
+type testSubscriber struct {
+	told  chan *protoplugin.Notification
+	err   error
+	stuck chan struct{}
+}
+
+func newTestSubscriber() *testSubscriber {
+	return &testSubscriber{told: make(chan *protoplugin.Notification, 2*outboxSize)}
+}
+
+func (p *testSubscriber) Tell(ctx context.Context, message proto.Message) error {
+	if p.stuck != nil {
+		<-p.stuck
+	}
+	if p.err != nil {
+		return p.err
+	}
+	p.told <- message.(*protoplugin.Notification)
+	return nil
+}
+
+// next waits for the next notification sent to p
+func (p *testSubscriber) next(t *testing.T) *protoplugin.Notification {
+	select {
+	case notification := <-p.told:
+		return notification
+	case <-time.After(time.Second):
+		require.FailNow(t, "no notification sent")
+		return nil
+	}
+}
+
+// none checks no notification is sent to p
+func (p *testSubscriber) none(t *testing.T) {
+	select {
+	case notification := <-p.told:
+		assert.Fail(t, "unexpected notification", "subscription %d", notification.SubscriptionId)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func subscriptionCount(s *SubscriptionPlugin) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.subs)
+}
+
+func outboxCount(s *SubscriptionPlugin) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.outboxes)
+}
+
+// waitSubscriptions waits for s to hold count subscriptions
+func waitSubscriptions(t *testing.T, s *SubscriptionPlugin, count int) {
+	deadline := time.Now().Add(time.Second)
+	for subscriptionCount(s) != count && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	assert.Equal(t, count, subscriptionCount(s))
+}
+
+func txDetail(id, sender, receiver string) *protoplugin.TxDetailResponse {
+	return &protoplugin.TxDetailResponse{
+		TxId: id,
+		Tx:   &protoplugin.Tx{SenderAddress: sender, RecieverAddress: receiver},
+	}
+}
+
+func TestSubscribeValidation(t *testing.T) {
+	s := NewSubscriptionPlugin()
+	peer := newTestSubscriber()
+
+	res, _ := s.subscribe(peer, &protoplugin.SubscribeRequest{Topic: protoplugin.SubscriptionTopic_ADDRESS_TXS})
+	assert.False(t, res.Status)
+	res, _ = s.subscribe(peer, &protoplugin.SubscribeRequest{Topic: protoplugin.SubscriptionTopic_TX_STATUS})
+	assert.False(t, res.Status)
+	res, _ = s.subscribe(peer, &protoplugin.SubscribeRequest{Topic: protoplugin.SubscriptionTopic(9)})
+	assert.False(t, res.Status)
+
+	for i := 0; i < maxSubscriptionsPerPeer; i++ {
+		res, _ = s.subscribe(peer, &protoplugin.SubscribeRequest{Topic: protoplugin.SubscriptionTopic_BLOCKS})
+		require.True(t, res.Status)
+	}
+	res, _ = s.subscribe(peer, &protoplugin.SubscribeRequest{Topic: protoplugin.SubscriptionTopic_BLOCKS})
+	assert.False(t, res.Status)
+	res, _ = s.subscribe(newTestSubscriber(), &protoplugin.SubscribeRequest{Topic: protoplugin.SubscriptionTopic_BLOCKS})
+	assert.True(t, res.Status)
+}
+
+func TestNotify(t *testing.T) {
+	s := NewSubscriptionPlugin()
+	blocks, alice, bob := newTestSubscriber(), newTestSubscriber(), newTestSubscriber()
+
+	blocksRes, _ := s.subscribe(blocks, &protoplugin.SubscribeRequest{Topic: protoplugin.SubscriptionTopic_BLOCKS})
+	aliceRes, _ := s.subscribe(alice, &protoplugin.SubscribeRequest{
+		Topic: protoplugin.SubscriptionTopic_ADDRESS_TXS, Address: "HAlice",
+	})
+	// Added directly as subscribing to a tx status looks the tx up in the chain
+	s.mu.Lock()
+	s.nextID++
+	s.subs[s.nextID] = &subscription{
+		id: s.nextID, peer: bob, topic: protoplugin.SubscriptionTopic_TX_STATUS, txID: "HTx2",
+	}
+	s.openOutboxLocked(bob)
+	s.mu.Unlock()
+
+	s.notify(&protoplugin.BlockResponse{BlockHeight: 1}, []*protoplugin.TxDetailResponse{
+		txDetail("HTx1", "HCarol", "HDave"),
+	})
+	notification := blocks.next(t)
+	assert.Equal(t, blocksRes.SubscriptionId, notification.SubscriptionId)
+	assert.Equal(t, int64(1), notification.Block.BlockHeight)
+	assert.Empty(t, notification.Txs)
+	alice.none(t)
+	bob.none(t)
+
+	s.notify(&protoplugin.BlockResponse{BlockHeight: 2}, []*protoplugin.TxDetailResponse{
+		txDetail("HTx2", "HCarol", "halice"),
+		txDetail("HTx3", "HAlice", "HCarol"),
+		txDetail("HTx4", "HCarol", "HDave"),
+	})
+	assert.Equal(t, int64(2), blocks.next(t).Block.BlockHeight)
+	notification = alice.next(t)
+	assert.Equal(t, aliceRes.SubscriptionId, notification.SubscriptionId)
+	assert.Equal(t, 2, len(notification.Txs))
+	assert.Equal(t, "HTx2", bob.next(t).Txs[0].TxId)
+
+	// The status subscription ended with its notification, and with it the
+	// outbox of its peer
+	s.notify(&protoplugin.BlockResponse{BlockHeight: 3}, []*protoplugin.TxDetailResponse{
+		txDetail("HTx2", "HCarol", "HDave"),
+	})
+	bob.none(t)
+	assert.Equal(t, 2, subscriptionCount(s))
+	assert.Equal(t, 2, outboxCount(s))
+}
+
+func TestNotifySlowSubscriber(t *testing.T) {
+	s := NewSubscriptionPlugin()
+	slow, other := newTestSubscriber(), newTestSubscriber()
+	slow.stuck = make(chan struct{})
+	defer close(slow.stuck)
+	s.subscribe(slow, &protoplugin.SubscribeRequest{Topic: protoplugin.SubscriptionTopic_BLOCKS})
+	s.subscribe(other, &protoplugin.SubscribeRequest{Topic: protoplugin.SubscriptionTopic_BLOCKS})
+
+	// Notifying doesn't wait for a subscriber that doesn't take notifications
+	for height := int64(1); height <= outboxSize+2; height++ {
+		done := make(chan struct{})
+		go func() {
+			s.notify(&protoplugin.BlockResponse{BlockHeight: height}, nil)
+			close(done)
+		}()
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+			require.FailNow(t, "notify blocked on a stuck subscriber")
+		}
+		assert.Equal(t, height, other.next(t).Block.BlockHeight)
+	}
+
+	// The subscriber whose outbox filled up lost its subscriptions
+	assert.Equal(t, 1, subscriptionCount(s))
+}
+
+func TestUnsubscribeAndRemovePeer(t *testing.T) {
+	s := NewSubscriptionPlugin()
+	peer, other := newTestSubscriber(), newTestSubscriber()
+	other.err = errors.New("connection closed")
+	res, _ := s.subscribe(peer, &protoplugin.SubscribeRequest{Topic: protoplugin.SubscriptionTopic_BLOCKS})
+	s.subscribe(peer, &protoplugin.SubscribeRequest{Topic: protoplugin.SubscriptionTopic_ADDRESS_TXS, Address: "HAlice"})
+	s.subscribe(other, &protoplugin.SubscribeRequest{Topic: protoplugin.SubscriptionTopic_BLOCKS})
+
+	assert.False(t, s.unsubscribe(other, res.SubscriptionId).Status, "only the subscriber may unsubscribe")
+	assert.True(t, s.unsubscribe(peer, res.SubscriptionId).Status)
+	assert.False(t, s.unsubscribe(peer, res.SubscriptionId).Status)
+	assert.Equal(t, 2, subscriptionCount(s))
+	assert.Equal(t, 2, outboxCount(s))
+
+	s.removePeer(peer)
+	assert.Equal(t, 1, subscriptionCount(s))
+	assert.Len(t, s.outboxes, 1)
+
+	// Subscriptions of peers that can't be notified are dropped
+	s.notify(&protoplugin.BlockResponse{BlockHeight: 1}, nil)
+	waitSubscriptions(t, s, 0)
+}
+
+func TestUnsubscribeReleasesOutbox(t *testing.T) {
+	s := NewSubscriptionPlugin()
+	peer := newTestSubscriber()
+	res, _ := s.subscribe(peer, &protoplugin.SubscribeRequest{Topic: protoplugin.SubscriptionTopic_BLOCKS})
+	s.mu.Lock()
+	ob := s.outboxes[peer]
+	s.mu.Unlock()
+
+	// A notification queued before the last subscription ends is still sent
+	s.notify(&protoplugin.BlockResponse{BlockHeight: 1}, nil)
+	assert.True(t, s.unsubscribe(peer, res.SubscriptionId).Status)
+	assert.Equal(t, 0, outboxCount(s))
+	assert.Equal(t, int64(1), peer.next(t).Block.BlockHeight)
+	select {
+	case <-ob.idle:
+	default:
+		assert.Fail(t, "outbox not released")
+	}
+
+	// Subscribing again opens a new outbox
+	res, _ = s.subscribe(peer, &protoplugin.SubscribeRequest{Topic: protoplugin.SubscriptionTopic_BLOCKS})
+	require.True(t, res.Status)
+	assert.Equal(t, 1, outboxCount(s))
+	s.notify(&protoplugin.BlockResponse{BlockHeight: 2}, nil)
+	notification := peer.next(t)
+	assert.Equal(t, res.SubscriptionId, notification.SubscriptionId)
+	assert.Equal(t, int64(2), notification.Block.BlockHeight)
+}
@@ -3,9 +3,11 @@
 
 package protobuf
 
-import proto "github.com/golang/protobuf/proto"
-import fmt "fmt"
-import math "math"
+import (
+	fmt "fmt"
+	proto "github.com/golang/protobuf/proto"
+	math "math"
+)
 
 // Reference imports to suppress errors if they are not otherwise used.
 var _ = proto.Marshal
@@ -16,7 +18,39 @@ var _ = math.Inf
 // is compatible with the proto package it is being compiled against.
 // A compilation error at this line likely means your copy of the
 // proto package needs to be updated.
-const _ = proto.ProtoPackageIsVersion2 // please upgrade the proto package
+const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
+
+// SubscriptionTopic is what a subscriber is notified of
+type SubscriptionTopic int32
+
+const (
+	// every new base block
+	SubscriptionTopic_BLOCKS SubscriptionTopic = 0
+	// txs sent from or to an address, as their blocks are added
+	SubscriptionTopic_ADDRESS_TXS SubscriptionTopic = 1
+	// the status of a tx, once its block is added
+	SubscriptionTopic_TX_STATUS SubscriptionTopic = 2
+)
+
+var SubscriptionTopic_name = map[int32]string{
+	0: "BLOCKS",
+	1: "ADDRESS_TXS",
+	2: "TX_STATUS",
+}
+
+var SubscriptionTopic_value = map[string]int32{
+	"BLOCKS":      0,
+	"ADDRESS_TXS": 1,
+	"TX_STATUS":   2,
+}
+
+func (x SubscriptionTopic) String() string {
+	return proto.EnumName(SubscriptionTopic_name, int32(x))
+}
+
+func (SubscriptionTopic) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_ebece8ff681ed6b0, []int{0}
+}
 
 type Timestamp struct {
 	Seconds              int64    `protobuf:"varint,1,opt,name=seconds,proto3" json:"seconds,omitempty"`
@@ -30,16 +64,17 @@ func (m *Timestamp) Reset()         { *m = Timestamp{} }
 func (m *Timestamp) String() string { return proto.CompactTextString(m) }
 func (*Timestamp) ProtoMessage()    {}
 func (*Timestamp) Descriptor() ([]byte, []int) {
-	return fileDescriptor_service_7c6a820e3f820643, []int{0}
+	return fileDescriptor_ebece8ff681ed6b0, []int{0}
 }
+
 func (m *Timestamp) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_Timestamp.Unmarshal(m, b)
 }
 func (m *Timestamp) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
 	return xxx_messageInfo_Timestamp.Marshal(b, m, deterministic)
 }
-func (dst *Timestamp) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_Timestamp.Merge(dst, src)
+func (m *Timestamp) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Timestamp.Merge(m, src)
 }
 func (m *Timestamp) XXX_Size() int {
 	return xxx_messageInfo_Timestamp.Size(m)
@@ -75,16 +110,17 @@ func (m *BlockHeightRequest) Reset()         { *m = BlockHeightRequest{} }
 func (m *BlockHeightRequest) String() string { return proto.CompactTextString(m) }
 func (*BlockHeightRequest) ProtoMessage()    {}
 func (*BlockHeightRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_service_7c6a820e3f820643, []int{1}
+	return fileDescriptor_ebece8ff681ed6b0, []int{1}
 }
+
 func (m *BlockHeightRequest) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_BlockHeightRequest.Unmarshal(m, b)
 }
 func (m *BlockHeightRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
 	return xxx_messageInfo_BlockHeightRequest.Marshal(b, m, deterministic)
 }
-func (dst *BlockHeightRequest) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_BlockHeightRequest.Merge(dst, src)
+func (m *BlockHeightRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BlockHeightRequest.Merge(m, src)
 }
 func (m *BlockHeightRequest) XXX_Size() int {
 	return xxx_messageInfo_BlockHeightRequest.Size(m)
@@ -118,16 +154,17 @@ func (m *BlockResponse) Reset()         { *m = BlockResponse{} }
 func (m *BlockResponse) String() string { return proto.CompactTextString(m) }
 func (*BlockResponse) ProtoMessage()    {}
 func (*BlockResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_service_7c6a820e3f820643, []int{2}
+	return fileDescriptor_ebece8ff681ed6b0, []int{2}
 }
+
 func (m *BlockResponse) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_BlockResponse.Unmarshal(m, b)
 }
 func (m *BlockResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
 	return xxx_messageInfo_BlockResponse.Marshal(b, m, deterministic)
 }
-func (dst *BlockResponse) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_BlockResponse.Merge(dst, src)
+func (m *BlockResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BlockResponse.Merge(m, src)
 }
 func (m *BlockResponse) XXX_Size() int {
 	return xxx_messageInfo_BlockResponse.Size(m)
@@ -177,16 +214,17 @@ func (m *AccountRequest) Reset()         { *m = AccountRequest{} }
 func (m *AccountRequest) String() string { return proto.CompactTextString(m) }
 func (*AccountRequest) ProtoMessage()    {}
 func (*AccountRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_service_7c6a820e3f820643, []int{3}
+	return fileDescriptor_ebece8ff681ed6b0, []int{3}
 }
+
 func (m *AccountRequest) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_AccountRequest.Unmarshal(m, b)
 }
 func (m *AccountRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
 	return xxx_messageInfo_AccountRequest.Marshal(b, m, deterministic)
 }
-func (dst *AccountRequest) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_AccountRequest.Merge(dst, src)
+func (m *AccountRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AccountRequest.Merge(m, src)
 }
 func (m *AccountRequest) XXX_Size() int {
 	return xxx_messageInfo_AccountRequest.Size(m)
@@ -224,16 +262,17 @@ func (m *AccountResponse) Reset()         { *m = AccountResponse{} }
 func (m *AccountResponse) String() string { return proto.CompactTextString(m) }
 func (*AccountResponse) ProtoMessage()    {}
 func (*AccountResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_service_7c6a820e3f820643, []int{4}
+	return fileDescriptor_ebece8ff681ed6b0, []int{4}
 }
+
 func (m *AccountResponse) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_AccountResponse.Unmarshal(m, b)
 }
 func (m *AccountResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
 	return xxx_messageInfo_AccountResponse.Marshal(b, m, deterministic)
 }
-func (dst *AccountResponse) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_AccountResponse.Merge(dst, src)
+func (m *AccountResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AccountResponse.Merge(m, src)
 }
 func (m *AccountResponse) XXX_Size() int {
 	return xxx_messageInfo_AccountResponse.Size(m)
@@ -336,16 +375,17 @@ func (m *Asset) Reset()         { *m = Asset{} }
 func (m *Asset) String() string { return proto.CompactTextString(m) }
 func (*Asset) ProtoMessage()    {}
 func (*Asset) Descriptor() ([]byte, []int) {
-	return fileDescriptor_service_7c6a820e3f820643, []int{5}
+	return fileDescriptor_ebece8ff681ed6b0, []int{5}
 }
+
 func (m *Asset) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_Asset.Unmarshal(m, b)
 }
 func (m *Asset) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
 	return xxx_messageInfo_Asset.Marshal(b, m, deterministic)
 }
-func (dst *Asset) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_Asset.Merge(dst, src)
+func (m *Asset) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Asset.Merge(m, src)
 }
 func (m *Asset) XXX_Size() int {
 	return xxx_messageInfo_Asset.Size(m)
@@ -448,8 +488,12 @@ type Tx struct {
 	Message         string `protobuf:"bytes,5,opt,name=message,proto3" json:"message,omitempty"`
 	Sign            string `protobuf:"bytes,6,opt,name=sign,proto3" json:"sign,omitempty"`
 	// type will check if tx is of type Account Registeration or Value Transfer
-	Type                 string   `protobuf:"bytes,7,opt,name=type,proto3" json:"type,omitempty"`
-	Status               string   `protobuf:"bytes,8,opt,name=status,proto3" json:"status,omitempty"`
+	Type   string `protobuf:"bytes,7,opt,name=type,proto3" json:"type,omitempty"`
+	Status string `protobuf:"bytes,8,opt,name=status,proto3" json:"status,omitempty"`
+	// sign_version is the format of the signed bytes, see tx.SignBytes
+	SignVersion uint32 `protobuf:"varint,9,opt,name=sign_version,json=signVersion,proto3" json:"sign_version,omitempty"`
+	// chain_id is the chain the tx is valid on, signed from sign_version 1
+	ChainId              string   `protobuf:"bytes,10,opt,name=chain_id,json=chainId,proto3" json:"chain_id,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -459,16 +503,17 @@ func (m *Tx) Reset()         { *m = Tx{} }
 func (m *Tx) String() string { return proto.CompactTextString(m) }
 func (*Tx) ProtoMessage()    {}
 func (*Tx) Descriptor() ([]byte, []int) {
-	return fileDescriptor_service_7c6a820e3f820643, []int{6}
+	return fileDescriptor_ebece8ff681ed6b0, []int{6}
 }
+
 func (m *Tx) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_Tx.Unmarshal(m, b)
 }
 func (m *Tx) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
 	return xxx_messageInfo_Tx.Marshal(b, m, deterministic)
 }
-func (dst *Tx) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_Tx.Merge(dst, src)
+func (m *Tx) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Tx.Merge(m, src)
 }
 func (m *Tx) XXX_Size() int {
 	return xxx_messageInfo_Tx.Size(m)
@@ -535,6 +580,20 @@ func (m *Tx) GetStatus() string {
 	return ""
 }
 
+func (m *Tx) GetSignVersion() uint32 {
+	if m != nil {
+		return m.SignVersion
+	}
+	return 0
+}
+
+func (m *Tx) GetChainId() string {
+	if m != nil {
+		return m.ChainId
+	}
+	return ""
+}
+
 type TxRequest struct {
 	Tx                   *Tx      `protobuf:"bytes,1,opt,name=tx,proto3" json:"tx,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
@@ -546,16 +605,17 @@ func (m *TxRequest) Reset()         { *m = TxRequest{} }
 func (m *TxRequest) String() string { return proto.CompactTextString(m) }
 func (*TxRequest) ProtoMessage()    {}
 func (*TxRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_service_7c6a820e3f820643, []int{7}
+	return fileDescriptor_ebece8ff681ed6b0, []int{7}
 }
+
 func (m *TxRequest) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_TxRequest.Unmarshal(m, b)
 }
 func (m *TxRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
 	return xxx_messageInfo_TxRequest.Marshal(b, m, deterministic)
 }
-func (dst *TxRequest) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_TxRequest.Merge(dst, src)
+func (m *TxRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TxRequest.Merge(m, src)
 }
 func (m *TxRequest) XXX_Size() int {
 	return xxx_messageInfo_TxRequest.Size(m)
@@ -588,16 +648,17 @@ func (m *TxResponse) Reset()         { *m = TxResponse{} }
 func (m *TxResponse) String() string { return proto.CompactTextString(m) }
 func (*TxResponse) ProtoMessage()    {}
 func (*TxResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_service_7c6a820e3f820643, []int{8}
+	return fileDescriptor_ebece8ff681ed6b0, []int{8}
 }
+
 func (m *TxResponse) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_TxResponse.Unmarshal(m, b)
 }
 func (m *TxResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
 	return xxx_messageInfo_TxResponse.Marshal(b, m, deterministic)
 }
-func (dst *TxResponse) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_TxResponse.Merge(dst, src)
+func (m *TxResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TxResponse.Merge(m, src)
 }
 func (m *TxResponse) XXX_Size() int {
 	return xxx_messageInfo_TxResponse.Size(m)
@@ -654,16 +715,17 @@ func (m *AccountRegisterRequest) Reset()         { *m = AccountRegisterRequest{}
 func (m *AccountRegisterRequest) String() string { return proto.CompactTextString(m) }
 func (*AccountRegisterRequest) ProtoMessage()    {}
 func (*AccountRegisterRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_service_7c6a820e3f820643, []int{9}
+	return fileDescriptor_ebece8ff681ed6b0, []int{9}
 }
+
 func (m *AccountRegisterRequest) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_AccountRegisterRequest.Unmarshal(m, b)
 }
 func (m *AccountRegisterRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
 	return xxx_messageInfo_AccountRegisterRequest.Marshal(b, m, deterministic)
 }
-func (dst *AccountRegisterRequest) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_AccountRegisterRequest.Merge(dst, src)
+func (m *AccountRegisterRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AccountRegisterRequest.Merge(m, src)
 }
 func (m *AccountRegisterRequest) XXX_Size() int {
 	return xxx_messageInfo_AccountRegisterRequest.Size(m)
@@ -693,16 +755,17 @@ func (m *TxDetailRequest) Reset()         { *m = TxDetailRequest{} }
 func (m *TxDetailRequest) String() string { return proto.CompactTextString(m) }
 func (*TxDetailRequest) ProtoMessage()    {}
 func (*TxDetailRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_service_7c6a820e3f820643, []int{10}
+	return fileDescriptor_ebece8ff681ed6b0, []int{10}
 }
+
 func (m *TxDetailRequest) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_TxDetailRequest.Unmarshal(m, b)
 }
 func (m *TxDetailRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
 	return xxx_messageInfo_TxDetailRequest.Marshal(b, m, deterministic)
 }
-func (dst *TxDetailRequest) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_TxDetailRequest.Merge(dst, src)
+func (m *TxDetailRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TxDetailRequest.Merge(m, src)
 }
 func (m *TxDetailRequest) XXX_Size() int {
 	return xxx_messageInfo_TxDetailRequest.Size(m)
@@ -726,6 +789,7 @@ type TxDetailResponse struct {
 	Tx                   *Tx        `protobuf:"bytes,2,opt,name=tx,proto3" json:"tx,omitempty"`
 	CreationDt           *Timestamp `protobuf:"bytes,3,opt,name=creationDt,proto3" json:"creationDt,omitempty"`
 	BlockId              uint64     `protobuf:"varint,4,opt,name=block_id,json=blockId,proto3" json:"block_id,omitempty"`
+	Receipt              *Receipt   `protobuf:"bytes,5,opt,name=receipt,proto3" json:"receipt,omitempty"`
 	XXX_NoUnkeyedLiteral struct{}   `json:"-"`
 	XXX_unrecognized     []byte     `json:"-"`
 	XXX_sizecache        int32      `json:"-"`
@@ -735,16 +799,17 @@ func (m *TxDetailResponse) Reset()         { *m = TxDetailResponse{} }
 func (m *TxDetailResponse) String() string { return proto.CompactTextString(m) }
 func (*TxDetailResponse) ProtoMessage()    {}
 func (*TxDetailResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_service_7c6a820e3f820643, []int{11}
+	return fileDescriptor_ebece8ff681ed6b0, []int{11}
 }
+
 func (m *TxDetailResponse) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_TxDetailResponse.Unmarshal(m, b)
 }
 func (m *TxDetailResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
 	return xxx_messageInfo_TxDetailResponse.Marshal(b, m, deterministic)
 }
-func (dst *TxDetailResponse) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_TxDetailResponse.Merge(dst, src)
+func (m *TxDetailResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TxDetailResponse.Merge(m, src)
 }
 func (m *TxDetailResponse) XXX_Size() int {
 	return xxx_messageInfo_TxDetailResponse.Size(m)
@@ -783,6 +848,96 @@ func (m *TxDetailResponse) GetBlockId() uint64 {
 	return 0
 }
 
+func (m *TxDetailResponse) GetReceipt() *Receipt {
+	if m != nil {
+		return m.Receipt
+	}
+	return nil
+}
+
+// Receipt is the outcome of applying a tx to the state
+type Receipt struct {
+	TxId   string `protobuf:"bytes,1,opt,name=tx_id,json=txId,proto3" json:"tx_id,omitempty"`
+	Status string `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"`
+	// code of the reason the tx failed, 0 if it succeeded
+	Code    uint32 `protobuf:"varint,3,opt,name=code,proto3" json:"code,omitempty"`
+	Message string `protobuf:"bytes,4,opt,name=message,proto3" json:"message,omitempty"`
+	// HER fee charged to the sender
+	Fee uint64 `protobuf:"varint,5,opt,name=fee,proto3" json:"fee,omitempty"`
+	// state root after the tx was applied
+	StateRoot            []byte   `protobuf:"bytes,6,opt,name=state_root,json=stateRoot,proto3" json:"state_root,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Receipt) Reset()         { *m = Receipt{} }
+func (m *Receipt) String() string { return proto.CompactTextString(m) }
+func (*Receipt) ProtoMessage()    {}
+func (*Receipt) Descriptor() ([]byte, []int) {
+	return fileDescriptor_ebece8ff681ed6b0, []int{12}
+}
+
+func (m *Receipt) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Receipt.Unmarshal(m, b)
+}
+func (m *Receipt) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Receipt.Marshal(b, m, deterministic)
+}
+func (m *Receipt) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Receipt.Merge(m, src)
+}
+func (m *Receipt) XXX_Size() int {
+	return xxx_messageInfo_Receipt.Size(m)
+}
+func (m *Receipt) XXX_DiscardUnknown() {
+	xxx_messageInfo_Receipt.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Receipt proto.InternalMessageInfo
+
+func (m *Receipt) GetTxId() string {
+	if m != nil {
+		return m.TxId
+	}
+	return ""
+}
+
+func (m *Receipt) GetStatus() string {
+	if m != nil {
+		return m.Status
+	}
+	return ""
+}
+
+func (m *Receipt) GetCode() uint32 {
+	if m != nil {
+		return m.Code
+	}
+	return 0
+}
+
+func (m *Receipt) GetMessage() string {
+	if m != nil {
+		return m.Message
+	}
+	return ""
+}
+
+func (m *Receipt) GetFee() uint64 {
+	if m != nil {
+		return m.Fee
+	}
+	return 0
+}
+
+func (m *Receipt) GetStateRoot() []byte {
+	if m != nil {
+		return m.StateRoot
+	}
+	return nil
+}
+
 type Transaction struct {
 	Senderpubkey         []byte   `protobuf:"bytes,1,opt,name=senderpubkey,proto3" json:"senderpubkey,omitempty"`
 	Signature            string   `protobuf:"bytes,2,opt,name=signature,proto3" json:"signature,omitempty"`
@@ -797,16 +952,17 @@ func (m *Transaction) Reset()         { *m = Transaction{} }
 func (m *Transaction) String() string { return proto.CompactTextString(m) }
 func (*Transaction) ProtoMessage()    {}
 func (*Transaction) Descriptor() ([]byte, []int) {
-	return fileDescriptor_service_7c6a820e3f820643, []int{12}
+	return fileDescriptor_ebece8ff681ed6b0, []int{13}
 }
+
 func (m *Transaction) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_Transaction.Unmarshal(m, b)
 }
 func (m *Transaction) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
 	return xxx_messageInfo_Transaction.Marshal(b, m, deterministic)
 }
-func (dst *Transaction) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_Transaction.Merge(dst, src)
+func (m *Transaction) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Transaction.Merge(m, src)
 }
 func (m *Transaction) XXX_Size() int {
 	return xxx_messageInfo_Transaction.Size(m)
@@ -856,16 +1012,17 @@ func (m *TransactionRequest) Reset()         { *m = TransactionRequest{} }
 func (m *TransactionRequest) String() string { return proto.CompactTextString(m) }
 func (*TransactionRequest) ProtoMessage()    {}
 func (*TransactionRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_service_7c6a820e3f820643, []int{13}
+	return fileDescriptor_ebece8ff681ed6b0, []int{14}
 }
+
 func (m *TransactionRequest) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_TransactionRequest.Unmarshal(m, b)
 }
 func (m *TransactionRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
 	return xxx_messageInfo_TransactionRequest.Marshal(b, m, deterministic)
 }
-func (dst *TransactionRequest) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_TransactionRequest.Merge(dst, src)
+func (m *TransactionRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TransactionRequest.Merge(m, src)
 }
 func (m *TransactionRequest) XXX_Size() int {
 	return xxx_messageInfo_TransactionRequest.Size(m)
@@ -897,16 +1054,17 @@ func (m *TransactionResponse) Reset()         { *m = TransactionResponse{} }
 func (m *TransactionResponse) String() string { return proto.CompactTextString(m) }
 func (*TransactionResponse) ProtoMessage()    {}
 func (*TransactionResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_service_7c6a820e3f820643, []int{14}
+	return fileDescriptor_ebece8ff681ed6b0, []int{15}
 }
+
 func (m *TransactionResponse) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_TransactionResponse.Unmarshal(m, b)
 }
 func (m *TransactionResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
 	return xxx_messageInfo_TransactionResponse.Marshal(b, m, deterministic)
 }
-func (dst *TransactionResponse) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_TransactionResponse.Merge(dst, src)
+func (m *TransactionResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TransactionResponse.Merge(m, src)
 }
 func (m *TransactionResponse) XXX_Size() int {
 	return xxx_messageInfo_TransactionResponse.Size(m)
@@ -957,16 +1115,17 @@ func (m *TxsByAddressRequest) Reset()         { *m = TxsByAddressRequest{} }
 func (m *TxsByAddressRequest) String() string { return proto.CompactTextString(m) }
 func (*TxsByAddressRequest) ProtoMessage()    {}
 func (*TxsByAddressRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_service_7c6a820e3f820643, []int{15}
+	return fileDescriptor_ebece8ff681ed6b0, []int{16}
 }
+
 func (m *TxsByAddressRequest) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_TxsByAddressRequest.Unmarshal(m, b)
 }
 func (m *TxsByAddressRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
 	return xxx_messageInfo_TxsByAddressRequest.Marshal(b, m, deterministic)
 }
-func (dst *TxsByAddressRequest) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_TxsByAddressRequest.Merge(dst, src)
+func (m *TxsByAddressRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TxsByAddressRequest.Merge(m, src)
 }
 func (m *TxsByAddressRequest) XXX_Size() int {
 	return xxx_messageInfo_TxsByAddressRequest.Size(m)
@@ -996,16 +1155,17 @@ func (m *TxsResponse) Reset()         { *m = TxsResponse{} }
 func (m *TxsResponse) String() string { return proto.CompactTextString(m) }
 func (*TxsResponse) ProtoMessage()    {}
 func (*TxsResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_service_7c6a820e3f820643, []int{16}
+	return fileDescriptor_ebece8ff681ed6b0, []int{17}
 }
+
 func (m *TxsResponse) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_TxsResponse.Unmarshal(m, b)
 }
 func (m *TxsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
 	return xxx_messageInfo_TxsResponse.Marshal(b, m, deterministic)
 }
-func (dst *TxsResponse) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_TxsResponse.Merge(dst, src)
+func (m *TxsResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TxsResponse.Merge(m, src)
 }
 func (m *TxsResponse) XXX_Size() int {
 	return xxx_messageInfo_TxsResponse.Size(m)
@@ -1036,16 +1196,17 @@ func (m *TxsByAssetAndAddressRequest) Reset()         { *m = TxsByAssetAndAddres
 func (m *TxsByAssetAndAddressRequest) String() string { return proto.CompactTextString(m) }
 func (*TxsByAssetAndAddressRequest) ProtoMessage()    {}
 func (*TxsByAssetAndAddressRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_service_7c6a820e3f820643, []int{17}
+	return fileDescriptor_ebece8ff681ed6b0, []int{18}
 }
+
 func (m *TxsByAssetAndAddressRequest) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_TxsByAssetAndAddressRequest.Unmarshal(m, b)
 }
 func (m *TxsByAssetAndAddressRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
 	return xxx_messageInfo_TxsByAssetAndAddressRequest.Marshal(b, m, deterministic)
 }
-func (dst *TxsByAssetAndAddressRequest) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_TxsByAssetAndAddressRequest.Merge(dst, src)
+func (m *TxsByAssetAndAddressRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TxsByAssetAndAddressRequest.Merge(m, src)
 }
 func (m *TxsByAssetAndAddressRequest) XXX_Size() int {
 	return xxx_messageInfo_TxsByAssetAndAddressRequest.Size(m)
@@ -1083,16 +1244,17 @@ func (m *TxUpdateRequest) Reset()         { *m = TxUpdateRequest{} }
 func (m *TxUpdateRequest) String() string { return proto.CompactTextString(m) }
 func (*TxUpdateRequest) ProtoMessage()    {}
 func (*TxUpdateRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_service_7c6a820e3f820643, []int{18}
+	return fileDescriptor_ebece8ff681ed6b0, []int{19}
 }
+
 func (m *TxUpdateRequest) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_TxUpdateRequest.Unmarshal(m, b)
 }
 func (m *TxUpdateRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
 	return xxx_messageInfo_TxUpdateRequest.Marshal(b, m, deterministic)
 }
-func (dst *TxUpdateRequest) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_TxUpdateRequest.Merge(dst, src)
+func (m *TxUpdateRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TxUpdateRequest.Merge(m, src)
 }
 func (m *TxUpdateRequest) XXX_Size() int {
 	return xxx_messageInfo_TxUpdateRequest.Size(m)
@@ -1132,16 +1294,17 @@ func (m *TxUpdateResponse) Reset()         { *m = TxUpdateResponse{} }
 func (m *TxUpdateResponse) String() string { return proto.CompactTextString(m) }
 func (*TxUpdateResponse) ProtoMessage()    {}
 func (*TxUpdateResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_service_7c6a820e3f820643, []int{19}
+	return fileDescriptor_ebece8ff681ed6b0, []int{20}
 }
+
 func (m *TxUpdateResponse) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_TxUpdateResponse.Unmarshal(m, b)
 }
 func (m *TxUpdateResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
 	return xxx_messageInfo_TxUpdateResponse.Marshal(b, m, deterministic)
 }
-func (dst *TxUpdateResponse) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_TxUpdateResponse.Merge(dst, src)
+func (m *TxUpdateResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TxUpdateResponse.Merge(m, src)
 }
 func (m *TxUpdateResponse) XXX_Size() int {
 	return xxx_messageInfo_TxUpdateResponse.Size(m)
@@ -1194,16 +1357,17 @@ func (m *EBalance) Reset()         { *m = EBalance{} }
 func (m *EBalance) String() string { return proto.CompactTextString(m) }
 func (*EBalance) ProtoMessage()    {}
 func (*EBalance) Descriptor() ([]byte, []int) {
-	return fileDescriptor_service_7c6a820e3f820643, []int{20}
+	return fileDescriptor_ebece8ff681ed6b0, []int{21}
 }
+
 func (m *EBalance) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_EBalance.Unmarshal(m, b)
 }
 func (m *EBalance) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
 	return xxx_messageInfo_EBalance.Marshal(b, m, deterministic)
 }
-func (dst *EBalance) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_EBalance.Merge(dst, src)
+func (m *EBalance) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_EBalance.Merge(m, src)
 }
 func (m *EBalance) XXX_Size() int {
 	return xxx_messageInfo_EBalance.Size(m)
@@ -1253,16 +1417,17 @@ func (m *EBalanceAsset) Reset()         { *m = EBalanceAsset{} }
 func (m *EBalanceAsset) String() string { return proto.CompactTextString(m) }
 func (*EBalanceAsset) ProtoMessage()    {}
 func (*EBalanceAsset) Descriptor() ([]byte, []int) {
-	return fileDescriptor_service_7c6a820e3f820643, []int{21}
+	return fileDescriptor_ebece8ff681ed6b0, []int{22}
 }
+
 func (m *EBalanceAsset) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_EBalanceAsset.Unmarshal(m, b)
 }
 func (m *EBalanceAsset) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
 	return xxx_messageInfo_EBalanceAsset.Marshal(b, m, deterministic)
 }
-func (dst *EBalanceAsset) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_EBalanceAsset.Merge(dst, src)
+func (m *EBalanceAsset) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_EBalanceAsset.Merge(m, src)
 }
 func (m *EBalanceAsset) XXX_Size() int {
 	return xxx_messageInfo_EBalanceAsset.Size(m)
@@ -1291,16 +1456,17 @@ func (m *TxDeleteRequest) Reset()         { *m = TxDeleteRequest{} }
 func (m *TxDeleteRequest) String() string { return proto.CompactTextString(m) }
 func (*TxDeleteRequest) ProtoMessage()    {}
 func (*TxDeleteRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_service_7c6a820e3f820643, []int{22}
+	return fileDescriptor_ebece8ff681ed6b0, []int{23}
 }
+
 func (m *TxDeleteRequest) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_TxDeleteRequest.Unmarshal(m, b)
 }
 func (m *TxDeleteRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
 	return xxx_messageInfo_TxDeleteRequest.Marshal(b, m, deterministic)
 }
-func (dst *TxDeleteRequest) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_TxDeleteRequest.Merge(dst, src)
+func (m *TxDeleteRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TxDeleteRequest.Merge(m, src)
 }
 func (m *TxDeleteRequest) XXX_Size() int {
 	return xxx_messageInfo_TxDeleteRequest.Size(m)
@@ -1329,16 +1495,17 @@ func (m *TxLockedRequest) Reset()         { *m = TxLockedRequest{} }
 func (m *TxLockedRequest) String() string { return proto.CompactTextString(m) }
 func (*TxLockedRequest) ProtoMessage()    {}
 func (*TxLockedRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_service_7c6a820e3f820643, []int{23}
+	return fileDescriptor_ebece8ff681ed6b0, []int{24}
 }
+
 func (m *TxLockedRequest) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_TxLockedRequest.Unmarshal(m, b)
 }
 func (m *TxLockedRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
 	return xxx_messageInfo_TxLockedRequest.Marshal(b, m, deterministic)
 }
-func (dst *TxLockedRequest) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_TxLockedRequest.Merge(dst, src)
+func (m *TxLockedRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TxLockedRequest.Merge(m, src)
 }
 func (m *TxLockedRequest) XXX_Size() int {
 	return xxx_messageInfo_TxLockedRequest.Size(m)
@@ -1367,16 +1534,17 @@ func (m *TxLockedResponse) Reset()         { *m = TxLockedResponse{} }
 func (m *TxLockedResponse) String() string { return proto.CompactTextString(m) }
 func (*TxLockedResponse) ProtoMessage()    {}
 func (*TxLockedResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_service_7c6a820e3f820643, []int{24}
+	return fileDescriptor_ebece8ff681ed6b0, []int{25}
 }
+
 func (m *TxLockedResponse) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_TxLockedResponse.Unmarshal(m, b)
 }
 func (m *TxLockedResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
 	return xxx_messageInfo_TxLockedResponse.Marshal(b, m, deterministic)
 }
-func (dst *TxLockedResponse) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_TxLockedResponse.Merge(dst, src)
+func (m *TxLockedResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TxLockedResponse.Merge(m, src)
 }
 func (m *TxLockedResponse) XXX_Size() int {
 	return xxx_messageInfo_TxLockedResponse.Size(m)
@@ -1405,16 +1573,17 @@ func (m *TxRedeemRequest) Reset()         { *m = TxRedeemRequest{} }
 func (m *TxRedeemRequest) String() string { return proto.CompactTextString(m) }
 func (*TxRedeemRequest) ProtoMessage()    {}
 func (*TxRedeemRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_service_7c6a820e3f820643, []int{25}
+	return fileDescriptor_ebece8ff681ed6b0, []int{26}
 }
+
 func (m *TxRedeemRequest) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_TxRedeemRequest.Unmarshal(m, b)
 }
 func (m *TxRedeemRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
 	return xxx_messageInfo_TxRedeemRequest.Marshal(b, m, deterministic)
 }
-func (dst *TxRedeemRequest) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_TxRedeemRequest.Merge(dst, src)
+func (m *TxRedeemRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TxRedeemRequest.Merge(m, src)
 }
 func (m *TxRedeemRequest) XXX_Size() int {
 	return xxx_messageInfo_TxRedeemRequest.Size(m)
@@ -1443,16 +1612,17 @@ func (m *TxRedeemResponse) Reset()         { *m = TxRedeemResponse{} }
 func (m *TxRedeemResponse) String() string { return proto.CompactTextString(m) }
 func (*TxRedeemResponse) ProtoMessage()    {}
 func (*TxRedeemResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_service_7c6a820e3f820643, []int{26}
+	return fileDescriptor_ebece8ff681ed6b0, []int{27}
 }
+
 func (m *TxRedeemResponse) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_TxRedeemResponse.Unmarshal(m, b)
 }
 func (m *TxRedeemResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
 	return xxx_messageInfo_TxRedeemResponse.Marshal(b, m, deterministic)
 }
-func (dst *TxRedeemResponse) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_TxRedeemResponse.Merge(dst, src)
+func (m *TxRedeemResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TxRedeemResponse.Merge(m, src)
 }
 func (m *TxRedeemResponse) XXX_Size() int {
 	return xxx_messageInfo_TxRedeemResponse.Size(m)
@@ -1481,16 +1651,17 @@ func (m *TxsByBlockHeightRequest) Reset()         { *m = TxsByBlockHeightRequest
 func (m *TxsByBlockHeightRequest) String() string { return proto.CompactTextString(m) }
 func (*TxsByBlockHeightRequest) ProtoMessage()    {}
 func (*TxsByBlockHeightRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_service_7c6a820e3f820643, []int{27}
+	return fileDescriptor_ebece8ff681ed6b0, []int{28}
 }
+
 func (m *TxsByBlockHeightRequest) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_TxsByBlockHeightRequest.Unmarshal(m, b)
 }
 func (m *TxsByBlockHeightRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
 	return xxx_messageInfo_TxsByBlockHeightRequest.Marshal(b, m, deterministic)
 }
-func (dst *TxsByBlockHeightRequest) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_TxsByBlockHeightRequest.Merge(dst, src)
+func (m *TxsByBlockHeightRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TxsByBlockHeightRequest.Merge(m, src)
 }
 func (m *TxsByBlockHeightRequest) XXX_Size() int {
 	return xxx_messageInfo_TxsByBlockHeightRequest.Size(m)
@@ -1518,16 +1689,17 @@ func (m *LastBlockRequest) Reset()         { *m = LastBlockRequest{} }
 func (m *LastBlockRequest) String() string { return proto.CompactTextString(m) }
 func (*LastBlockRequest) ProtoMessage()    {}
 func (*LastBlockRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_service_7c6a820e3f820643, []int{28}
+	return fileDescriptor_ebece8ff681ed6b0, []int{29}
 }
+
 func (m *LastBlockRequest) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_LastBlockRequest.Unmarshal(m, b)
 }
 func (m *LastBlockRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
 	return xxx_messageInfo_LastBlockRequest.Marshal(b, m, deterministic)
 }
-func (dst *LastBlockRequest) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_LastBlockRequest.Merge(dst, src)
+func (m *LastBlockRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_LastBlockRequest.Merge(m, src)
 }
 func (m *LastBlockRequest) XXX_Size() int {
 	return xxx_messageInfo_LastBlockRequest.Size(m)
@@ -1538,7 +1710,338 @@ func (m *LastBlockRequest) XXX_DiscardUnknown() {
 
 var xxx_messageInfo_LastBlockRequest proto.InternalMessageInfo
 
+// SubscribeRequest subscribes the requesting peer to a topic
+type SubscribeRequest struct {
+	Topic SubscriptionTopic `protobuf:"varint,1,opt,name=topic,proto3,enum=protobuf.SubscriptionTopic" json:"topic,omitempty"`
+	// address of ADDRESS_TXS subscriptions
+	Address string `protobuf:"bytes,2,opt,name=address,proto3" json:"address,omitempty"`
+	// tx id of TX_STATUS subscriptions
+	TxId                 string   `protobuf:"bytes,3,opt,name=tx_id,json=txId,proto3" json:"tx_id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SubscribeRequest) Reset()         { *m = SubscribeRequest{} }
+func (m *SubscribeRequest) String() string { return proto.CompactTextString(m) }
+func (*SubscribeRequest) ProtoMessage()    {}
+func (*SubscribeRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_ebece8ff681ed6b0, []int{30}
+}
+
+func (m *SubscribeRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SubscribeRequest.Unmarshal(m, b)
+}
+func (m *SubscribeRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SubscribeRequest.Marshal(b, m, deterministic)
+}
+func (m *SubscribeRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SubscribeRequest.Merge(m, src)
+}
+func (m *SubscribeRequest) XXX_Size() int {
+	return xxx_messageInfo_SubscribeRequest.Size(m)
+}
+func (m *SubscribeRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_SubscribeRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SubscribeRequest proto.InternalMessageInfo
+
+func (m *SubscribeRequest) GetTopic() SubscriptionTopic {
+	if m != nil {
+		return m.Topic
+	}
+	return SubscriptionTopic_BLOCKS
+}
+
+func (m *SubscribeRequest) GetAddress() string {
+	if m != nil {
+		return m.Address
+	}
+	return ""
+}
+
+func (m *SubscribeRequest) GetTxId() string {
+	if m != nil {
+		return m.TxId
+	}
+	return ""
+}
+
+// SubscribeResponse replies to subscribe and unsubscribe requests
+type SubscribeResponse struct {
+	SubscriptionId       uint64   `protobuf:"varint,1,opt,name=subscription_id,json=subscriptionId,proto3" json:"subscription_id,omitempty"`
+	Status               bool     `protobuf:"varint,2,opt,name=status,proto3" json:"status,omitempty"`
+	Error                string   `protobuf:"bytes,3,opt,name=error,proto3" json:"error,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SubscribeResponse) Reset()         { *m = SubscribeResponse{} }
+func (m *SubscribeResponse) String() string { return proto.CompactTextString(m) }
+func (*SubscribeResponse) ProtoMessage()    {}
+func (*SubscribeResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_ebece8ff681ed6b0, []int{31}
+}
+
+func (m *SubscribeResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SubscribeResponse.Unmarshal(m, b)
+}
+func (m *SubscribeResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SubscribeResponse.Marshal(b, m, deterministic)
+}
+func (m *SubscribeResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SubscribeResponse.Merge(m, src)
+}
+func (m *SubscribeResponse) XXX_Size() int {
+	return xxx_messageInfo_SubscribeResponse.Size(m)
+}
+func (m *SubscribeResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_SubscribeResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SubscribeResponse proto.InternalMessageInfo
+
+func (m *SubscribeResponse) GetSubscriptionId() uint64 {
+	if m != nil {
+		return m.SubscriptionId
+	}
+	return 0
+}
+
+func (m *SubscribeResponse) GetStatus() bool {
+	if m != nil {
+		return m.Status
+	}
+	return false
+}
+
+func (m *SubscribeResponse) GetError() string {
+	if m != nil {
+		return m.Error
+	}
+	return ""
+}
+
+type UnsubscribeRequest struct {
+	SubscriptionId       uint64   `protobuf:"varint,1,opt,name=subscription_id,json=subscriptionId,proto3" json:"subscription_id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *UnsubscribeRequest) Reset()         { *m = UnsubscribeRequest{} }
+func (m *UnsubscribeRequest) String() string { return proto.CompactTextString(m) }
+func (*UnsubscribeRequest) ProtoMessage()    {}
+func (*UnsubscribeRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_ebece8ff681ed6b0, []int{32}
+}
+
+func (m *UnsubscribeRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_UnsubscribeRequest.Unmarshal(m, b)
+}
+func (m *UnsubscribeRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_UnsubscribeRequest.Marshal(b, m, deterministic)
+}
+func (m *UnsubscribeRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_UnsubscribeRequest.Merge(m, src)
+}
+func (m *UnsubscribeRequest) XXX_Size() int {
+	return xxx_messageInfo_UnsubscribeRequest.Size(m)
+}
+func (m *UnsubscribeRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_UnsubscribeRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_UnsubscribeRequest proto.InternalMessageInfo
+
+func (m *UnsubscribeRequest) GetSubscriptionId() uint64 {
+	if m != nil {
+		return m.SubscriptionId
+	}
+	return 0
+}
+
+// Notification is pushed to a subscriber when an event of its topic happens
+type Notification struct {
+	SubscriptionId uint64         `protobuf:"varint,1,opt,name=subscription_id,json=subscriptionId,proto3" json:"subscription_id,omitempty"`
+	Block          *BlockResponse `protobuf:"bytes,2,opt,name=block,proto3" json:"block,omitempty"`
+	// txs of the block matching the subscription, empty for BLOCKS
+	Txs                  []*TxDetailResponse `protobuf:"bytes,3,rep,name=txs,proto3" json:"txs,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}            `json:"-"`
+	XXX_unrecognized     []byte              `json:"-"`
+	XXX_sizecache        int32               `json:"-"`
+}
+
+func (m *Notification) Reset()         { *m = Notification{} }
+func (m *Notification) String() string { return proto.CompactTextString(m) }
+func (*Notification) ProtoMessage()    {}
+func (*Notification) Descriptor() ([]byte, []int) {
+	return fileDescriptor_ebece8ff681ed6b0, []int{33}
+}
+
+func (m *Notification) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Notification.Unmarshal(m, b)
+}
+func (m *Notification) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Notification.Marshal(b, m, deterministic)
+}
+func (m *Notification) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Notification.Merge(m, src)
+}
+func (m *Notification) XXX_Size() int {
+	return xxx_messageInfo_Notification.Size(m)
+}
+func (m *Notification) XXX_DiscardUnknown() {
+	xxx_messageInfo_Notification.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Notification proto.InternalMessageInfo
+
+func (m *Notification) GetSubscriptionId() uint64 {
+	if m != nil {
+		return m.SubscriptionId
+	}
+	return 0
+}
+
+func (m *Notification) GetBlock() *BlockResponse {
+	if m != nil {
+		return m.Block
+	}
+	return nil
+}
+
+func (m *Notification) GetTxs() []*TxDetailResponse {
+	if m != nil {
+		return m.Txs
+	}
+	return nil
+}
+
+// TxAnnouncement announces the ids of txs a node accepted to its peers
+type TxAnnouncement struct {
+	TxIds                []string `protobuf:"bytes,1,rep,name=tx_ids,json=txIds,proto3" json:"tx_ids,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *TxAnnouncement) Reset()         { *m = TxAnnouncement{} }
+func (m *TxAnnouncement) String() string { return proto.CompactTextString(m) }
+func (*TxAnnouncement) ProtoMessage()    {}
+func (*TxAnnouncement) Descriptor() ([]byte, []int) {
+	return fileDescriptor_ebece8ff681ed6b0, []int{34}
+}
+
+func (m *TxAnnouncement) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TxAnnouncement.Unmarshal(m, b)
+}
+func (m *TxAnnouncement) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TxAnnouncement.Marshal(b, m, deterministic)
+}
+func (m *TxAnnouncement) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TxAnnouncement.Merge(m, src)
+}
+func (m *TxAnnouncement) XXX_Size() int {
+	return xxx_messageInfo_TxAnnouncement.Size(m)
+}
+func (m *TxAnnouncement) XXX_DiscardUnknown() {
+	xxx_messageInfo_TxAnnouncement.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TxAnnouncement proto.InternalMessageInfo
+
+func (m *TxAnnouncement) GetTxIds() []string {
+	if m != nil {
+		return m.TxIds
+	}
+	return nil
+}
+
+// TxFetchRequest requests the txs with the given ids from a peer
+type TxFetchRequest struct {
+	TxIds                []string `protobuf:"bytes,1,rep,name=tx_ids,json=txIds,proto3" json:"tx_ids,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *TxFetchRequest) Reset()         { *m = TxFetchRequest{} }
+func (m *TxFetchRequest) String() string { return proto.CompactTextString(m) }
+func (*TxFetchRequest) ProtoMessage()    {}
+func (*TxFetchRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_ebece8ff681ed6b0, []int{35}
+}
+
+func (m *TxFetchRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TxFetchRequest.Unmarshal(m, b)
+}
+func (m *TxFetchRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TxFetchRequest.Marshal(b, m, deterministic)
+}
+func (m *TxFetchRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TxFetchRequest.Merge(m, src)
+}
+func (m *TxFetchRequest) XXX_Size() int {
+	return xxx_messageInfo_TxFetchRequest.Size(m)
+}
+func (m *TxFetchRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_TxFetchRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TxFetchRequest proto.InternalMessageInfo
+
+func (m *TxFetchRequest) GetTxIds() []string {
+	if m != nil {
+		return m.TxIds
+	}
+	return nil
+}
+
+// TxFetchResponse holds the requested txs the peer knows of
+type TxFetchResponse struct {
+	Txs                  []*Tx    `protobuf:"bytes,1,rep,name=txs,proto3" json:"txs,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *TxFetchResponse) Reset()         { *m = TxFetchResponse{} }
+func (m *TxFetchResponse) String() string { return proto.CompactTextString(m) }
+func (*TxFetchResponse) ProtoMessage()    {}
+func (*TxFetchResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_ebece8ff681ed6b0, []int{36}
+}
+
+func (m *TxFetchResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TxFetchResponse.Unmarshal(m, b)
+}
+func (m *TxFetchResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TxFetchResponse.Marshal(b, m, deterministic)
+}
+func (m *TxFetchResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TxFetchResponse.Merge(m, src)
+}
+func (m *TxFetchResponse) XXX_Size() int {
+	return xxx_messageInfo_TxFetchResponse.Size(m)
+}
+func (m *TxFetchResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_TxFetchResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TxFetchResponse proto.InternalMessageInfo
+
+func (m *TxFetchResponse) GetTxs() []*Tx {
+	if m != nil {
+		return m.Txs
+	}
+	return nil
+}
+
 func init() {
+	proto.RegisterEnum("protobuf.SubscriptionTopic", SubscriptionTopic_name, SubscriptionTopic_value)
 	proto.RegisterType((*Timestamp)(nil), "protobuf.Timestamp")
 	proto.RegisterType((*BlockHeightRequest)(nil), "protobuf.BlockHeightRequest")
 	proto.RegisterType((*BlockResponse)(nil), "protobuf.BlockResponse")
@@ -1553,6 +2056,7 @@ func init() {
 	proto.RegisterType((*AccountRegisterRequest)(nil), "protobuf.AccountRegisterRequest")
 	proto.RegisterType((*TxDetailRequest)(nil), "protobuf.TxDetailRequest")
 	proto.RegisterType((*TxDetailResponse)(nil), "protobuf.TxDetailResponse")
+	proto.RegisterType((*Receipt)(nil), "protobuf.Receipt")
 	proto.RegisterType((*Transaction)(nil), "protobuf.Transaction")
 	proto.RegisterType((*TransactionRequest)(nil), "protobuf.TransactionRequest")
 	proto.RegisterType((*TransactionResponse)(nil), "protobuf.TransactionResponse")
@@ -1571,87 +2075,114 @@ func init() {
 	proto.RegisterType((*TxRedeemResponse)(nil), "protobuf.TxRedeemResponse")
 	proto.RegisterType((*TxsByBlockHeightRequest)(nil), "protobuf.TxsByBlockHeightRequest")
 	proto.RegisterType((*LastBlockRequest)(nil), "protobuf.LastBlockRequest")
-}
-
-func init() { proto.RegisterFile("hbi/protobuf/service.proto", fileDescriptor_service_7c6a820e3f820643) }
-
-var fileDescriptor_service_7c6a820e3f820643 = []byte{
-	// 1232 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xbc, 0x57, 0xdd, 0x6e, 0x1b, 0xc5,
-	0x17, 0xd7, 0xfa, 0x23, 0xf1, 0x1e, 0xdb, 0x71, 0x3a, 0x49, 0x1b, 0xc7, 0xed, 0xff, 0xaf, 0x30,
-	0x51, 0xa9, 0x5b, 0x91, 0x04, 0x39, 0x08, 0xaa, 0x06, 0x24, 0x12, 0x25, 0xa5, 0x15, 0xa1, 0x8a,
-	0x16, 0xf7, 0x86, 0x1b, 0x6b, 0xbd, 0x3b, 0x75, 0x56, 0xb1, 0x77, 0xdd, 0x99, 0xd9, 0xb0, 0xbe,
-	0xe3, 0x86, 0x47, 0x00, 0x1e, 0x81, 0x5b, 0x9e, 0x8a, 0xa7, 0xe0, 0x02, 0xcd, 0xd7, 0x7e, 0xd4,
-	0x76, 0x08, 0xb9, 0xe0, 0x6e, 0xcf, 0x99, 0xf3, 0x7d, 0x7e, 0x33, 0xe7, 0x2c, 0x74, 0x2e, 0x87,
-	0xc1, 0xc1, 0x94, 0x46, 0x3c, 0x1a, 0xc6, 0xef, 0x0e, 0x18, 0xa1, 0xd7, 0x81, 0x47, 0xf6, 0x25,
-	0x03, 0xd5, 0x0c, 0x1f, 0x1f, 0x81, 0xdd, 0x0f, 0x26, 0x84, 0x71, 0x77, 0x32, 0x45, 0x6d, 0x58,
-	0x65, 0xc4, 0x8b, 0x42, 0x9f, 0xb5, 0xad, 0x1d, 0xab, 0x5b, 0x76, 0x0c, 0x89, 0x36, 0xa1, 0x1a,
-	0xba, 0x61, 0xc4, 0xda, 0x25, 0xc9, 0x57, 0x04, 0xfe, 0x02, 0xd0, 0xc9, 0x38, 0xf2, 0xae, 0x5e,
-	0x91, 0x60, 0x74, 0xc9, 0x1d, 0xf2, 0x3e, 0x26, 0x8c, 0xa3, 0x8f, 0xa0, 0x31, 0x14, 0xdc, 0xc1,
-	0xa5, 0x64, 0x6b, 0x53, 0xf5, 0x61, 0x26, 0x89, 0x7f, 0xb7, 0xa0, 0x29, 0x35, 0x1d, 0xc2, 0xa6,
-	0x51, 0xc8, 0xc8, 0x2d, 0x94, 0xd0, 0x13, 0xa8, 0xf0, 0x60, 0x42, 0x64, 0x08, 0xf5, 0xde, 0xc6,
-	0xbe, 0xc9, 0x61, 0x3f, 0x4d, 0xc0, 0x91, 0x02, 0xe8, 0x21, 0xd8, 0x3c, 0xe2, 0xee, 0x78, 0xc0,
-	0x13, 0xd6, 0x2e, 0xef, 0x58, 0xdd, 0x8a, 0x53, 0x93, 0x8c, 0x7e, 0xc2, 0xd0, 0x1e, 0x20, 0x16,
-	0x4f, 0x45, 0x35, 0x58, 0x44, 0x07, 0xae, 0xef, 0x53, 0xc2, 0x58, 0xbb, 0xb2, 0x63, 0x75, 0x6d,
-	0xe7, 0x5e, 0x76, 0x72, 0xac, 0x0e, 0xf0, 0x33, 0x58, 0x3b, 0xf6, 0xbc, 0x28, 0x0e, 0xd3, 0xf4,
-	0xda, 0xb0, 0x6a, 0xb4, 0x2c, 0xa9, 0x65, 0x48, 0xfc, 0x67, 0x05, 0x5a, 0xa9, 0xb0, 0xce, 0x6b,
-	0xa9, 0xb4, 0x2c, 0x69, 0x14, 0x7a, 0x2a, 0x9f, 0x8a, 0xa3, 0x08, 0x51, 0x07, 0xc6, 0x23, 0xea,
-	0x8e, 0xc8, 0x80, 0x46, 0x11, 0x97, 0xe1, 0xdb, 0x4e, 0x5d, 0xf3, 0x9c, 0x28, 0xe2, 0xe8, 0x7f,
-	0x00, 0xd3, 0x78, 0x38, 0x0e, 0xbc, 0xc1, 0x15, 0x99, 0xe9, 0xc8, 0x6d, 0xc5, 0xf9, 0x96, 0xcc,
-	0x84, 0xc7, 0xa1, 0x3b, 0x76, 0x85, 0xe5, 0xaa, 0xb4, 0x6c, 0x48, 0xb4, 0x0b, 0x4d, 0x42, 0xbd,
-	0xde, 0xa7, 0x69, 0xd6, 0x2b, 0x52, 0xb7, 0x21, 0x99, 0x3a, 0x61, 0xf4, 0x18, 0xd6, 0x48, 0xc2,
-	0x09, 0x0d, 0xdd, 0xf1, 0x40, 0xc5, 0xb7, 0x2a, 0xad, 0x34, 0x0d, 0xf7, 0x8d, 0x8c, 0xf3, 0x19,
-	0xdc, 0x1b, 0xbb, 0x8c, 0x0f, 0x0a, 0x4d, 0xab, 0x49, 0xc9, 0x96, 0x38, 0xc8, 0xe1, 0x02, 0xbd,
-	0x04, 0x9b, 0x9c, 0xa8, 0x18, 0x58, 0xdb, 0xde, 0x29, 0x77, 0xeb, 0xbd, 0x6e, 0xd6, 0xbd, 0x0f,
-	0x2a, 0xb6, 0x7f, 0x66, 0x44, 0xcf, 0x42, 0x4e, 0x67, 0x4e, 0xa6, 0x8a, 0x46, 0xb0, 0xf9, 0x32,
-	0xa0, 0x8c, 0x9f, 0xe9, 0x48, 0x74, 0xc8, 0x6d, 0x90, 0x26, 0x0f, 0x97, 0x9b, 0x5c, 0xa4, 0xa5,
-	0xac, 0x2f, 0x34, 0xd8, 0x79, 0x0b, 0x6b, 0xc5, 0x28, 0xd0, 0x3a, 0x94, 0x45, 0xb1, 0x55, 0x0b,
-	0xc5, 0x27, 0xda, 0x83, 0xea, 0xb5, 0x3b, 0x8e, 0x0d, 0x1c, 0xb7, 0x32, 0xef, 0x46, 0xf5, 0x98,
-	0x31, 0xc2, 0x1d, 0x25, 0xf5, 0xa2, 0xf4, 0xdc, 0xea, 0x7c, 0x03, 0xdb, 0x4b, 0x23, 0x59, 0xe0,
-	0x61, 0x33, 0xef, 0xc1, 0xce, 0x19, 0xc2, 0x7f, 0x94, 0xa1, 0x2a, 0xad, 0xa3, 0x0e, 0xd4, 0x3c,
-	0x97, 0x93, 0x51, 0x44, 0x8d, 0x6a, 0x4a, 0xa3, 0x07, 0xb0, 0xc2, 0x66, 0x93, 0x61, 0x34, 0xd6,
-	0x06, 0x34, 0x25, 0x00, 0x12, 0x12, 0xfe, 0x63, 0x44, 0xaf, 0x34, 0xba, 0x0c, 0x99, 0x79, 0xac,
-	0x28, 0x48, 0x4a, 0x42, 0x44, 0xf6, 0x8e, 0x18, 0x30, 0x89, 0xcf, 0x0c, 0xba, 0x2b, 0x79, 0xe8,
-	0x7e, 0x0e, 0x5b, 0x29, 0x72, 0x18, 0x09, 0x7d, 0x92, 0x5d, 0xaf, 0x55, 0xe9, 0xe7, 0xbe, 0x39,
-	0xfe, 0x5e, 0x9e, 0x1a, 0xc4, 0xbd, 0x80, 0xed, 0x54, 0x8f, 0x12, 0x2f, 0x20, 0xd7, 0x39, 0xcd,
-	0x9a, 0xd4, 0x4c, 0x0d, 0x3b, 0xfa, 0x7c, 0x39, 0x5a, 0xed, 0x45, 0x68, 0xed, 0x41, 0xea, 0xbb,
-	0x88, 0x58, 0x90, 0xd2, 0x1b, 0xe6, 0x30, 0x8f, 0xda, 0x5d, 0x68, 0x0a, 0x8a, 0xf8, 0x03, 0x77,
-	0x22, 0xd0, 0xd4, 0xae, 0x4b, 0xd9, 0x86, 0x62, 0x1e, 0x4b, 0x1e, 0x7a, 0x02, 0x2d, 0x4a, 0x7c,
-	0x42, 0x26, 0x99, 0x58, 0x43, 0x8a, 0xad, 0x19, 0xb6, 0x12, 0xc4, 0x7f, 0x59, 0x50, 0xea, 0x27,
-	0x22, 0xde, 0x0f, 0x4a, 0xa3, 0xba, 0xd6, 0x64, 0x85, 0x92, 0xec, 0x82, 0x66, 0x0c, 0xa6, 0xf1,
-	0x50, 0xc0, 0x42, 0x75, 0xb0, 0xa1, 0x98, 0x17, 0x92, 0x87, 0x9e, 0xc2, 0xfa, 0x5c, 0xb9, 0x54,
-	0x43, 0x5b, 0x74, 0xae, 0x4c, 0x55, 0x57, 0xe0, 0x45, 0x36, 0xb6, 0xde, 0x6b, 0xe5, 0xae, 0x8a,
-	0x02, 0xa9, 0x3c, 0x15, 0xc8, 0x98, 0x10, 0xc6, 0xdc, 0x91, 0xea, 0xb6, 0xed, 0x18, 0x12, 0x21,
-	0xa8, 0xb0, 0x60, 0x14, 0xea, 0x17, 0x43, 0x7e, 0x0b, 0x1e, 0x9f, 0x4d, 0x89, 0x6e, 0xae, 0xfc,
-	0x96, 0x98, 0xe3, 0x2e, 0x8f, 0x4d, 0xe3, 0x34, 0x85, 0x9f, 0x82, 0xdd, 0x4f, 0xcc, 0x0b, 0xfa,
-	0x08, 0x4a, 0x3c, 0x91, 0x89, 0xd7, 0x7b, 0x8d, 0xdc, 0x33, 0x9e, 0x38, 0x25, 0x9e, 0xe0, 0x9f,
-	0x2d, 0x00, 0x21, 0xab, 0x1f, 0xd0, 0x0d, 0xa8, 0xf2, 0x64, 0x10, 0xf8, 0xba, 0x50, 0x15, 0x9e,
-	0xbc, 0xf6, 0x45, 0xa0, 0x53, 0x12, 0xfa, 0x41, 0x38, 0xd2, 0x03, 0xc9, 0x90, 0x22, 0x80, 0xf7,
-	0x31, 0x89, 0x89, 0x2f, 0x4b, 0x51, 0x76, 0x34, 0x95, 0x0b, 0xac, 0x92, 0x0f, 0x6c, 0x79, 0xca,
-	0xf8, 0x2b, 0x78, 0x90, 0xbe, 0x23, 0xa3, 0x80, 0x71, 0x42, 0x4d, 0xfc, 0x73, 0xdd, 0xb1, 0xe6,
-	0xbb, 0x83, 0x3f, 0x86, 0x56, 0x3f, 0x39, 0x25, 0xdc, 0x0d, 0xc6, 0x46, 0x6f, 0x51, 0x2a, 0xf8,
-	0x17, 0x0b, 0xd6, 0x33, 0xc1, 0x9b, 0x92, 0x56, 0x65, 0x2b, 0x2d, 0x2e, 0x1b, 0x3a, 0x04, 0xf0,
-	0x28, 0x71, 0x79, 0x10, 0x85, 0xa7, 0x6a, 0x6c, 0x2c, 0x99, 0x91, 0x39, 0x31, 0xb4, 0x0d, 0x35,
-	0x75, 0x1d, 0x02, 0x5f, 0xdf, 0xf9, 0x55, 0x49, 0xbf, 0xf6, 0xf1, 0xaf, 0x16, 0xd4, 0xfb, 0xd4,
-	0x0d, 0x99, 0xeb, 0x09, 0x61, 0x84, 0x41, 0xe7, 0x97, 0xcb, 0xb9, 0xe1, 0x14, 0x78, 0xe8, 0x11,
-	0xd8, 0x02, 0x19, 0x2e, 0x8f, 0xa9, 0x79, 0xb5, 0x32, 0x06, 0xfa, 0x3f, 0x00, 0x25, 0x5e, 0x11,
-	0xa9, 0x39, 0xce, 0x2d, 0x41, 0x8a, 0x8f, 0x00, 0xe5, 0xe2, 0x32, 0xb5, 0x7d, 0x2c, 0xae, 0x97,
-	0xc6, 0xd4, 0xfd, 0x5c, 0xda, 0x39, 0xc9, 0x52, 0x3f, 0xc1, 0x1c, 0x36, 0x0a, 0xca, 0xff, 0x09,
-	0xc8, 0xf0, 0x01, 0x6c, 0xf4, 0x13, 0x76, 0x32, 0xd3, 0xd7, 0xf1, 0x9f, 0x37, 0x89, 0x23, 0xa8,
-	0xf7, 0x13, 0x96, 0x86, 0xf7, 0x09, 0x94, 0xc5, 0x2a, 0x63, 0xc9, 0x39, 0xd7, 0xc9, 0xb7, 0xbe,
-	0x88, 0x1b, 0x47, 0x88, 0xe1, 0xef, 0xe0, 0xa1, 0xf2, 0x26, 0xca, 0x75, 0x1c, 0xfa, 0xb7, 0xf5,
-	0x2a, 0x9e, 0x75, 0xd5, 0x00, 0x3d, 0x70, 0x54, 0xbd, 0x4f, 0x05, 0x90, 0xdf, 0x4e, 0x7d, 0x97,
-	0x93, 0x9b, 0x80, 0x7c, 0x33, 0x3c, 0x31, 0x13, 0x28, 0x37, 0x56, 0x74, 0x5a, 0x59, 0xb9, 0x84,
-	0x9d, 0x5a, 0x7a, 0x27, 0x53, 0xf3, 0xa5, 0x39, 0xf3, 0xe5, 0x25, 0xe8, 0xdf, 0x84, 0x2a, 0xa1,
-	0x34, 0xa2, 0xba, 0xf0, 0x8a, 0xc0, 0x3f, 0x59, 0x50, 0x33, 0xd3, 0xf8, 0x86, 0xbc, 0x73, 0x1b,
-	0x53, 0xa9, 0xb8, 0x31, 0x2d, 0xdc, 0x72, 0xca, 0x8b, 0xb7, 0x9c, 0x74, 0x28, 0x56, 0x72, 0x43,
-	0x11, 0xff, 0x66, 0x41, 0xb3, 0xb0, 0x10, 0xa0, 0xe7, 0xa6, 0xca, 0xaa, 0x9d, 0x78, 0xc9, 0xe2,
-	0xa0, 0x40, 0xaf, 0xb6, 0x14, 0xa5, 0xd0, 0x39, 0x07, 0xc8, 0x98, 0x0b, 0x16, 0x86, 0x6e, 0x71,
-	0x25, 0x41, 0xf3, 0x96, 0xf3, 0x4b, 0x84, 0x7e, 0xa0, 0xc6, 0xe4, 0xe6, 0xbe, 0xe2, 0xcf, 0x84,
-	0xdc, 0xb9, 0x1c, 0x7a, 0x73, 0x1b, 0x7e, 0x18, 0x4f, 0x86, 0x84, 0x16, 0x96, 0xf5, 0x37, 0x92,
-	0x85, 0xbf, 0x16, 0xfd, 0x36, 0x5a, 0x77, 0x82, 0xb1, 0xf4, 0xeb, 0xc8, 0x29, 0xfa, 0x6f, 0xfd,
-	0x1a, 0xad, 0x3b, 0xf9, 0xfd, 0x12, 0xb6, 0xe4, 0xf5, 0xb9, 0xdb, 0x9f, 0x0d, 0x82, 0xf5, 0x73,
-	0x03, 0x0c, 0xad, 0x76, 0xb2, 0x07, 0xf7, 0xbc, 0x68, 0xb2, 0x7f, 0x49, 0xa8, 0x1f, 0xc4, 0x4c,
-	0xf9, 0x3f, 0x69, 0xbc, 0x52, 0xe4, 0x85, 0xa0, 0x2e, 0xac, 0x1f, 0xd2, 0x5f, 0xb2, 0xe1, 0x8a,
-	0xfc, 0x3a, 0xfc, 0x3b, 0x00, 0x00, 0xff, 0xff, 0x2e, 0xe8, 0x6e, 0xfa, 0xc1, 0x0d, 0x00, 0x00,
+	proto.RegisterType((*SubscribeRequest)(nil), "protobuf.SubscribeRequest")
+	proto.RegisterType((*SubscribeResponse)(nil), "protobuf.SubscribeResponse")
+	proto.RegisterType((*UnsubscribeRequest)(nil), "protobuf.UnsubscribeRequest")
+	proto.RegisterType((*Notification)(nil), "protobuf.Notification")
+	proto.RegisterType((*TxAnnouncement)(nil), "protobuf.TxAnnouncement")
+	proto.RegisterType((*TxFetchRequest)(nil), "protobuf.TxFetchRequest")
+	proto.RegisterType((*TxFetchResponse)(nil), "protobuf.TxFetchResponse")
+}
+
+func init() { proto.RegisterFile("hbi/protobuf/service.proto", fileDescriptor_ebece8ff681ed6b0) }
+
+var fileDescriptor_ebece8ff681ed6b0 = []byte{
+	// 1549 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xbc, 0x58, 0x4b, 0x73, 0xdb, 0x46,
+	0x12, 0x5e, 0xf0, 0x21, 0x12, 0x4d, 0x52, 0xa4, 0x46, 0xb2, 0x4d, 0xcb, 0x8f, 0xd2, 0xc2, 0xe5,
+	0x95, 0xec, 0x5d, 0xc9, 0x6b, 0x7a, 0x6b, 0xd7, 0x65, 0xaf, 0x2b, 0xa1, 0x22, 0x39, 0x76, 0x59,
+	0x71, 0x5c, 0x43, 0x28, 0xe5, 0xca, 0x85, 0x05, 0x02, 0x63, 0x0a, 0x31, 0x09, 0xd0, 0x98, 0x81,
+	0x02, 0xdd, 0x72, 0xc9, 0x3f, 0xc8, 0xe3, 0x27, 0xe4, 0x9a, 0x73, 0x8e, 0xf9, 0x31, 0xf9, 0x1d,
+	0xa9, 0x79, 0xe1, 0x61, 0x92, 0x8a, 0xe2, 0x43, 0x6e, 0xe8, 0x9e, 0x7e, 0xf7, 0xd7, 0x33, 0x4d,
+	0xc2, 0xe6, 0xc9, 0xc8, 0xbf, 0x37, 0x8b, 0x42, 0x16, 0x8e, 0xe2, 0x37, 0xf7, 0x28, 0x89, 0x4e,
+	0x7d, 0x97, 0xec, 0x09, 0x06, 0xaa, 0x6b, 0xbe, 0xf5, 0x18, 0x4c, 0xdb, 0x9f, 0x12, 0xca, 0x9c,
+	0xe9, 0x0c, 0x75, 0xa1, 0x46, 0x89, 0x1b, 0x06, 0x1e, 0xed, 0x1a, 0x5b, 0xc6, 0x4e, 0x19, 0x6b,
+	0x12, 0x6d, 0x40, 0x35, 0x70, 0x82, 0x90, 0x76, 0x4b, 0x82, 0x2f, 0x09, 0xeb, 0x7f, 0x80, 0xf6,
+	0x27, 0xa1, 0xfb, 0xf6, 0x19, 0xf1, 0xc7, 0x27, 0x0c, 0x93, 0x77, 0x31, 0xa1, 0x0c, 0xfd, 0x1d,
+	0x9a, 0x23, 0xce, 0x1d, 0x9e, 0x08, 0xb6, 0x32, 0xd5, 0x18, 0x65, 0x92, 0xd6, 0x4f, 0x06, 0xb4,
+	0x84, 0x26, 0x26, 0x74, 0x16, 0x06, 0x94, 0x5c, 0x40, 0x09, 0x6d, 0x43, 0x85, 0xf9, 0x53, 0x22,
+	0x42, 0x68, 0xf4, 0xd6, 0xf7, 0x74, 0x0e, 0x7b, 0x69, 0x02, 0x58, 0x08, 0xa0, 0x6b, 0x60, 0xb2,
+	0x90, 0x39, 0x93, 0x21, 0x4b, 0x68, 0xb7, 0xbc, 0x65, 0xec, 0x54, 0x70, 0x5d, 0x30, 0xec, 0x84,
+	0xa2, 0x5d, 0x40, 0x34, 0x9e, 0xf1, 0x6a, 0xd0, 0x30, 0x1a, 0x3a, 0x9e, 0x17, 0x11, 0x4a, 0xbb,
+	0x95, 0x2d, 0x63, 0xc7, 0xc4, 0x6b, 0xd9, 0x49, 0x5f, 0x1e, 0x58, 0x77, 0x61, 0xb5, 0xef, 0xba,
+	0x61, 0x1c, 0xa4, 0xe9, 0x75, 0xa1, 0xa6, 0xb5, 0x0c, 0xa1, 0xa5, 0x49, 0xeb, 0xb7, 0x0a, 0xb4,
+	0x53, 0x61, 0x95, 0xd7, 0x52, 0x69, 0x51, 0xd2, 0x30, 0x70, 0x65, 0x3e, 0x15, 0x2c, 0x09, 0x5e,
+	0x07, 0xca, 0xc2, 0xc8, 0x19, 0x93, 0x61, 0x14, 0x86, 0x4c, 0x84, 0x6f, 0xe2, 0x86, 0xe2, 0xe1,
+	0x30, 0x64, 0xe8, 0x06, 0xc0, 0x2c, 0x1e, 0x4d, 0x7c, 0x77, 0xf8, 0x96, 0x9c, 0xa9, 0xc8, 0x4d,
+	0xc9, 0x79, 0x41, 0xce, 0xb8, 0xc7, 0x91, 0x33, 0x71, 0xb8, 0xe5, 0xaa, 0xb0, 0xac, 0x49, 0x74,
+	0x0b, 0x5a, 0x24, 0x72, 0x7b, 0xff, 0x4e, 0xb3, 0x5e, 0x11, 0xba, 0x4d, 0xc1, 0x54, 0x09, 0xa3,
+	0xdb, 0xb0, 0x4a, 0x12, 0x46, 0xa2, 0xc0, 0x99, 0x0c, 0x65, 0x7c, 0x35, 0x61, 0xa5, 0xa5, 0xb9,
+	0x2f, 0x45, 0x9c, 0x77, 0x61, 0x6d, 0xe2, 0x50, 0x36, 0x2c, 0x34, 0xad, 0x2e, 0x24, 0xdb, 0xfc,
+	0x20, 0x87, 0x0b, 0xf4, 0x14, 0x4c, 0xb2, 0x2f, 0x63, 0xa0, 0x5d, 0x73, 0xab, 0xbc, 0xd3, 0xe8,
+	0xed, 0x64, 0xdd, 0x7b, 0xaf, 0x62, 0x7b, 0x87, 0x5a, 0xf4, 0x30, 0x60, 0xd1, 0x19, 0xce, 0x54,
+	0xd1, 0x18, 0x36, 0x9e, 0xfa, 0x11, 0x65, 0x87, 0x2a, 0x12, 0x15, 0x72, 0x17, 0x84, 0xc9, 0x07,
+	0xcb, 0x4d, 0x2e, 0xd2, 0x92, 0xd6, 0x17, 0x1a, 0xdc, 0x3c, 0x86, 0xd5, 0x62, 0x14, 0xa8, 0x03,
+	0x65, 0x5e, 0x6c, 0xd9, 0x42, 0xfe, 0x89, 0x76, 0xa1, 0x7a, 0xea, 0x4c, 0x62, 0x0d, 0xc7, 0x2b,
+	0x99, 0x77, 0xad, 0xda, 0xa7, 0x94, 0x30, 0x2c, 0xa5, 0x1e, 0x95, 0x1e, 0x1a, 0x9b, 0x9f, 0xc2,
+	0xd5, 0xa5, 0x91, 0x2c, 0xf0, 0xb0, 0x91, 0xf7, 0x60, 0xe6, 0x0c, 0x59, 0x3f, 0x97, 0xa1, 0x2a,
+	0xac, 0xa3, 0x4d, 0xa8, 0xbb, 0x0e, 0x23, 0xe3, 0x30, 0xd2, 0xaa, 0x29, 0x8d, 0x2e, 0xc3, 0x0a,
+	0x3d, 0x9b, 0x8e, 0xc2, 0x89, 0x32, 0xa0, 0x28, 0x0e, 0x90, 0x80, 0xb0, 0xaf, 0xc3, 0xe8, 0xad,
+	0x42, 0x97, 0x26, 0x33, 0x8f, 0x15, 0x09, 0x49, 0x41, 0xf0, 0xc8, 0xde, 0x10, 0x0d, 0x26, 0xfe,
+	0x99, 0x41, 0x77, 0x25, 0x0f, 0xdd, 0xff, 0xc2, 0x95, 0x14, 0x39, 0x94, 0x04, 0x1e, 0xc9, 0xc6,
+	0xab, 0x26, 0xfc, 0x5c, 0xd2, 0xc7, 0x03, 0x71, 0xaa, 0x11, 0xf7, 0x08, 0xae, 0xa6, 0x7a, 0x11,
+	0x71, 0x7d, 0x72, 0x9a, 0xd3, 0xac, 0x0b, 0xcd, 0xd4, 0x30, 0x56, 0xe7, 0xcb, 0xd1, 0x6a, 0x2e,
+	0x42, 0x6b, 0x0f, 0x52, 0xdf, 0x45, 0xc4, 0x82, 0x90, 0x5e, 0xd7, 0x87, 0x79, 0xd4, 0xde, 0x82,
+	0x16, 0xa7, 0x88, 0x37, 0x74, 0xa6, 0x1c, 0x4d, 0xdd, 0x86, 0x90, 0x6d, 0x4a, 0x66, 0x5f, 0xf0,
+	0xd0, 0x36, 0xb4, 0x23, 0xe2, 0x11, 0x32, 0xcd, 0xc4, 0x9a, 0x42, 0x6c, 0x55, 0xb3, 0xa5, 0xa0,
+	0xf5, 0x4b, 0x09, 0x4a, 0x76, 0xc2, 0xe3, 0x7d, 0xaf, 0x34, 0xb2, 0x6b, 0x2d, 0x5a, 0x28, 0xc9,
+	0x2d, 0x50, 0x8c, 0xe1, 0x2c, 0x1e, 0x71, 0x58, 0xc8, 0x0e, 0x36, 0x25, 0xf3, 0x95, 0xe0, 0xa1,
+	0x3b, 0xd0, 0x99, 0x2b, 0x97, 0x6c, 0x68, 0x3b, 0x9a, 0x2b, 0x53, 0xd5, 0xe1, 0x78, 0x11, 0x8d,
+	0x6d, 0xf4, 0xda, 0xb9, 0x51, 0x91, 0x20, 0x15, 0xa7, 0x1c, 0x19, 0x53, 0x42, 0xa9, 0x33, 0x96,
+	0xdd, 0x36, 0xb1, 0x26, 0x11, 0x82, 0x0a, 0xf5, 0xc7, 0x81, 0xba, 0x31, 0xc4, 0x37, 0xe7, 0xb1,
+	0xb3, 0x19, 0x51, 0xcd, 0x15, 0xdf, 0x02, 0x73, 0xcc, 0x61, 0xb1, 0x6e, 0x9c, 0xa2, 0xc4, 0xb5,
+	0xe6, 0x8f, 0x83, 0xe1, 0x29, 0x89, 0xa8, 0x1f, 0x06, 0xa2, 0x4b, 0x2d, 0xdc, 0xe0, 0xbc, 0x2f,
+	0x24, 0x0b, 0x5d, 0x85, 0xba, 0x7b, 0xe2, 0xf8, 0xc1, 0xd0, 0xf7, 0x44, 0x5b, 0x4c, 0x5c, 0x13,
+	0xf4, 0x73, 0xcf, 0xba, 0x03, 0xa6, 0x9d, 0xe8, 0xfb, 0xf7, 0x3a, 0x94, 0x58, 0x22, 0xca, 0xd6,
+	0xe8, 0x35, 0x73, 0x8f, 0x40, 0x82, 0x4b, 0x2c, 0xb1, 0xbe, 0x35, 0x00, 0xec, 0x44, 0x4f, 0x3e,
+	0x5a, 0x87, 0x2a, 0x4b, 0xb8, 0x45, 0x43, 0x05, 0x99, 0x3c, 0xf7, 0x78, 0x9a, 0x33, 0x12, 0x78,
+	0x7e, 0x30, 0x56, 0xcf, 0x99, 0x26, 0x79, 0xf8, 0xef, 0x62, 0x12, 0x13, 0x4f, 0x14, 0xb2, 0x8c,
+	0x15, 0x95, 0x4b, 0xab, 0x52, 0x48, 0x6b, 0x69, 0xc1, 0xac, 0x27, 0x70, 0x39, 0xbd, 0x85, 0xc6,
+	0x3e, 0x65, 0x24, 0xd2, 0xf1, 0xcf, 0xf5, 0xd6, 0x98, 0xef, 0xad, 0xf5, 0x0f, 0x68, 0xdb, 0xc9,
+	0x01, 0x61, 0x8e, 0x3f, 0xd1, 0x7a, 0x8b, 0x52, 0xb1, 0x7e, 0x35, 0xa0, 0x93, 0x09, 0x9e, 0x97,
+	0xb4, 0x2c, 0x5b, 0x69, 0x71, 0xd9, 0xd0, 0x03, 0x00, 0x37, 0x22, 0x0e, 0xf3, 0xc3, 0xe0, 0x40,
+	0x3e, 0x3a, 0x4b, 0x5e, 0xd8, 0x9c, 0x18, 0xef, 0x98, 0x1c, 0x26, 0xdf, 0x53, 0x37, 0x46, 0x4d,
+	0xd0, 0xcf, 0x3d, 0xf4, 0x4f, 0xa8, 0x45, 0xc4, 0x25, 0xfe, 0x8c, 0x89, 0xc2, 0x34, 0x7a, 0x6b,
+	0x99, 0x31, 0x2c, 0x0f, 0xb0, 0x96, 0xb0, 0xbe, 0x37, 0xa0, 0xa6, 0x98, 0x8b, 0x63, 0xcf, 0xca,
+	0x5f, 0x2a, 0x94, 0x1f, 0x41, 0xc5, 0x0d, 0x3d, 0x22, 0xe2, 0x6d, 0x61, 0xf1, 0x9d, 0x6f, 0x49,
+	0xa5, 0x88, 0xe1, 0xf9, 0x7b, 0xec, 0x06, 0x00, 0xb7, 0xa4, 0x9e, 0x5a, 0x8e, 0xed, 0x26, 0x36,
+	0x05, 0x87, 0x3f, 0xb4, 0xd6, 0x0f, 0x06, 0x34, 0xec, 0xc8, 0x09, 0xa8, 0xe3, 0xf2, 0x8c, 0x91,
+	0x05, 0xaa, 0x49, 0xb9, 0xc6, 0x35, 0x71, 0x81, 0x87, 0xae, 0x83, 0xc9, 0x41, 0xed, 0xb0, 0x38,
+	0xd2, 0x17, 0x77, 0xc6, 0x40, 0x37, 0x01, 0x22, 0xe2, 0x16, 0x87, 0x35, 0xc7, 0xb9, 0xe0, 0x9c,
+	0x5a, 0x8f, 0x01, 0xe5, 0xe2, 0xd2, 0x00, 0xb9, 0xcd, 0x6f, 0x18, 0x35, 0x18, 0x97, 0x72, 0xbd,
+	0xcb, 0x49, 0x96, 0xec, 0xc4, 0x62, 0xb0, 0x5e, 0x50, 0xfe, 0x4b, 0x26, 0xc5, 0xba, 0x07, 0xeb,
+	0x76, 0x42, 0xf7, 0xcf, 0xd4, 0x8d, 0xf4, 0xc7, 0xcb, 0xd4, 0x63, 0x68, 0xd8, 0x09, 0x4d, 0xc3,
+	0xfb, 0x17, 0x94, 0xf9, 0x36, 0x67, 0x88, 0xa7, 0x7e, 0x33, 0x8f, 0xdf, 0x22, 0xf8, 0x31, 0x17,
+	0xb3, 0x3e, 0x83, 0x6b, 0xd2, 0x1b, 0x2f, 0x57, 0x3f, 0xf0, 0x2e, 0xea, 0x95, 0xbf, 0x6c, 0xb2,
+	0x01, 0xea, 0xcd, 0x95, 0xf5, 0x3e, 0xe0, 0xd3, 0x78, 0x3c, 0xf3, 0x38, 0x30, 0xce, 0x99, 0xc6,
+	0xf3, 0x67, 0xcc, 0xa2, 0xd0, 0xc9, 0xac, 0xa8, 0xb4, 0xb2, 0x72, 0x71, 0x3b, 0xf5, 0x14, 0xd9,
+	0xa9, 0xf9, 0xd2, 0x9c, 0xf9, 0xf2, 0x92, 0x11, 0xde, 0x80, 0x2a, 0x89, 0xa2, 0x30, 0x52, 0x85,
+	0x97, 0x84, 0xf5, 0x8d, 0x01, 0x75, 0xbd, 0x90, 0x9c, 0x93, 0x77, 0x6e, 0x69, 0x2c, 0x15, 0x97,
+	0xc6, 0x85, 0x8b, 0x5e, 0x79, 0xf1, 0xa2, 0x97, 0xee, 0x05, 0x95, 0xdc, 0x5e, 0x60, 0xfd, 0x68,
+	0x40, 0xab, 0xb0, 0x13, 0xa1, 0x87, 0xba, 0xca, 0xb2, 0x9d, 0xd6, 0x92, 0xdd, 0x49, 0x82, 0x5e,
+	0x2e, 0x6a, 0x52, 0x61, 0xf3, 0x08, 0x20, 0x63, 0x2e, 0xd8, 0x99, 0x76, 0x8a, 0x5b, 0x19, 0x9a,
+	0xb7, 0x9c, 0xdf, 0xa3, 0xd4, 0x2d, 0x3b, 0x21, 0xe7, 0xf7, 0xd5, 0xfa, 0x0f, 0x97, 0x3b, 0x12,
+	0xef, 0xfe, 0xdc, 0x8f, 0x9c, 0x20, 0x9e, 0x8e, 0x48, 0x54, 0xf8, 0xbd, 0xf2, 0x52, 0xb0, 0xac,
+	0x8f, 0xa1, 0x93, 0x69, 0x7d, 0x10, 0x8c, 0x85, 0x5f, 0x2c, 0x16, 0x89, 0x3f, 0xeb, 0x57, 0x6b,
+	0x7d, 0x90, 0xdf, 0xff, 0xc3, 0x15, 0x31, 0x3e, 0x1f, 0xf6, 0xe3, 0x0e, 0x41, 0xe7, 0x48, 0x03,
+	0x43, 0xa9, 0x59, 0x0c, 0x3a, 0x83, 0x78, 0x44, 0xdd, 0xc8, 0x1f, 0xa5, 0xa5, 0xbe, 0x0f, 0x55,
+	0x16, 0xce, 0x7c, 0x57, 0xd8, 0x58, 0xed, 0x5d, 0xcb, 0xa2, 0x52, 0xa2, 0x33, 0x7e, 0x41, 0xd9,
+	0x5c, 0x04, 0x4b, 0xc9, 0x3c, 0x80, 0x4b, 0x45, 0x00, 0xa7, 0x7d, 0x2b, 0xe7, 0xfa, 0xf6, 0x15,
+	0xac, 0xe5, 0xbc, 0xaa, 0x52, 0x6c, 0x43, 0x9b, 0xe6, 0xec, 0xeb, 0x5e, 0x57, 0xf0, 0x6a, 0x9e,
+	0x3d, 0xf7, 0xea, 0x64, 0xb3, 0x99, 0x0e, 0x5a, 0x39, 0x3f, 0x68, 0x4f, 0x00, 0x1d, 0x07, 0xf4,
+	0xfd, 0x1c, 0x2f, 0xea, 0xcc, 0xfa, 0xce, 0x80, 0xe6, 0xcb, 0x90, 0xf9, 0x6f, 0x7c, 0x57, 0x3c,
+	0xaf, 0x17, 0x0f, 0x73, 0x17, 0xaa, 0xa2, 0xfa, 0xf3, 0x3f, 0x44, 0x0a, 0xbf, 0xb0, 0xb1, 0x94,
+	0xd2, 0x48, 0x28, 0x5f, 0x0c, 0x09, 0xdb, 0xb0, 0x6a, 0x27, 0xfd, 0x20, 0x08, 0xe3, 0xc0, 0x25,
+	0x53, 0x12, 0x30, 0x74, 0x09, 0x56, 0x44, 0xa1, 0x25, 0x98, 0x4c, 0x5c, 0xe5, 0x95, 0x56, 0x82,
+	0x4f, 0x09, 0x73, 0x4f, 0x74, 0xea, 0x4b, 0x04, 0xef, 0x43, 0x3b, 0x15, 0x54, 0x1d, 0xb9, 0x99,
+	0x07, 0x67, 0xf1, 0x62, 0xe3, 0x07, 0x77, 0x3f, 0x4a, 0xdb, 0x98, 0x21, 0x02, 0x01, 0xac, 0xec,
+	0x1f, 0x7d, 0xfe, 0xc9, 0x8b, 0x41, 0xe7, 0x6f, 0xa8, 0x0d, 0x8d, 0xfe, 0xc1, 0x01, 0x3e, 0x1c,
+	0x0c, 0x86, 0xf6, 0xeb, 0x41, 0xc7, 0x40, 0x2d, 0x30, 0xed, 0xd7, 0xc3, 0x81, 0xdd, 0xb7, 0x8f,
+	0x07, 0x9d, 0xd2, 0xfe, 0x2e, 0xac, 0xb9, 0xe1, 0x74, 0xef, 0x84, 0x44, 0x9e, 0x1f, 0x53, 0xe9,
+	0x60, 0xbf, 0xf9, 0x4c, 0x92, 0xaf, 0x38, 0xf5, 0xca, 0xf8, 0x32, 0xfd, 0x4f, 0x64, 0xb4, 0x22,
+	0xbe, 0x1e, 0xfc, 0x3e, 0x00, 0x7f, 0xdd, 0xd0, 0xec, 0x42, 0x11, 0x00, 0x00,
 }
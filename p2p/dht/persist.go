@@ -0,0 +1,76 @@
+package dht
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/herdius/herdius-core/p2p/peer"
+	"github.com/pkg/errors"
+)
+
+// savedPeer is a peer as persisted by Save.
+type savedPeer struct {
+	Address   string `json:"address"`
+	PublicKey string `json:"public_key"`
+}
+
+// Save persists the peers of the routing table (excluding itself) to path.
+// The file is replaced at once, through a temporary file of its own, so that
+// concurrent saves never leave a partly written table.
+func (t *RoutingTable) Save(path string) error {
+	peers := make([]savedPeer, 0)
+	for _, id := range t.GetPeers() {
+		peers = append(peers, savedPeer{Address: id.Address, PublicKey: id.PublicKeyHex()})
+	}
+	data, err := json.MarshalIndent(peers, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	f, err := ioutil.TempFile(filepath.Dir(path), "."+filepath.Base(path)+".tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(f.Name(), 0644); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), path)
+}
+
+// LoadPeers returns the peers persisted to path by Save, or no peers if
+// nothing was saved yet.
+func LoadPeers(path string) ([]peer.ID, error) {
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var saved []savedPeer
+	if err := json.Unmarshal(data, &saved); err != nil {
+		return nil, errors.Wrap(err, "failed to decode routing table")
+	}
+	peers := make([]peer.ID, 0, len(saved))
+	for _, p := range saved {
+		publicKey, err := hex.DecodeString(p.PublicKey)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to decode public key of peer %s", p.Address)
+		}
+		peers = append(peers, peer.CreateID(p.Address, publicKey))
+	}
+	return peers, nil
+}
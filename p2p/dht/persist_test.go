@@ -0,0 +1,67 @@
+package dht
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSaveLoadPeers(t *testing.T) {
+	dir, err := ioutil.TempDir(os.TempDir(), "dht_test_")
+	require.Nil(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "routes", "routes.json")
+
+	peers, err := LoadPeers(path)
+	require.Nil(t, err)
+	assert.Empty(t, peers)
+
+	routingTable := CreateRoutingTable(id1)
+	routingTable.Update(id2)
+	routingTable.Update(id3)
+	require.Nil(t, routingTable.Save(path))
+
+	peers, err = LoadPeers(path)
+	require.Nil(t, err)
+	require.Equal(t, 2, len(peers))
+	for _, id := range peers {
+		assert.True(t, routingTable.PeerExists(id))
+		assert.False(t, id.Equals(id1), "the table's own ID should not be saved")
+	}
+
+	require.Nil(t, ioutil.WriteFile(path, []byte("not json"), 0644))
+	_, err = LoadPeers(path)
+	assert.Error(t, err)
+}
+
+func TestSaveConcurrently(t *testing.T) {
+	dir, err := ioutil.TempDir(os.TempDir(), "dht_test_")
+	require.Nil(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "routes.json")
+
+	routingTable := CreateRoutingTable(id1)
+	routingTable.Update(id2)
+	routingTable.Update(id3)
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			assert.Nil(t, routingTable.Save(path))
+		}()
+	}
+	wg.Wait()
+
+	peers, err := LoadPeers(path)
+	require.Nil(t, err)
+	assert.Equal(t, 2, len(peers))
+	files, err := ioutil.ReadDir(dir)
+	require.Nil(t, err)
+	assert.Equal(t, 1, len(files), "no temporary file is left behind")
+}
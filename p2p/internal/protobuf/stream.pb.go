@@ -3,10 +3,12 @@
 
 package protobuf
 
-import proto "github.com/golang/protobuf/proto"
-import fmt "fmt"
-import math "math"
-import _ "github.com/gogo/protobuf/gogoproto"
+import (
+	fmt "fmt"
+	_ "github.com/gogo/protobuf/gogoproto"
+	proto "github.com/golang/protobuf/proto"
+	math "math"
+)
 
 // Reference imports to suppress errors if they are not otherwise used.
 var _ = proto.Marshal
@@ -17,7 +19,7 @@ var _ = math.Inf
 // is compatible with the proto package it is being compiled against.
 // A compilation error at this line likely means your copy of the
 // proto package needs to be updated.
-const _ = proto.ProtoPackageIsVersion2 // please upgrade the proto package
+const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
 
 type ID struct {
 	// public_key of the peer (we no longer use the public key as the peer ID, but use it to verify messages)
@@ -35,16 +37,17 @@ func (m *ID) Reset()         { *m = ID{} }
 func (m *ID) String() string { return proto.CompactTextString(m) }
 func (*ID) ProtoMessage()    {}
 func (*ID) Descriptor() ([]byte, []int) {
-	return fileDescriptor_stream_4f891be46b934eb3, []int{0}
+	return fileDescriptor_bb17ef3f514bfe54, []int{0}
 }
+
 func (m *ID) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_ID.Unmarshal(m, b)
 }
 func (m *ID) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
 	return xxx_messageInfo_ID.Marshal(b, m, deterministic)
 }
-func (dst *ID) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_ID.Merge(dst, src)
+func (m *ID) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ID.Merge(m, src)
 }
 func (m *ID) XXX_Size() int {
 	return xxx_messageInfo_ID.Size(m)
@@ -93,16 +96,17 @@ func (m *ChildBlock) Reset()         { *m = ChildBlock{} }
 func (m *ChildBlock) String() string { return proto.CompactTextString(m) }
 func (*ChildBlock) ProtoMessage()    {}
 func (*ChildBlock) Descriptor() ([]byte, []int) {
-	return fileDescriptor_stream_4f891be46b934eb3, []int{1}
+	return fileDescriptor_bb17ef3f514bfe54, []int{1}
 }
+
 func (m *ChildBlock) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_ChildBlock.Unmarshal(m, b)
 }
 func (m *ChildBlock) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
 	return xxx_messageInfo_ChildBlock.Marshal(b, m, deterministic)
 }
-func (dst *ChildBlock) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_ChildBlock.Merge(dst, src)
+func (m *ChildBlock) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ChildBlock.Merge(m, src)
 }
 func (m *ChildBlock) XXX_Size() int {
 	return xxx_messageInfo_ChildBlock.Size(m)
@@ -185,16 +189,17 @@ func (m *Message) Reset()         { *m = Message{} }
 func (m *Message) String() string { return proto.CompactTextString(m) }
 func (*Message) ProtoMessage()    {}
 func (*Message) Descriptor() ([]byte, []int) {
-	return fileDescriptor_stream_4f891be46b934eb3, []int{2}
+	return fileDescriptor_bb17ef3f514bfe54, []int{2}
 }
+
 func (m *Message) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_Message.Unmarshal(m, b)
 }
 func (m *Message) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
 	return xxx_messageInfo_Message.Marshal(b, m, deterministic)
 }
-func (dst *Message) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_Message.Merge(dst, src)
+func (m *Message) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Message.Merge(m, src)
 }
 func (m *Message) XXX_Size() int {
 	return xxx_messageInfo_Message.Size(m)
@@ -264,16 +269,17 @@ func (m *Ping) Reset()         { *m = Ping{} }
 func (m *Ping) String() string { return proto.CompactTextString(m) }
 func (*Ping) ProtoMessage()    {}
 func (*Ping) Descriptor() ([]byte, []int) {
-	return fileDescriptor_stream_4f891be46b934eb3, []int{3}
+	return fileDescriptor_bb17ef3f514bfe54, []int{3}
 }
+
 func (m *Ping) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_Ping.Unmarshal(m, b)
 }
 func (m *Ping) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
 	return xxx_messageInfo_Ping.Marshal(b, m, deterministic)
 }
-func (dst *Ping) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_Ping.Merge(dst, src)
+func (m *Ping) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Ping.Merge(m, src)
 }
 func (m *Ping) XXX_Size() int {
 	return xxx_messageInfo_Ping.Size(m)
@@ -294,16 +300,17 @@ func (m *Pong) Reset()         { *m = Pong{} }
 func (m *Pong) String() string { return proto.CompactTextString(m) }
 func (*Pong) ProtoMessage()    {}
 func (*Pong) Descriptor() ([]byte, []int) {
-	return fileDescriptor_stream_4f891be46b934eb3, []int{4}
+	return fileDescriptor_bb17ef3f514bfe54, []int{4}
 }
+
 func (m *Pong) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_Pong.Unmarshal(m, b)
 }
 func (m *Pong) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
 	return xxx_messageInfo_Pong.Marshal(b, m, deterministic)
 }
-func (dst *Pong) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_Pong.Merge(dst, src)
+func (m *Pong) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Pong.Merge(m, src)
 }
 func (m *Pong) XXX_Size() int {
 	return xxx_messageInfo_Pong.Size(m)
@@ -325,16 +332,17 @@ func (m *LookupNodeRequest) Reset()         { *m = LookupNodeRequest{} }
 func (m *LookupNodeRequest) String() string { return proto.CompactTextString(m) }
 func (*LookupNodeRequest) ProtoMessage()    {}
 func (*LookupNodeRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_stream_4f891be46b934eb3, []int{5}
+	return fileDescriptor_bb17ef3f514bfe54, []int{5}
 }
+
 func (m *LookupNodeRequest) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_LookupNodeRequest.Unmarshal(m, b)
 }
 func (m *LookupNodeRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
 	return xxx_messageInfo_LookupNodeRequest.Marshal(b, m, deterministic)
 }
-func (dst *LookupNodeRequest) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_LookupNodeRequest.Merge(dst, src)
+func (m *LookupNodeRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_LookupNodeRequest.Merge(m, src)
 }
 func (m *LookupNodeRequest) XXX_Size() int {
 	return xxx_messageInfo_LookupNodeRequest.Size(m)
@@ -363,16 +371,17 @@ func (m *LookupNodeResponse) Reset()         { *m = LookupNodeResponse{} }
 func (m *LookupNodeResponse) String() string { return proto.CompactTextString(m) }
 func (*LookupNodeResponse) ProtoMessage()    {}
 func (*LookupNodeResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_stream_4f891be46b934eb3, []int{6}
+	return fileDescriptor_bb17ef3f514bfe54, []int{6}
 }
+
 func (m *LookupNodeResponse) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_LookupNodeResponse.Unmarshal(m, b)
 }
 func (m *LookupNodeResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
 	return xxx_messageInfo_LookupNodeResponse.Marshal(b, m, deterministic)
 }
-func (dst *LookupNodeResponse) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_LookupNodeResponse.Merge(dst, src)
+func (m *LookupNodeResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_LookupNodeResponse.Merge(m, src)
 }
 func (m *LookupNodeResponse) XXX_Size() int {
 	return xxx_messageInfo_LookupNodeResponse.Size(m)
@@ -401,16 +410,17 @@ func (m *Bytes) Reset()         { *m = Bytes{} }
 func (m *Bytes) String() string { return proto.CompactTextString(m) }
 func (*Bytes) ProtoMessage()    {}
 func (*Bytes) Descriptor() ([]byte, []int) {
-	return fileDescriptor_stream_4f891be46b934eb3, []int{7}
+	return fileDescriptor_bb17ef3f514bfe54, []int{7}
 }
+
 func (m *Bytes) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_Bytes.Unmarshal(m, b)
 }
 func (m *Bytes) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
 	return xxx_messageInfo_Bytes.Marshal(b, m, deterministic)
 }
-func (dst *Bytes) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_Bytes.Merge(dst, src)
+func (m *Bytes) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Bytes.Merge(m, src)
 }
 func (m *Bytes) XXX_Size() int {
 	return xxx_messageInfo_Bytes.Size(m)
@@ -428,6 +438,130 @@ func (m *Bytes) GetData() []byte {
 	return nil
 }
 
+// Chunk is a piece of a message too large to be sent in one frame.
+type Chunk struct {
+	// transfer_id identifies the chunked message among the sender's transfers.
+	TransferId uint64 `protobuf:"varint,1,opt,name=transfer_id,json=transferId,proto3" json:"transfer_id,omitempty"`
+	// index is the position of the chunk in the message, starting at 0.
+	Index uint32 `protobuf:"varint,2,opt,name=index,proto3" json:"index,omitempty"`
+	Data  []byte `protobuf:"bytes,3,opt,name=data,proto3" json:"data,omitempty"`
+	// size is the size of the whole message. Only set on the first chunk.
+	Size uint64 `protobuf:"varint,4,opt,name=size,proto3" json:"size,omitempty"`
+	// digest is the SHA-256 digest of the whole message. Only set on the first chunk.
+	Digest               []byte   `protobuf:"bytes,5,opt,name=digest,proto3" json:"digest,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Chunk) Reset()         { *m = Chunk{} }
+func (m *Chunk) String() string { return proto.CompactTextString(m) }
+func (*Chunk) ProtoMessage()    {}
+func (*Chunk) Descriptor() ([]byte, []int) {
+	return fileDescriptor_bb17ef3f514bfe54, []int{8}
+}
+
+func (m *Chunk) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Chunk.Unmarshal(m, b)
+}
+func (m *Chunk) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Chunk.Marshal(b, m, deterministic)
+}
+func (m *Chunk) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Chunk.Merge(m, src)
+}
+func (m *Chunk) XXX_Size() int {
+	return xxx_messageInfo_Chunk.Size(m)
+}
+func (m *Chunk) XXX_DiscardUnknown() {
+	xxx_messageInfo_Chunk.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Chunk proto.InternalMessageInfo
+
+func (m *Chunk) GetTransferId() uint64 {
+	if m != nil {
+		return m.TransferId
+	}
+	return 0
+}
+
+func (m *Chunk) GetIndex() uint32 {
+	if m != nil {
+		return m.Index
+	}
+	return 0
+}
+
+func (m *Chunk) GetData() []byte {
+	if m != nil {
+		return m.Data
+	}
+	return nil
+}
+
+func (m *Chunk) GetSize() uint64 {
+	if m != nil {
+		return m.Size
+	}
+	return 0
+}
+
+func (m *Chunk) GetDigest() []byte {
+	if m != nil {
+		return m.Digest
+	}
+	return nil
+}
+
+// ChunkAck acknowledges a received chunk, letting the sender send more.
+type ChunkAck struct {
+	TransferId           uint64   `protobuf:"varint,1,opt,name=transfer_id,json=transferId,proto3" json:"transfer_id,omitempty"`
+	Index                uint32   `protobuf:"varint,2,opt,name=index,proto3" json:"index,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ChunkAck) Reset()         { *m = ChunkAck{} }
+func (m *ChunkAck) String() string { return proto.CompactTextString(m) }
+func (*ChunkAck) ProtoMessage()    {}
+func (*ChunkAck) Descriptor() ([]byte, []int) {
+	return fileDescriptor_bb17ef3f514bfe54, []int{9}
+}
+
+func (m *ChunkAck) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ChunkAck.Unmarshal(m, b)
+}
+func (m *ChunkAck) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ChunkAck.Marshal(b, m, deterministic)
+}
+func (m *ChunkAck) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ChunkAck.Merge(m, src)
+}
+func (m *ChunkAck) XXX_Size() int {
+	return xxx_messageInfo_ChunkAck.Size(m)
+}
+func (m *ChunkAck) XXX_DiscardUnknown() {
+	xxx_messageInfo_ChunkAck.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ChunkAck proto.InternalMessageInfo
+
+func (m *ChunkAck) GetTransferId() uint64 {
+	if m != nil {
+		return m.TransferId
+	}
+	return 0
+}
+
+func (m *ChunkAck) GetIndex() uint32 {
+	if m != nil {
+		return m.Index
+	}
+	return 0
+}
+
 // Timestamp wraps how amino encodes time.
 // This is the protobuf well-known type protobuf/timestamp.proto
 // See:
@@ -445,16 +579,17 @@ func (m *Timestamp) Reset()         { *m = Timestamp{} }
 func (m *Timestamp) String() string { return proto.CompactTextString(m) }
 func (*Timestamp) ProtoMessage()    {}
 func (*Timestamp) Descriptor() ([]byte, []int) {
-	return fileDescriptor_stream_4f891be46b934eb3, []int{8}
+	return fileDescriptor_bb17ef3f514bfe54, []int{10}
 }
+
 func (m *Timestamp) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_Timestamp.Unmarshal(m, b)
 }
 func (m *Timestamp) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
 	return xxx_messageInfo_Timestamp.Marshal(b, m, deterministic)
 }
-func (dst *Timestamp) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_Timestamp.Merge(dst, src)
+func (m *Timestamp) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Timestamp.Merge(m, src)
 }
 func (m *Timestamp) XXX_Size() int {
 	return xxx_messageInfo_Timestamp.Size(m)
@@ -488,47 +623,53 @@ func init() {
 	proto.RegisterType((*LookupNodeRequest)(nil), "protobuf.LookupNodeRequest")
 	proto.RegisterType((*LookupNodeResponse)(nil), "protobuf.LookupNodeResponse")
 	proto.RegisterType((*Bytes)(nil), "protobuf.Bytes")
+	proto.RegisterType((*Chunk)(nil), "protobuf.Chunk")
+	proto.RegisterType((*ChunkAck)(nil), "protobuf.ChunkAck")
 	proto.RegisterType((*Timestamp)(nil), "protobuf.Timestamp")
 }
 
-func init() { proto.RegisterFile("stream.proto", fileDescriptor_stream_4f891be46b934eb3) }
-
-var fileDescriptor_stream_4f891be46b934eb3 = []byte{
-	// 561 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x74, 0x52, 0x4d, 0x6f, 0xd4, 0x30,
-	0x10, 0xc5, 0xbb, 0x9b, 0x6d, 0x77, 0x48, 0x11, 0x18, 0x54, 0x45, 0x40, 0xa3, 0x2a, 0x20, 0xd1,
-	0x0b, 0x29, 0x02, 0x09, 0x81, 0xb8, 0x2d, 0x55, 0xa1, 0x82, 0x56, 0x95, 0x55, 0x71, 0xad, 0xbc,
-	0xc9, 0x34, 0xb5, 0x9a, 0xd8, 0xc1, 0x76, 0xaa, 0xee, 0x8d, 0x7f, 0xc1, 0x5f, 0xe0, 0xa7, 0x70,
-	0xe4, 0xc8, 0xb1, 0xdd, 0x13, 0x47, 0x7e, 0x02, 0x8a, 0x9d, 0x2d, 0xf4, 0x83, 0x93, 0xe7, 0xbd,
-	0x79, 0xcf, 0xf6, 0x3c, 0x1b, 0x42, 0x63, 0x35, 0xf2, 0x2a, 0xad, 0xb5, 0xb2, 0x8a, 0x2e, 0xba,
-	0x65, 0xd2, 0x1c, 0xdc, 0x7f, 0x5a, 0x08, 0x7b, 0xd8, 0x4c, 0xd2, 0x4c, 0x55, 0xeb, 0x85, 0x2a,
-	0xd4, 0xfa, 0xbc, 0xe3, 0x90, 0x03, 0xae, 0xf2, 0xc6, 0x64, 0x1b, 0x7a, 0x5b, 0x1b, 0x74, 0x05,
-	0xa0, 0x6e, 0x26, 0xa5, 0xc8, 0xf6, 0x8f, 0x70, 0x1a, 0x91, 0x55, 0xb2, 0x16, 0xb2, 0x91, 0x67,
-	0x3e, 0xe0, 0x94, 0x46, 0xb0, 0xc0, 0xf3, 0x5c, 0xa3, 0x31, 0x51, 0x6f, 0x95, 0xac, 0x8d, 0xd8,
-	0x1c, 0xd2, 0x5b, 0xd0, 0x13, 0x79, 0xd4, 0x77, 0x86, 0x9e, 0xc8, 0x93, 0xaf, 0x3d, 0x80, 0xb7,
-	0x87, 0xa2, 0xcc, 0xc7, 0xa5, 0xca, 0x8e, 0xe8, 0x33, 0x08, 0x4d, 0x53, 0xa3, 0x3e, 0x16, 0x46,
-	0xe9, 0xad, 0x0d, 0xb7, 0xf3, 0xcd, 0xe7, 0x61, 0x3a, 0xbf, 0x53, 0xba, 0xb5, 0xc1, 0x2e, 0x28,
-	0xe8, 0x6d, 0xe8, 0xdb, 0x13, 0x7f, 0x4c, 0xc8, 0xda, 0x92, 0x2e, 0xc3, 0x50, 0x36, 0xd5, 0xde,
-	0x89, 0x71, 0xc7, 0xf4, 0x59, 0x87, 0xe8, 0x13, 0x18, 0x58, 0x51, 0x61, 0x34, 0x70, 0x7b, 0xde,
-	0xfd, 0xbb, 0xe7, 0x9e, 0xa8, 0xd0, 0x58, 0x5e, 0xd5, 0xcc, 0x09, 0xe8, 0x43, 0x18, 0x19, 0x51,
-	0x48, 0x6e, 0x1b, 0x8d, 0x51, 0xe0, 0x67, 0x3b, 0x27, 0x68, 0x0a, 0xf4, 0x98, 0x97, 0x22, 0xe7,
-	0x56, 0xe9, 0x77, 0x5a, 0x35, 0xf5, 0x7b, 0x6e, 0x0e, 0xa3, 0xa1, 0x93, 0x5d, 0xd3, 0xa1, 0x2f,
-	0x61, 0x59, 0xe2, 0x89, 0xfd, 0x74, 0xd5, 0xb3, 0xe0, 0x3c, 0xff, 0xe9, 0x26, 0xbf, 0x08, 0x2c,
-	0x6c, 0xa3, 0x31, 0xbc, 0xc0, 0x36, 0xcf, 0xca, 0x97, 0x5d, 0xd6, 0x73, 0x48, 0x1f, 0xc3, 0xd0,
-	0xa0, 0xcc, 0x51, 0xbb, 0x04, 0x2e, 0x47, 0xd5, 0xf5, 0x2e, 0x4e, 0xd4, 0xbf, 0x3c, 0xd1, 0x23,
-	0x58, 0xd2, 0xf8, 0xb9, 0x41, 0x63, 0xf7, 0xa5, 0x92, 0x99, 0x4f, 0x68, 0xc0, 0xc2, 0x8e, 0xdc,
-	0x69, 0xb9, 0x56, 0xd4, 0x9d, 0xd9, 0x89, 0x02, 0x2f, 0xea, 0x48, 0x2f, 0x5a, 0x01, 0xd0, 0x58,
-	0x97, 0xd3, 0xfd, 0x83, 0x92, 0x17, 0x2e, 0x93, 0x45, 0x36, 0x72, 0xcc, 0x66, 0xc9, 0x8b, 0xf6,
-	0x65, 0x54, 0x9d, 0xa9, 0x1c, 0xdd, 0xe8, 0x4b, 0xac, 0x43, 0xc9, 0x10, 0x06, 0xbb, 0x42, 0x16,
-	0x6e, 0x55, 0xb2, 0x48, 0x5e, 0xc3, 0x9d, 0x8f, 0x4a, 0x1d, 0x35, 0xf5, 0x8e, 0xca, 0x91, 0xf9,
-	0x5b, 0xb4, 0x93, 0x5a, 0xae, 0x0b, 0xb4, 0xd7, 0x7e, 0x8a, 0xae, 0x97, 0xbc, 0x02, 0xfa, 0xaf,
-	0xd5, 0xd4, 0x4a, 0x1a, 0xa4, 0x09, 0x04, 0x35, 0xa2, 0x36, 0x11, 0x59, 0xed, 0x5f, 0xb1, 0xfa,
-	0x56, 0xf2, 0x00, 0x82, 0xf1, 0xd4, 0xa2, 0xa1, 0x14, 0x06, 0x39, 0xb7, 0xbc, 0x4b, 0xda, 0xd5,
-	0xc9, 0x1b, 0x18, 0x9d, 0xff, 0x92, 0xf6, 0x35, 0x0c, 0x66, 0x4a, 0xe6, 0xc6, 0x69, 0xfa, 0x6c,
-	0x0e, 0xe9, 0x3d, 0x08, 0x24, 0x97, 0xca, 0x7f, 0xc7, 0x80, 0x79, 0x30, 0xde, 0xfc, 0x79, 0x16,
-	0xdf, 0x38, 0x3d, 0x8b, 0xc9, 0xef, 0xb3, 0x98, 0x7c, 0x99, 0xc5, 0xe4, 0xdb, 0x2c, 0x26, 0xdf,
-	0x67, 0x31, 0xf9, 0x31, 0x8b, 0xc9, 0xe9, 0x2c, 0x26, 0xb0, 0xac, 0x74, 0x91, 0xd6, 0xa8, 0x4b,
-	0x21, 0x53, 0xa9, 0x84, 0x41, 0x7f, 0xc1, 0x31, 0xec, 0xb4, 0x60, 0xb7, 0xad, 0x77, 0xc9, 0x64,
-	0xe8, 0xc8, 0x17, 0x7f, 0x02, 0x00, 0x00, 0xff, 0xff, 0xfc, 0xa8, 0x7b, 0x4a, 0xca, 0x03, 0x00,
-	0x00,
+func init() { proto.RegisterFile("stream.proto", fileDescriptor_bb17ef3f514bfe54) }
+
+var fileDescriptor_bb17ef3f514bfe54 = []byte{
+	// 632 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x9c, 0x53, 0x4d, 0x6f, 0x13, 0x3d,
+	0x10, 0x7e, 0x37, 0x5f, 0x6d, 0xa6, 0xe9, 0xab, 0xf7, 0x35, 0xa8, 0x5a, 0x01, 0x5d, 0xaa, 0x05,
+	0x89, 0x5e, 0x48, 0x11, 0x48, 0x08, 0xc4, 0xa9, 0x69, 0x55, 0x88, 0xa0, 0x55, 0x65, 0x55, 0x5c,
+	0x23, 0x27, 0x9e, 0x6e, 0xac, 0x6c, 0xec, 0xc5, 0xf6, 0x56, 0x09, 0x07, 0xc4, 0xbf, 0xe0, 0x2f,
+	0xf0, 0x53, 0x38, 0x72, 0xe4, 0xd8, 0xe6, 0xc4, 0x91, 0x9f, 0x80, 0xd6, 0xde, 0x6d, 0x29, 0x2d,
+	0x17, 0x4e, 0x3b, 0xcf, 0x33, 0xf3, 0x8c, 0x3d, 0x8f, 0x67, 0xa1, 0x63, 0xac, 0x46, 0x36, 0xed,
+	0x66, 0x5a, 0x59, 0x45, 0x96, 0xdd, 0x67, 0x98, 0x1f, 0xdf, 0x7a, 0x98, 0x08, 0x3b, 0xce, 0x87,
+	0xdd, 0x91, 0x9a, 0x6e, 0x25, 0x2a, 0x51, 0x5b, 0x55, 0xc6, 0x21, 0x07, 0x5c, 0xe4, 0x85, 0xf1,
+	0x3e, 0xd4, 0xfa, 0xbb, 0x64, 0x1d, 0x20, 0xcb, 0x87, 0xa9, 0x18, 0x0d, 0x26, 0x38, 0x0f, 0x83,
+	0x8d, 0x60, 0xb3, 0x43, 0xdb, 0x9e, 0x79, 0x8d, 0x73, 0x12, 0xc2, 0x12, 0xe3, 0x5c, 0xa3, 0x31,
+	0x61, 0x6d, 0x23, 0xd8, 0x6c, 0xd3, 0x0a, 0x92, 0x7f, 0xa1, 0x26, 0x78, 0x58, 0x77, 0x82, 0x9a,
+	0xe0, 0xf1, 0xa7, 0x1a, 0xc0, 0xce, 0x58, 0xa4, 0xbc, 0x97, 0xaa, 0xd1, 0x84, 0x3c, 0x82, 0x8e,
+	0xc9, 0x33, 0xd4, 0x27, 0xc2, 0x28, 0xdd, 0xdf, 0x75, 0x9d, 0x57, 0x1e, 0x77, 0xba, 0xd5, 0x9d,
+	0xba, 0xfd, 0x5d, 0x7a, 0xa9, 0x82, 0xfc, 0x07, 0x75, 0x3b, 0xf3, 0xc7, 0x74, 0x68, 0x11, 0x92,
+	0x35, 0x68, 0xc9, 0x7c, 0x7a, 0x34, 0x33, 0xee, 0x98, 0x3a, 0x2d, 0x11, 0x79, 0x00, 0x0d, 0x2b,
+	0xa6, 0x18, 0x36, 0x5c, 0xcf, 0x1b, 0x17, 0x3d, 0x8f, 0xc4, 0x14, 0x8d, 0x65, 0xd3, 0x8c, 0xba,
+	0x02, 0x72, 0x07, 0xda, 0x46, 0x24, 0x92, 0xd9, 0x5c, 0x63, 0xd8, 0xf4, 0xb3, 0x9d, 0x13, 0xa4,
+	0x0b, 0xe4, 0x84, 0xa5, 0x82, 0x33, 0xab, 0xf4, 0x4b, 0xad, 0xf2, 0xec, 0x15, 0x33, 0xe3, 0xb0,
+	0xe5, 0xca, 0xae, 0xc9, 0x90, 0xa7, 0xb0, 0x26, 0x71, 0x66, 0xdf, 0x5e, 0xd5, 0x2c, 0x39, 0xcd,
+	0x1f, 0xb2, 0xf1, 0xf7, 0x00, 0x96, 0xf6, 0xd1, 0x18, 0x96, 0x60, 0xe1, 0xe7, 0xd4, 0x87, 0xa5,
+	0xd7, 0x15, 0x24, 0xf7, 0xa1, 0x65, 0x50, 0x72, 0xd4, 0x61, 0xed, 0x1a, 0xab, 0xca, 0xdc, 0xe5,
+	0x89, 0xea, 0xbf, 0x4f, 0x74, 0x0f, 0x56, 0x35, 0xbe, 0xcb, 0xd1, 0xd8, 0x81, 0x54, 0x72, 0xe4,
+	0x1d, 0x6a, 0xd0, 0x4e, 0x49, 0x1e, 0x14, 0x5c, 0x51, 0x54, 0x9e, 0x59, 0x16, 0x35, 0x7d, 0x51,
+	0x49, 0xfa, 0xa2, 0x75, 0x00, 0x8d, 0x59, 0x3a, 0x1f, 0x1c, 0xa7, 0x2c, 0x71, 0x9e, 0x2c, 0xd3,
+	0xb6, 0x63, 0xf6, 0x52, 0x96, 0x14, 0x2f, 0xa3, 0xb2, 0x91, 0xe2, 0xe8, 0x46, 0x5f, 0xa5, 0x25,
+	0x8a, 0x5b, 0xd0, 0x38, 0x14, 0x32, 0x71, 0x5f, 0x25, 0x93, 0xf8, 0x39, 0xfc, 0xff, 0x46, 0xa9,
+	0x49, 0x9e, 0x1d, 0x28, 0x8e, 0xd4, 0xdf, 0xa2, 0x98, 0xd4, 0x32, 0x9d, 0xa0, 0xbd, 0x76, 0x29,
+	0xca, 0x5c, 0xfc, 0x0c, 0xc8, 0xaf, 0x52, 0x93, 0x29, 0x69, 0x90, 0xc4, 0xd0, 0xcc, 0x10, 0xb5,
+	0x09, 0x83, 0x8d, 0xfa, 0x15, 0xa9, 0x4f, 0xc5, 0xb7, 0xa1, 0xd9, 0x9b, 0x5b, 0x34, 0x84, 0x40,
+	0x83, 0x33, 0xcb, 0x4a, 0xa7, 0x5d, 0x1c, 0x7f, 0x80, 0xe6, 0xce, 0x38, 0x97, 0x13, 0x72, 0x17,
+	0x56, 0xac, 0x66, 0xd2, 0x1c, 0xa3, 0x1e, 0x08, 0xee, 0x6a, 0x1a, 0x14, 0x2a, 0xaa, 0xcf, 0xc9,
+	0x4d, 0x68, 0x0a, 0xc9, 0x71, 0xe6, 0xde, 0x63, 0x95, 0x7a, 0x70, 0xde, 0xb3, 0x7e, 0xd1, 0xb3,
+	0xe0, 0x8c, 0x78, 0x5f, 0xb9, 0xed, 0xe2, 0xc2, 0x21, 0x2e, 0x12, 0x34, 0xb6, 0xdc, 0xbb, 0x12,
+	0xc5, 0xdb, 0xb0, 0xec, 0xce, 0xdf, 0x1e, 0xfd, 0xed, 0x15, 0xe2, 0x17, 0xd0, 0x3e, 0x5f, 0xf4,
+	0x62, 0xa1, 0x0c, 0x8e, 0x94, 0xe4, 0xc6, 0xe9, 0xeb, 0xb4, 0x82, 0x85, 0x58, 0x32, 0xa9, 0xfc,
+	0x1f, 0xd5, 0xa4, 0x1e, 0xf4, 0xf6, 0xbe, 0x9d, 0x45, 0xff, 0x9c, 0x9e, 0x45, 0xc1, 0x8f, 0xb3,
+	0x28, 0xf8, 0xb8, 0x88, 0x82, 0xcf, 0x8b, 0x28, 0xf8, 0xb2, 0x88, 0x82, 0xaf, 0x8b, 0x28, 0x38,
+	0x5d, 0x44, 0x01, 0xac, 0x29, 0x9d, 0x74, 0x33, 0xd4, 0xa9, 0x90, 0x5d, 0xa9, 0x84, 0x41, 0xef,
+	0x71, 0x0f, 0x0e, 0x0a, 0x70, 0x58, 0xc4, 0x87, 0xc1, 0xb0, 0xe5, 0xc8, 0x27, 0x3f, 0x07, 0x00,
+	0x83, 0x95, 0xca, 0x90, 0x8d, 0x04, 0x00, 0x00,
 }
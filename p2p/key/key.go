@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"io/ioutil"
 
+	"github.com/herdius/herdius-core/accounts/keystore"
 	"github.com/herdius/herdius-core/crypto"
 	ed25519 "github.com/herdius/herdius-core/crypto/ed"
 	"github.com/herdius/herdius-core/crypto/secp256k1"
@@ -59,6 +60,9 @@ func LoadNodeKey(filePath string) (*NodeKey, error) {
 	if err != nil {
 		return nil, err
 	}
+	if keystore.IsEncryptedKey(jsonBytes) {
+		return nil, fmt.Errorf("NodeKey %v is encrypted, a passphrase is needed to unlock it", filePath)
+	}
 	nodeKey := new(NodeKey)
 	err = cdc.UnmarshalJSON(jsonBytes, nodeKey)
 	if err != nil {
@@ -67,6 +71,63 @@ func LoadNodeKey(filePath string) (*NodeKey, error) {
 	return nodeKey, nil
 }
 
+// LoadOrGenEncryptedNodeKey attempts to unlock the encrypted NodeKey at
+// filePath with passphrase. If the file does not exist, it generates a new
+// NodeKey and saves it encrypted with passphrase.
+func LoadOrGenEncryptedNodeKey(filePath, passphrase string) (*NodeKey, error) {
+	if !cmn.FileExists(filePath) {
+		nodeKey := &NodeKey{PrivKey: secp256k1.GenPrivKey()}
+		keyjson, err := keystore.EncryptKey(nodeKey.PrivKey, passphrase, keystore.KDFScrypt)
+		if err != nil {
+			return nil, err
+		}
+		if err := keystore.WriteKeyFile(filePath, keyjson); err != nil {
+			return nil, err
+		}
+		return nodeKey, nil
+	}
+	jsonBytes, err := ioutil.ReadFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+	if !keystore.IsEncryptedKey(jsonBytes) {
+		return nil, fmt.Errorf("NodeKey %v isn't encrypted, encrypt it with the key import command", filePath)
+	}
+	privKey, err := keystore.DecryptKey(jsonBytes, passphrase)
+	if err != nil {
+		return nil, fmt.Errorf("Error unlocking NodeKey %v: %v", filePath, err)
+	}
+	return &NodeKey{PrivKey: privKey}, nil
+}
+
+// UnlockNodeKey loads the node key at path, generating it if it doesn't
+// exist. With a passphrase or a passphrase file the node key is encrypted.
+// Without either, an encrypted node key is unlocked with a passphrase
+// prompted for, and a plaintext one is loaded as is.
+func UnlockNodeKey(path, passphrase, passphraseFile string) (*NodeKey, error) {
+	if len(passphraseFile) > 0 {
+		var err error
+		if passphrase, err = keystore.ReadPassphrase(passphraseFile, "", false); err != nil {
+			return nil, err
+		}
+	}
+	if len(passphrase) == 0 && cmn.FileExists(path) {
+		keyjson, err := ioutil.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		if keystore.IsEncryptedKey(keyjson) {
+			if passphrase, err = keystore.ReadPassphrase("", "Node key passphrase: ", false); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if len(passphrase) == 0 {
+		return LoadOrGenNodeKey(path)
+	}
+	return LoadOrGenEncryptedNodeKey(path, passphrase)
+}
+
 func genNodeKey(filePath string) (*NodeKey, error) {
 	privKey := ed25519.GenPrivKey()
 	nodeKey := &NodeKey{
@@ -1,6 +1,7 @@
 package key
 
 import (
+	"io/ioutil"
 	"os"
 	"path/filepath"
 	"testing"
@@ -22,3 +23,51 @@ func TestLoadOrGenNodeKey(t *testing.T) {
 
 	os.RemoveAll(filePath)
 }
+
+func TestLoadOrGenEncryptedNodeKey(t *testing.T) {
+	filePath := filepath.Join(os.TempDir(), cmn.RandStr(12)+"_peer_id.json")
+	defer os.RemoveAll(filePath)
+
+	nodeKey, err := LoadOrGenEncryptedNodeKey(filePath, "passphrase")
+	assert.Nil(t, err)
+
+	nodeKey2, err := LoadOrGenEncryptedNodeKey(filePath, "passphrase")
+	assert.Nil(t, err)
+	assert.Equal(t, nodeKey, nodeKey2)
+
+	_, err = LoadOrGenEncryptedNodeKey(filePath, "wrong passphrase")
+	assert.Error(t, err)
+	_, err = LoadOrGenNodeKey(filePath)
+	assert.Error(t, err, "an encrypted key needs a passphrase")
+
+	plainPath := filepath.Join(os.TempDir(), cmn.RandStr(12)+"_peer_id.json")
+	defer os.RemoveAll(plainPath)
+	_, err = LoadOrGenNodeKey(plainPath)
+	assert.Nil(t, err)
+	_, err = LoadOrGenEncryptedNodeKey(plainPath, "passphrase")
+	assert.Error(t, err, "a plaintext key isn't unlocked")
+}
+
+func TestUnlockNodeKey(t *testing.T) {
+	filePath := filepath.Join(os.TempDir(), cmn.RandStr(12)+"_peer_id.json")
+	defer os.RemoveAll(filePath)
+	passphraseFile := filepath.Join(os.TempDir(), cmn.RandStr(12)+"_passphrase")
+	defer os.RemoveAll(passphraseFile)
+	assert.Nil(t, ioutil.WriteFile(passphraseFile, []byte("passphrase\n"), 0600))
+
+	nodeKey, err := UnlockNodeKey(filePath, "", passphraseFile)
+	assert.Nil(t, err)
+	nodeKey2, err := UnlockNodeKey(filePath, "passphrase", "")
+	assert.Nil(t, err)
+	assert.Equal(t, nodeKey, nodeKey2)
+	_, err = UnlockNodeKey(filePath, "wrong passphrase", "")
+	assert.Error(t, err)
+
+	plainPath := filepath.Join(os.TempDir(), cmn.RandStr(12)+"_peer_id.json")
+	defer os.RemoveAll(plainPath)
+	plainKey, err := UnlockNodeKey(plainPath, "", "")
+	assert.Nil(t, err)
+	plainKey2, err := UnlockNodeKey(plainPath, "", "")
+	assert.Nil(t, err)
+	assert.Equal(t, plainKey, plainKey2)
+}
@@ -7,12 +7,15 @@ import (
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/herdius/herdius-core/config"
 	"github.com/herdius/herdius-core/p2p/crypto"
 	"github.com/herdius/herdius-core/p2p/crypto/ed25519"
+	"github.com/herdius/herdius-core/p2p/internal/protobuf"
 	"github.com/herdius/herdius-core/p2p/network"
 	"github.com/herdius/herdius-core/p2p/network/discovery"
+	"github.com/herdius/herdius-core/p2p/network/transport"
 	"github.com/herdius/herdius-core/p2p/tests/basic/messages"
 	"github.com/herdius/herdius-core/p2p/types/opcode"
 
@@ -180,3 +183,66 @@ func TestPlugin(t *testing.T) {
 	// 	t.Fatal(err)
 	// }
 }
+
+// newMemoryNode creates a node listening on port of the in-memory network mem
+func newMemoryNode(t *testing.T, mem *transport.MemoryNetwork, port uint16, plugins ...network.PluginInterface) *network.Network {
+	builder := network.NewBuilder()
+	builder.ClearTransportLayers()
+	builder.RegisterTransportLayer(protocol, mem.Transport())
+	builder.SetKeys(ed25519.RandomKeyPair())
+	builder.SetAddress(network.FormatAddress(protocol, host, port))
+	for _, plugin := range plugins {
+		require.Nil(t, builder.AddPlugin(plugin))
+	}
+	node, err := builder.Build()
+	require.Nil(t, err)
+	go node.Listen()
+	node.BlockUntilListening()
+	return node
+}
+
+// waitUntil polls cond until it is true or timeout passes
+func waitUntil(timeout time.Duration, cond func() bool) bool {
+	deadline := time.Now().Add(timeout)
+	for !cond() {
+		if time.Now().After(deadline) {
+			return false
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	return true
+}
+
+// TestPluginReconnectsAfterPartition tests the backoff plugin reconnects to
+// a peer once a partition between them heals.
+func TestPluginReconnectsAfterPartition(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping backoff plugin test in short mode")
+	}
+
+	mem := transport.NewMemoryNetwork(1)
+	node := newMemoryNode(t, mem, 3000, New(WithInitialDelay(10*time.Millisecond)))
+	defer node.Close()
+	peer := newMemoryNode(t, mem, 3001)
+	defer peer.Close()
+
+	// Nodes dial back and notice disconnects once they receive a message
+	client, err := node.Client(peer.Address)
+	require.Nil(t, err)
+	require.Nil(t, client.Tell(context.Background(), &protobuf.Ping{}))
+	require.True(t, waitUntil(time.Second, func() bool { return peer.ConnectionStateExists(node.Address) }))
+	peerClient, err := peer.Client(node.Address)
+	require.Nil(t, err)
+	require.Nil(t, peerClient.Tell(context.Background(), &protobuf.Ping{}))
+	require.True(t, client.IsIncomingReady())
+
+	mem.Partition([]int{3000})
+	require.True(t, waitUntil(3*time.Second, func() bool { return !node.ConnectionStateExists(peer.Address) }))
+	// The first reconnection attempt fails while the partition lasts
+	time.Sleep(defaultMinInterval + 500*time.Millisecond)
+	assert.False(t, node.ConnectionStateExists(peer.Address))
+
+	mem.Heal()
+	assert.True(t, waitUntil(5*time.Second, func() bool { return node.ConnectionStateExists(peer.Address) }))
+	assert.True(t, waitUntil(time.Second, func() bool { return peer.ConnectionStateExists(node.Address) }))
+}
@@ -53,6 +53,8 @@ var defaultBuilderOptions = options{
 	writeBufferSize:   defaultWriteBufferSize,
 	writeFlushLatency: defaultWriteFlushLatency,
 	writeTimeout:      defaultWriteTimeout,
+	maxTransferSize:   defaultMaxTransferSize,
+	retryPolicy:       DefaultRetryPolicy(),
 	address:           defaultaddress,
 }
 
@@ -130,6 +132,30 @@ func WriteTimeout(d time.Duration) BuilderOption {
 	}
 }
 
+// MaxTransferSize returns a BuilderOption that sets the maximum size of a
+// message received in chunks (default: 256MB).
+func MaxTransferSize(byteSize int) BuilderOption {
+	return func(o *options) {
+		o.maxTransferSize = byteSize
+	}
+}
+
+// RequestRetryPolicy returns a BuilderOption that sets how requests to peers
+// are retried (default: not retried). WithRetryPolicy overrides it per request.
+func RequestRetryPolicy(policy RetryPolicy) BuilderOption {
+	return func(o *options) {
+		o.retryPolicy = policy
+	}
+}
+
+// RequireSignedMessages returns a BuilderOption that makes the network drop
+// messages that aren't signed by their sender (default: false).
+func RequireSignedMessages(required bool) BuilderOption {
+	return func(o *options) {
+		o.requireSignature = required
+	}
+}
+
 // NewBuilder returns a new builder with default options.
 func NewBuilder() *Builder {
 	builder := &Builder{
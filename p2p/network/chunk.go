@@ -0,0 +1,193 @@
+package network
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"sync/atomic"
+	"time"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/herdius/herdius-core/blockchain/protobuf"
+	pb "github.com/herdius/herdius-core/p2p/internal/protobuf"
+	"github.com/herdius/herdius-core/p2p/log"
+	"github.com/herdius/herdius-core/p2p/peer"
+	"github.com/pkg/errors"
+)
+
+const (
+	// maxMessageSize bounds the size of a message sent in one frame.
+	maxMessageSize = 4e+6
+	// chunkSize is the size of the chunks larger messages are split into.
+	chunkSize = 1 << 20
+	// chunkWindow is the number of chunks sent ahead of their acknowledgement.
+	chunkWindow = 8
+	// chunkAckTimeout bounds the wait for a chunk to be acknowledged.
+	chunkAckTimeout = 30 * time.Second
+	// maxIncomingTransfers bounds the chunked messages received at once from a peer.
+	maxIncomingTransfers = 4
+)
+
+// incomingTransfer is a chunked message being received.
+type incomingTransfer struct {
+	size   uint64
+	digest []byte
+	next   uint32
+	data   []byte
+}
+
+// writeChunked sends a message too large for one frame to the peer at
+// address in chunks. The peer reassembles and dispatches it like any other
+// message.
+func (n *Network) writeChunked(ctx context.Context, address string, message *protobuf.Message) error {
+	c, ok := n.peers.Load(address)
+	if !ok {
+		return errors.New("network: peer client does not exist")
+	}
+
+	raw, err := proto.Marshal(message)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal message")
+	}
+	if len(raw) > n.opts.maxTransferSize {
+		return errors.Errorf("network: message has length of %d which exceeds the maximum transfer size", len(raw))
+	}
+
+	return c.(*PeerClient).writeChunks(ctx, raw)
+}
+
+// writeChunks sends raw in chunks, keeping at most chunkWindow chunks
+// unacknowledged by the peer, until ctx is done.
+func (c *PeerClient) writeChunks(ctx context.Context, raw []byte) error {
+	id := atomic.AddUint64(&c.transferNonce, 1)
+	acks := make(chan uint32, chunkWindow)
+	c.outgoingTransfers.Store(id, acks)
+	defer c.outgoingTransfers.Delete(id)
+
+	digest := sha256.Sum256(raw)
+	ctx = WithSignMessage(ctx, true)
+	acked := 0
+	for index, start := 0, 0; start < len(raw); index, start = index+1, start+chunkSize {
+		for index-acked >= chunkWindow {
+			select {
+			case <-acks:
+				acked++
+			case <-time.After(chunkAckTimeout):
+				return errors.Errorf("network: chunk %d of transfer %d was not acknowledged", acked, id)
+			case <-c.closeSignal:
+				return errors.New("network: peer disconnected during chunked transfer")
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+
+		end := start + chunkSize
+		if end > len(raw) {
+			end = len(raw)
+		}
+		chunk := &pb.Chunk{TransferId: id, Index: uint32(index), Data: raw[start:end]}
+		if index == 0 {
+			chunk.Size = uint64(len(raw))
+			chunk.Digest = digest[:]
+		}
+		if err := c.Tell(ctx, chunk); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// handleChunkAck lets the transfer acknowledged by ack send more chunks.
+func (c *PeerClient) handleChunkAck(ack *pb.ChunkAck) {
+	if acks, ok := c.outgoingTransfers.Load(ack.TransferId); ok {
+		select {
+		case acks.(chan uint32) <- ack.Index:
+		default:
+		}
+	}
+}
+
+// handleChunk acknowledges chunk and, once it completes its message,
+// verifies and dispatches the message.
+func (c *PeerClient) handleChunk(chunk *pb.Chunk) {
+	raw, err := c.receiveChunk(chunk)
+	if err != nil {
+		log.Warn().Err(err).Str("address", c.Address).Msg("network: dropped chunk")
+		if misbehavior, ok := err.(*misbehaviorError); ok {
+			c.Network.ReportMisbehavior(c.ID.PublicKey, c.Address, misbehavior.misbehavior)
+		}
+		return
+	}
+
+	ack := &pb.ChunkAck{TransferId: chunk.TransferId, Index: chunk.Index}
+	if err := c.Tell(WithSignMessage(context.Background(), true), ack); err != nil {
+		log.Warn().Err(err).Str("address", c.Address).Msg("network: failed to acknowledge chunk")
+	}
+	if raw == nil {
+		return
+	}
+
+	msg := new(protobuf.Message)
+	if err := proto.Unmarshal(raw, msg); err != nil {
+		log.Error().Err(err).Str("address", c.Address).Msg("network: failed to unmarshal chunked message")
+		c.Network.ReportMisbehavior(c.ID.PublicKey, c.Address, MalformedMessage)
+		return
+	}
+	if err := c.Network.verifyMessage(msg); err != nil {
+		log.Error().Err(err).Str("address", c.Address).Msg("network: invalid chunked message")
+		c.Network.ReportMisbehavior(c.ID.PublicKey, c.Address, err.(*misbehaviorError).misbehavior)
+		return
+	}
+	// Peer sent message on behalf of another node.
+	if !c.ID.Equals(peer.ID(*msg.Sender)) {
+		log.Error().Str("address", c.Address).Msg("network: chunked message sender does not match peer")
+		c.Network.ReportMisbehavior(c.ID.PublicKey, c.Address, MalformedSignature)
+		return
+	}
+
+	c.Network.dispatchMessage(c, msg)
+}
+
+// receiveChunk adds chunk to its transfer. It returns the message once all of
+// its chunks arrived and match its digest.
+func (c *PeerClient) receiveChunk(chunk *pb.Chunk) ([]byte, error) {
+	c.transfersMutex.Lock()
+	defer c.transfersMutex.Unlock()
+
+	transfer, exists := c.incomingTransfers[chunk.TransferId]
+	if !exists {
+		// Chunks following the first chunk of a refused transfer end up here.
+		if chunk.Index != 0 {
+			return nil, errors.Errorf("chunk %d of unknown transfer %d", chunk.Index, chunk.TransferId)
+		}
+		if chunk.Size == 0 || len(chunk.Digest) != sha256.Size {
+			return nil, &misbehaviorError{MalformedMessage, errors.Errorf("transfer %d has no size or digest", chunk.TransferId)}
+		}
+		if chunk.Size > uint64(c.Network.opts.maxTransferSize) {
+			return nil, errors.Errorf("transfer has length of %d which exceeds the maximum transfer size", chunk.Size)
+		}
+		if len(c.incomingTransfers) >= maxIncomingTransfers {
+			return nil, errors.New("too many transfers in progress")
+		}
+		transfer = &incomingTransfer{size: chunk.Size, digest: chunk.Digest}
+		c.incomingTransfers[chunk.TransferId] = transfer
+	}
+
+	if chunk.Index != transfer.next || len(chunk.Data) == 0 || len(chunk.Data) > chunkSize ||
+		uint64(len(transfer.data)+len(chunk.Data)) > transfer.size {
+		delete(c.incomingTransfers, chunk.TransferId)
+		return nil, &misbehaviorError{MalformedMessage, errors.Errorf("chunk %d of transfer %d is out of order or oversized", chunk.Index, chunk.TransferId)}
+	}
+	transfer.data = append(transfer.data, chunk.Data...)
+	transfer.next++
+	if uint64(len(transfer.data)) < transfer.size {
+		return nil, nil
+	}
+
+	delete(c.incomingTransfers, chunk.TransferId)
+	digest := sha256.Sum256(transfer.data)
+	if !bytes.Equal(digest[:], transfer.digest) {
+		return nil, &misbehaviorError{MalformedMessage, errors.Errorf("transfer %d doesn't match its digest", chunk.TransferId)}
+	}
+	return transfer.data, nil
+}
@@ -0,0 +1,148 @@
+package network
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/herdius/herdius-core/blockchain/protobuf"
+	"github.com/herdius/herdius-core/p2p/crypto/ed25519"
+	pb "github.com/herdius/herdius-core/p2p/internal/protobuf"
+	"github.com/herdius/herdius-core/p2p/network/transport"
+)
+
+// largeReplyPlugin replies to pings with data.
+type largeReplyPlugin struct {
+	*Plugin
+	data []byte
+}
+
+func (p *largeReplyPlugin) Receive(ctx *PluginContext) error {
+	if _, ok := ctx.Message().(*pb.Ping); ok {
+		return ctx.Reply(context.Background(), &pb.Bytes{Data: p.data})
+	}
+	return nil
+}
+
+func buildMemoryNode(t *testing.T, mem *transport.MemoryNetwork, port uint16, plugins ...PluginInterface) *Network {
+	builder := NewBuilder()
+	builder.ClearTransportLayers()
+	builder.RegisterTransportLayer("tcp", mem.Transport())
+	builder.SetKeys(ed25519.RandomKeyPair())
+	builder.SetAddress(FormatAddress("tcp", "127.0.0.1", port))
+	for _, plugin := range plugins {
+		require.Nil(t, builder.AddPlugin(plugin))
+	}
+	node, err := builder.Build()
+	require.Nil(t, err)
+	go node.Listen()
+	node.BlockUntilListening()
+	return node
+}
+
+func randomBytes(t *testing.T, size int) []byte {
+	data := make([]byte, size)
+	_, err := rand.Read(data)
+	require.Nil(t, err)
+	return data
+}
+
+func TestChunkedStream(t *testing.T) {
+	mem := transport.NewMemoryNetwork(1)
+	alice := buildMemoryNode(t, mem, 3000)
+	defer alice.Close()
+	bob := buildMemoryNode(t, mem, 3001)
+	defer bob.Close()
+
+	data := randomBytes(t, 3*maxMessageSize)
+	client, err := alice.Client(bob.Address)
+	require.Nil(t, err)
+	written, err := client.Write(data)
+	require.Nil(t, err)
+	assert.Equal(t, len(data), written)
+
+	bobClient, err := bob.Client(alice.Address)
+	require.Nil(t, err)
+	received := make([]byte, len(data))
+	_, err = io.ReadFull(bobClient, received)
+	require.Nil(t, err)
+	assert.Equal(t, data, received)
+
+	// The transfer size is limited.
+	_, err = client.Write(make([]byte, defaultMaxTransferSize+1))
+	assert.Error(t, err)
+}
+
+func TestChunkedReply(t *testing.T) {
+	mem := transport.NewMemoryNetwork(1)
+	alice := buildMemoryNode(t, mem, 3000)
+	defer alice.Close()
+	plugin := &largeReplyPlugin{data: randomBytes(t, 2*maxMessageSize)}
+	bob := buildMemoryNode(t, mem, 3001, plugin)
+	defer bob.Close()
+
+	client, err := alice.Client(bob.Address)
+	require.Nil(t, err)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	reply, err := client.Request(ctx, &pb.Ping{})
+	require.Nil(t, err)
+	// Bytes are received as the blockchain type, see dispatchMessage.
+	require.IsType(t, &protobuf.Bytes{}, reply)
+	assert.Equal(t, plugin.data, reply.(*protobuf.Bytes).Data)
+}
+
+func TestReceiveChunk(t *testing.T) {
+	node := buildHandshakeNetwork(t, MaxTransferSize(4*chunkSize))
+	client, err := createPeerClient(node, "tcp://127.0.0.1:3001")
+	require.Nil(t, err)
+
+	data := randomBytes(t, 2*chunkSize+1)
+	digest := sha256.Sum256(data)
+	chunks := []*pb.Chunk{
+		{TransferId: 1, Index: 0, Data: data[:chunkSize], Size: uint64(len(data)), Digest: digest[:]},
+		{TransferId: 1, Index: 1, Data: data[chunkSize : 2*chunkSize]},
+		{TransferId: 1, Index: 2, Data: data[2*chunkSize:]},
+	}
+
+	// Chunks in order reassemble the message.
+	for i, chunk := range chunks {
+		raw, err := client.receiveChunk(chunk)
+		require.Nil(t, err)
+		if i < len(chunks)-1 {
+			assert.Nil(t, raw)
+		} else {
+			assert.Equal(t, data, raw)
+		}
+	}
+	assert.Empty(t, client.incomingTransfers)
+
+	// Chunks out of order are rejected.
+	_, err = client.receiveChunk(chunks[0])
+	require.Nil(t, err)
+	_, err = client.receiveChunk(chunks[2])
+	assert.IsType(t, &misbehaviorError{}, err)
+	assert.Empty(t, client.incomingTransfers)
+
+	// A tampered message doesn't match its digest.
+	tampered := append([]byte{}, chunks[2].Data...)
+	tampered[0]++
+	for _, chunk := range []*pb.Chunk{chunks[0], chunks[1], {TransferId: 1, Index: 2, Data: tampered}} {
+		raw, err := client.receiveChunk(chunk)
+		assert.Nil(t, raw)
+		if chunk.Index == 2 {
+			assert.IsType(t, &misbehaviorError{}, err)
+		}
+	}
+
+	// Transfers above the maximum transfer size are refused.
+	_, err = client.receiveChunk(&pb.Chunk{TransferId: 2, Data: data[:chunkSize], Size: 4*chunkSize + 1, Digest: digest[:]})
+	assert.Error(t, err)
+	assert.Empty(t, client.incomingTransfers)
+}
@@ -33,6 +33,11 @@ type PeerClient struct {
 
 	jobs chan func()
 
+	transferNonce     uint64
+	outgoingTransfers sync.Map // uint64 -> chan uint32
+	transfersMutex    sync.Mutex
+	incomingTransfers map[uint64]*incomingTransfer
+
 	closed      uint32 // for atomic ops
 	closeSignal chan struct{}
 }
@@ -75,6 +80,8 @@ func createPeerClient(network *Network, address string) (*PeerClient, error) {
 
 		jobs:        make(chan func(), 128),
 		closeSignal: make(chan struct{}),
+
+		incomingTransfers: make(map[uint64]*incomingTransfer),
 	}
 
 	return client, nil
@@ -137,6 +144,15 @@ func (c *PeerClient) Close() error {
 	return nil
 }
 
+// RemotePublicKey returns the node key the peer proved it owns when the
+// connection to it was established.
+func (c *PeerClient) RemotePublicKey() []byte {
+	if state, ok := c.Network.ConnectionState(c.Address); ok {
+		return state.remoteKey
+	}
+	return nil
+}
+
 // Tell will asynchronously emit a message to a given peer.
 func (c *PeerClient) Tell(ctx context.Context, message proto.Message) error {
 	signed, err := c.Network.PrepareMessage(ctx, message)
@@ -144,7 +160,7 @@ func (c *PeerClient) Tell(ctx context.Context, message proto.Message) error {
 		return errors.Wrap(err, "failed to sign message")
 	}
 
-	err = c.Network.Write(c.Address, signed)
+	err = c.Network.write(ctx, c.Address, signed)
 
 	if err != nil {
 		return errors.Wrapf(err, "failed to send message to %s", c.Address)
@@ -153,12 +169,50 @@ func (c *PeerClient) Tell(ctx context.Context, message proto.Message) error {
 	return nil
 }
 
-// Request requests for a response for a request sent to a given peer.
+// Request requests for a response for a request sent to a given peer. Failed
+// attempts are retried following the retry policy of ctx, or else the network's.
 func (c *PeerClient) Request(ctx context.Context, req proto.Message) (proto.Message, error) {
 	if ctx == nil {
 		return nil, errors.New("network: invalid context")
 	}
 
+	policy, ok := GetRetryPolicy(ctx)
+	if !ok {
+		policy = c.Network.opts.retryPolicy
+	}
+
+	for attempt := 1; ; attempt++ {
+		res, err := c.request(ctx, req, policy.AttemptTimeout)
+		if err == nil {
+			return res, nil
+		}
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		if attempt >= policy.MaxAttempts {
+			if attempt > 1 {
+				return nil, errors.Wrapf(err, "request failed after %d attempts", attempt)
+			}
+			return nil, err
+		}
+
+		select {
+		case <-time.After(policy.delay(attempt)):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// request sends a request once and waits for its response, for at most
+// timeout if set.
+func (c *PeerClient) request(ctx context.Context, req proto.Message, timeout time.Duration) (proto.Message, error) {
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
 	if ctx.Err() != nil {
 		return nil, ctx.Err()
 	}
@@ -170,12 +224,8 @@ func (c *PeerClient) Request(ctx context.Context, req proto.Message) (proto.Mess
 
 	signed.RequestNonce = atomic.AddUint64(&c.RequestNonce, 1)
 
-	err = c.Network.Write(c.Address, signed)
-	if err != nil {
-		return nil, err
-	}
-
-	// Start tracking the request.
+	// Start tracking the request before sending it, as the response may
+	// arrive before the write returns.
 	channel := make(chan proto.Message, 1)
 	closeSignal := make(chan struct{})
 
@@ -188,11 +238,18 @@ func (c *PeerClient) Request(ctx context.Context, req proto.Message) (proto.Mess
 	defer close(closeSignal)
 	defer c.Requests.Delete(signed.RequestNonce)
 
+	err = c.Network.write(ctx, c.Address, signed)
+	if err != nil {
+		return nil, err
+	}
+
 	select {
 	case res := <-channel:
 		return res, nil
 	case <-ctx.Done():
 		return nil, ctx.Err()
+	case <-c.closeSignal:
+		return nil, errors.New("network: peer disconnected before responding")
 	}
 }
 
@@ -207,7 +264,7 @@ func (c *PeerClient) Reply(ctx context.Context, nonce uint64, message proto.Mess
 	msg.RequestNonce = nonce
 	msg.ReplyFlag = true
 
-	err = c.Network.Write(c.Address, msg)
+	err = c.Network.write(ctx, c.Address, msg)
 	if err != nil {
 		return err
 	}
@@ -6,10 +6,12 @@ import (
 
 type (
 	signMessageCtxKeyType string
+	retryPolicyCtxKeyType string
 )
 
 const (
 	signMessageCtxKey signMessageCtxKeyType = "signMessage"
+	retryPolicyCtxKey retryPolicyCtxKeyType = "retryPolicy"
 )
 
 // WithSignMessage sets whether the request should be signed
@@ -25,3 +27,15 @@ func GetSignMessage(ctx context.Context) bool {
 	}
 	return sign
 }
+
+// WithRetryPolicy sets how the request should be retried, overriding the
+// network's policy
+func WithRetryPolicy(ctx context.Context, policy RetryPolicy) context.Context {
+	return context.WithValue(ctx, retryPolicyCtxKey, policy)
+}
+
+// GetRetryPolicy returns how the request should be retried, if set
+func GetRetryPolicy(ctx context.Context) (RetryPolicy, bool) {
+	policy, ok := ctx.Value(retryPolicyCtxKey).(RetryPolicy)
+	return policy, ok
+}
@@ -1,13 +1,24 @@
 package discovery
 
 import (
+	"bytes"
 	"context"
+	"sync"
+	"time"
 
 	"github.com/herdius/herdius-core/p2p/dht"
 	"github.com/herdius/herdius-core/p2p/internal/protobuf"
 	"github.com/herdius/herdius-core/p2p/log"
 	"github.com/herdius/herdius-core/p2p/network"
 	"github.com/herdius/herdius-core/p2p/peer"
+	"github.com/pkg/errors"
+)
+
+const (
+	// pingTimeout bounds the liveness check of seed and reloaded peers.
+	pingTimeout = 5 * time.Second
+	// saveRoutesInterval is how often the routing table is persisted.
+	saveRoutesInterval = time.Minute
 )
 
 type Plugin struct {
@@ -17,7 +28,18 @@ type Plugin struct {
 	DisablePong   bool
 	DisableLookup bool
 
+	// RoutesPath is the file the routing table is persisted to and reloaded
+	// from, if set.
+	RoutesPath string
+	// SeedNodes are the addresses of nodes to bootstrap from on startup.
+	SeedNodes []string
+
 	Routes *dht.RoutingTable
+
+	stop chan struct{}
+	// saveMu serializes saves, which run from the save loop, the bootstrap
+	// and received pongs alike.
+	saveMu sync.Mutex
 }
 
 var (
@@ -28,6 +50,112 @@ var (
 func (state *Plugin) Startup(net *network.Network) {
 	// Create routing table.
 	state.Routes = dht.CreateRoutingTable(net.ID)
+
+	var saved []peer.ID
+	if state.RoutesPath != "" {
+		var err error
+		saved, err = dht.LoadPeers(state.RoutesPath)
+		if err != nil {
+			log.Error().Err(err).Str("path", state.RoutesPath).Msg("failed to load routing table")
+		}
+	}
+	if state.RoutesPath != "" {
+		state.stop = make(chan struct{})
+		go state.saveRoutesLoop()
+	}
+	if len(saved) > 0 || len(state.SeedNodes) > 0 {
+		go state.bootstrap(net, saved)
+	}
+}
+
+// bootstrap pings the seed nodes and the peers reloaded from the routing
+// table once the network listens. Only the peers that answer with a pong are
+// added to the routing table, then peers closest to us are looked up.
+func (state *Plugin) bootstrap(net *network.Network, saved []peer.ID) {
+	net.BlockUntilListening()
+
+	var alive []string
+	for _, address := range state.SeedNodes {
+		client, err := ping(net, address)
+		if err != nil {
+			log.Warn().Err(err).Str("address", address).Msg("seed node is not reachable")
+			continue
+		}
+		state.Routes.Update(peer.CreateID(client.Address, client.RemotePublicKey()))
+		alive = append(alive, client.Address)
+	}
+	for _, id := range saved {
+		client, err := ping(net, id.Address)
+		if err != nil {
+			log.Warn().Err(err).Str("address", id.Address).Msg("saved peer is not reachable")
+			continue
+		}
+		if !bytes.Equal(client.RemotePublicKey(), id.PublicKey) {
+			log.Warn().Str("address", id.Address).Msg("saved peer changed its node key")
+			continue
+		}
+		state.Routes.Update(id)
+		alive = append(alive, client.Address)
+	}
+
+	if len(alive) > 0 {
+		for _, peerID := range FindNode(net, net.ID, dht.BucketSize, 8) {
+			state.Routes.Update(peerID)
+		}
+	}
+	state.saveRoutes()
+}
+
+// ping checks the node at address answers a ping with a pong.
+func ping(net *network.Network, address string) (*network.PeerClient, error) {
+	client, err := net.Client(address)
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithTimeout(network.WithSignMessage(context.Background(), true), pingTimeout)
+	defer cancel()
+	reply, err := client.Request(ctx, &protobuf.Ping{})
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := reply.(*protobuf.Pong); !ok {
+		return nil, errors.Errorf("expected a pong, got %T", reply)
+	}
+	return client, nil
+}
+
+// saveRoutesLoop persists the routing table every saveRoutesInterval until
+// the network stops.
+func (state *Plugin) saveRoutesLoop() {
+	t := time.NewTicker(saveRoutesInterval)
+	defer t.Stop()
+	for {
+		select {
+		case <-state.stop:
+			return
+		case <-t.C:
+			state.saveRoutes()
+		}
+	}
+}
+
+// saveRoutes persists the routing table to RoutesPath, if set. An empty
+// table doesn't overwrite the saved one, so peers survive losing all
+// connections for a while. Nothing is saved once the network closed.
+func (state *Plugin) saveRoutes() {
+	if state.RoutesPath == "" || state.Routes == nil || len(state.Routes.GetPeers()) == 0 {
+		return
+	}
+	select {
+	case <-state.stop:
+		return
+	default:
+	}
+	state.saveMu.Lock()
+	defer state.saveMu.Unlock()
+	if err := state.Routes.Save(state.RoutesPath); err != nil {
+		log.Error().Err(err).Str("path", state.RoutesPath).Msg("failed to save routing table")
+	}
 }
 
 func (state *Plugin) Receive(ctx *network.PluginContext) error {
@@ -59,6 +187,7 @@ func (state *Plugin) Receive(ctx *network.PluginContext) error {
 		for _, peerID := range peers {
 			state.Routes.Update(peerID)
 		}
+		state.saveRoutes()
 
 		log.Info().
 			Strs("peers", state.Routes.GetPeerAddresses()).
@@ -90,8 +219,12 @@ func (state *Plugin) Receive(ctx *network.PluginContext) error {
 	return nil
 }
 
+// Cleanup stops persisting the routing table. It isn't saved here as peers
+// are being disconnected, and so removed from it, while the network stops.
 func (state *Plugin) Cleanup(net *network.Network) {
-	// TODO: Save routing table?
+	if state.stop != nil {
+		close(state.stop)
+	}
 }
 
 // PeerDisconnect run when a peer disconnected.
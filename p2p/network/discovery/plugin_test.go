@@ -0,0 +1,119 @@
+package discovery
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/herdius/herdius-core/p2p/crypto/ed25519"
+	"github.com/herdius/herdius-core/p2p/dht"
+	"github.com/herdius/herdius-core/p2p/network"
+	"github.com/herdius/herdius-core/p2p/network/transport"
+	"github.com/herdius/herdius-core/p2p/peer"
+)
+
+func newNode(t *testing.T, plugin *Plugin) *network.Network {
+	return buildNode(t, network.NewBuilder(), uint16(network.GetRandomUnusedPort()), plugin)
+}
+
+// newMemoryNode creates a node listening on port of the in-memory network mem
+func newMemoryNode(t *testing.T, mem *transport.MemoryNetwork, port uint16, plugin *Plugin) *network.Network {
+	builder := network.NewBuilder()
+	builder.ClearTransportLayers()
+	builder.RegisterTransportLayer("tcp", mem.Transport())
+	return buildNode(t, builder, port, plugin)
+}
+
+func buildNode(t *testing.T, builder *network.Builder, port uint16, plugin *Plugin) *network.Network {
+	builder.SetKeys(ed25519.RandomKeyPair())
+	builder.SetAddress(network.FormatAddress("tcp", "127.0.0.1", port))
+	require.Nil(t, builder.AddPlugin(plugin))
+	node, err := builder.Build()
+	require.Nil(t, err)
+	go node.Listen()
+	node.BlockUntilListening()
+	return node
+}
+
+// waitForPeers waits for routes to hold n peers
+func waitForPeers(routes *dht.RoutingTable, n int) {
+	deadline := time.Now().Add(5 * time.Second)
+	for len(routes.GetPeers()) < n && time.Now().Before(deadline) {
+		time.Sleep(50 * time.Millisecond)
+	}
+}
+
+func TestReloadRoutingTable(t *testing.T) {
+	dir, err := ioutil.TempDir(os.TempDir(), "discovery_test_")
+	require.Nil(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "routes.json")
+
+	seed := newNode(t, new(Plugin))
+	defer seed.Close()
+	alive := newNode(t, new(Plugin))
+	defer alive.Close()
+
+	// A node bootstrapped from the seed saves the seed to its routing table
+	first := &Plugin{RoutesPath: path, SeedNodes: []string{seed.Address}}
+	node := newNode(t, first)
+	var saved []peer.ID
+	deadline := time.Now().Add(5 * time.Second)
+	for len(saved) == 0 && time.Now().Before(deadline) {
+		time.Sleep(50 * time.Millisecond)
+		saved, err = dht.LoadPeers(path)
+		require.Nil(t, err)
+	}
+	node.Close()
+	require.Equal(t, 1, len(saved))
+	assert.True(t, saved[0].Equals(seed.ID))
+
+	// Add a live peer and a peer that doesn't answer to the saved table
+	dead := peer.CreateID(network.FormatAddress("tcp", "127.0.0.1", uint16(network.GetRandomUnusedPort())), ed25519.RandomKeyPair().PublicKey)
+	routes := dht.CreateRoutingTable(node.ID)
+	for _, id := range append(saved, alive.ID, dead) {
+		routes.Update(id)
+	}
+	require.Nil(t, routes.Save(path))
+
+	second := &Plugin{RoutesPath: path}
+	restarted := newNode(t, second)
+	defer restarted.Close()
+	waitForPeers(second.Routes, 2)
+	assert.True(t, second.Routes.PeerExists(seed.ID))
+	assert.True(t, second.Routes.PeerExists(alive.ID))
+	assert.False(t, second.Routes.PeerExists(dead), "peers failing the liveness check should not be trusted")
+}
+
+func TestDiscoverPeersOfSeedNode(t *testing.T) {
+	mem := transport.NewMemoryNetwork(1)
+	seed := newMemoryNode(t, mem, 3000, new(Plugin))
+	defer seed.Close()
+
+	var plugins []*Plugin
+	var nodes []*network.Network
+	for i := 0; i < 3; i++ {
+		plugin := &Plugin{SeedNodes: []string{seed.Address}}
+		node := newMemoryNode(t, mem, uint16(3001+i), plugin)
+		defer node.Close()
+		plugins = append(plugins, plugin)
+		nodes = append(nodes, node)
+	}
+
+	// Every node learns about the seed and the nodes that bootstrapped
+	// from it, including those that joined after it
+	for i, plugin := range plugins {
+		waitForPeers(plugin.Routes, 3)
+		assert.True(t, plugin.Routes.PeerExists(seed.ID))
+		for j, node := range nodes {
+			if i != j {
+				assert.True(t, plugin.Routes.PeerExists(node.ID), "node %d should know node %d", i, j)
+			}
+		}
+	}
+}
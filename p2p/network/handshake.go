@@ -0,0 +1,233 @@
+package network
+
+import (
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"io"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/chacha20poly1305"
+	"golang.org/x/crypto/curve25519"
+	"golang.org/x/crypto/hkdf"
+)
+
+const (
+	// maxHandshakeFrameSize bounds the hello and signature frames of a handshake.
+	maxHandshakeFrameSize = 1024
+	// maxSecureFrameSize bounds the plaintext carried by one encrypted frame.
+	maxSecureFrameSize = 16 * 1024
+
+	roleInitiator byte = 1
+	roleResponder byte = 2
+)
+
+var handshakeLabel = []byte("herdius-p2p-handshake-v1")
+
+// SecureConn is a connection authenticated by a handshake, carrying
+// length prefixed frames sealed with ChaCha20-Poly1305.
+type SecureConn struct {
+	net.Conn
+
+	remoteKey []byte
+
+	writeMutex sync.Mutex
+	sendAEAD   cipher.AEAD
+	sendNonce  uint64
+
+	readMutex sync.Mutex
+	recvAEAD  cipher.AEAD
+	recvNonce uint64
+	readBuf   []byte
+}
+
+// RemotePublicKey returns the node public key the peer proved it owns during
+// the handshake.
+func (c *SecureConn) RemotePublicKey() []byte {
+	return c.remoteKey
+}
+
+// Write seals p into one or more frames and writes them to the connection.
+func (c *SecureConn) Write(p []byte) (int, error) {
+	c.writeMutex.Lock()
+	defer c.writeMutex.Unlock()
+
+	written := 0
+	for written < len(p) {
+		end := written + maxSecureFrameSize
+		if end > len(p) {
+			end = len(p)
+		}
+		sealed := c.sendAEAD.Seal(nil, frameNonce(c.sendNonce), p[written:end], nil)
+		c.sendNonce++
+		if err := writeFrame(c.Conn, sealed); err != nil {
+			return written, err
+		}
+		written = end
+	}
+	return written, nil
+}
+
+// Read opens the next frame from the connection when the previous one has
+// been consumed and copies its plaintext into p.
+func (c *SecureConn) Read(p []byte) (int, error) {
+	c.readMutex.Lock()
+	defer c.readMutex.Unlock()
+
+	for len(c.readBuf) == 0 {
+		sealed, err := readFrame(c.Conn, maxSecureFrameSize+c.recvAEAD.Overhead())
+		if err != nil {
+			return 0, err
+		}
+		plain, err := c.recvAEAD.Open(sealed[:0], frameNonce(c.recvNonce), sealed, nil)
+		if err != nil {
+			return 0, errors.New("secure conn: failed to authenticate frame")
+		}
+		c.recvNonce++
+		c.readBuf = plain
+	}
+
+	n := copy(p, c.readBuf)
+	c.readBuf = c.readBuf[n:]
+	return n, nil
+}
+
+// handshake authenticates conn and derives its session keys. Both sides send
+// their node public key and an ephemeral X25519 key, then sign both hellos
+// with their node key. The X25519 shared secret keys one cipher per direction.
+func (n *Network) handshake(conn net.Conn, initiator bool) (*SecureConn, error) {
+	if n.opts.connectionTimeout > 0 {
+		conn.SetDeadline(time.Now().Add(n.opts.connectionTimeout))
+		defer conn.SetDeadline(time.Time{})
+	}
+
+	var ephemeralPriv, ephemeralPub [32]byte
+	if _, err := io.ReadFull(rand.Reader, ephemeralPriv[:]); err != nil {
+		return nil, errors.Wrap(err, "handshake: failed to generate ephemeral key")
+	}
+	curve25519.ScalarBaseMult(&ephemeralPub, &ephemeralPriv)
+
+	// The ephemeral key goes first, as node keys differ in length.
+	hello := make([]byte, 0, len(ephemeralPub)+len(n.keys.PublicKey))
+	hello = append(hello, ephemeralPub[:]...)
+	hello = append(hello, n.keys.PublicKey...)
+	remoteHello, err := exchangeFrames(conn, hello, initiator)
+	if err != nil {
+		return nil, errors.Wrap(err, "handshake: failed to exchange hellos")
+	}
+	if len(remoteHello) <= len(ephemeralPub) {
+		return nil, errors.Errorf("handshake: hello has length %d", len(remoteHello))
+	}
+	var remoteEphemeral [32]byte
+	copy(remoteEphemeral[:], remoteHello)
+	remoteKey := remoteHello[len(remoteEphemeral):]
+
+	role, remoteRole := roleInitiator, roleResponder
+	transcript := append(append(append([]byte{}, handshakeLabel...), hello...), remoteHello...)
+	if !initiator {
+		role, remoteRole = roleResponder, roleInitiator
+		transcript = append(append(append([]byte{}, handshakeLabel...), remoteHello...), hello...)
+	}
+
+	signature, err := n.sign(signedTranscript(transcript, role))
+	if err != nil {
+		return nil, errors.Wrap(err, "handshake: failed to sign transcript")
+	}
+	remoteSignature, err := exchangeFrames(conn, signature, initiator)
+	if err != nil {
+		return nil, errors.Wrap(err, "handshake: failed to exchange signatures")
+	}
+	if !n.verify(remoteKey, signedTranscript(transcript, remoteRole), remoteSignature) {
+		return nil, errors.New("handshake: peer failed to prove possession of its node key")
+	}
+
+	var shared [32]byte
+	curve25519.ScalarMult(&shared, &ephemeralPriv, &remoteEphemeral)
+	if shared == [32]byte{} {
+		return nil, errors.New("handshake: peer sent a low order ephemeral key")
+	}
+
+	salt := sha256.Sum256(transcript)
+	keys := make([]byte, 2*chacha20poly1305.KeySize)
+	if _, err := io.ReadFull(hkdf.New(sha256.New, shared[:], salt[:], handshakeLabel), keys); err != nil {
+		return nil, errors.Wrap(err, "handshake: failed to derive session keys")
+	}
+	sendKey, recvKey := keys[:chacha20poly1305.KeySize], keys[chacha20poly1305.KeySize:]
+	if !initiator {
+		sendKey, recvKey = recvKey, sendKey
+	}
+	sendAEAD, err := chacha20poly1305.New(sendKey)
+	if err != nil {
+		return nil, err
+	}
+	recvAEAD, err := chacha20poly1305.New(recvKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return &SecureConn{
+		Conn:      conn,
+		remoteKey: append([]byte{}, remoteKey...),
+		sendAEAD:  sendAEAD,
+		recvAEAD:  recvAEAD,
+	}, nil
+}
+
+// signedTranscript returns the bytes signed by the side of the handshake
+// playing role, so that a signature can't be reflected back to its signer.
+func signedTranscript(transcript []byte, role byte) []byte {
+	return append(append([]byte{}, transcript...), role)
+}
+
+// frameNonce returns the AEAD nonce of the frame with sequence number seq.
+func frameNonce(seq uint64) []byte {
+	nonce := make([]byte, chacha20poly1305.NonceSize)
+	binary.BigEndian.PutUint64(nonce[chacha20poly1305.NonceSize-8:], seq)
+	return nonce
+}
+
+// exchangeFrames sends data and reads the peer's frame in return. The
+// initiator writes first so the exchange works over unbuffered connections.
+func exchangeFrames(conn net.Conn, data []byte, initiator bool) ([]byte, error) {
+	if initiator {
+		if err := writeFrame(conn, data); err != nil {
+			return nil, err
+		}
+		return readFrame(conn, maxHandshakeFrameSize)
+	}
+	remote, err := readFrame(conn, maxHandshakeFrameSize)
+	if err != nil {
+		return nil, err
+	}
+	return remote, writeFrame(conn, data)
+}
+
+// writeFrame writes data prefixed by its length.
+func writeFrame(w io.Writer, data []byte) error {
+	frame := make([]byte, 4+len(data))
+	binary.BigEndian.PutUint32(frame, uint32(len(data)))
+	copy(frame[4:], data)
+	_, err := w.Write(frame)
+	return err
+}
+
+// readFrame reads a length prefixed frame of at most maxSize bytes.
+func readFrame(r io.Reader, maxSize int) ([]byte, error) {
+	header := make([]byte, 4)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return nil, err
+	}
+	size := binary.BigEndian.Uint32(header)
+	if size == 0 || size > uint32(maxSize) {
+		return nil, errors.Errorf("frame has length of %d which is either broken or too large", size)
+	}
+	data := make([]byte, size)
+	if _, err := io.ReadFull(r, data); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
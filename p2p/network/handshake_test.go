@@ -0,0 +1,205 @@
+package network
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net"
+	"testing"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/herdius/herdius-core/crypto/secp256k1"
+	"github.com/herdius/herdius-core/p2p/crypto"
+	"github.com/herdius/herdius-core/p2p/crypto/ed25519"
+	pb "github.com/herdius/herdius-core/p2p/internal/protobuf"
+)
+
+// recordingConn records the bytes written to the underlying connection.
+type recordingConn struct {
+	net.Conn
+	written bytes.Buffer
+}
+
+func (c *recordingConn) Write(p []byte) (int, error) {
+	c.written.Write(p)
+	return c.Conn.Write(p)
+}
+
+func buildHandshakeNetwork(t *testing.T, opts ...BuilderOption) *Network {
+	return buildHandshakeNetworkWithKeys(t, ed25519.RandomKeyPair(), opts...)
+}
+
+func buildHandshakeNetworkWithKeys(t *testing.T, keys *crypto.KeyPair, opts ...BuilderOption) *Network {
+	builder := NewBuilderWithOptions(opts...)
+	builder.SetKeys(keys)
+	builder.SetAddress("tcp://127.0.0.1:3000")
+	net, err := builder.Build()
+	require.Nil(t, err)
+	return net
+}
+
+// handshakePipe handshakes initiator with responder over a pipe. Like
+// Accept, the responder closes its end when the handshake fails.
+func handshakePipe(initiator, responder *Network, initiatorConn, responderConn net.Conn) (*SecureConn, *SecureConn, error, error) {
+	type result struct {
+		conn *SecureConn
+		err  error
+	}
+	done := make(chan result)
+	go func() {
+		conn, err := responder.handshake(responderConn, false)
+		if err != nil {
+			responderConn.Close()
+		}
+		done <- result{conn, err}
+	}()
+	conn, err := initiator.handshake(initiatorConn, true)
+	res := <-done
+	return conn, res.conn, err, res.err
+}
+
+func TestHandshake(t *testing.T) {
+	alice, bob := buildHandshakeNetwork(t), buildHandshakeNetwork(t)
+	a, b := net.Pipe()
+	recorder := &recordingConn{Conn: a}
+
+	aliceConn, bobConn, aliceErr, bobErr := handshakePipe(alice, bob, recorder, b)
+	require.Nil(t, aliceErr)
+	require.Nil(t, bobErr)
+	assert.Equal(t, bob.keys.PublicKey, aliceConn.RemotePublicKey())
+	assert.Equal(t, alice.keys.PublicKey, bobConn.RemotePublicKey())
+
+	plaintext := bytes.Repeat([]byte("herdius secret "), 2000)
+	go func() {
+		_, err := aliceConn.Write(plaintext)
+		assert.Nil(t, err)
+	}()
+	received := make([]byte, len(plaintext))
+	_, err := io.ReadFull(bobConn, received)
+	require.Nil(t, err)
+	assert.Equal(t, plaintext, received)
+	assert.False(t, bytes.Contains(recorder.written.Bytes(), []byte("herdius secret")), "frames should be encrypted")
+
+	go func() {
+		_, err := bobConn.Write([]byte("reply"))
+		assert.Nil(t, err)
+	}()
+	reply := make([]byte, 5)
+	_, err = io.ReadFull(aliceConn, reply)
+	require.Nil(t, err)
+	assert.Equal(t, []byte("reply"), reply)
+}
+
+// nodeKeyPair returns a key pair built from a secp256k1 node key, the way
+// herserver and hervalidator build theirs.
+func nodeKeyPair() *crypto.KeyPair {
+	privKey := secp256k1.GenPrivKey()
+	pubKey := privKey.PubKey()
+	return &crypto.KeyPair{
+		PublicKey:  pubKey.Bytes(),
+		PrivateKey: privKey.Bytes(),
+		PrivKey:    privKey,
+		PubKey:     pubKey,
+	}
+}
+
+func TestHandshakeWithNodeKeys(t *testing.T) {
+	alice := buildHandshakeNetworkWithKeys(t, nodeKeyPair(), RequireSignedMessages(true))
+	bob := buildHandshakeNetworkWithKeys(t, nodeKeyPair(), RequireSignedMessages(true))
+	a, b := net.Pipe()
+
+	aliceConn, bobConn, aliceErr, bobErr := handshakePipe(alice, bob, a, b)
+	require.Nil(t, aliceErr)
+	require.Nil(t, bobErr)
+	assert.Equal(t, bob.keys.PublicKey, aliceConn.RemotePublicKey())
+	assert.Equal(t, alice.keys.PublicKey, bobConn.RemotePublicKey())
+
+	msg, err := alice.PrepareMessage(WithSignMessage(context.Background(), true), &pb.Ping{})
+	require.Nil(t, err)
+	raw, err := proto.Marshal(msg)
+	require.Nil(t, err)
+	go writeFrame(aliceConn, raw)
+	_, err = bob.receiveMessage(bobConn)
+	assert.Nil(t, err)
+
+	msg.Message = []byte("forged")
+	raw, err = proto.Marshal(msg)
+	require.Nil(t, err)
+	go writeFrame(aliceConn, raw)
+	_, err = bob.receiveMessage(bobConn)
+	assert.Error(t, err)
+}
+
+// impersonatingConn replaces the node key in the hello written through it,
+// the way a peer claiming another node's identity would.
+type impersonatingConn struct {
+	net.Conn
+	publicKey []byte
+	replaced  bool
+}
+
+func (c *impersonatingConn) Write(p []byte) (int, error) {
+	if !c.replaced {
+		c.replaced = true
+		forged := append([]byte{}, p...)
+		copy(forged[4+32:], c.publicKey)
+		return c.Conn.Write(forged)
+	}
+	return c.Conn.Write(p)
+}
+
+func TestHandshakeRejectsImpersonation(t *testing.T) {
+	alice, bob, mallory := buildHandshakeNetwork(t), buildHandshakeNetwork(t), buildHandshakeNetwork(t)
+	a, b := net.Pipe()
+	forger := &impersonatingConn{Conn: a, publicKey: alice.keys.PublicKey}
+
+	_, _, malloryErr, bobErr := handshakePipe(mallory, bob, forger, b)
+	a.Close()
+	b.Close()
+	assert.Error(t, bobErr)
+	assert.Error(t, malloryErr)
+}
+
+func TestSecureConnRejectsTamperedFrame(t *testing.T) {
+	alice, bob := buildHandshakeNetwork(t), buildHandshakeNetwork(t)
+	a, b := net.Pipe()
+	aliceConn, bobConn, aliceErr, bobErr := handshakePipe(alice, bob, a, b)
+	require.Nil(t, aliceErr)
+	require.Nil(t, bobErr)
+
+	frame := aliceConn.sendAEAD.Seal(nil, frameNonce(aliceConn.sendNonce), []byte("hello"), nil)
+	frame[0] ^= 0xFF
+	go writeFrame(a, frame)
+	_, err := bobConn.Read(make([]byte, 5))
+	assert.Error(t, err)
+}
+
+func TestRequireSignedMessages(t *testing.T) {
+	alice := buildHandshakeNetwork(t)
+	bob := buildHandshakeNetwork(t, RequireSignedMessages(true))
+	carol := buildHandshakeNetwork(t)
+	assert.True(t, bob.opts.requireSignature)
+
+	for _, signed := range []bool{false, true} {
+		msg, err := alice.PrepareMessage(WithSignMessage(context.Background(), signed), &pb.Ping{})
+		require.Nil(t, err)
+		raw, err := proto.Marshal(msg)
+		require.Nil(t, err)
+
+		for _, receiver := range []*Network{bob, carol} {
+			a, b := net.Pipe()
+			go writeFrame(a, raw)
+			_, err = receiver.receiveMessage(b)
+			if receiver == bob && !signed {
+				assert.Error(t, err)
+			} else {
+				assert.Nil(t, err)
+			}
+			a.Close()
+			b.Close()
+		}
+	}
+}
@@ -0,0 +1,98 @@
+package network
+
+import (
+	"bytes"
+)
+
+// Misbehavior is a protocol violation committed by a peer.
+type Misbehavior int
+
+const (
+	// MalformedSignature is a message with an invalid or missing signature,
+	// or sent on behalf of another node key.
+	MalformedSignature Misbehavior = iota + 1
+	// UnregisteredOpcode is a message with an opcode this node doesn't know.
+	UnregisteredOpcode
+	// OversizedMessage is a message over the maximum message size.
+	OversizedMessage
+	// MalformedMessage is a message that can't be decoded.
+	MalformedMessage
+	// InvalidVote is a child block vote that doesn't verify.
+	InvalidVote
+)
+
+func (m Misbehavior) String() string {
+	switch m {
+	case MalformedSignature:
+		return "malformed signature"
+	case UnregisteredOpcode:
+		return "unregistered opcode"
+	case OversizedMessage:
+		return "oversized message"
+	case MalformedMessage:
+		return "malformed message"
+	case InvalidVote:
+		return "invalid vote"
+	}
+	return "unknown misbehavior"
+}
+
+// MisbehaviorPlugin is implemented by plugins that want to be told about
+// peers misbehaving.
+type MisbehaviorPlugin interface {
+	// PeerMisbehaved is called with the node key and, when known, the
+	// address of a peer that committed m.
+	PeerMisbehaved(publicKey []byte, address string, m Misbehavior)
+}
+
+// PeerFilterPlugin is implemented by plugins that refuse connections to or
+// from some peers.
+type PeerFilterPlugin interface {
+	// AllowPeer is called after the handshake with the peer's node key.
+	AllowPeer(publicKey []byte) bool
+}
+
+// misbehaviorError is an error caused by a peer misbehaving.
+type misbehaviorError struct {
+	misbehavior Misbehavior
+	err         error
+}
+
+func (e *misbehaviorError) Error() string {
+	return e.err.Error()
+}
+
+// ReportMisbehavior tells the plugins implementing MisbehaviorPlugin that
+// the peer with publicKey at address misbehaved.
+func (n *Network) ReportMisbehavior(publicKey []byte, address string, m Misbehavior) {
+	if len(publicKey) == 0 {
+		return
+	}
+	n.plugins.Each(func(plugin PluginInterface) {
+		if p, ok := plugin.(MisbehaviorPlugin); ok {
+			p.PeerMisbehaved(publicKey, address, m)
+		}
+	})
+}
+
+// allowPeer returns false if any plugin implementing PeerFilterPlugin
+// refuses the peer with publicKey.
+func (n *Network) allowPeer(publicKey []byte) bool {
+	allowed := true
+	n.plugins.Each(func(plugin PluginInterface) {
+		if p, ok := plugin.(PeerFilterPlugin); ok && !p.AllowPeer(publicKey) {
+			allowed = false
+		}
+	})
+	return allowed
+}
+
+// DisconnectPeer closes the clients of the peer with publicKey.
+func (n *Network) DisconnectPeer(publicKey []byte) {
+	n.eachPeer(func(client *PeerClient) bool {
+		if bytes.Equal(client.RemotePublicKey(), publicKey) {
+			client.Close()
+		}
+		return true
+	})
+}
@@ -6,6 +6,7 @@ package network
 
 import (
 	"bufio"
+	"bytes"
 	"context"
 	"math/rand"
 	"net"
@@ -15,6 +16,7 @@ import (
 
 	"github.com/herdius/herdius-core/blockchain/protobuf"
 	"github.com/herdius/herdius-core/p2p/crypto"
+	pb "github.com/herdius/herdius-core/p2p/internal/protobuf"
 	"github.com/herdius/herdius-core/p2p/log"
 	"github.com/herdius/herdius-core/p2p/network/transport"
 	"github.com/herdius/herdius-core/p2p/peer"
@@ -31,6 +33,7 @@ const (
 	defaultWriteBufferSize   = 4096
 	defaultWriteFlushLatency = 50 * time.Millisecond
 	defaultWriteTimeout      = 3 * time.Second
+	defaultMaxTransferSize   = 256 << 20
 	defaultaddress           = "tcp://127.0.0.1:2001"
 )
 
@@ -91,11 +94,15 @@ type options struct {
 	writeFlushLatency time.Duration
 	writeTimeout      time.Duration
 	address           string
+	requireSignature  bool
+	maxTransferSize   int
+	retryPolicy       RetryPolicy
 }
 
 // ConnState represents a connection.
 type ConnState struct {
 	conn         net.Conn
+	remoteKey    []byte
 	writer       *bufio.Writer
 	messageNonce uint64
 	writerMutex  *sync.Mutex
@@ -144,22 +151,16 @@ func (n *Network) dispatchMessage(client *PeerClient, msg *protobuf.Message) {
 	switch code {
 	case opcode.BytesCode:
 		ptr = new(protobuf.Bytes)
-	case opcode.PingCode:
-		ptr = new(protobuf.Ping)
-	case opcode.PongCode:
-		ptr = new(protobuf.Pong)
-	case opcode.LookupNodeRequestCode:
-		ptr = new(protobuf.LookupNodeRequest)
-	case opcode.LookupNodeResponseCode:
-		ptr = new(protobuf.LookupNodeResponse)
 	case opcode.UnregisteredCode:
 		log.Error().Msg("network: message received had no opcode")
+		n.ReportMisbehavior(client.ID.PublicKey, client.Address, UnregisteredOpcode)
 		return
 	default:
 		var err error
 		ptr, err = opcode.GetMessageType(code)
 		if err != nil {
 			log.Error().Err(err).Msg("network: received message opcode is not registered")
+			n.ReportMisbehavior(client.ID.PublicKey, client.Address, UnregisteredOpcode)
 			return
 		}
 	}
@@ -167,6 +168,7 @@ func (n *Network) dispatchMessage(client *PeerClient, msg *protobuf.Message) {
 	if len(msg.Message) > 0 {
 		if err := proto.Unmarshal(msg.Message, ptr); err != nil {
 			log.Error().Msgf("%v", err)
+			n.ReportMisbehavior(client.ID.PublicKey, client.Address, MalformedMessage)
 			return
 		}
 	}
@@ -185,6 +187,10 @@ func (n *Network) dispatchMessage(client *PeerClient, msg *protobuf.Message) {
 	switch msgRaw := ptr.(type) {
 	case *protobuf.Bytes:
 		client.handleBytes(msgRaw.Data)
+	case *pb.Chunk:
+		client.handleChunk(msgRaw)
+	case *pb.ChunkAck:
+		client.handleChunkAck(msgRaw)
 	default:
 		ctx := contextPool.Get().(*PluginContext)
 		ctx.client = client
@@ -307,9 +313,15 @@ func (n *Network) Client(address string) (*PeerClient, error) {
 		n.peers.Delete(address)
 		return nil, err
 	}
+	if !n.allowPeer(conn.(*SecureConn).RemotePublicKey()) {
+		conn.Close()
+		n.peers.Delete(address)
+		return nil, errors.New("network: peer is banned")
+	}
 
 	n.connections.Store(address, &ConnState{
 		conn:        conn,
+		remoteKey:   conn.(*SecureConn).RemotePublicKey(),
 		writer:      bufio.NewWriterSize(conn, n.opts.writeBufferSize),
 		writerMutex: new(sync.Mutex),
 	})
@@ -408,14 +420,34 @@ func (n *Network) Dial(address string) (net.Conn, error) {
 		return nil, err
 	}
 
-	return conn, nil
+	secure, err := n.handshake(conn, true)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	return secure, nil
 }
 
-// Accept handles peer registration and processes incoming message streams.
+// Accept handshakes with the peer, then handles peer registration and
+// processes incoming message streams.
 func (n *Network) Accept(incoming net.Conn) {
 
 	var client *PeerClient
 
+	secure, err := n.handshake(incoming, false)
+	if err != nil {
+		log.Warn().Err(err).Str("remote", incoming.RemoteAddr().String()).Msg("network: handshake failed")
+		incoming.Close()
+		return
+	}
+	incoming = secure
+	if !n.allowPeer(secure.RemotePublicKey()) {
+		log.Warn().Str("remote", incoming.RemoteAddr().String()).Msg("network: refused banned peer")
+		incoming.Close()
+		return
+	}
+
 	recvWindow := NewRecvWindow(n.opts.recvWindowSize)
 
 	// Cleanup connections when we are done with them.
@@ -439,6 +471,28 @@ func (n *Network) Accept(incoming net.Conn) {
 			if err != errEmptyMsg {
 				log.Error().Msgf("%v", err)
 			}
+			if misbehavior, ok := err.(*misbehaviorError); ok {
+				address := ""
+				if client != nil {
+					address = client.Address
+				}
+				n.ReportMisbehavior(secure.RemotePublicKey(), address, misbehavior.misbehavior)
+			}
+			break
+		}
+
+		// Peer got banned while connected. Disconnect.
+		if !n.allowPeer(secure.RemotePublicKey()) {
+			break
+		}
+
+		// Peer sent message on behalf of another node key. Disconnect.
+		if !bytes.Equal(msg.Sender.PublicKey, secure.RemotePublicKey()) ||
+			!peer.CreateID(msg.Sender.Address, msg.Sender.PublicKey).Equals(peer.ID(*msg.Sender)) {
+			log.Error().
+				Interface("peer_id", peer.ID(*msg.Sender)).
+				Msg("network: message sender does not match handshake key")
+			n.ReportMisbehavior(secure.RemotePublicKey(), "", MalformedSignature)
 			break
 		}
 
@@ -448,6 +502,16 @@ func (n *Network) Accept(incoming net.Conn) {
 			if err != nil {
 				return
 			}
+
+			// The node dialed back at the claimed address must own the same key.
+			state, ok := n.ConnectionState(client.Address)
+			if !ok || !bytes.Equal(state.remoteKey, secure.RemotePublicKey()) {
+				log.Error().
+					Str("address", msg.Sender.Address).
+					Msg("network: peer claims an address owned by another node key")
+				client = nil
+				break
+			}
 		}
 
 		client.Do(func() {
@@ -465,26 +529,32 @@ func (n *Network) Accept(incoming net.Conn) {
 			return
 		}
 
-		go func() {
-			// Peer sent message with a completely different ID. Disconnect.
-			if !client.ID.Equals(peer.ID(*msg.Sender)) {
-				log.Error().
-					Interface("peer_id", peer.ID(*msg.Sender)).
-					Interface("client_id", client.ID).
-					Msg("Message signed by peer does not match client ID.")
-				return
-			}
-
-			recvWindow.Push(msg.MessageNonce, msg)
+		// Peer sent message with a completely different ID.
+		if !client.ID.Equals(peer.ID(*msg.Sender)) {
+			log.Error().
+				Interface("peer_id", peer.ID(*msg.Sender)).
+				Interface("client_id", client.ID).
+				Msg("Message signed by peer does not match client ID.")
+			continue
+		}
 
-			ready := recvWindow.Pop()
-			for _, msg := range ready {
-				msg := msg
-				client.Submit(func() {
-					n.dispatchMessage(client, msg.(*protobuf.Message))
-				})
+		// Messages are pushed and dispatched in the order they are received,
+		// as the window starts at the first message pushed.
+		recvWindow.Push(msg.MessageNonce, msg)
+
+		ready := recvWindow.Pop()
+		for _, msg := range ready {
+			msg := msg
+			// Handle acknowledgements right away, as a job sending a
+			// chunked message waits for them.
+			if opcode.Opcode(msg.(*protobuf.Message).Opcode) == opcode.ChunkAckCode {
+				n.dispatchMessage(client, msg.(*protobuf.Message))
+				continue
 			}
-		}()
+			client.Submit(func() {
+				n.dispatchMessage(client, msg.(*protobuf.Message))
+			})
+		}
 	}
 }
 
@@ -522,11 +592,7 @@ func (n *Network) PrepareMessage(ctx context.Context, message proto.Message) (*p
 	}
 
 	if GetSignMessage(ctx) {
-		signature, err := n.keys.Sign(
-			n.opts.signaturePolicy,
-			n.opts.hashPolicy,
-			SerializeMessage(&id, raw),
-		)
+		signature, err := n.sign(SerializeMessage(&id, raw))
 		if err != nil {
 			return nil, err
 		}
@@ -538,11 +604,24 @@ func (n *Network) PrepareMessage(ctx context.Context, message proto.Message) (*p
 
 // Write asynchronously sends a message to a denoted target address.
 func (n *Network) Write(address string, message *protobuf.Message) error {
+	return n.write(context.Background(), address, message)
+}
+
+// write sends a message to a denoted target address, unless ctx is done.
+func (n *Network) write(ctx context.Context, address string, message *protobuf.Message) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
 	state, ok := n.ConnectionState(address)
 	if !ok {
 		return errors.New("network: connection does not exist")
 	}
 
+	if proto.Size(message) > maxMessageSize {
+		return n.writeChunked(ctx, address, message)
+	}
+
 	message.MessageNonce = atomic.AddUint64(&state.messageNonce, 1)
 
 	state.conn.SetWriteDeadline(time.Now().Add(n.opts.writeTimeout))
@@ -0,0 +1,239 @@
+package reputation
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/herdius/herdius-core/p2p/log"
+	"github.com/herdius/herdius-core/p2p/network"
+	"github.com/pkg/errors"
+)
+
+const (
+	defaultBanThreshold = -100
+	defaultBanDuration  = 24 * time.Hour
+)
+
+// defaultPenalties are the scores taken off a peer for each misbehavior.
+var defaultPenalties = map[network.Misbehavior]int{
+	network.MalformedSignature: 50,
+	network.UnregisteredOpcode: 10,
+	network.OversizedMessage:   50,
+	network.MalformedMessage:   20,
+	network.InvalidVote:        25,
+}
+
+// Plugin scores peers on their misbehavior, and disconnects and bans the
+// peers whose score drops to the ban threshold.
+type Plugin struct {
+	*network.Plugin
+
+	// plugin options
+	// threshold is the score at which a peer gets banned
+	threshold int
+	// banDuration is how long a peer stays banned
+	banDuration time.Duration
+	// banListPath is the file the ban list is persisted to, if set
+	banListPath string
+	// penalties are the scores taken off a peer for each misbehavior
+	penalties map[network.Misbehavior]int
+
+	net *network.Network
+	now func() time.Time
+
+	mutex  sync.Mutex
+	scores map[string]int       // hex node key -> score
+	bans   map[string]time.Time // hex node key -> end of ban
+}
+
+// PluginOption are configurable options for the reputation plugin
+type PluginOption func(*Plugin)
+
+// WithThreshold specifies the score at which a peer gets banned
+func WithThreshold(threshold int) PluginOption {
+	return func(p *Plugin) {
+		p.threshold = threshold
+	}
+}
+
+// WithBanDuration specifies how long a peer stays banned
+func WithBanDuration(d time.Duration) PluginOption {
+	return func(p *Plugin) {
+		p.banDuration = d
+	}
+}
+
+// WithBanListPath specifies the file the ban list is loaded from and
+// persisted to
+func WithBanListPath(path string) PluginOption {
+	return func(p *Plugin) {
+		p.banListPath = path
+	}
+}
+
+// WithPenalty specifies the score taken off a peer for misbehavior m
+func WithPenalty(m network.Misbehavior, penalty int) PluginOption {
+	return func(p *Plugin) {
+		p.penalties[m] = penalty
+	}
+}
+
+var (
+	_ network.PluginInterface   = (*Plugin)(nil)
+	_ network.MisbehaviorPlugin = (*Plugin)(nil)
+	_ network.PeerFilterPlugin  = (*Plugin)(nil)
+	// PluginID is used to check existence of the reputation plugin
+	PluginID = (*Plugin)(nil)
+)
+
+// New returns a new reputation plugin with specified options. The ban list
+// is loaded from the ban list path if one is given.
+func New(opts ...PluginOption) *Plugin {
+	p := &Plugin{
+		threshold:   defaultBanThreshold,
+		banDuration: defaultBanDuration,
+		penalties:   make(map[network.Misbehavior]int),
+		now:         time.Now,
+		scores:      make(map[string]int),
+		bans:        make(map[string]time.Time),
+	}
+	for m, penalty := range defaultPenalties {
+		p.penalties[m] = penalty
+	}
+
+	for _, opt := range opts {
+		opt(p)
+	}
+
+	if err := p.loadBanList(); err != nil {
+		log.Error().Err(err).Str("path", p.banListPath).Msg("reputation: failed to load ban list")
+	}
+	return p
+}
+
+// Startup implements the plugin callback
+func (p *Plugin) Startup(net *network.Network) {
+	p.net = net
+}
+
+// PeerMisbehaved implements the network.MisbehaviorPlugin callback
+func (p *Plugin) PeerMisbehaved(publicKey []byte, address string, m network.Misbehavior) {
+	key := hex.EncodeToString(publicKey)
+
+	p.mutex.Lock()
+	if _, banned := p.bans[key]; banned {
+		p.mutex.Unlock()
+		return
+	}
+	p.scores[key] -= p.penalties[m]
+	score := p.scores[key]
+	banned := score <= p.threshold
+	if banned {
+		delete(p.scores, key)
+		p.bans[key] = p.now().Add(p.banDuration)
+		if err := p.saveBanList(); err != nil {
+			log.Error().Err(err).Str("path", p.banListPath).Msg("reputation: failed to save ban list")
+		}
+	}
+	p.mutex.Unlock()
+
+	log.Warn().
+		Str("peer", key).
+		Str("address", address).
+		Str("misbehavior", m.String()).
+		Int("score", score).
+		Msg("reputation: peer misbehaved")
+
+	if banned {
+		log.Warn().
+			Str("peer", key).
+			Str("address", address).
+			Dur("duration", p.banDuration).
+			Msg("reputation: banning peer")
+		if p.net != nil {
+			p.net.DisconnectPeer(publicKey)
+		}
+	}
+}
+
+// AllowPeer implements the network.PeerFilterPlugin callback
+func (p *Plugin) AllowPeer(publicKey []byte) bool {
+	return !p.IsBanned(publicKey)
+}
+
+// IsBanned returns whether the peer with publicKey is banned
+func (p *Plugin) IsBanned(publicKey []byte) bool {
+	key := hex.EncodeToString(publicKey)
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	until, banned := p.bans[key]
+	if !banned {
+		return false
+	}
+	if p.now().Before(until) {
+		return true
+	}
+	delete(p.bans, key)
+	if err := p.saveBanList(); err != nil {
+		log.Error().Err(err).Str("path", p.banListPath).Msg("reputation: failed to save ban list")
+	}
+	return false
+}
+
+// Score returns the score of the peer with publicKey
+func (p *Plugin) Score(publicKey []byte) int {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	return p.scores[hex.EncodeToString(publicKey)]
+}
+
+// loadBanList loads the bans that haven't ended from the ban list path
+func (p *Plugin) loadBanList() error {
+	if p.banListPath == "" {
+		return nil
+	}
+	data, err := ioutil.ReadFile(p.banListPath)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	bans := make(map[string]time.Time)
+	if err := json.Unmarshal(data, &bans); err != nil {
+		return errors.Wrap(err, "failed to decode ban list")
+	}
+	now := p.now()
+	for key, until := range bans {
+		if now.Before(until) {
+			p.bans[key] = until
+		}
+	}
+	return nil
+}
+
+// saveBanList persists the bans to the ban list path. It must be called with
+// the mutex held.
+func (p *Plugin) saveBanList() error {
+	if p.banListPath == "" {
+		return nil
+	}
+	data, err := json.MarshalIndent(p.bans, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(p.banListPath), 0755); err != nil {
+		return err
+	}
+	tmp := p.banListPath + ".tmp"
+	if err := ioutil.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, p.banListPath)
+}
@@ -0,0 +1,92 @@
+package reputation
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/herdius/herdius-core/p2p/crypto/ed25519"
+	"github.com/herdius/herdius-core/p2p/internal/protobuf"
+	"github.com/herdius/herdius-core/p2p/network"
+)
+
+func TestBanAtThreshold(t *testing.T) {
+	dir, err := ioutil.TempDir(os.TempDir(), "reputation_test_")
+	require.Nil(t, err)
+	defer os.RemoveAll(dir)
+	banListPath := filepath.Join(dir, "banlist.json")
+
+	now := time.Now()
+	p := New(WithThreshold(-30), WithBanDuration(time.Hour), WithBanListPath(banListPath))
+	p.now = func() time.Time { return now }
+	spammer, other := []byte{1, 2, 3}, []byte{4, 5, 6}
+
+	p.PeerMisbehaved(spammer, "tcp://127.0.0.1:3001", network.UnregisteredOpcode)
+	p.PeerMisbehaved(other, "tcp://127.0.0.1:3002", network.UnregisteredOpcode)
+	assert.Equal(t, -10, p.Score(spammer))
+	assert.True(t, p.AllowPeer(spammer))
+
+	p.PeerMisbehaved(spammer, "tcp://127.0.0.1:3001", network.MalformedMessage)
+	assert.False(t, p.AllowPeer(spammer))
+	assert.True(t, p.AllowPeer(other))
+
+	// The ban list survives restarts
+	restarted := New(WithBanListPath(banListPath))
+	restarted.now = func() time.Time { return now }
+	assert.True(t, restarted.IsBanned(spammer))
+	assert.False(t, restarted.IsBanned(other))
+
+	// Bans end after the ban duration
+	now = now.Add(time.Hour + time.Second)
+	assert.True(t, p.AllowPeer(spammer))
+	assert.Equal(t, 0, p.Score(spammer))
+	restarted = New(WithBanListPath(banListPath))
+	assert.False(t, restarted.IsBanned(spammer))
+}
+
+func TestBanListPathMissing(t *testing.T) {
+	p := New(WithBanListPath(filepath.Join(os.TempDir(), "reputation_test_missing", "banlist.json")))
+	assert.False(t, p.IsBanned([]byte{1}))
+}
+
+func newNode(t *testing.T, opts []network.BuilderOption, plugins ...network.PluginInterface) *network.Network {
+	builder := network.NewBuilderWithOptions(opts...)
+	builder.SetKeys(ed25519.RandomKeyPair())
+	builder.SetAddress(network.FormatAddress("tcp", "127.0.0.1", uint16(network.GetRandomUnusedPort())))
+	for _, plugin := range plugins {
+		require.Nil(t, builder.AddPlugin(plugin))
+	}
+	node, err := builder.Build()
+	require.Nil(t, err)
+	go node.Listen()
+	node.BlockUntilListening()
+	return node
+}
+
+func TestPluginBansMisbehavingPeer(t *testing.T) {
+	plugin := New(WithThreshold(-50))
+	supervisor := newNode(t, []network.BuilderOption{network.RequireSignedMessages(true)}, plugin)
+	defer supervisor.Close()
+	spammer := newNode(t, nil)
+	defer spammer.Close()
+
+	client, err := spammer.Client(supervisor.Address)
+	require.Nil(t, err)
+	// Unsigned messages are malformed for a supervisor requiring signatures
+	require.Nil(t, client.Tell(context.Background(), &protobuf.Ping{}))
+
+	deadline := time.Now().Add(3 * time.Second)
+	for !plugin.IsBanned(spammer.ID.PublicKey) && time.Now().Before(deadline) {
+		time.Sleep(50 * time.Millisecond)
+	}
+	require.True(t, plugin.IsBanned(spammer.ID.PublicKey))
+
+	_, err = supervisor.Client(spammer.Address)
+	assert.Error(t, err, "banned peers shouldn't be dialed")
+}
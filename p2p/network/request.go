@@ -0,0 +1,97 @@
+package network
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/pkg/errors"
+)
+
+// RetryPolicy configures how PeerClient.Request retries failed requests.
+type RetryPolicy struct {
+	// MaxAttempts is the number of times a request is sent before erroring
+	MaxAttempts int
+	// AttemptTimeout bounds the wait for a response to each attempt, if set
+	AttemptTimeout time.Duration
+	// MinInterval is the delay before the first retry
+	MinInterval time.Duration
+	// MaxInterval is the maximum delay between two attempts
+	MaxInterval time.Duration
+	// Multiplier increases the delay after each retry
+	Multiplier float64
+}
+
+// DefaultRetryPolicy creates a policy sending requests once.
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts: 1,
+		MinInterval: 100 * time.Millisecond,
+		MaxInterval: 5 * time.Second,
+		Multiplier:  2,
+	}
+}
+
+// delay returns the delay before retrying a request which failed attempt
+// times already.
+func (p RetryPolicy) delay(attempt int) time.Duration {
+	multiplier := math.Max(p.Multiplier, 1)
+	delay := float64(p.MinInterval) * math.Pow(multiplier, float64(attempt-1))
+	if p.MaxInterval > 0 && delay > float64(p.MaxInterval) {
+		return p.MaxInterval
+	}
+	return time.Duration(delay)
+}
+
+// Response is the response of a peer to a request sent by RequestAll.
+type Response struct {
+	Address string
+	Message proto.Message
+	Err     error
+}
+
+// RequestAll sends req to the peers at addresses concurrently. It returns the
+// responses received once quorum peers responded, every peer failed or ctx is
+// done, and errors if fewer than quorum peers responded. A quorum of 0 waits
+// for every peer.
+func (n *Network) RequestAll(ctx context.Context, addresses []string, req proto.Message, quorum int) ([]Response, error) {
+	if quorum <= 0 || quorum > len(addresses) {
+		quorum = len(addresses)
+	}
+
+	// Requests still running once the quorum is reached are cancelled.
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	results := make(chan Response, len(addresses))
+	for _, address := range addresses {
+		go func(address string) {
+			res := Response{Address: address}
+			client, err := n.Client(address)
+			if err == nil {
+				res.Message, err = client.Request(ctx, req)
+			}
+			res.Err = err
+			results <- res
+		}(address)
+	}
+
+	responses := make([]Response, 0, len(addresses))
+	succeeded := 0
+	for succeeded < quorum && len(responses) < len(addresses) {
+		select {
+		case res := <-results:
+			responses = append(responses, res)
+			if res.Err == nil {
+				succeeded++
+			}
+		case <-ctx.Done():
+			return responses, errors.Wrapf(ctx.Err(), "network: %d of %d peers responded", succeeded, quorum)
+		}
+	}
+	if succeeded < quorum {
+		return responses, errors.Errorf("network: %d of %d peers responded", succeeded, quorum)
+	}
+	return responses, nil
+}
@@ -0,0 +1,127 @@
+package network
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/uber-go/atomic"
+
+	pb "github.com/herdius/herdius-core/p2p/internal/protobuf"
+	"github.com/herdius/herdius-core/p2p/network/transport"
+)
+
+// pongPlugin replies to pings with pongs, once it ignored the first drops
+// pings, unless it is silent.
+type pongPlugin struct {
+	*Plugin
+	drops  atomic.Int32
+	silent bool
+}
+
+func (p *pongPlugin) Receive(ctx *PluginContext) error {
+	if _, ok := ctx.Message().(*pb.Ping); !ok || p.silent || p.drops.Dec() >= 0 {
+		return nil
+	}
+	return ctx.Reply(context.Background(), &pb.Pong{})
+}
+
+func TestRetryPolicyDelay(t *testing.T) {
+	policy := RetryPolicy{MinInterval: time.Second, MaxInterval: 5 * time.Second, Multiplier: 2}
+	assert.Equal(t, time.Second, policy.delay(1))
+	assert.Equal(t, 2*time.Second, policy.delay(2))
+	assert.Equal(t, 4*time.Second, policy.delay(3))
+	assert.Equal(t, 5*time.Second, policy.delay(4))
+
+	policy.Multiplier = 0
+	assert.Equal(t, time.Second, policy.delay(3))
+}
+
+func TestRequestContext(t *testing.T) {
+	mem := transport.NewMemoryNetwork(1)
+	alice := buildMemoryNode(t, mem, 3000)
+	defer alice.Close()
+	bob := buildMemoryNode(t, mem, 3001, &pongPlugin{silent: true})
+	defer bob.Close()
+	client, err := alice.Client(bob.Address)
+	require.Nil(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	_, err = client.Request(ctx, &pb.Ping{})
+	assert.Equal(t, context.DeadlineExceeded, err)
+
+	ctx, cancel = context.WithCancel(context.Background())
+	time.AfterFunc(100*time.Millisecond, cancel)
+	_, err = client.Request(ctx, &pb.Ping{})
+	assert.Equal(t, context.Canceled, err)
+
+	_, err = client.Request(ctx, &pb.Ping{})
+	assert.Equal(t, context.Canceled, err, "a done context isn't sent")
+}
+
+func TestRequestRetries(t *testing.T) {
+	mem := transport.NewMemoryNetwork(1)
+	alice := buildMemoryNode(t, mem, 3000)
+	defer alice.Close()
+	plugin := new(pongPlugin)
+	bob := buildMemoryNode(t, mem, 3001, plugin)
+	defer bob.Close()
+	client, err := alice.Client(bob.Address)
+	require.Nil(t, err)
+
+	policy := RetryPolicy{MaxAttempts: 3, AttemptTimeout: 200 * time.Millisecond, MinInterval: 10 * time.Millisecond}
+	plugin.drops.Store(2)
+	res, err := client.Request(WithRetryPolicy(context.Background(), policy), &pb.Ping{})
+	require.Nil(t, err)
+	assert.IsType(t, &pb.Pong{}, res)
+
+	plugin.drops.Store(3)
+	_, err = client.Request(WithRetryPolicy(context.Background(), policy), &pb.Ping{})
+	assert.Error(t, err)
+}
+
+func TestRequestAll(t *testing.T) {
+	mem := transport.NewMemoryNetwork(1)
+	alice := buildMemoryNode(t, mem, 3000)
+	defer alice.Close()
+	var addresses []string
+	for i, silent := range []bool{false, false, true} {
+		peer := buildMemoryNode(t, mem, uint16(3001+i), &pongPlugin{silent: silent})
+		defer peer.Close()
+		addresses = append(addresses, peer.Address)
+	}
+	addresses = append(addresses, "tcp://127.0.0.1:3009")
+
+	// The quorum is reached without waiting for the silent peer.
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	start := time.Now()
+	responses, err := alice.RequestAll(ctx, addresses, &pb.Ping{}, 2)
+	require.Nil(t, err)
+	assert.True(t, time.Since(start) < 5*time.Second)
+	succeeded := 0
+	for _, res := range responses {
+		if res.Err == nil {
+			assert.IsType(t, &pb.Pong{}, res.Message)
+			succeeded++
+		}
+	}
+	assert.Equal(t, 2, succeeded)
+
+	// Every peer is waited for until the timeout.
+	ctx, cancel = context.WithTimeout(context.Background(), 500*time.Millisecond)
+	defer cancel()
+	responses, err = alice.RequestAll(ctx, addresses, &pb.Ping{}, 0)
+	assert.Error(t, err)
+	assert.Len(t, responses, 3, "the silent peer doesn't respond")
+	for _, res := range responses {
+		if res.Address == addresses[3] {
+			assert.Error(t, res.Err, "the peer can't be dialed")
+		} else {
+			assert.Nil(t, res.Err)
+		}
+	}
+}
@@ -0,0 +1,29 @@
+package network
+
+import (
+	"github.com/herdius/herdius-core/p2p/crypto"
+
+	cryptoAmino "github.com/herdius/herdius-core/crypto/encoding/amino"
+)
+
+// sign signs message with the node key. Nodes started from a node key file
+// carry an amino encoded key, which the signature policy can't use.
+func (n *Network) sign(message []byte) ([]byte, error) {
+	if n.keys.PrivKey != nil {
+		return n.keys.PrivKey.Sign(message)
+	}
+	return n.keys.Sign(n.opts.signaturePolicy, n.opts.hashPolicy, message)
+}
+
+// verify returns true if signature was made by the node key publicKey over
+// message, publicKey being either a signature policy or an amino encoded key.
+func (n *Network) verify(publicKey []byte, message []byte, signature []byte) bool {
+	if len(publicKey) == n.opts.signaturePolicy.PublicKeySize() {
+		return crypto.Verify(n.opts.signaturePolicy, n.opts.hashPolicy, publicKey, message, signature)
+	}
+	pubKey, err := cryptoAmino.PubKeyFromBytes(publicKey)
+	if err != nil {
+		return false
+	}
+	return pubKey.VerifyBytes(message, signature)
+}
@@ -9,9 +9,6 @@ import (
 
 	"github.com/gogo/protobuf/proto"
 	"github.com/herdius/herdius-core/blockchain/protobuf"
-
-	"github.com/herdius/herdius-core/p2p/crypto"
-
 	"github.com/herdius/herdius-core/p2p/log"
 	"github.com/pkg/errors"
 )
@@ -85,10 +82,10 @@ func (n *Network) receiveMessage(conn net.Conn) (*protobuf.Message, error) {
 		return nil, errEmptyMsg
 	}
 
-	// Message size at most is limited to 4MB. If a big message need be sent,
-	// consider partitioning to message into chunks of 4MB.
-	if size > 4e+6 {
-		return nil, errors.Errorf("message has length of %d which is either broken or too large", size)
+	// Message size at most is limited to 4MB. Bigger messages are sent in
+	// chunks, see writeChunked.
+	if size > maxMessageSize {
+		return nil, &misbehaviorError{OversizedMessage, errors.Errorf("message has length of %d which is either broken or too large", size)}
 	}
 
 	// Read until all message bytes have been read.
@@ -107,24 +104,31 @@ func (n *Network) receiveMessage(conn net.Conn) (*protobuf.Message, error) {
 	err = proto.Unmarshal(buffer, msg)
 
 	if err != nil {
-		return nil, errors.Wrap(err, "failed to unmarshal message")
+		return nil, &misbehaviorError{MalformedMessage, errors.Wrap(err, "failed to unmarshal message")}
+	}
+
+	if err := n.verifyMessage(msg); err != nil {
+		return nil, err
 	}
 
+	return msg, nil
+}
+
+// verifyMessage checks the headers and the signature of a received message.
+func (n *Network) verifyMessage(msg *protobuf.Message) error {
 	// Check if any of the message headers are invalid or null.
 	if msg.Opcode == 0 || msg.Sender == nil || msg.Sender.PublicKey == nil || len(msg.Sender.Address) == 0 {
-		return nil, errors.New("received an invalid message (either no opcode, no sender, or no signature) from a peer")
+		return &misbehaviorError{MalformedMessage, errors.New("received an invalid message (either no opcode, no sender, or no signature) from a peer")}
+	}
+
+	if msg.Signature == nil && n.opts.requireSignature {
+		return &misbehaviorError{MalformedSignature, errors.New("received an unsigned message from a peer while signed messages are required")}
 	}
 
 	// Verify signature of message.
-	if msg.Signature != nil && !crypto.Verify(
-		n.opts.signaturePolicy,
-		n.opts.hashPolicy,
-		msg.Sender.PublicKey,
-		SerializeMessage(msg.Sender, msg.Message),
-		msg.Signature,
-	) {
-		return nil, errors.New("received message had an malformed signature")
+	if msg.Signature != nil && !n.verify(msg.Sender.PublicKey, SerializeMessage(msg.Sender, msg.Message), msg.Signature) {
+		return &misbehaviorError{MalformedSignature, errors.New("received message had an malformed signature")}
 	}
 
-	return msg, nil
+	return nil
 }
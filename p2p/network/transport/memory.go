@@ -0,0 +1,321 @@
+package transport
+
+import (
+	"errors"
+	"math/rand"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+)
+
+var (
+	errMemoryClosed      = errors.New("memory: use of closed connection")
+	errMemoryLost        = errors.New("memory: write lost")
+	errMemoryRefused     = errors.New("memory: connection refused")
+	errMemoryPartitioned = errors.New("memory: peer is unreachable")
+)
+
+// MemoryNetwork is an in-process network connecting Memory transports by
+// port, with configurable latency, loss and partitions. It lets tests run
+// many nodes in one process without binding real ports.
+type MemoryNetwork struct {
+	mutex     sync.Mutex
+	rand      *rand.Rand
+	latency   time.Duration
+	lossRate  float64
+	listeners map[int]*memoryListener
+	conns     map[*memoryConn]struct{}
+	groups    map[int]int
+}
+
+// NewMemoryNetwork creates an in-process network. seed makes the writes it
+// loses reproducible.
+func NewMemoryNetwork(seed int64) *MemoryNetwork {
+	return &MemoryNetwork{
+		rand:      rand.New(rand.NewSource(seed)),
+		listeners: make(map[int]*memoryListener),
+		conns:     make(map[*memoryConn]struct{}),
+	}
+}
+
+// Transport returns a transport layer attached to the network. Each node
+// needs its own, as the port it listens on identifies it in partitions.
+func (m *MemoryNetwork) Transport() *Memory {
+	return &Memory{network: m}
+}
+
+// SetLatency delays the delivery of every write by latency.
+func (m *MemoryNetwork) SetLatency(latency time.Duration) {
+	m.mutex.Lock()
+	m.latency = latency
+	m.mutex.Unlock()
+}
+
+// SetLossRate loses writes with probability rate. A stream can't skip bytes,
+// so a lost write closes its connection.
+func (m *MemoryNetwork) SetLossRate(rate float64) {
+	m.mutex.Lock()
+	m.lossRate = rate
+	m.mutex.Unlock()
+}
+
+// Partition splits the network into groups of ports which can't reach each
+// other. Ports not listed form one more group. Connections between groups
+// are closed.
+func (m *MemoryNetwork) Partition(groups ...[]int) {
+	m.mutex.Lock()
+	m.groups = make(map[int]int)
+	for i, group := range groups {
+		for _, port := range group {
+			m.groups[port] = i + 1
+		}
+	}
+	var cut []*memoryConn
+	for conn := range m.conns {
+		if !m.reachable(conn.localPort, conn.remotePort) {
+			cut = append(cut, conn)
+		}
+	}
+	m.mutex.Unlock()
+
+	for _, conn := range cut {
+		conn.Close()
+	}
+}
+
+// Heal removes the partitions.
+func (m *MemoryNetwork) Heal() {
+	m.mutex.Lock()
+	m.groups = nil
+	m.mutex.Unlock()
+}
+
+// reachable returns true if port a can reach port b. It must be called with
+// the mutex held.
+func (m *MemoryNetwork) reachable(a, b int) bool {
+	return m.groups == nil || m.groups[a] == m.groups[b]
+}
+
+// dial connects the transport listening on port from to the one on port to.
+func (m *MemoryNetwork) dial(from, to int) (net.Conn, error) {
+	m.mutex.Lock()
+	listener, exists := m.listeners[to]
+	if !exists {
+		m.mutex.Unlock()
+		return nil, errMemoryRefused
+	}
+	if !m.reachable(from, to) {
+		m.mutex.Unlock()
+		return nil, errMemoryPartitioned
+	}
+	a, b := net.Pipe()
+	local := newMemoryConn(m, a, from, to)
+	remote := newMemoryConn(m, b, to, from)
+	m.conns[local] = struct{}{}
+	m.conns[remote] = struct{}{}
+	m.mutex.Unlock()
+
+	select {
+	case listener.incoming <- remote:
+		return local, nil
+	case <-listener.closed:
+		local.Close()
+		remote.Close()
+		return nil, errMemoryRefused
+	}
+}
+
+// send returns when a write should be delivered, or an error if it is lost.
+func (m *MemoryNetwork) send(from, to int) (time.Time, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	if !m.reachable(from, to) {
+		return time.Time{}, errMemoryPartitioned
+	}
+	if m.lossRate > 0 && m.rand.Float64() < m.lossRate {
+		return time.Time{}, errMemoryLost
+	}
+	return time.Now().Add(m.latency), nil
+}
+
+// Memory is a transport layer over a MemoryNetwork.
+type Memory struct {
+	network *MemoryNetwork
+	port    int
+}
+
+// Listen listens for connections dialed to port on the memory network.
+func (t *Memory) Listen(port int) (net.Listener, error) {
+	t.network.mutex.Lock()
+	defer t.network.mutex.Unlock()
+
+	if _, exists := t.network.listeners[port]; exists {
+		return nil, errors.New("memory: port " + strconv.Itoa(port) + " is already in use")
+	}
+	listener := &memoryListener{
+		network:  t.network,
+		port:     port,
+		incoming: make(chan net.Conn),
+		closed:   make(chan struct{}),
+	}
+	t.network.listeners[port] = listener
+	t.port = port
+	return listener, nil
+}
+
+// Dial dials an address of the form host:port on the memory network. The
+// host is ignored.
+func (t *Memory) Dial(address string) (net.Conn, error) {
+	_, rawPort, err := net.SplitHostPort(address)
+	if err != nil {
+		return nil, err
+	}
+	port, err := strconv.Atoi(rawPort)
+	if err != nil {
+		return nil, err
+	}
+	return t.network.dial(t.port, port)
+}
+
+type memoryAddr int
+
+func (a memoryAddr) Network() string {
+	return "memory"
+}
+
+func (a memoryAddr) String() string {
+	return net.JoinHostPort("127.0.0.1", strconv.Itoa(int(a)))
+}
+
+type memoryListener struct {
+	network   *MemoryNetwork
+	port      int
+	incoming  chan net.Conn
+	closed    chan struct{}
+	closeOnce sync.Once
+}
+
+func (l *memoryListener) Accept() (net.Conn, error) {
+	select {
+	case conn := <-l.incoming:
+		return conn, nil
+	case <-l.closed:
+		return nil, errMemoryClosed
+	}
+}
+
+func (l *memoryListener) Close() error {
+	l.closeOnce.Do(func() {
+		close(l.closed)
+		l.network.mutex.Lock()
+		if l.network.listeners[l.port] == l {
+			delete(l.network.listeners, l.port)
+		}
+		l.network.mutex.Unlock()
+	})
+	return nil
+}
+
+func (l *memoryListener) Addr() net.Addr {
+	return memoryAddr(l.port)
+}
+
+// delivery is a write waiting for its latency to pass.
+type delivery struct {
+	data []byte
+	at   time.Time
+}
+
+// memoryConn is one end of a pipe. Writes are queued and delivered in order
+// by a goroutine once the network latency has passed, so like a TCP socket
+// a write doesn't wait for the peer to read it.
+type memoryConn struct {
+	net.Conn
+
+	network    *MemoryNetwork
+	localPort  int
+	remotePort int
+
+	mutex   sync.Mutex
+	cond    *sync.Cond
+	pending []delivery
+	closed  bool
+}
+
+func newMemoryConn(network *MemoryNetwork, pipe net.Conn, localPort, remotePort int) *memoryConn {
+	c := &memoryConn{
+		Conn:       pipe,
+		network:    network,
+		localPort:  localPort,
+		remotePort: remotePort,
+	}
+	c.cond = sync.NewCond(&c.mutex)
+	go c.deliver()
+	return c
+}
+
+func (c *memoryConn) Write(p []byte) (int, error) {
+	at, err := c.network.send(c.localPort, c.remotePort)
+	if err != nil {
+		c.Close()
+		return 0, err
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if c.closed {
+		return 0, errMemoryClosed
+	}
+	c.pending = append(c.pending, delivery{data: append([]byte{}, p...), at: at})
+	c.cond.Signal()
+	return len(p), nil
+}
+
+// deliver writes the queued writes to the pipe until the connection closes.
+func (c *memoryConn) deliver() {
+	for {
+		c.mutex.Lock()
+		for len(c.pending) == 0 && !c.closed {
+			c.cond.Wait()
+		}
+		if c.closed {
+			c.mutex.Unlock()
+			return
+		}
+		next := c.pending[0]
+		c.pending = c.pending[1:]
+		c.mutex.Unlock()
+
+		time.Sleep(time.Until(next.at))
+		if _, err := c.Conn.Write(next.data); err != nil {
+			c.Close()
+			return
+		}
+	}
+}
+
+func (c *memoryConn) Close() error {
+	c.mutex.Lock()
+	if c.closed {
+		c.mutex.Unlock()
+		return nil
+	}
+	c.closed = true
+	c.pending = nil
+	c.cond.Signal()
+	c.mutex.Unlock()
+
+	c.network.mutex.Lock()
+	delete(c.network.conns, c)
+	c.network.mutex.Unlock()
+	return c.Conn.Close()
+}
+
+func (c *memoryConn) LocalAddr() net.Addr {
+	return memoryAddr(c.localPort)
+}
+
+func (c *memoryConn) RemoteAddr() net.Addr {
+	return memoryAddr(c.remotePort)
+}
@@ -0,0 +1,102 @@
+package transport
+
+import (
+	"io"
+	"net"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// connect dials the transport listening on port from the one listening on
+// port+1000 and returns both ends.
+func connect(t *testing.T, network *MemoryNetwork, port int) (net.Conn, net.Conn) {
+	listener, err := network.Transport().Listen(port)
+	require.Nil(t, err)
+	dialer := network.Transport()
+	_, err = dialer.Listen(port + 1000)
+	require.Nil(t, err)
+
+	accepted := make(chan net.Conn)
+	go func() {
+		conn, err := listener.Accept()
+		assert.Nil(t, err)
+		accepted <- conn
+	}()
+	conn, err := dialer.Dial("127.0.0.1:" + strconv.Itoa(port))
+	require.Nil(t, err)
+	return conn, <-accepted
+}
+
+func TestMemoryDial(t *testing.T) {
+	network := NewMemoryNetwork(1)
+	local, remote := connect(t, network, 3000)
+	assert.Equal(t, "127.0.0.1:4000", local.LocalAddr().String())
+	assert.Equal(t, "127.0.0.1:3000", local.RemoteAddr().String())
+
+	_, err := local.Write([]byte("hello"))
+	require.Nil(t, err)
+	_, err = local.Write([]byte(" world"))
+	require.Nil(t, err)
+	received := make([]byte, 11)
+	_, err = io.ReadFull(remote, received)
+	require.Nil(t, err)
+	assert.Equal(t, "hello world", string(received))
+
+	local.Close()
+	_, err = remote.Read(received)
+	assert.Error(t, err)
+
+	_, err = network.Transport().Dial("127.0.0.1:5000")
+	assert.Equal(t, errMemoryRefused, err)
+	_, err = network.Transport().Listen(3000)
+	assert.Error(t, err, "port is in use")
+}
+
+func TestMemoryLatency(t *testing.T) {
+	network := NewMemoryNetwork(1)
+	network.SetLatency(50 * time.Millisecond)
+	local, remote := connect(t, network, 3000)
+
+	start := time.Now()
+	_, err := local.Write([]byte("hello"))
+	require.Nil(t, err)
+	assert.True(t, time.Since(start) < 50*time.Millisecond, "writes shouldn't wait for delivery")
+	_, err = io.ReadFull(remote, make([]byte, 5))
+	require.Nil(t, err)
+	assert.True(t, time.Since(start) >= 50*time.Millisecond)
+}
+
+func TestMemoryLoss(t *testing.T) {
+	network := NewMemoryNetwork(1)
+	network.SetLossRate(1)
+	local, remote := connect(t, network, 3000)
+
+	_, err := local.Write([]byte("hello"))
+	assert.Equal(t, errMemoryLost, err)
+	_, err = remote.Read(make([]byte, 5))
+	assert.Error(t, err, "a lost write closes the connection")
+}
+
+func TestMemoryPartition(t *testing.T) {
+	network := NewMemoryNetwork(1)
+	local, remote := connect(t, network, 3000)
+	listener, err := network.Transport().Listen(3001)
+	require.Nil(t, err)
+
+	network.Partition([]int{3000, 3001})
+	_, err = remote.Read(make([]byte, 5))
+	assert.Error(t, err, "connections across the partition are closed")
+	_, err = local.Write([]byte("hello"))
+	assert.Error(t, err)
+	_, err = network.Transport().Dial("127.0.0.1:3001")
+	assert.Equal(t, errMemoryPartitioned, err)
+
+	network.Heal()
+	go listener.Accept()
+	_, err = network.Transport().Dial("127.0.0.1:3001")
+	assert.Nil(t, err)
+}
@@ -60,3 +60,16 @@ func (c *Cache) Get(key string, init func() (interface{}, error)) (interface{},
 	c.mutex.Unlock()
 	return item.value, nil
 }
+
+// Peek returns the cached value for a key without initializing it or marking
+// it as used. The second returning parameter is false should it not exist.
+func (c *Cache) Peek(key string) (interface{}, bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	item, exists := c.items[key]
+	if !exists {
+		return nil, false
+	}
+	return item.value, true
+}
@@ -85,3 +85,24 @@ func TestUnusedEntryDeleting(t *testing.T) {
 		t.Fatalf("deleting error")
 	}
 }
+
+func TestPeek(t *testing.T) {
+	t.Parallel()
+
+	cache := NewCache(2)
+	if _, exists := cache.Peek("mykey"); exists {
+		t.Fatal("peeking created the entry")
+	}
+	cache.Get("mykey", func() (interface{}, error) { return "mydata", nil })
+	cache.Get("other", emptyFunc)
+	data, exists := cache.Peek("mykey")
+	if !exists || data != "mydata" {
+		t.Fatalf("peeking error, got : %v/%v", data, exists)
+	}
+
+	// Peeking doesn't mark the entry as used, so it is evicted first.
+	cache.Get("new", emptyFunc)
+	if _, exists := cache.Peek("mykey"); exists {
+		t.Fatal("peeked entry wasn't evicted")
+	}
+}
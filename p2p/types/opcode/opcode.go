@@ -16,6 +16,8 @@ func init() {
 		opcode Opcode
 	}{
 		{&protobuf.Bytes{}, BytesCode},
+		{&protobuf.Chunk{}, ChunkCode},
+		{&protobuf.ChunkAck{}, ChunkAckCode},
 		{&protobuf.Ping{}, PingCode},
 		{&protobuf.Pong{}, PongCode},
 		{&protobuf.LookupNodeRequest{}, LookupNodeRequestCode},
@@ -34,6 +36,8 @@ type Opcode uint32
 const (
 	UnregisteredCode       Opcode = 0x00000 // 0
 	BytesCode              Opcode = 0x00001 // 1
+	ChunkCode              Opcode = 0x00002 // 2
+	ChunkAckCode           Opcode = 0x00003 // 3
 	PingCode               Opcode = 0x0000a // 10
 	PongCode               Opcode = 0x0000b // 11
 	LookupNodeRequestCode  Opcode = 0x0000c // 12
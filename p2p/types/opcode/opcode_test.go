@@ -43,6 +43,8 @@ func TestGetMessageType(t *testing.T) {
 		opcode Opcode
 	}{
 		{&pb.Bytes{}, BytesCode},
+		{&pb.Chunk{}, ChunkCode},
+		{&pb.ChunkAck{}, ChunkAckCode},
 		{&pb.Ping{}, PingCode},
 		{&pb.Pong{}, PongCode},
 		{&pb.LookupNodeRequest{}, LookupNodeRequestCode},
@@ -73,6 +75,8 @@ func TestGetOpcode(t *testing.T) {
 		opcode Opcode
 	}{
 		{&pb.Bytes{}, BytesCode},
+		{&pb.Chunk{}, ChunkCode},
+		{&pb.ChunkAck{}, ChunkAckCode},
 		{&pb.Ping{}, PingCode},
 		{&pb.Pong{}, PongCode},
 		{&pb.LookupNodeRequest{}, LookupNodeRequestCode},
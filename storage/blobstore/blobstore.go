@@ -0,0 +1,32 @@
+// Package blobstore stores the blobs of chain and state backups under
+// slash-separated keys, e.g. "3/blocks/<hash>" or "3/statedb/<manifest>/<file>".
+package blobstore
+
+import (
+	"errors"
+	"net/url"
+)
+
+// ErrNotFound is returned when no blob is stored under a key
+var ErrNotFound = errors.New("blob not found")
+
+// BlobStore stores blobs by key
+type BlobStore interface {
+	// Put stores data under key, replacing any blob stored under it. Tags
+	// describe the blob; stores that can't keep them ignore them.
+	Put(key string, data []byte, tags map[string]string) error
+	// Get returns the blob stored under key, or ErrNotFound
+	Get(key string) ([]byte, error)
+	// List returns the keys starting with prefix in lexical order
+	List(prefix string) ([]string, error)
+	// Exists reports whether a blob is stored under key
+	Exists(key string) (bool, error)
+}
+
+func encodeTags(tags map[string]string) string {
+	values := url.Values{}
+	for k, v := range tags {
+		values.Set(k, v)
+	}
+	return values.Encode()
+}
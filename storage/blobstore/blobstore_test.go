@@ -0,0 +1,72 @@
+package blobstore
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testBlobStore(t *testing.T, store BlobStore) {
+	_, err := store.Get("1/blocks/a")
+	assert.Equal(t, ErrNotFound, err)
+	ok, err := store.Exists("1/blocks/a")
+	require.Nil(t, err)
+	assert.False(t, ok)
+
+	require.Nil(t, store.Put("1/blocks/a", []byte("a"), map[string]string{"height": "1"}))
+	require.Nil(t, store.Put("1/statedb/MANIFEST-1/000001.log", []byte("log"), nil))
+	require.Nil(t, store.Put("10/blocks/b", []byte("b"), nil))
+	require.Nil(t, store.Put("1/blocks/a", []byte("a2"), nil))
+
+	data, err := store.Get("1/blocks/a")
+	require.Nil(t, err)
+	assert.Equal(t, []byte("a2"), data)
+	ok, err = store.Exists("1/blocks/a")
+	require.Nil(t, err)
+	assert.True(t, ok)
+	ok, err = store.Exists("1/blocks")
+	require.Nil(t, err)
+	assert.False(t, ok, "prefixes of keys are not blobs")
+
+	keys, err := store.List("1/")
+	require.Nil(t, err)
+	assert.Equal(t, []string{"1/blocks/a", "1/statedb/MANIFEST-1/000001.log"}, keys)
+	keys, err = store.List("")
+	require.Nil(t, err)
+	assert.Equal(t, 3, len(keys))
+	keys, err = store.List("2/")
+	require.Nil(t, err)
+	assert.Empty(t, keys)
+}
+
+func TestMemStore(t *testing.T) {
+	store := NewMemStore()
+	testBlobStore(t, store)
+
+	require.Nil(t, store.Put("1/blocks/c", []byte("c"), map[string]string{"height": "1"}))
+	assert.Equal(t, map[string]string{"height": "1"}, store.Tags("1/blocks/c"))
+}
+
+func TestLocalStore(t *testing.T) {
+	dir, err := ioutil.TempDir(os.TempDir(), "blobstore_test_")
+	require.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	store, err := NewLocalStore(dir)
+	require.Nil(t, err)
+	testBlobStore(t, store)
+
+	for _, key := range []string{"../escape", "1/../../escape", ""} {
+		assert.Error(t, store.Put(key, []byte("x"), nil), key)
+		_, err := store.Get(key)
+		assert.Error(t, err, key)
+	}
+}
+
+func TestEncodeTags(t *testing.T) {
+	tags := map[string]string{"height": "3", "blockhash": "AB12", "timestamp": "1500000000"}
+	assert.Equal(t, "blockhash=AB12&height=3&timestamp=1500000000", encodeTags(tags))
+}
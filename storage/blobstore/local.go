@@ -0,0 +1,124 @@
+package blobstore
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// tmpPrefix starts the names of files being written
+const tmpPrefix = ".tmp-"
+
+// LocalStore stores blobs as files under a directory, e.g. an NFS mount.
+// Keys map to paths relative to the directory.
+type LocalStore struct {
+	dir string
+}
+
+// NewLocalStore creates a LocalStore storing blobs under dir, creating dir if needed
+func NewLocalStore(dir string) (*LocalStore, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create blob dir %v: %v", dir, err)
+	}
+	return &LocalStore{dir: dir}, nil
+}
+
+func (s *LocalStore) path(key string) (string, error) {
+	p := filepath.Join(s.dir, filepath.FromSlash(key))
+	rel, err := filepath.Rel(s.dir, p)
+	if err != nil || rel == "." || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("invalid blob key: %q", key)
+	}
+	return p, nil
+}
+
+// Put writes data to the file of key. The file is written under a temporary
+// name first so readers never see a partial blob.
+func (s *LocalStore) Put(key string, data []byte, tags map[string]string) error {
+	p, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+		return fmt.Errorf("failed to create dir of %v: %v", key, err)
+	}
+	tmp, err := ioutil.TempFile(filepath.Dir(p), tmpPrefix)
+	if err != nil {
+		return fmt.Errorf("failed to create file of %v: %v", key, err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write %v: %v", key, err)
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to sync %v: %v", key, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to close %v: %v", key, err)
+	}
+	if err := os.Rename(tmp.Name(), p); err != nil {
+		return fmt.Errorf("failed to write %v: %v", key, err)
+	}
+	return nil
+}
+
+// Get reads the file of key
+func (s *LocalStore) Get(key string) ([]byte, error) {
+	p, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+	data, err := ioutil.ReadFile(p)
+	if os.IsNotExist(err) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %v: %v", key, err)
+	}
+	return data, nil
+}
+
+// List walks the directory for the keys starting with prefix
+func (s *LocalStore) List(prefix string) ([]string, error) {
+	var keys []string
+	err := filepath.Walk(s.dir, func(p string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() || strings.HasPrefix(info.Name(), tmpPrefix) {
+			return nil
+		}
+		rel, err := filepath.Rel(s.dir, p)
+		if err != nil {
+			return err
+		}
+		if key := filepath.ToSlash(rel); strings.HasPrefix(key, prefix) {
+			keys = append(keys, key)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list blobs (prefix=%v): %v", prefix, err)
+	}
+	return keys, nil
+}
+
+// Exists reports whether the file of key exists
+func (s *LocalStore) Exists(key string) (bool, error) {
+	p, err := s.path(key)
+	if err != nil {
+		return false, err
+	}
+	info, err := os.Stat(p)
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to stat %v: %v", key, err)
+	}
+	return !info.IsDir(), nil
+}
@@ -0,0 +1,71 @@
+package blobstore
+
+import (
+	"sort"
+	"strings"
+	"sync"
+)
+
+// MemStore keeps blobs in memory, for tests
+type MemStore struct {
+	mu    sync.RWMutex
+	blobs map[string][]byte
+	tags  map[string]map[string]string
+}
+
+// NewMemStore creates an empty MemStore
+func NewMemStore() *MemStore {
+	return &MemStore{
+		blobs: make(map[string][]byte),
+		tags:  make(map[string]map[string]string),
+	}
+}
+
+// Put stores a copy of data under key
+func (s *MemStore) Put(key string, data []byte, tags map[string]string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.blobs[key] = append([]byte(nil), data...)
+	s.tags[key] = tags
+	return nil
+}
+
+// Get returns a copy of the blob under key
+func (s *MemStore) Get(key string) ([]byte, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	data, ok := s.blobs[key]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return append([]byte(nil), data...), nil
+}
+
+// Tags returns the tags the blob under key was put with
+func (s *MemStore) Tags(key string) map[string]string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.tags[key]
+}
+
+// List returns the sorted keys starting with prefix
+func (s *MemStore) List(prefix string) ([]string, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	var keys []string
+	for key := range s.blobs {
+		if strings.HasPrefix(key, prefix) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys, nil
+}
+
+// Exists reports whether a blob is stored under key
+func (s *MemStore) Exists(key string) (bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	_, ok := s.blobs[key]
+	return ok, nil
+}
@@ -0,0 +1,97 @@
+package blobstore
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3manager"
+)
+
+// S3Store stores blobs as server side encrypted objects of an S3 bucket
+type S3Store struct {
+	bucket   string
+	svc      *s3.S3
+	uploader *s3manager.Uploader
+}
+
+// NewS3Store creates a S3Store storing blobs in bucket
+func NewS3Store(sess *session.Session, bucket string) *S3Store {
+	return &S3Store{
+		bucket:   bucket,
+		svc:      s3.New(sess),
+		uploader: s3manager.NewUploader(sess),
+	}
+}
+
+// Put uploads data to key, tagging the object with tags
+func (s *S3Store) Put(key string, data []byte, tags map[string]string) error {
+	input := &s3manager.UploadInput{
+		Bucket:               aws.String(s.bucket),
+		Key:                  aws.String(key),
+		Body:                 bytes.NewReader(data),
+		ServerSideEncryption: aws.String("AES256"),
+	}
+	if len(tags) > 0 {
+		input.Tagging = aws.String(encodeTags(tags))
+	}
+	if _, err := s.uploader.Upload(input); err != nil {
+		return fmt.Errorf("failed to upload %v to S3: %v", key, err)
+	}
+	return nil
+}
+
+// Get downloads the object at key
+func (s *S3Store) Get(key string) ([]byte, error) {
+	out, err := s.svc.GetObject(&s3.GetObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		if aerr, ok := err.(awserr.Error); ok && aerr.Code() == s3.ErrCodeNoSuchKey {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to download %v from S3: %v", key, err)
+	}
+	defer out.Body.Close()
+	data, err := ioutil.ReadAll(out.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %v from S3: %v", key, err)
+	}
+	return data, nil
+}
+
+// List returns the keys of the objects starting with prefix
+func (s *S3Store) List(prefix string) ([]string, error) {
+	var keys []string
+	err := s.svc.ListObjectsV2Pages(&s3.ListObjectsV2Input{
+		Bucket: aws.String(s.bucket),
+		Prefix: aws.String(prefix),
+	}, func(page *s3.ListObjectsV2Output, lastPage bool) bool {
+		for _, obj := range page.Contents {
+			keys = append(keys, aws.StringValue(obj.Key))
+		}
+		return true
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list S3 objects (prefix=%v): %v", prefix, err)
+	}
+	return keys, nil
+}
+
+// Exists reports whether an object is stored at key
+func (s *S3Store) Exists(key string) (bool, error) {
+	out, err := s.svc.ListObjectsV2(&s3.ListObjectsV2Input{
+		Bucket:  aws.String(s.bucket),
+		Prefix:  aws.String(key),
+		MaxKeys: aws.Int64(1),
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to list S3 objects (prefix=%v): %v", key, err)
+	}
+	return len(out.Contents) > 0 && aws.StringValue(out.Contents[0].Key) == key, nil
+}
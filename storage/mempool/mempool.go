@@ -1,29 +1,75 @@
 package mempool
 
 import (
+	"errors"
 	"fmt"
 	"log"
+	"sort"
 	"sync"
-	"sync/atomic"
 
+	"github.com/golang/protobuf/proto"
 	"github.com/herdius/herdius-core/accounts/account"
+	accProto "github.com/herdius/herdius-core/accounts/protobuf"
 	"github.com/herdius/herdius-core/hbi/protobuf"
 	"github.com/herdius/herdius-core/libs/common"
 	"github.com/herdius/herdius-core/tx"
 	"github.com/tendermint/go-amino"
 )
 
+const (
+	// DefaultMaxPending is the default number of txs ready to be included in a block the MemPool holds
+	DefaultMaxPending = 10000
+	// DefaultMaxQueued is the default number of txs waiting on a nonce gap the MemPool holds
+	DefaultMaxQueued = 5000
+)
+
+var (
+	// ErrTxExists is returned when the tx is already in the MemPool
+	ErrTxExists = errors.New("tx already exists in memory pool")
+	// ErrNonceExists is returned when a tx with the same sender and nonce is already in the MemPool
+	ErrNonceExists = errors.New("tx with the same nonce already exists in memory pool")
+	// ErrNonceTooLow is returned when the tx nonce was already used by the sender
+	ErrNonceTooLow = errors.New("tx nonce too low")
+	// ErrMemPoolFull is returned when the MemPool has no room left for the tx
+	ErrMemPoolFull = errors.New("memory pool is full")
+	// ErrTxDrained is returned when the tx was already handed out to be included in a block
+	ErrTxDrained = errors.New("tx is being included in a block")
+)
+
+var cdc = amino.NewCodec()
+
 // Service ...
 type Service interface {
-	AddTx(tx.Tx) int
+	AddTx(*protobuf.Tx, account.ServiceI) (int, int, error)
 	GetTxs() *tx.Txs
+	GetTx(id string) *protobuf.Tx
+	UpdateTx(id string, updated *protobuf.Tx) (*protobuf.Tx, error)
+	DeleteTx(id string) bool
 	RemoveTxs(int)
+	RemoveDrainedTxs([]int)
 }
 
-// MemPool ...
+var (
+	_ Service = (*MemPool)(nil)
+)
+
+// MemPool holds the txs waiting to be included in a block.
+// Pending txs are ready to be included, queued txs wait for the txs
+// filling the nonce gap to their sender's account nonce.
 type MemPool struct {
-	pending []mempoolTx
-	queue   []mempoolTx
+	mu         sync.Mutex
+	pending    []*mempoolTx
+	queue      map[string][]*mempoolTx // sender address to its queued txs sorted by nonce
+	numQueued  int
+	txs        map[string]*mempoolTx // tx ID index of pending and queued txs
+	nonces     map[string]uint64     // sender address to its highest pending nonce
+	drained    []string              // IDs of the txs last handed out by GetTxs
+	maxPending int
+	maxQueued  int
+	height     int64
+
+	// accountService provides the account nonces queued txs are promoted against
+	accountService account.ServiceI
 }
 
 // Only one instance of MemPool will be instantiated.
@@ -33,117 +79,273 @@ var once sync.Once
 // GetMemPool ..
 func GetMemPool() *MemPool {
 	once.Do(func() {
-		memPool = &MemPool{}
+		memPool = NewMemPool(DefaultMaxPending, DefaultMaxQueued)
 	})
 	return memPool
 }
 
+// NewMemPool creates a MemPool holding at most maxPending pending txs and maxQueued queued txs
+func NewMemPool(maxPending, maxQueued int) *MemPool {
+	return &MemPool{
+		queue:          make(map[string][]*mempoolTx),
+		txs:            make(map[string]*mempoolTx),
+		nonces:         make(map[string]uint64),
+		maxPending:     maxPending,
+		maxQueued:      maxQueued,
+		accountService: account.NewAccountService(),
+	}
+}
+
+// SetAccountService sets the account service queued txs are promoted against
+func (m *MemPool) SetAccountService(accSrv account.ServiceI) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.accountService = accSrv
+}
+
 // mempoolTx is a transaction that successfully ran
 type mempoolTx struct {
-	height int64 // height that this tx had been validated in
-	tx     *protobuf.Tx
+	id      string
+	height  int64 // order in which this tx was added to the MemPool
+	tx      *protobuf.Tx
+	txbz    []byte
+	queued  bool
+	drained bool // handed out by GetTxs and not yet removed
 }
 
 // Height returns the height for this transaction
 func (memTx *mempoolTx) Height() int64 {
-	return atomic.LoadInt64(&memTx.height)
+	return memTx.height
+}
+
+func (memTx *mempoolTx) sender() string {
+	return memTx.tx.GetSenderAddress()
+}
+
+func (memTx *mempoolTx) nonce() uint64 {
+	return memTx.tx.GetAsset().GetNonce()
+}
+
+// Len returns the number of pending and queued txs
+func (m *MemPool) Len() (int, int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return len(m.pending), m.numQueued
 }
 
 // AddTx adds the tx Transaction to the MemPool and returns the total
-// number of current Transactions within the MemPool
-func (m *MemPool) AddTx(tx *protobuf.Tx, accSrv account.ServiceI) (int, int) {
+// number of pending and queued Transactions within the MemPool
+func (m *MemPool) AddTx(tx *protobuf.Tx, accSrv account.ServiceI) (int, int, error) {
+	txbz, err := cdc.MarshalJSON(tx)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to marshal tx: %v", err)
+	}
+	// Lookup the account before locking, it is read from the state db
 	account, _ := accSrv.GetAccountByAddress(tx.GetSenderAddress())
-	mpSize := len(m.pending)
-	mt := mempoolTx{
-		tx:     tx,
-		height: int64(mpSize) + 1,
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	// Keep a copy so the caller can't change the tx behind the MemPool's back
+	mt := &mempoolTx{
+		id:   common.CreateTxID(txbz),
+		tx:   proto.Clone(tx).(*protobuf.Tx),
+		txbz: txbz,
+	}
+	if _, ok := m.txs[mt.id]; ok {
+		return len(m.pending), m.numQueued, ErrTxExists
 	}
 
-	if account != nil {
-		if tx.GetAsset().Nonce == account.Nonce+1 {
-			log.Println("First time tx Add to pending")
-			m.pending = append(m.pending, mt)
-			return len(m.pending), len(m.queue)
+	// Txs without nonce register new accounts and are always ready
+	if mt.nonce() == 0 {
+		if len(m.pending) >= m.maxPending {
+			return len(m.pending), m.numQueued, ErrMemPoolFull
 		}
-	}
-	if tx.GetAsset().Nonce == 0 {
 		log.Println("First time tx Add to pending")
+		m.index(mt)
+		m.pending = append(m.pending, mt)
+		return len(m.pending), m.numQueued, nil
+	}
+
+	next := m.nextNonce(mt.sender(), account)
+	if mt.nonce() < next {
+		return len(m.pending), m.numQueued, ErrNonceTooLow
+	}
+	if err := m.enqueue(mt); err != nil {
+		return len(m.pending), m.numQueued, err
+	}
+	m.index(mt)
+	m.promote(mt.sender(), next)
+	if evicted := m.evict(); evicted == mt {
+		return len(m.pending), m.numQueued, ErrMemPoolFull
+	}
+	return len(m.pending), m.numQueued, nil
+}
+
+// index adds mt to the tx ID index
+func (m *MemPool) index(mt *mempoolTx) {
+	m.height++
+	mt.height = m.height
+	m.txs[mt.id] = mt
+}
+
+// nextNonce returns the nonce the next pending tx of sender must have
+func (m *MemPool) nextNonce(sender string, account *accProto.Account) uint64 {
+	if nonce, ok := m.nonces[sender]; ok {
+		return nonce + 1
+	}
+	return account.GetNonce() + 1
+}
+
+// enqueue inserts mt in its sender's queue keeping the queue sorted by nonce
+func (m *MemPool) enqueue(mt *mempoolTx) error {
+	queue := m.queue[mt.sender()]
+	i := sort.Search(len(queue), func(i int) bool { return queue[i].nonce() >= mt.nonce() })
+	if i < len(queue) && queue[i].nonce() == mt.nonce() {
+		return ErrNonceExists
+	}
+	queue = append(queue, nil)
+	copy(queue[i+1:], queue[i:])
+	queue[i] = mt
+	m.queue[mt.sender()] = queue
+	mt.queued = true
+	m.numQueued++
+	return nil
+}
+
+// promote moves the gapless run of sender's queued txs starting at nonce next to pending
+func (m *MemPool) promote(sender string, next uint64) {
+	queue := m.queue[sender]
+	promoted := 0
+	for _, mt := range queue {
+		if mt.nonce() != next || len(m.pending) >= m.maxPending {
+			break
+		}
+		mt.queued = false
 		m.pending = append(m.pending, mt)
-		return len(m.pending), len(m.queue)
+		m.nonces[sender] = next
+		next++
+		promoted++
+	}
+	m.numQueued -= promoted
+	m.setQueue(sender, queue[promoted:])
+}
 
+func (m *MemPool) setQueue(sender string, queue []*mempoolTx) {
+	if len(queue) == 0 {
+		delete(m.queue, sender)
+		return
 	}
-	log.Println(" Add tx to queue")
-	m.queue = append(m.queue, mt)
-	return len(m.pending), len(m.queue)
+	m.queue[sender] = queue
 }
 
-func (m *MemPool) processQueue(accountService account.ServiceI) {
-	/*
-		1) get all queue tx
-		2) compare account nonce with tx nonce
-
-	*/
-	log.Printf("Processing queue and pending txs, Size of pending %d, Size of queue: %d", len(m.pending), len(m.queue))
-
-	for i, mTx := range m.queue {
-		account, _ := accountService.GetAccountByAddress(mTx.tx.GetSenderAddress())
-		if account != nil {
-			if account.Nonce+1 == mTx.tx.GetAsset().GetNonce() {
-				m.pending = append(m.pending, mTx)
-				m.queue = append(m.queue[:i], m.queue[i+1:]...)
+// evict drops the highest nonce queued tx of the sender with the most queued txs
+// while the queue is over capacity, and returns the last evicted tx
+func (m *MemPool) evict() *mempoolTx {
+	var evicted *mempoolTx
+	for m.numQueued > m.maxQueued {
+		var sender string
+		for s, queue := range m.queue {
+			if len(queue) > len(m.queue[sender]) || (len(queue) == len(m.queue[sender]) && s > sender) {
+				sender = s
 			}
 		}
+		queue := m.queue[sender]
+		evicted = queue[len(queue)-1]
+		log.Printf("Memory pool is full, evicting queued tx: %v", evicted.id)
+		delete(m.txs, evicted.id)
+		m.numQueued--
+		m.setQueue(sender, queue[:len(queue)-1])
+	}
+	return evicted
+}
+
+func (m *MemPool) processQueue(accountService account.ServiceI) {
+	m.mu.Lock()
+	log.Printf("Processing queue and pending txs, Size of pending %d, Size of queue: %d", len(m.pending), m.numQueued)
+	senders := make([]string, 0, len(m.queue))
+	for sender := range m.queue {
+		senders = append(senders, sender)
 	}
-	log.Printf("Finish Processing queue and pending txs, Size of pending %d, Size of queue: %d", len(m.pending), len(m.queue))
+	m.mu.Unlock()
+
+	for _, sender := range senders {
+		account, _ := accountService.GetAccountByAddress(sender)
 
+		m.mu.Lock()
+		next := m.nextNonce(sender, account)
+		queue := m.queue[sender]
+		// Drop the txs whose nonce was already used, they can never be applied
+		stale := 0
+		for stale < len(queue) && queue[stale].nonce() < next {
+			delete(m.txs, queue[stale].id)
+			stale++
+		}
+		m.numQueued -= stale
+		m.setQueue(sender, queue[stale:])
+		m.promote(sender, next)
+		m.mu.Unlock()
+	}
+
+	m.mu.Lock()
+	log.Printf("Finish Processing queue and pending txs, Size of pending %d, Size of queue: %d", len(m.pending), m.numQueued)
+	m.mu.Unlock()
 }
 
-// GetTxs gets all transactions from the MemPool
+// GetTxs gets all pending transactions from the MemPool.
+// A following RemoveTxs removes the returned transactions.
 func (m *MemPool) GetTxs() *tx.Txs {
-	accSrv := account.NewAccountService()
-	txs := &tx.Txs{}
+	m.mu.Lock()
+	accSrv := m.accountService
+	m.mu.Unlock()
 	m.processQueue(accSrv)
-	var cdc = amino.NewCodec()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.undrain(0)
+	txs := make(tx.Txs, 0, len(m.pending))
+	m.drained = make([]string, 0, len(m.pending))
 	for _, mt := range m.pending {
-		tx, _ := cdc.MarshalJSON(mt.tx)
-		*txs = append(*txs, tx)
-	}
-	return txs
-}
-
-// GetTx returns a Tx for the given ID or nil if the corresponding TX exists
-// Returns empty if Tx not found
-func (m *MemPool) GetTx(id string) (int, *protobuf.Tx, error) {
-	log.Println("Retrieving MemPool Tx's")
-	for i, txQ := range m.pending {
-		var cdc = amino.NewCodec()
-		// txStr := &protobuf.Tx{}
-		// err := cdc.UnmarshalJSON(txbz.tx, txStr)
-		txbz, err := cdc.MarshalJSON(txQ.tx)
-		if err != nil {
-			return 0, nil, fmt.Errorf("unable to unmarshal tx bytes to txStr: %v", err)
-		}
+		txbz := make([]byte, len(mt.txbz))
+		copy(txbz, mt.txbz)
+		txs = append(txs, txbz)
+		mt.drained = true
+		m.drained = append(m.drained, mt.id)
+	}
+	return &txs
+}
 
-		txbzID := common.CreateTxID(txbz)
-		if txbzID == id {
-			log.Println("Matching transaction found for Tx ID:", id)
-			return i, txQ.tx, nil
-		}
+// GetTx returns the Tx for the given ID
+// Returns nil if Tx not found
+func (m *MemPool) GetTx(id string) *protobuf.Tx {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	mt, ok := m.txs[id]
+	if !ok {
+		return nil
 	}
-	return 0, nil, nil
+	log.Println("Matching transaction found for Tx ID:", id)
+	return proto.Clone(mt.tx).(*protobuf.Tx)
 }
 
-// UpdateTx receives a Tx (newTx) and updates the corresponding Tx (origTx)
-// with all non-empty fields in newTx
-func (m *MemPool) UpdateTx(origI int, updated *protobuf.Tx) (*protobuf.Tx, error) {
+// UpdateTx receives a Tx (updated) and updates the Tx of the given ID
+// with all non-empty fields in updated. The updated Tx is indexed under its new ID.
+func (m *MemPool) UpdateTx(id string, updated *protobuf.Tx) (*protobuf.Tx, error) {
 	log.Println("Beginning update of transaction")
-	//origBz := m.queue[origI].tx
-	//var cdc = amino.NewCodec()
-	orig := &protobuf.Tx{}
-	// err := cdc.UnmarshalJSON(origBz, orig)
-	// if err != nil {
-	// 	return nil, fmt.Errorf("unable to unmarshal orig tx bytes to structured: %v", err)
-	// }
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	mt, ok := m.txs[id]
+	if !ok {
+		return nil, fmt.Errorf("tx (id: %v) does not exist in memory pool", id)
+	}
+	if mt.drained {
+		return nil, ErrTxDrained
+	}
+	orig := proto.Clone(mt.tx).(*protobuf.Tx)
+	if orig.Asset == nil {
+		orig.Asset = &protobuf.Asset{}
+	}
 	if updated.RecieverAddress != "" && updated.RecieverAddress != orig.RecieverAddress {
 		orig.RecieverAddress = updated.RecieverAddress
 		log.Println("updated receiver address")
@@ -176,40 +378,148 @@ func (m *MemPool) UpdateTx(origI int, updated *protobuf.Tx) (*protobuf.Tx, error
 		log.Println("updated type")
 		orig.Type = updated.Type
 	}
-	//updatedBz, err := cdc.MarshalJSON(orig)
-	// if err != nil {
-	// 	return nil, fmt.Errorf("could not marshal updated transaction back into memory pool: %v", err)
-	// }
 
-	m.pending[origI].tx = orig
-	return orig, nil
+	updatedBz, err := cdc.MarshalJSON(orig)
+	if err != nil {
+		return nil, fmt.Errorf("could not marshal updated transaction back into memory pool: %v", err)
+	}
+	updatedID := common.CreateTxID(updatedBz)
+	if existing, ok := m.txs[updatedID]; ok && existing != mt {
+		return nil, ErrTxExists
+	}
+	delete(m.txs, mt.id)
+	mt.id = updatedID
+	mt.tx = orig
+	mt.txbz = updatedBz
+	m.txs[mt.id] = mt
+	return proto.Clone(orig).(*protobuf.Tx), nil
 }
 
 // DeleteTx deletes a transaction currently in the MemPool by the transaction ID
 // Returns true if successfully cancelled, false if can't find or cancel the transaction
 func (m *MemPool) DeleteTx(id string) bool {
 	log.Println("Beginning attempted removal from memory pool of Tx w/ ID:", id)
-	for i, txStr := range m.pending {
-		var cdc = amino.NewCodec()
-
-		txbz, _ := cdc.MarshalJSON(txStr)
-		mTxID := common.CreateTxID(txbz)
-		if mTxID == id {
-			log.Printf("Matched Tx ID (%v), removing from memory memory pool", id)
-			m.pending = append(m.pending[:i], m.pending[i+1:]...)
-			return true
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	mt, ok := m.txs[id]
+	if !ok {
+		log.Printf("Unable to find Tx (id: %v) in memory pool", id)
+		return false
+	}
+	if mt.drained {
+		log.Printf("Unable to remove Tx (id: %v), it is being included in a block", id)
+		return false
+	}
+	log.Printf("Matched Tx ID (%v), removing from memory memory pool", id)
+	delete(m.txs, id)
+	if mt.queued {
+		queue := m.queue[mt.sender()]
+		for i, qt := range queue {
+			if qt == mt {
+				m.setQueue(mt.sender(), append(queue[:i:i], queue[i+1:]...))
+				break
+			}
+		}
+		m.numQueued--
+		return true
+	}
+
+	// The sender's later pending txs now have a nonce gap, move them back to the queue
+	pending := m.pending[:0]
+	for _, pt := range m.pending {
+		if pt == mt {
+			continue
+		}
+		if mt.nonce() > 0 && pt.sender() == mt.sender() && pt.nonce() > mt.nonce() {
+			m.enqueue(pt)
+			continue
 		}
+		pending = append(pending, pt)
 	}
-	log.Printf("Unable to find Tx (id: %v) in memory pool", id)
-	return false
+	for j := len(pending); j < len(m.pending); j++ {
+		m.pending[j] = nil
+	}
+	m.pending = pending
+	m.updateNonce(mt.sender())
+	return true
 }
 
-// RemoveTxs transactions from the MemPool
+// updateNonce recomputes the highest pending nonce of sender
+func (m *MemPool) updateNonce(sender string) {
+	delete(m.nonces, sender)
+	for _, mt := range m.pending {
+		if mt.sender() == sender && mt.nonce() > 0 {
+			m.nonces[sender] = mt.nonce()
+		}
+	}
+}
+
+// undrain releases the txs handed out by the last GetTxs from index i on
+func (m *MemPool) undrain(i int) {
+	for _, id := range m.drained[i:] {
+		if mt, ok := m.txs[id]; ok {
+			mt.drained = false
+		}
+	}
+	m.drained = nil
+}
+
+// RemoveTxs removes the first i transactions handed out by the last GetTxs from the MemPool
 func (m *MemPool) RemoveTxs(i int) {
 	log.Println("Removing tx from mempool", i)
-	if len(m.pending) < 1 {
-		m.pending = m.pending[len(m.pending):]
-		return
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if i > len(m.drained) {
+		i = len(m.drained)
+	}
+	m.removeDrained(m.drained[:i])
+}
+
+// RemoveDrainedTxs removes the transactions at indexes of the ones handed out
+// by the last GetTxs from the MemPool. The others are kept to be included in
+// a later block.
+func (m *MemPool) RemoveDrainedTxs(indexes []int) {
+	log.Println("Removing tx from mempool", len(indexes))
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	ids := make([]string, 0, len(indexes))
+	for _, i := range indexes {
+		if i >= 0 && i < len(m.drained) {
+			ids = append(ids, m.drained[i])
+		}
+	}
+	m.removeDrained(ids)
+}
+
+// removeDrained removes the drained txs of ids and releases the other drained txs
+func (m *MemPool) removeDrained(ids []string) {
+	removed := make(map[*mempoolTx]bool)
+	senders := make(map[string]bool)
+	for _, id := range ids {
+		mt, ok := m.txs[id]
+		if !ok {
+			continue
+		}
+		removed[mt] = true
+		senders[mt.sender()] = true
+		delete(m.txs, id)
+	}
+	m.undrain(0)
+
+	pending := m.pending[:0]
+	for _, mt := range m.pending {
+		if !removed[mt] {
+			pending = append(pending, mt)
+		}
+	}
+	for j := len(pending); j < len(m.pending); j++ {
+		m.pending[j] = nil
+	}
+	m.pending = pending
+	for sender := range senders {
+		m.updateNonce(sender)
 	}
-	m.pending = m.pending[i:]
 }
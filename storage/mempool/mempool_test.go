@@ -2,8 +2,12 @@ package mempool
 
 import (
 	"encoding/json"
+	"fmt"
 	"log"
+	"math/rand"
+	"sync"
 	"testing"
+	"time"
 
 	acc "github.com/herdius/herdius-core/accounts/protobuf"
 	"github.com/herdius/herdius-core/hbi/protobuf"
@@ -13,33 +17,29 @@ import (
 )
 
 func TestAddTxHighNonce(t *testing.T) {
-	m := GetMemPool()
-	m.queue = m.queue[:0]
-	m.pending = m.pending[:0]
+	m := NewMemPool(DefaultMaxPending, DefaultMaxQueued)
 
 	as := new(mockAccountService)
 	tx, _ := NewTx(uint64(2), "nonce1")
-	pending, queue := m.AddTx(&tx, as)
+	pending, queue, err := m.AddTx(&tx, as)
+	assert.NoError(t, err)
 	assert.Equal(t, 1, pending, "pending tx")
 	assert.Equal(t, 0, queue, "queue tx")
 }
 
 func TestAddTxGapNonce(t *testing.T) {
-	m := GetMemPool()
-	m.queue = m.queue[:0]
-	m.pending = m.pending[:0]
+	m := NewMemPool(DefaultMaxPending, DefaultMaxQueued)
 
 	as := new(mockAccountService)
 	tx, _ := NewTx(uint64(8), "nonce1")
-	pending, queue := m.AddTx(&tx, as)
+	pending, queue, err := m.AddTx(&tx, as)
+	assert.NoError(t, err)
 	assert.Equal(t, 0, pending, "pending tx")
 	assert.Equal(t, 1, queue, "queue tx")
 }
 
 func TestProcessQueueNoGap(t *testing.T) {
-	m := GetMemPool()
-	m.queue = m.queue[:0]
-	m.pending = m.pending[:0]
+	m := NewMemPool(DefaultMaxPending, DefaultMaxQueued)
 
 	as := new(mockAccountService)
 	tx, _ := NewTx(uint64(3), "nonce1")
@@ -49,13 +49,11 @@ func TestProcessQueueNoGap(t *testing.T) {
 
 	m.processQueue(as)
 	assert.Equal(t, 2, len(m.pending), "pending tx")
-	assert.Equal(t, 0, len(m.queue), "queue tx")
+	assert.Equal(t, 0, m.numQueued, "queue tx")
 }
 
 func TestProcessQueueGap(t *testing.T) {
-	m := GetMemPool()
-	m.queue = m.queue[:0]
-	m.pending = m.pending[:0]
+	m := NewMemPool(DefaultMaxPending, DefaultMaxQueued)
 
 	as := new(mockAccountService)
 	tx, _ := NewTx(uint64(9), "nonce1")
@@ -65,7 +63,7 @@ func TestProcessQueueGap(t *testing.T) {
 
 	m.processQueue(as)
 	assert.Equal(t, 0, len(m.pending), "pending tx")
-	assert.Equal(t, 2, len(m.queue), "queue tx")
+	assert.Equal(t, 2, m.numQueued, "queue tx")
 }
 func NewTx(i uint64, address string) (protobuf.Tx, string) {
 	asset := &protobuf.Asset{
@@ -95,9 +93,16 @@ func NewTx(i uint64, address string) (protobuf.Tx, string) {
 }
 
 type mockAccountService struct {
+	mu     sync.Mutex
+	nonces map[string]uint64
 }
 
 func (m *mockAccountService) GetAccountByAddress(address string) (*acc.Account, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if nonce, ok := m.nonces[address]; ok {
+		return &acc.Account{Nonce: nonce}, nil
+	}
 
 	switch address {
 	case "nonce1":
@@ -106,3 +111,292 @@ func (m *mockAccountService) GetAccountByAddress(address string) (*acc.Account,
 	return nil, nil
 
 }
+
+func (m *mockAccountService) nonce(address string) uint64 {
+	account, _ := m.GetAccountByAddress(address)
+	return account.GetNonce()
+}
+
+func (m *mockAccountService) setNonce(address string, nonce uint64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.nonces[address] = nonce
+}
+
+func mempoolTxID(t *testing.T, tx *protobuf.Tx) string {
+	txbz, err := cdc.MarshalJSON(tx)
+	assert.NoError(t, err)
+	return common.CreateTxID(txbz)
+}
+
+func TestAddTxDuplicates(t *testing.T) {
+	m := NewMemPool(DefaultMaxPending, DefaultMaxQueued)
+	as := new(mockAccountService)
+
+	tx, _ := NewTx(uint64(2), "nonce1")
+	_, _, err := m.AddTx(&tx, as)
+	assert.NoError(t, err)
+	_, _, err = m.AddTx(&tx, as)
+	assert.Equal(t, ErrTxExists, err)
+
+	// Nonce already used by a pending tx
+	tx, _ = NewTx(uint64(2), "nonce1")
+	tx.Message = "replacement"
+	_, _, err = m.AddTx(&tx, as)
+	assert.Equal(t, ErrNonceTooLow, err)
+
+	// Nonce already used by a queued tx
+	tx, _ = NewTx(uint64(5), "nonce1")
+	_, _, err = m.AddTx(&tx, as)
+	assert.NoError(t, err)
+	tx, _ = NewTx(uint64(5), "nonce1")
+	tx.Message = "replacement"
+	_, _, err = m.AddTx(&tx, as)
+	assert.Equal(t, ErrNonceExists, err)
+
+	// Nonce already used by the account
+	tx, _ = NewTx(uint64(1), "nonce1")
+	_, _, err = m.AddTx(&tx, as)
+	assert.Equal(t, ErrNonceTooLow, err)
+
+	pending, queue := m.Len()
+	assert.Equal(t, 1, pending, "pending tx")
+	assert.Equal(t, 1, queue, "queue tx")
+	assert.Equal(t, 2, len(m.txs))
+}
+
+func TestAddTxPromotesGaplessRun(t *testing.T) {
+	m := NewMemPool(DefaultMaxPending, DefaultMaxQueued)
+	as := new(mockAccountService)
+
+	for _, nonce := range []uint64{5, 3, 4, 7} {
+		tx, _ := NewTx(nonce, "nonce1")
+		_, _, err := m.AddTx(&tx, as)
+		assert.NoError(t, err)
+	}
+	pending, queue := m.Len()
+	assert.Equal(t, 0, pending, "pending tx")
+	assert.Equal(t, 4, queue, "queue tx")
+
+	tx, _ := NewTx(uint64(2), "nonce1")
+	pending, queue, err := m.AddTx(&tx, as)
+	assert.NoError(t, err)
+	assert.Equal(t, 4, pending, "pending tx")
+	assert.Equal(t, 1, queue, "queue tx")
+	for i, mt := range m.pending {
+		assert.Equal(t, uint64(i+2), mt.nonce())
+	}
+}
+
+func TestAddTxEvictsQueued(t *testing.T) {
+	m := NewMemPool(DefaultMaxPending, 2)
+	as := new(mockAccountService)
+
+	for _, nonce := range []uint64{5, 6} {
+		tx, _ := NewTx(nonce, "sender-a")
+		_, _, err := m.AddTx(&tx, as)
+		assert.NoError(t, err)
+	}
+	// The farthest queued tx of the sender with the most queued txs is evicted
+	txB, _ := NewTx(uint64(5), "sender-b")
+	_, queue, err := m.AddTx(&txB, as)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, queue, "queue tx")
+	evicted, _ := NewTx(uint64(6), "sender-a")
+	assert.Nil(t, m.GetTx(mempoolTxID(t, &evicted)))
+	assert.NotNil(t, m.GetTx(mempoolTxID(t, &txB)))
+
+	// The new tx itself may be the one evicted
+	tx, _ := NewTx(uint64(7), "sender-a")
+	_, queue, err = m.AddTx(&tx, as)
+	assert.Equal(t, ErrMemPoolFull, err)
+	assert.Equal(t, 2, queue, "queue tx")
+	assert.Nil(t, m.GetTx(mempoolTxID(t, &tx)))
+}
+
+func TestAddTxPendingFull(t *testing.T) {
+	m := NewMemPool(1, DefaultMaxQueued)
+	as := new(mockAccountService)
+
+	tx, _ := NewTx(uint64(2), "nonce1")
+	_, _, err := m.AddTx(&tx, as)
+	assert.NoError(t, err)
+
+	// Ready txs wait in the queue until pending has room
+	tx, _ = NewTx(uint64(3), "nonce1")
+	pending, queue, err := m.AddTx(&tx, as)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, pending, "pending tx")
+	assert.Equal(t, 1, queue, "queue tx")
+
+	tx, _ = NewTx(uint64(0), "new-account")
+	_, _, err = m.AddTx(&tx, as)
+	assert.Equal(t, ErrMemPoolFull, err)
+
+	m.SetAccountService(as)
+	assert.Equal(t, 1, len(*m.GetTxs()))
+	m.RemoveTxs(1)
+	// Account nonce hasn't moved on, the queued tx is promoted after the removed pending one
+	as.nonces = map[string]uint64{"nonce1": 2}
+	assert.Equal(t, 1, len(*m.GetTxs()))
+	pending, queue = m.Len()
+	assert.Equal(t, 1, pending, "pending tx")
+	assert.Equal(t, 0, queue, "queue tx")
+}
+
+func TestGetTxsRemoveTxs(t *testing.T) {
+	m := NewMemPool(DefaultMaxPending, DefaultMaxQueued)
+	as := new(mockAccountService)
+	m.SetAccountService(as)
+
+	for _, nonce := range []uint64{2, 3} {
+		tx, _ := NewTx(nonce, "nonce1")
+		m.AddTx(&tx, as)
+	}
+	txs := m.GetTxs()
+	assert.Equal(t, 2, len(*txs))
+
+	// Txs handed out for the block can't be changed until they are removed
+	first, _ := NewTx(uint64(2), "nonce1")
+	assert.False(t, m.DeleteTx(mempoolTxID(t, &first)))
+	_, err := m.UpdateTx(mempoolTxID(t, &first), &protobuf.Tx{Message: "updated"})
+	assert.Equal(t, ErrTxDrained, err)
+
+	// Txs added while the block is created stay in the MemPool
+	tx, _ := NewTx(uint64(4), "nonce1")
+	m.AddTx(&tx, as)
+
+	m.RemoveTxs(len(*txs))
+	pending, queue := m.Len()
+	assert.Equal(t, 1, pending, "pending tx")
+	assert.Equal(t, 0, queue, "queue tx")
+	assert.Equal(t, 1, len(m.txs))
+	assert.True(t, m.DeleteTx(mempoolTxID(t, &tx)))
+}
+
+func TestRemoveDrainedTxs(t *testing.T) {
+	m := NewMemPool(DefaultMaxPending, DefaultMaxQueued)
+	as := &mockAccountService{nonces: map[string]uint64{"nonce1": 1, "nonce2": 1, "nonce3": 1}}
+	m.SetAccountService(as)
+
+	for _, sender := range []string{"nonce1", "nonce2", "nonce3"} {
+		tx, _ := NewTx(uint64(2), sender)
+		m.AddTx(&tx, as)
+	}
+	txs := m.GetTxs()
+	assert.Equal(t, 3, len(*txs))
+
+	// The txs left out of the block are released to be included later
+	m.RemoveDrainedTxs([]int{0, 2, 5})
+	pending, queue := m.Len()
+	assert.Equal(t, 1, pending, "pending tx")
+	assert.Equal(t, 0, queue, "queue tx")
+	left, _ := NewTx(uint64(2), "nonce2")
+	assert.True(t, m.DeleteTx(mempoolTxID(t, &left)), "the released tx can be changed")
+}
+
+func TestDeleteTxRequeuesLaterNonces(t *testing.T) {
+	m := NewMemPool(DefaultMaxPending, DefaultMaxQueued)
+	as := new(mockAccountService)
+
+	for _, nonce := range []uint64{2, 3, 4} {
+		tx, _ := NewTx(nonce, "nonce1")
+		m.AddTx(&tx, as)
+	}
+	tx, _ := NewTx(uint64(3), "nonce1")
+	assert.True(t, m.DeleteTx(mempoolTxID(t, &tx)))
+	assert.False(t, m.DeleteTx(mempoolTxID(t, &tx)))
+	pending, queue := m.Len()
+	assert.Equal(t, 1, pending, "pending tx")
+	assert.Equal(t, 1, queue, "queue tx")
+
+	_, _, err := m.AddTx(&tx, as)
+	assert.NoError(t, err)
+	pending, queue = m.Len()
+	assert.Equal(t, 3, pending, "pending tx")
+	assert.Equal(t, 0, queue, "queue tx")
+}
+
+func TestUpdateTx(t *testing.T) {
+	m := NewMemPool(DefaultMaxPending, DefaultMaxQueued)
+	as := new(mockAccountService)
+
+	tx, _ := NewTx(uint64(2), "nonce1")
+	m.AddTx(&tx, as)
+	id := mempoolTxID(t, &tx)
+
+	updated, err := m.UpdateTx(id, &protobuf.Tx{Message: "updated", Asset: &protobuf.Asset{Value: 5}})
+	assert.NoError(t, err)
+	assert.Equal(t, "updated", updated.Message)
+	assert.Equal(t, uint64(5), updated.Asset.Value)
+	assert.Equal(t, "HER", updated.Asset.Symbol)
+
+	assert.Nil(t, m.GetTx(id))
+	assert.Equal(t, "updated", m.GetTx(mempoolTxID(t, updated)).Message)
+	// The caller's tx is left untouched
+	assert.Equal(t, "sending tokens", tx.Message)
+
+	_, err = m.UpdateTx(id, &protobuf.Tx{Message: "again"})
+	assert.Error(t, err)
+}
+
+// TestMemPoolConcurrent adds txs of several senders in random nonce order while
+// blocks are drained concurrently. Run with -race.
+func TestMemPoolConcurrent(t *testing.T) {
+	const (
+		numSenders = 8
+		numTxs     = 50
+	)
+	// Room for every queued tx, nothing gets evicted
+	m := NewMemPool(64, numSenders*numTxs)
+	as := &mockAccountService{nonces: make(map[string]uint64)}
+	m.SetAccountService(as)
+
+	var wg sync.WaitGroup
+	for s := 0; s < numSenders; s++ {
+		wg.Add(1)
+		go func(sender string) {
+			defer wg.Done()
+			for _, nonce := range rand.Perm(numTxs) {
+				tx, _ := NewTx(uint64(nonce+1), sender)
+				_, _, err := m.AddTx(&tx, as)
+				assert.NoError(t, err)
+				m.GetTx(mempoolTxID(t, &tx))
+				m.Len()
+			}
+		}(fmt.Sprintf("sender-%d", s))
+	}
+
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+
+	drained := 0
+	deadline := time.After(30 * time.Second)
+	for drained < numSenders*numTxs {
+		select {
+		case <-deadline:
+			t.Fatalf("drained %d of %d txs", drained, numSenders*numTxs)
+		default:
+		}
+		txs := m.GetTxs()
+		for _, txbz := range *txs {
+			tx := protobuf.Tx{}
+			assert.NoError(t, cdc.UnmarshalJSON(txbz, &tx))
+			// Each block continues the sender's nonces without a gap
+			assert.Equal(t, as.nonce(tx.SenderAddress)+1, tx.Asset.Nonce)
+			as.setNonce(tx.SenderAddress, tx.Asset.Nonce)
+		}
+		m.RemoveTxs(len(*txs))
+		drained += len(*txs)
+		time.Sleep(time.Millisecond)
+	}
+	<-done
+
+	pending, queue := m.Len()
+	assert.Equal(t, 0, pending, "pending tx")
+	assert.Equal(t, 0, queue, "queue tx")
+	assert.Equal(t, 0, len(m.txs))
+}
@@ -1,10 +1,13 @@
 package statedb
 
 import (
+	"bytes"
+	"fmt"
 	"log"
 	"sync"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/ethdb"
 	"github.com/ethereum/go-ethereum/trie"
 	cryptoAmino "github.com/herdius/herdius-core/crypto/encoding/amino"
@@ -31,9 +34,11 @@ type Trie interface {
 	TryUpdate(key, value []byte) error
 	TryDelete(key []byte) error
 	Commit(onleaf trie.LeafCallback) ([]byte, error)
+	Stage() ([]byte, error)
 	Hash() []byte //common.Hash
 	NodeIterator(startKey []byte) trie.NodeIterator
 	GetKey([]byte) []byte
+	Prove(key []byte) ([][]byte, error)
 }
 
 // StateDB ...
@@ -41,8 +46,12 @@ type Trie interface {
 type state struct {
 	trie *trie.Trie
 	db   *trie.Database
+	disk ethdb.Database // set for the loaded state db only
 }
 
+// lastRootKey is the key of the root of the state last written to the state db
+var lastRootKey = []byte("LastStateRoot")
+
 // GetState return global singleton state.
 func GetState(dir string) Trie {
 	once.Do(func() {
@@ -60,7 +69,7 @@ func GetState(dir string) Trie {
 		if err != nil {
 			log.Fatalf("Error Getting TrieDB %v", err)
 		}
-		singleton = &state{trie: t, db: triedb}
+		singleton = &state{trie: t, db: triedb, disk: ldb}
 	})
 	return singleton
 }
@@ -77,6 +86,17 @@ func NewTrie(hash common.Hash) (Trie, error) {
 	return state, nil
 }
 
+// NewMemTrie returns an empty Trie kept in memory only,
+// for state that is rebuilt from proofs and never persisted
+func NewMemTrie() (Trie, error) {
+	triedb := trie.NewDatabase(ethdb.NewMemDatabase())
+	t, err := trie.New(common.Hash{}, triedb)
+	if err != nil {
+		return nil, err
+	}
+	return &state{trie: t, db: triedb}, nil
+}
+
 var singleton *state
 
 func (s *state) GetTrie() *trie.Trie {
@@ -126,10 +146,70 @@ func (s *state) Commit(onleaf trie.LeafCallback) ([]byte, error) {
 		return nil, err
 	}
 	s.db.Commit(root, true)
+	if err := writeLastRoot(s.db, root.Bytes()); err != nil {
+		return nil, err
+	}
 
 	return root.Bytes(), nil
 }
 
+// Stage hashes the trie and keeps its nodes in the memory cache of the trie
+// database without writing them to disk. The staged root can be loaded with
+// NewTrie until it is written by Persist or dropped by Discard.
+func (s *state) Stage() ([]byte, error) {
+	root, err := s.trie.Commit(nil)
+	if err != nil {
+		return nil, err
+	}
+	s.db.Reference(root, common.Hash{})
+	return root.Bytes(), nil
+}
+
+// Persist writes the trie staged at root to the state db
+func Persist(root []byte) error {
+	if singleton == nil {
+		return fmt.Errorf("state db not loaded")
+	}
+	if err := singleton.db.Commit(common.BytesToHash(root), false); err != nil {
+		return fmt.Errorf("failed to write state %x: %v", root, err)
+	}
+	return writeLastRoot(singleton.db, root)
+}
+
+// writeLastRoot records root as the state last written to the loaded state
+// db, if triedb is its trie database
+func writeLastRoot(triedb *trie.Database, root []byte) error {
+	if singleton == nil || triedb != singleton.db {
+		return nil
+	}
+	if err := singleton.disk.Put(lastRootKey, root); err != nil {
+		return fmt.Errorf("failed to record state %x: %v", root, err)
+	}
+	return nil
+}
+
+// LastRoot returns the root of the state last written to the loaded state
+// db, nil if the state db doesn't record it
+func LastRoot() []byte {
+	if singleton == nil {
+		return nil
+	}
+	root, err := singleton.disk.Get(lastRootKey)
+	if err != nil {
+		return nil
+	}
+	return root
+}
+
+// Discard drops the nodes of the trie staged at root which aren't shared
+// with another staged or persisted trie
+func Discard(root []byte) {
+	if singleton == nil || len(root) == 0 {
+		return
+	}
+	singleton.db.Dereference(common.BytesToHash(root))
+}
+
 func (s *state) Hash() []byte {
 	t := s.trie
 	return t.Root()
@@ -142,6 +222,108 @@ func (s *state) GetKey([]byte) []byte {
 	return nil
 }
 
+// Prove returns the merkle proof nodes of key against the current trie root.
+// The proof holds the encoded nodes on the path to key and can be checked
+// with VerifyProof by anyone knowing only the root.
+func (s *state) Prove(key []byte) ([][]byte, error) {
+	proofDB := ethdb.NewMemDatabase()
+	if err := s.trie.Prove(key, 0, proofDB); err != nil {
+		return nil, err
+	}
+	proof := make([][]byte, 0, proofDB.Len())
+	for _, k := range proofDB.Keys() {
+		node, err := proofDB.Get(k)
+		if err != nil {
+			return nil, err
+		}
+		proof = append(proof, node)
+	}
+	return proof, nil
+}
+
+// VerifyProof checks the proof nodes of key against root and returns the value
+// stored at key. A nil value with no error proves key is absent from the trie.
+func VerifyProof(root, key []byte, proof [][]byte) ([]byte, error) {
+	proofDB := ethdb.NewMemDatabase()
+	for _, node := range proof {
+		if err := proofDB.Put(crypto.Keccak256(node), node); err != nil {
+			return nil, err
+		}
+	}
+	value, _, err := trie.VerifyProof(common.BytesToHash(root), key, proofDB)
+	if err != nil {
+		return nil, err
+	}
+	return value, nil
+}
+
+// VerifyStateDB checks that the state db in dir holds the whole trie at root.
+// The trie is rebuilt from its leaves so that tampered nodes are detected.
+func VerifyStateDB(dir string, root []byte) error {
+	ldb, err := loadLevelDB(dir)
+	if err != nil {
+		return fmt.Errorf("failed to open state db: %v", err)
+	}
+	defer ldb.Close()
+	return verifyTrie(trie.NewDatabase(ldb), root)
+}
+
+// HasState checks that the loaded state db holds the root node of the trie
+// at root. The nodes of a trie are written before the root which references
+// them, so that the root is only found once the whole trie is written. It
+// doesn't detect nodes lost since, VerifyState walks the whole trie for that.
+func HasState(root []byte) error {
+	if singleton == nil {
+		return fmt.Errorf("state db not loaded")
+	}
+	if common.BytesToHash(root) == (common.Hash{}) {
+		return nil
+	}
+	if _, err := trie.New(common.BytesToHash(root), singleton.db); err != nil {
+		return fmt.Errorf("state root %x not found: %v", root, err)
+	}
+	return nil
+}
+
+// VerifyState checks that the loaded state db holds the whole trie at root
+func VerifyState(root []byte) error {
+	if singleton == nil {
+		return fmt.Errorf("state db not loaded")
+	}
+	return verifyTrie(singleton.db, root)
+}
+
+func verifyTrie(triedb *trie.Database, root []byte) error {
+	// The zero root stands for the empty trie
+	if common.BytesToHash(root) == (common.Hash{}) {
+		return nil
+	}
+	t, err := trie.New(common.BytesToHash(root), triedb)
+	if err != nil {
+		return fmt.Errorf("state root %x not found: %v", root, err)
+	}
+	rebuilt, err := NewMemTrie()
+	if err != nil {
+		return err
+	}
+	it := t.NodeIterator(nil)
+	for it.Next(true) {
+		if !it.Leaf() {
+			continue
+		}
+		if err := rebuilt.TryUpdate(it.LeafKey(), it.LeafBlob()); err != nil {
+			return fmt.Errorf("failed to rebuild state trie: %v", err)
+		}
+	}
+	if err := it.Error(); err != nil {
+		return fmt.Errorf("state trie at %x is incomplete: %v", root, err)
+	}
+	if !bytes.Equal(rebuilt.Hash(), root) {
+		return fmt.Errorf("state trie hashes to %x, not %x", rebuilt.Hash(), root)
+	}
+	return nil
+}
+
 func loadLevelDB(dir string) (*ethdb.LDBDatabase, error) {
 	return ethdb.NewLDBDatabase(dir, 0, 0)
 }
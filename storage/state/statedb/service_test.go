@@ -1,6 +1,7 @@
 package statedb
 
 import (
+	"bytes"
 	"fmt"
 	"io/ioutil"
 	"os"
@@ -226,3 +227,146 @@ func createSecp256k1Account(nonce int) Account {
 
 	return account
 }
+
+func TestProveAndVerifyProof(t *testing.T) {
+	dir, err := ioutil.TempDir("", "trie-singleton")
+	assert.NoError(t, err, fmt.Sprintf("can't create temporary directory: %v", err))
+	defer os.RemoveAll(dir)
+
+	GetState(dir)
+	trie, err := NewTrie(common.Hash{})
+	assert.NoError(t, err)
+	for i := 0; i < 10; i++ {
+		err = trie.TryUpdate([]byte(fmt.Sprintf("key-%d", i)), []byte(fmt.Sprintf("value-%d", i)))
+		assert.NoError(t, err, fmt.Sprintf("can't add (key, value) to Trie: %v", err))
+	}
+	root := trie.Hash()
+
+	proof, err := trie.Prove([]byte("key-3"))
+	assert.NoError(t, err)
+	value, err := VerifyProof(root, []byte("key-3"), proof)
+	assert.NoError(t, err)
+	assert.Equal(t, "value-3", string(value))
+
+	// Absence of a key is provable too
+	proof, err = trie.Prove([]byte("missing"))
+	assert.NoError(t, err)
+	value, err = VerifyProof(root, []byte("missing"), proof)
+	assert.NoError(t, err)
+	assert.Nil(t, value)
+
+	// A proof doesn't hold against a different root
+	err = trie.TryUpdate([]byte("key-3"), []byte("changed"))
+	assert.NoError(t, err)
+	proof, err = trie.Prove([]byte("key-3"))
+	assert.NoError(t, err)
+	_, err = VerifyProof(root, []byte("key-3"), proof)
+	assert.Error(t, err)
+}
+
+func TestVerifyStateDB(t *testing.T) {
+	dir, err := ioutil.TempDir(os.TempDir(), "ethdb_test_")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	ldb, err := ethdb.NewLDBDatabase(dir, 0, 0)
+	assert.NoError(t, err)
+	triedb := tt.NewDatabase(ldb)
+	tr, err := tt.New(common.Hash{}, triedb)
+	assert.NoError(t, err)
+	for i := 0; i < 50; i++ {
+		assert.NoError(t, tr.TryUpdate([]byte(fmt.Sprintf("account-%d", i)), []byte(fmt.Sprintf("balance-%d", i))))
+	}
+	root, err := tr.Commit(nil)
+	assert.NoError(t, err)
+	assert.NoError(t, triedb.Commit(root, true))
+
+	// Drop a node that isn't the root
+	var corrupted []byte
+	it := tr.NodeIterator(nil)
+	for it.Next(true) {
+		if it.Hash() != (common.Hash{}) && it.Hash() != root {
+			corrupted = it.Hash().Bytes()
+			break
+		}
+	}
+	ldb.Close()
+
+	assert.NoError(t, VerifyStateDB(dir, root.Bytes()))
+	assert.Error(t, VerifyStateDB(dir, common.BytesToHash([]byte("unknown root")).Bytes()))
+
+	ldb, err = ethdb.NewLDBDatabase(dir, 0, 0)
+	assert.NoError(t, err)
+	assert.NoError(t, ldb.Delete(corrupted))
+	ldb.Close()
+	assert.Error(t, VerifyStateDB(dir, root.Bytes()))
+}
+
+func TestStagePersistDiscard(t *testing.T) {
+	dir, err := ioutil.TempDir(os.TempDir(), "stage")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	GetState(dir)
+
+	staged, err := NewTrie(common.Hash{})
+	assert.NoError(t, err)
+	assert.NoError(t, staged.TryUpdate([]byte("account-1"), []byte("balance-1")))
+	root, err := staged.Stage()
+	assert.NoError(t, err)
+
+	// A staged trie can be loaded but isn't written yet
+	_, err = NewTrie(common.BytesToHash(root))
+	assert.NoError(t, err)
+	onDisk, _ := GetDB().DiskDB().Has(root)
+	assert.False(t, onDisk)
+
+	assert.NoError(t, Persist(root))
+	onDisk, _ = GetDB().DiskDB().Has(root)
+	assert.True(t, onDisk)
+	assert.NoError(t, VerifyState(root))
+
+	// Discarding a staged trie drops its own nodes only
+	next, err := NewTrie(common.BytesToHash(root))
+	assert.NoError(t, err)
+	assert.NoError(t, next.TryUpdate([]byte("account-2"), []byte("balance-2")))
+	discarded, err := next.Stage()
+	assert.NoError(t, err)
+	_, err = NewTrie(common.BytesToHash(discarded))
+	assert.NoError(t, err)
+	Discard(discarded)
+	_, err = NewTrie(common.BytesToHash(discarded))
+	assert.Error(t, err)
+	assert.Error(t, VerifyState(discarded))
+	assert.NoError(t, VerifyState(root))
+}
+
+func TestHasStateAndLastRoot(t *testing.T) {
+	dir, err := ioutil.TempDir(os.TempDir(), "hasstate")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	GetState(dir)
+
+	staged, err := NewTrie(common.Hash{})
+	assert.NoError(t, err)
+	for i := 0; i < 50; i++ {
+		assert.NoError(t, staged.TryUpdate([]byte(fmt.Sprintf("account-%d", i)), []byte(fmt.Sprintf("balance-%d", i))))
+	}
+	root, err := staged.Stage()
+	assert.NoError(t, err)
+	assert.NoError(t, Persist(root))
+	assert.Equal(t, root, LastRoot())
+	assert.NoError(t, HasState(root))
+	assert.NoError(t, HasState(nil), "the zero root is the empty trie")
+	assert.Error(t, HasState(common.BytesToHash([]byte("unknown root")).Bytes()))
+
+	// Only the root is looked up, a lost node is found by VerifyState
+	it := staged.(*state).GetTrie().NodeIterator(nil)
+	for it.Next(true) {
+		if it.Hash() != (common.Hash{}) && !bytes.Equal(it.Hash().Bytes(), root) {
+			assert.NoError(t, singleton.disk.Delete(it.Hash().Bytes()))
+			break
+		}
+	}
+	assert.NoError(t, HasState(root))
+	assert.Error(t, VerifyState(root))
+}
@@ -0,0 +1,147 @@
+package service
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/herdius/herdius-core/blockchain"
+	"github.com/herdius/herdius-core/blockchain/protobuf"
+	pluginproto "github.com/herdius/herdius-core/hbi/protobuf"
+	"github.com/herdius/herdius-core/storage/state/statedb"
+	"github.com/herdius/herdius-core/transition"
+)
+
+// CheckState checks the state db holds the state of the last block of chain
+// and sets it as the state root of the supervisor. Only the root of the state
+// is looked up, unless the whole trie is verified with SetVerifyState. A
+// state missing from the state db, as left by a crash of an older node
+// between writing the state and the block, is rebuilt by replaying the blocks
+// since the last block whose state is whole. Each block is replayed by the
+// rules of its height, with the external balance updates recorded in it.
+// Blocks of older nodes don't record them, so their state may only be
+// restored from a backup.
+func (s *Supervisor) CheckState(chain blockchain.ServiceI) error {
+	lastBlock := chain.GetLastBlock()
+	if lastBlock == nil {
+		return fmt.Errorf("failed to load the last block")
+	}
+	checkState := statedb.HasState
+	if s.verifyState {
+		checkState = statedb.VerifyState
+	}
+	root := lastBlock.GetHeader().GetStateRoot()
+	err := checkState(root)
+	if err == nil {
+		// The state last written may be that of a block which failed to be
+		// stored, and is left unused
+		if last := statedb.LastRoot(); len(last) > 0 && !bytes.Equal(last, root) {
+			log.Printf("State %x was written last, not the state of last block %d", last, lastBlock.GetHeader().GetHeight())
+		}
+		s.SetStateRoot(root)
+		return nil
+	}
+	log.Printf("State of last block %d doesn't match the state db: %v", lastBlock.GetHeader().GetHeight(), err)
+
+	// Walk back to the last block whose state is whole
+	missing := []*protobuf.BaseBlock{lastBlock}
+	var base *protobuf.BaseBlock
+	for height := lastBlock.GetHeader().GetHeight() - 1; height >= 0; height-- {
+		bb, err := chain.GetBlockByHeight(height)
+		if err != nil {
+			return fmt.Errorf("failed to load block %d: %v", height, err)
+		}
+		if checkState(bb.GetHeader().GetStateRoot()) == nil {
+			base = bb
+			break
+		}
+		missing = append(missing, bb)
+	}
+	if base == nil {
+		return fmt.Errorf("no block has its state in the state db")
+	}
+
+	root = base.GetHeader().GetStateRoot()
+	for i := len(missing) - 1; i >= 0; i-- {
+		if root, err = s.replayBlock(root, missing[i]); err != nil {
+			return fmt.Errorf("state of block %d can't be rebuilt, it has to be restored from a backup: %v", missing[i].GetHeader().GetHeight(), err)
+		}
+		log.Printf("Rebuilt state of block %d", missing[i].GetHeader().GetHeight())
+	}
+	s.SetStateRoot(root)
+	return nil
+}
+
+// replayBlock applies the external balance updates and the txs of bb to the
+// state at stateRoot and writes the resulting state if it is the state of bb
+func (s *Supervisor) replayBlock(stateRoot []byte, bb *protobuf.BaseBlock) ([]byte, error) {
+	stateTrie, err := statedb.NewTrie(common.BytesToHash(stateRoot))
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve the state trie: %v", err)
+	}
+	// The external balance updates of the syncer were written before the txs
+	for _, update := range bb.GetAccountUpdates() {
+		if err := stateTrie.TryUpdate([]byte(update.GetAddress()), update.GetAccount()); err != nil {
+			return nil, fmt.Errorf("failed to write account %v: %v", update.GetAddress(), err)
+		}
+	}
+	txs, err := appliedTxs(bb)
+	if err != nil {
+		return nil, err
+	}
+	fees := uint64(0)
+	for _, tx := range txs {
+		result, _ := applyBlockTx(stateTrie, tx, bb.GetHeader().GetHeight(), fees)
+		if fees, err = transition.AddBalance(fees, result.Fee); err != nil {
+			return nil, fmt.Errorf("failed to add up fees: %v", err)
+		}
+	}
+	if err := s.creditFees(stateTrie, fees); err != nil {
+		return nil, fmt.Errorf("failed to credit fees: %v", err)
+	}
+
+	root, err := stateTrie.Stage()
+	if err != nil {
+		return nil, fmt.Errorf("failed to stage state: %v", err)
+	}
+	if !bytes.Equal(root, bb.GetHeader().GetStateRoot()) {
+		statedb.Discard(root)
+		return nil, fmt.Errorf("replayed state root %x isn't the block's %x", root, bb.GetHeader().GetStateRoot())
+	}
+	if err := statedb.Persist(root); err != nil {
+		statedb.Discard(root)
+		return nil, err
+	}
+	return root, nil
+}
+
+// appliedTxs returns the txs of bb in the order they were applied to the
+// state, which is the order of the receipts rather than of the child blocks
+func appliedTxs(bb *protobuf.BaseBlock) ([]*pluginproto.Tx, error) {
+	txs := blockchain.BlockTxs(bb)
+	if len(bb.GetReceipts()) == 0 {
+		return txs, nil
+	}
+	byID := make(map[string]*pluginproto.Tx, len(txs))
+	for _, tx := range txs {
+		byID[blockchain.TxIDWithoutStatus(tx)] = tx
+	}
+	ordered := make([]*pluginproto.Tx, 0, len(txs))
+	for _, receiptbz := range bb.GetReceipts() {
+		var receipt pluginproto.Receipt
+		if err := cdc.UnmarshalJSON(receiptbz, &receipt); err != nil {
+			return nil, fmt.Errorf("failed to decode receipt: %v", err)
+		}
+		// Txs which failed to decode have no id and didn't change the state
+		if len(receipt.TxId) == 0 {
+			continue
+		}
+		tx, ok := byID[receipt.TxId]
+		if !ok {
+			return nil, fmt.Errorf("tx %v of receipt not in block", receipt.TxId)
+		}
+		ordered = append(ordered, tx)
+	}
+	return ordered, nil
+}
@@ -0,0 +1,177 @@
+package service
+
+import (
+	b64 "encoding/base64"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethdb"
+	ethtrie "github.com/ethereum/go-ethereum/trie"
+	"github.com/herdius/herdius-core/blockchain/protobuf"
+	"github.com/herdius/herdius-core/crypto/secp256k1"
+	pluginproto "github.com/herdius/herdius-core/hbi/protobuf"
+	"github.com/herdius/herdius-core/storage/state/statedb"
+	"github.com/herdius/herdius-core/transition"
+	txbyte "github.com/herdius/herdius-core/tx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckState(t *testing.T) {
+	privKey := secp256k1.GenPrivKey()
+	sender := privKey.PubKey().GetAddress()
+	receiver := secp256k1.GenPrivKey().PubKey().GetAddress()
+	chain := newTestChain(t, 100, sender, receiver)
+
+	supsvc := &Supervisor{}
+	supsvc.SetWriteMutex()
+	supsvc.SetRewardAddress("HKFpxVGG7Zgo7P38kqe4cXbDkmn7v7a7Sb")
+	require.NoError(t, supsvc.CheckState(chain))
+	assert.Equal(t, chain.GetLastBlock().GetHeader().GetStateRoot(), supsvc.StateRoot())
+
+	// Blocks stored without their state, as if the node crashed before
+	// writing it
+	for nonce := uint64(1); nonce <= 2; nonce++ {
+		lastBlock := chain.GetLastBlock()
+		txs := txbyte.Txs{signedHERTx(t, privKey, receiver, 10, 1, nonce)}
+		baseBlock, err := supsvc.createSingularBlock(lastBlock, nil, txs, nil, lastBlock.GetHeader().GetStateRoot())
+		require.NoError(t, err)
+		require.NoError(t, chain.AddBaseBlock(baseBlock))
+	}
+	supsvc.discardStagedState()
+	lastRoot := chain.GetLastBlock().GetHeader().GetStateRoot()
+	require.Error(t, statedb.VerifyState(lastRoot))
+
+	supsvc = &Supervisor{}
+	supsvc.SetRewardAddress("HKFpxVGG7Zgo7P38kqe4cXbDkmn7v7a7Sb")
+	require.NoError(t, supsvc.CheckState(chain))
+	assert.Equal(t, lastRoot, supsvc.StateRoot())
+	require.NoError(t, statedb.VerifyState(lastRoot))
+	state, err := statedb.NewTrie(common.BytesToHash(lastRoot))
+	require.NoError(t, err)
+	assert.Equal(t, uint64(78), getAccount(t, state, sender).Balance)
+	assert.Equal(t, uint64(120), getAccount(t, state, receiver).Balance)
+	assert.Equal(t, lastRoot, statedb.LastRoot())
+
+	// A node lost from the state of the last block is only found when the
+	// whole trie is verified, which rebuilds it
+	lostNode(t, chain.blocks[1].GetHeader().GetStateRoot(), lastRoot)
+	require.NoError(t, supsvc.CheckState(chain))
+	assert.Error(t, statedb.VerifyState(lastRoot))
+	supsvc.SetVerifyState(true)
+	require.NoError(t, supsvc.CheckState(chain))
+	assert.Equal(t, lastRoot, supsvc.StateRoot())
+	require.NoError(t, statedb.VerifyState(lastRoot))
+
+	// A state that can't be rebuilt is reported
+	tampered := *chain.GetLastBlock().GetHeader()
+	tampered.StateRoot = common.BytesToHash([]byte("unknown root")).Bytes()
+	chain.blocks = append(chain.blocks, &protobuf.BaseBlock{Header: &tampered})
+	assert.Error(t, supsvc.CheckState(chain))
+}
+
+func TestCheckStateReplaysLegacyBlocks(t *testing.T) {
+	chainID, legacySignHeight := transition.ChainID(), transition.LegacySignHeight()
+	defer func() {
+		transition.SetChainID(chainID)
+		transition.SetLegacySignHeight(legacySignHeight)
+	}()
+	transition.SetChainID("herdius-test")
+	transition.SetLegacySignHeight(3)
+
+	privKey := secp256k1.GenPrivKey()
+	sender := privKey.PubKey().GetAddress()
+	receiver := secp256k1.GenPrivKey().PubKey().GetAddress()
+	chain := newTestChain(t, 100, sender, receiver)
+	extAccount := statedb.Account{
+		Address:      secp256k1.GenPrivKey().PubKey().GetAddress(),
+		Balance:      7,
+		Erc20Address: "0xd7a4d3f7c4b5b4d8d5a4b1e3c2a1f0e9d8c7b6a5",
+	}
+	newTestAccountStorage(t, extAccount)
+
+	supsvc := &Supervisor{}
+	supsvc.SetWriteMutex()
+	supsvc.SetRewardAddress("HKFpxVGG7Zgo7P38kqe4cXbDkmn7v7a7Sb")
+
+	// Blocks below the cutover holding legacy signed txs, the first one with
+	// the external balance update of the syncer, stored without their state
+	for nonce := uint64(1); nonce <= 2; nonce++ {
+		lastBlock := chain.GetLastBlock()
+		txs := txbyte.Txs{legacySignedHERTx(t, privKey, receiver, 10, 1, nonce)}
+		baseBlock, err := supsvc.createSingularBlock(lastBlock, nil, txs, nil, lastBlock.GetHeader().GetStateRoot())
+		require.NoError(t, err)
+		require.NoError(t, chain.AddBaseBlock(baseBlock))
+		clearBalanceUpdates(supsvc.balanceUpdates)
+	}
+	require.Len(t, chain.blocks[1].GetAccountUpdates(), 1)
+	assert.Empty(t, chain.blocks[2].GetAccountUpdates())
+	supsvc.discardStagedState()
+	lastRoot := chain.GetLastBlock().GetHeader().GetStateRoot()
+	require.Error(t, statedb.VerifyState(lastRoot))
+
+	// Without its external balance update the state of a block, as of an
+	// older node, can't be rebuilt
+	stripped := *chain.blocks[1]
+	stripped.AccountUpdates = nil
+	supsvc = &Supervisor{}
+	supsvc.SetRewardAddress("HKFpxVGG7Zgo7P38kqe4cXbDkmn7v7a7Sb")
+	assert.Error(t, supsvc.CheckState(&testChain{blocks: []*protobuf.BaseBlock{chain.blocks[0], &stripped}}))
+
+	require.NoError(t, supsvc.CheckState(chain))
+	assert.Equal(t, lastRoot, supsvc.StateRoot())
+	state, err := statedb.NewTrie(common.BytesToHash(lastRoot))
+	require.NoError(t, err)
+	assert.Equal(t, uint64(78), getAccount(t, state, sender).Balance)
+	assert.Equal(t, uint64(120), getAccount(t, state, receiver).Balance)
+	assert.Equal(t, uint64(7), getAccount(t, state, extAccount.Address).Balance)
+}
+
+// legacySignedHERTx returns a HER transfer signed with the legacy sign
+// version, which doesn't sign the chain ID
+func legacySignedHERTx(t *testing.T, privKey secp256k1.PrivKeySecp256k1, receiver string, value, fee, nonce uint64) []byte {
+	pubKey := privKey.PubKey().(secp256k1.PubKeySecp256k1)
+	tx := pluginproto.Tx{
+		SenderAddress:   pubKey.GetAddress(),
+		SenderPubkey:    b64.StdEncoding.EncodeToString(pubKey[:]),
+		RecieverAddress: receiver,
+		Asset: &pluginproto.Asset{
+			Category: "crypto",
+			Symbol:   "HER",
+			Network:  "Herdius",
+			Value:    value,
+			Fee:      fee,
+			Nonce:    nonce,
+		},
+		Message:     "transfer",
+		SignVersion: txbyte.SignVersionLegacy,
+	}
+	signBytes, err := txbyte.SignBytes(&tx)
+	require.NoError(t, err)
+	sig, err := privKey.Sign(signBytes)
+	require.NoError(t, err)
+	tx.Sign = b64.StdEncoding.EncodeToString(sig)
+	txbz, err := cdc.MarshalJSON(&tx)
+	require.NoError(t, err)
+	return txbz
+}
+
+// lostNode deletes a node of the state at root which the state at parent
+// doesn't share from the state db
+func lostNode(t *testing.T, parent, root []byte) {
+	shared := make(map[common.Hash]bool)
+	parentTrie, err := ethtrie.New(common.BytesToHash(parent), statedb.GetDB())
+	require.NoError(t, err)
+	for it := parentTrie.NodeIterator(nil); it.Next(true); {
+		shared[it.Hash()] = true
+	}
+	rootTrie, err := ethtrie.New(common.BytesToHash(root), statedb.GetDB())
+	require.NoError(t, err)
+	for it := rootTrie.NodeIterator(nil); it.Next(true); {
+		if it.Hash() != (common.Hash{}) && it.Hash() != common.BytesToHash(root) && !shared[it.Hash()] {
+			require.NoError(t, statedb.GetDB().DiskDB().(ethdb.Deleter).Delete(it.Hash().Bytes()))
+			return
+		}
+	}
+	require.FailNow(t, "no node of its own in the state")
+}
@@ -1,12 +1,14 @@
 package service
 
 import (
+	"bytes"
 	"context"
-	b64 "encoding/base64"
-	"encoding/json"
+	"crypto/sha256"
+	"encoding/binary"
 	"fmt"
 	"log"
-	"strings"
+	"math"
+	"sort"
 	"sync"
 	"time"
 
@@ -14,11 +16,11 @@ import (
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/herdius/herdius-core/aws"
+	"github.com/herdius/herdius-core/blockchain"
 	"github.com/herdius/herdius-core/blockchain/protobuf"
 	cryptokey "github.com/herdius/herdius-core/crypto"
 	hehash "github.com/herdius/herdius-core/crypto/herhash"
 	"github.com/herdius/herdius-core/crypto/merkle"
-	"github.com/herdius/herdius-core/crypto/secp256k1"
 	cmn "github.com/herdius/herdius-core/libs/common"
 	cryptokeys "github.com/herdius/herdius-core/p2p/crypto"
 	plog "github.com/herdius/herdius-core/p2p/log"
@@ -26,6 +28,7 @@ import (
 	"github.com/herdius/herdius-core/storage/mempool"
 	"github.com/herdius/herdius-core/storage/state/statedb"
 	"github.com/herdius/herdius-core/supervisor/transaction"
+	"github.com/herdius/herdius-core/transition"
 	txbyte "github.com/herdius/herdius-core/tx"
 )
 
@@ -42,6 +45,7 @@ type SupervisorI interface {
 	CreateBaseBlock(lastBlock *protobuf.BaseBlock) (*protobuf.BaseBlock, error)
 	GetMutex() *sync.Mutex
 	ProcessTxs(lastBlock *protobuf.BaseBlock, net *network.Network) (*protobuf.BaseBlock, error)
+	CommitBlock(lastBlock, baseBlock *protobuf.BaseBlock, chain blockchain.ServiceI) error
 	ShardToValidators(*protobuf.BaseBlock, txbyte.Txs, *network.Network, []byte) (*protobuf.BaseBlock, error)
 }
 
@@ -49,6 +53,24 @@ var (
 	_ SupervisorI = (*Supervisor)(nil)
 )
 
+const (
+	// childBlockTimeout bounds the wait for the votes of a validator group
+	childBlockTimeout = 30 * time.Second
+)
+
+// defaultPeersInGroup is the number of validators in a group unless configured
+const defaultPeersInGroup = 3
+
+// childBlockRetryPolicy retries sending a child block to a validator which
+// didn't respond
+var childBlockRetryPolicy = network.RetryPolicy{
+	MaxAttempts:    3,
+	AttemptTimeout: 10 * time.Second,
+	MinInterval:    500 * time.Millisecond,
+	MaxInterval:    2 * time.Second,
+	Multiplier:     2,
+}
+
 // Supervisor is concrete implementation of SupervisorI
 type Supervisor struct {
 	TxBatches           *[]txbyte.Txs // TxGroups will consist of list of the transaction batches
@@ -62,6 +84,14 @@ type Supervisor struct {
 	waitTime            int
 	noOfPeersInGroup    int
 	backup              bool
+	verifyState         bool                       // CheckState walks the whole state trie of the last block
+	rewardAddress       string                     // HER account the tx fees are credited to
+	fees                uint64                     // HER fees collected from the txs of the block being created
+	receipts            [][]byte                   // encoded receipts of the txs of the block being created
+	groups              []*protobuf.ValidatorGroup // validator groups of the block being created
+	stagedStateRoot     []byte                     // state root of the block being created, staged until the block is stored
+	blockTxs            []int                      // indexes of the drained txs in the block being created
+	balanceUpdates      []balanceUpdate            // external balance updates written to the state of the block being created
 }
 
 // StateRoot returns Supervisor current state root
@@ -74,6 +104,21 @@ func (s *Supervisor) SetStateRoot(stateRoot []byte) {
 	s.stateRoot = stateRoot
 }
 
+// RewardAddress returns the HER account address the tx fees are credited to
+func (s *Supervisor) RewardAddress() string {
+	return s.rewardAddress
+}
+
+// SetRewardAddress sets the HER account address the tx fees are credited to
+func (s *Supervisor) SetRewardAddress(rewardAddress string) {
+	s.rewardAddress = rewardAddress
+}
+
+// Fees returns the HER fees collected from the txs of the block being created
+func (s *Supervisor) Fees() uint64 {
+	return s.fees
+}
+
 // Env returns environment name
 func (s *Supervisor) Env() string {
 	return s.env
@@ -104,6 +149,12 @@ func (s *Supervisor) Backup() bool {
 	return s.backup
 }
 
+// SetVerifyState sets whether CheckState verifies the whole state trie of the
+// last block rather than its root only
+func (s *Supervisor) SetVerifyState(verifyState bool) {
+	s.verifyState = verifyState
+}
+
 // NoOfPeersInGroup ...
 func (s *Supervisor) NoOfPeersInGroup() int {
 	return s.noOfPeersInGroup
@@ -235,6 +286,11 @@ func (s *Supervisor) CreateBaseBlock(lastBlock *protobuf.BaseBlock) (*protobuf.B
 		plog.Error().Msgf("Vote commits marshaling failed.: %v", err)
 	}
 
+	groupsHash, err := blockchain.ValidatorGroupsHash(s.groups)
+	if err != nil {
+		return nil, fmt.Errorf("failed to hash validator groups: %v", err)
+	}
+
 	ts := time.Now().UTC()
 	baseHeader := &protobuf.BaseHeader{
 		Block_ID:               &protobuf.BlockID{},
@@ -244,18 +300,20 @@ func (s *Supervisor) CreateBaseBlock(lastBlock *protobuf.BaseBlock) (*protobuf.B
 		NextValidatorGroupHash: nvgHash,
 		ChildBlockHash:         cbMerkleHash,
 		LastVoteHash:           vcbz,
-		StateRoot:              s.stateRoot,
+		StateRoot:              s.stagedStateRoot,
+		Fees:                   s.fees,
+		ReceiptRoot:            merkle.SimpleHashFromByteSlices(s.receipts),
+		ValidatorGroupsHash:    groupsHash,
 		Time: &protobuf.Timestamp{
 			Seconds: ts.Unix(),
 			Nanos:   ts.UnixNano(),
 		},
 	}
 
-	blockHashBz, err := cdc.MarshalJSON(baseHeader)
+	blockHash, err := blockchain.HeaderHash(baseHeader)
 	if err != nil {
 		plog.Error().Msgf("Base Header marshaling failed.: %v", err)
 	}
-	blockHash := hehash.Sum(blockHashBz)
 
 	baseHeader.GetBlock_ID().BlockHash = blockHash
 
@@ -277,11 +335,14 @@ func (s *Supervisor) CreateBaseBlock(lastBlock *protobuf.BaseBlock) (*protobuf.B
 	}
 	s.writerMutex.Lock()
 	baseBlock := &protobuf.BaseBlock{
-		Header:        baseHeader,
-		ChildBlock:    childBlocksBz,
-		VoteCommits:   vcBz,
-		Validator:     valsBz,
-		NextValidator: valsBz,
+		Header:          baseHeader,
+		ChildBlock:      childBlocksBz,
+		VoteCommits:     vcBz,
+		Validator:       valsBz,
+		NextValidator:   valsBz,
+		Receipts:        s.receipts,
+		ValidatorGroups: s.groups,
+		AccountUpdates:  accountUpdates(s.balanceUpdates),
 	}
 	s.writerMutex.Unlock()
 	return baseBlock, nil
@@ -289,6 +350,12 @@ func (s *Supervisor) CreateBaseBlock(lastBlock *protobuf.BaseBlock) (*protobuf.B
 
 // CreateChildBlock creates an initial child block
 func (s *Supervisor) CreateChildBlock(net *network.Network, txs *transaction.TxList, height int64, previousBlockHash []byte) *protobuf.ChildBlock {
+	return s.createChildBlock(net, txs, height, previousBlockHash, nil)
+}
+
+// createChildBlock creates a child block whose header commits to the state root
+// the validators are expected to verify its transactions against
+func (s *Supervisor) createChildBlock(net *network.Network, txs *transaction.TxList, height int64, previousBlockHash, stateRoot []byte) *protobuf.ChildBlock {
 	txList := *txs
 	if len(txList.Transactions) == 0 {
 		return nil
@@ -341,6 +408,7 @@ func (s *Supervisor) CreateChildBlock(net *network.Network, txs *transaction.TxL
 		RootHash:     rootHash,
 		Height:       height,
 		LastBlockID:  lastBlockID,
+		StateRoot:    stateRoot,
 	}
 	hbz, _ := cdc.MarshalJSON(header)
 
@@ -369,58 +437,136 @@ func (s *Supervisor) CreateChildBlock(net *network.Network, txs *transaction.TxL
 
 // ProcessTxs will process transactions.
 // It will check whether to send the transactions to Validators
-// or to be included in Singular base block
+// or to be included in Singular base block. The state of the block is staged
+// until the block is stored by CommitBlock.
 func (s *Supervisor) ProcessTxs(lastBlock *protobuf.BaseBlock, net *network.Network) (*protobuf.BaseBlock, error) {
 	mp := mempool.GetMemPool()
 	select {
 	case <-time.After(time.Duration(s.waitTime) * time.Second):
+		s.discardStagedState()
 		txs := mp.GetTxs()
 		if len(s.Validator) == 0 || len(*txs) == 0 {
 			log.Printf("Block creation wait time (%d) elapsed, creating singular base block but with %v transactions", s.waitTime, len(*txs))
 			baseBlock, err := s.createSingularBlock(lastBlock, net, *txs, mp, s.stateRoot)
 			if err != nil {
+				mp.RemoveDrainedTxs(nil)
 				return nil, fmt.Errorf("failed to create singular base block: %v", err)
 			}
-			mp.RemoveTxs(len(*txs))
-			if !s.Backup() {
-				log.Println("Backup value false, not backing up block or state")
-				return baseBlock, nil
-			}
-			backuper := aws.NewBackuper(s.env)
-			succ, err := backuper.TryBackupBaseBlock(lastBlock, baseBlock)
-			if err != nil {
-				log.Println("nonfatal: failed to backup to S3:", err)
-			} else if !succ {
-				log.Println("S3 backup criteria not met; proceeding to backup all unbacked base blocks")
-				err := backuper.BackupNeededBaseBlocks(baseBlock)
-				if err != nil {
-					log.Println("nonfatal: failed to backup both single new and all unbacked base blocks:", err)
-				}
-				log.Print("Successfully re-evaluated chain and backed up to S3")
+			s.blockTxs = make([]int, len(*txs))
+			for i := range s.blockTxs {
+				s.blockTxs[i] = i
 			}
-
 			return baseBlock, nil
 		}
-		baseBlock, err := s.ShardToValidators(lastBlock, *txs, net, s.stateRoot)
+		baseBlock, committed, err := s.shardToValidators(lastBlock, *txs, net, s.stateRoot)
 		if err != nil {
+			mp.RemoveDrainedTxs(nil)
 			return nil, fmt.Errorf("failed to shard Txs to child blocks: %v", err)
 		}
-		mp.RemoveTxs(len(*txs))
+		if baseBlock == nil {
+			mp.RemoveDrainedTxs(nil)
+			return nil, fmt.Errorf("no child block committed, %d txs kept in the memory pool", len(*txs))
+		}
+		// The txs of the child blocks which aren't committed stay in the
+		// memory pool
+		s.blockTxs = committed
 		return baseBlock, nil
 	}
 }
 
+// CommitBlock stores baseBlock, created by the last ProcessTxs, in chain along
+// with its staged state. The state is written first so that the state of the
+// last block of the chain is always on disk, and becomes the state root of the
+// supervisor once the block is stored too. If either write fails the staged
+// state is discarded, and the txs and the external balance updates of the
+// block are kept for the next block.
+func (s *Supervisor) CommitBlock(lastBlock, baseBlock *protobuf.BaseBlock, chain blockchain.ServiceI) error {
+	mp := mempool.GetMemPool()
+	root := s.stagedStateRoot
+	if len(root) == 0 || !bytes.Equal(root, baseBlock.GetHeader().GetStateRoot()) {
+		s.discardStagedState()
+		mp.RemoveDrainedTxs(nil)
+		return fmt.Errorf("state of base block not staged")
+	}
+	if err := statedb.Persist(root); err != nil {
+		s.discardStagedState()
+		mp.RemoveDrainedTxs(nil)
+		return err
+	}
+	// The state written for a block which fails to be stored isn't the
+	// state of any block, so it is left unused
+	if err := chain.AddBaseBlock(baseBlock); err != nil {
+		s.discardStagedState()
+		mp.RemoveDrainedTxs(nil)
+		return err
+	}
+	s.SetStateRoot(root)
+	s.stagedStateRoot = nil
+	mp.RemoveDrainedTxs(s.blockTxs)
+	s.blockTxs = nil
+	if accountStorage != nil {
+		clearBalanceUpdates(s.balanceUpdates)
+	}
+	s.balanceUpdates = nil
+
+	if !s.Backup() {
+		log.Println("Backup value false, not backing up block or state")
+		return nil
+	}
+	backuper, err := aws.NewBackuper(s.env)
+	if err != nil {
+		log.Println("nonfatal: failed to backup:", err)
+		return nil
+	}
+	succ, err := backuper.TryBackupBaseBlock(lastBlock, baseBlock)
+	if err != nil {
+		log.Println("nonfatal: failed to backup:", err)
+	} else if !succ {
+		log.Println("Backup criteria not met; proceeding to backup all unbacked base blocks")
+		err := backuper.BackupNeededBaseBlocks(baseBlock)
+		if err != nil {
+			log.Println("nonfatal: failed to backup both single new and all unbacked base blocks:", err)
+		}
+		log.Print("Successfully re-evaluated chain and backed up")
+	}
+	return nil
+}
+
+// stageState stages the state of the block being created
+func (s *Supervisor) stageState(stateTrie statedb.Trie) error {
+	root, err := stateTrie.Stage()
+	if err != nil {
+		return fmt.Errorf("failed to stage state: %v", err)
+	}
+	s.discardStagedState()
+	s.stagedStateRoot = root
+	return nil
+}
+
+// discardStagedState drops the state of a block which isn't stored
+func (s *Supervisor) discardStagedState() {
+	statedb.Discard(s.stagedStateRoot)
+	s.stagedStateRoot = nil
+	s.blockTxs = nil
+	s.balanceUpdates = nil
+}
+
 func (s *Supervisor) createSingularBlock(lastBlock *protobuf.BaseBlock, net *network.Network, txs txbyte.Txs, mp *mempool.MemPool, stateRoot []byte) (*protobuf.BaseBlock, error) {
 	stateTrie, err := statedb.NewTrie(common.BytesToHash(stateRoot))
 	if err != nil {
 		return nil, fmt.Errorf("failed to retrieve the state trie: %v", err)
 	}
+	var updates []balanceUpdate
 	if accountStorage != nil {
-		stateTrie = updateStateWithNewExternalBalance(stateTrie)
+		updates = updateStateWithNewExternalBalance(stateTrie)
 	}
-	if _, err := s.updateStateForTxs(&txs, stateTrie); err != nil {
+	if _, err := s.updateStateForTxs(&txs, stateTrie, lastBlock.GetHeader().GetHeight()+1); err != nil {
 		return nil, fmt.Errorf("failed to update state for txs: %v", err)
 	}
+	if err := s.stageState(stateTrie); err != nil {
+		return nil, err
+	}
+	s.balanceUpdates = updates
 
 	// Get Merkle Root Hash of all transactions
 	mrh := txs.MerkleHash()
@@ -431,266 +577,176 @@ func (s *Supervisor) createSingularBlock(lastBlock *protobuf.BaseBlock, net *net
 		Block_ID:    &protobuf.BlockID{},
 		LastBlockID: lastBlock.GetHeader().GetBlock_ID(),
 		Height:      lastBlock.Header.Height + 1,
-		StateRoot:   s.stateRoot,
+		StateRoot:   s.stagedStateRoot,
 		Time: &protobuf.Timestamp{
 			Seconds: ts.Unix(),
 			Nanos:   ts.UnixNano(),
 		},
-		RootHash: mrh,
-		TotalTxs: uint64(len(txs)),
+		RootHash:    mrh,
+		TotalTxs:    uint64(len(txs)),
+		Fees:        s.fees,
+		ReceiptRoot: merkle.SimpleHashFromByteSlices(s.receipts),
 	}
-	blockHashBz, err := cdc.MarshalJSON(baseHeader)
+	blockHash, err := blockchain.HeaderHash(baseHeader)
 	if err != nil {
 		plog.Error().Msgf("Base Header marshaling failed.: %v", err)
 	}
-
-	blockHash := hehash.Sum(blockHashBz)
 	baseHeader.GetBlock_ID().BlockHash = blockHash
 	// Add Header to Block
 
 	s.writerMutex.Lock()
 	baseBlock := &protobuf.BaseBlock{
-		Header:  baseHeader,
-		TxsData: &protobuf.TxsData{Tx: txs},
+		Header:         baseHeader,
+		TxsData:        &protobuf.TxsData{Tx: txs},
+		Receipts:       s.receipts,
+		AccountUpdates: accountUpdates(s.balanceUpdates),
 	}
 	s.writerMutex.Unlock()
 
 	return baseBlock, nil
 }
 
-func updateStateWithNewExternalBalance(stateTrie statedb.Trie) statedb.Trie {
+// balanceUpdate is an account written to the state for the external balance
+// updates of the syncer, and the keys of the updates it holds
+type balanceUpdate struct {
+	address      string
+	account      []byte   // encoded account written to the state
+	firstEntries []string // storage keys of the first external balances
+	newAmounts   []string // storage keys of the updated external balances
+	firstHER     bool
+	newHER       bool
+}
+
+// updateStateWithNewExternalBalance writes the accounts whose external
+// balances the syncer updated to stateTrie. The updates stay flagged in the
+// account storage until clearBalanceUpdates, once the block is stored, so
+// that the updates of a block which fails are written with the next one.
+func updateStateWithNewExternalBalance(stateTrie statedb.Trie) []balanceUpdate {
 	updateAccs := accountStorage.GetAll()
 	log.Println("Total Accounts to update", len(updateAccs))
+	var updates []balanceUpdate
 	for address, item := range updateAccs {
-		accountInAccountCache := item
 		account := item.Account
+		update := balanceUpdate{address: address}
 		for assetSymbol := range account.EBalances {
 			for _, eb := range account.EBalances[assetSymbol] {
 				storageKey := assetSymbol + "-" + eb.Address
-				IsFirstEntry := item.IsFirstEntry[storageKey]
-				IsNewAmountUpdate := item.IsNewAmountUpdate[storageKey]
-				if IsNewAmountUpdate && !IsFirstEntry {
-					log.Printf("Account from cache to be persisted to state: %v", account)
-					sactbz, err := cdc.MarshalJSON(account)
-					if err != nil {
-						plog.Error().Msgf("Failed to Marshal sender's account: %v", err)
-						continue
-					}
-					stateTrie.TryUpdate([]byte(address), sactbz)
-					accountInAccountCache.IsNewAmountUpdate[storageKey] = false
-					accountStorage.Set(address, accountInAccountCache)
-				}
-				if IsFirstEntry {
-					log.Println("Account from cache to be persisted to state first time: ", account)
-					sactbz, err := cdc.MarshalJSON(account)
-					if err != nil {
-						plog.Error().Msgf("Failed to Marshal sender's account: %v", err)
-						continue
-					}
-					stateTrie.TryUpdate([]byte(address), sactbz)
-					accountInAccountCache.IsFirstEntry[storageKey] = false
-					accountStorage.Set(address, accountInAccountCache)
+				if item.IsFirstEntry[storageKey] {
+					update.firstEntries = append(update.firstEntries, storageKey)
+				} else if item.IsNewAmountUpdate[storageKey] {
+					update.newAmounts = append(update.newAmounts, storageKey)
 				}
 			}
 		}
 
 		// IF ERC20Address is presend update accoun balance
 		if len(account.Erc20Address) > 0 {
-			IsFirstEntry := item.IsFirstHEREntry
-			IsNewAmountUpdate := item.IsNewHERAmountUpdate
-			if IsNewAmountUpdate && !IsFirstEntry {
-				log.Printf("Account from cache to be persisted to state: %v", account)
-				sactbz, err := cdc.MarshalJSON(account)
-				if err != nil {
-					plog.Error().Msgf("Failed to Marshal sender's account: %v", err)
-					continue
-				}
-				stateTrie.TryUpdate([]byte(address), sactbz)
-				accountInAccountCache.IsNewHERAmountUpdate = false
-				accountStorage.Set(address, accountInAccountCache)
+			if item.IsFirstHEREntry {
+				update.firstHER = true
+			} else if item.IsNewHERAmountUpdate {
+				update.newHER = true
 			}
-			if IsFirstEntry {
-				log.Println("Account from cache to be persisted to state first time: ", account)
-				sactbz, err := cdc.MarshalJSON(account)
-				if err != nil {
-					plog.Error().Msgf("Failed to Marshal sender's account: %v", err)
-					continue
-				}
-				stateTrie.TryUpdate([]byte(address), sactbz)
-				accountInAccountCache.IsFirstHEREntry = false
-				accountStorage.Set(address, accountInAccountCache)
-			}
-
+		}
+		if len(update.firstEntries) == 0 && len(update.newAmounts) == 0 && !update.firstHER && !update.newHER {
+			continue
 		}
 
-	}
-	return stateTrie
-}
-func isExternalAssetAddressExist(account *statedb.Account, assetSymbol, assetAddress string) bool {
-	if account == nil || account.EBalances == nil {
-		return false
-	}
-	if len(account.EBalances[assetSymbol][assetAddress].Address) > 0 {
-		return true
-	}
-	return false
-}
-
-func updateAccountLockedBalance(senderAccount *statedb.Account, tx *pluginproto.Tx) *statedb.Account {
-	if senderAccount.LockedBalance == nil {
-		senderAccount.LockedBalance = make(map[string]map[string]uint64)
-	}
-	asset := strings.ToUpper(tx.Asset.Symbol)
-	if senderAccount.LockedBalance[asset] == nil {
-		senderAccount.LockedBalance[asset] = make(map[string]uint64)
-	}
-
-	if tx.SenderAddress == senderAccount.Address {
-		senderAccount.LockedBalance[asset][tx.Asset.ExternalSenderAddress] += tx.Asset.LockedAmount
-	}
-	withdraw(senderAccount, tx.Asset.Symbol, tx.Asset.ExternalSenderAddress, tx.Asset.LockedAmount)
-	senderAccount.Nonce = tx.Asset.Nonce
-	if strings.EqualFold("BTC", tx.Asset.Symbol) {
-		if _, ok := senderAccount.EBalances["HBTC"]; !ok {
-			eBalance := statedb.EBalance{}
-			eBalance.Address = senderAccount.FirstExternalAddress["ETH"]
-			eBalance.Balance = 0
-			eBalance.LastBlockHeight = 0
-			eBalance.Nonce = 1
-			eBalances := senderAccount.EBalances
-			eBalances["HBTC"] = make(map[string]statedb.EBalance)
-			eBalances["HBTC"][senderAccount.FirstExternalAddress["ETH"]] = eBalance
-			senderAccount.EBalances = eBalances
+		log.Printf("Account from cache to be persisted to state: %v", account)
+		sactbz, err := cdc.MarshalJSON(account)
+		if err != nil {
+			plog.Error().Msgf("Failed to Marshal sender's account: %v", err)
+			continue
+		}
+		if err := stateTrie.TryUpdate([]byte(address), sactbz); err != nil {
+			plog.Error().Msgf("Failed to store account %v in state db: %v", address, err)
+			continue
 		}
+		update.account = sactbz
+		updates = append(updates, update)
 	}
-	log.Printf("Locked Account: %v+\n", *senderAccount)
-	return senderAccount
+	// Recorded in the block in the same order by every node
+	sort.Slice(updates, func(i, j int) bool { return updates[i].address < updates[j].address })
+	return updates
 }
 
-func updateRedeemAccountLockedBalance(senderAccount *statedb.Account, tx *pluginproto.Tx) *statedb.Account {
-	if senderAccount.LockedBalance == nil {
-		return senderAccount
-	}
-	asset := strings.ToUpper(tx.Asset.Symbol)
-	if strings.EqualFold(tx.Asset.Symbol, "HBTC") {
-		asset = "BTC"
-	}
-	if senderAccount.LockedBalance[asset] == nil {
-		if strings.EqualFold(tx.Asset.Symbol, "HBTC") {
-			// New HBTC Balance update
-			firstExternalAddress := senderAccount.FirstExternalAddress["ETH"]
-			newHBTCExternalBal := senderAccount.EBalances[tx.Asset.Symbol][tx.Asset.ExternalSenderAddress].Balance - tx.Asset.RedeemedAmount
-			newHBTCEBal := statedb.EBalance{
-				Address:         firstExternalAddress,
-				Balance:         newHBTCExternalBal,
-				LastBlockHeight: senderAccount.EBalances[tx.Asset.Symbol][firstExternalAddress].LastBlockHeight,
-				Nonce:           senderAccount.EBalances[tx.Asset.Symbol][firstExternalAddress].Nonce,
-			}
-			senderAccount.EBalances[tx.Asset.Symbol][firstExternalAddress] = newHBTCEBal
-		} else {
-			return senderAccount
+// clearBalanceUpdates clears the flags of the external balance updates
+// written with a stored block. An account the syncer updated again since is
+// left flagged, to be written with the next block.
+func clearBalanceUpdates(updates []balanceUpdate) {
+	for _, update := range updates {
+		item, ok := accountStorage.Get(update.address)
+		if !ok {
+			continue
 		}
-
-	} else if tx.SenderAddress == senderAccount.Address &&
-		tx.Asset.RedeemedAmount <= senderAccount.LockedBalance[asset][tx.Asset.ExternalSenderAddress] {
-		senderAccount.LockedBalance[asset][tx.Asset.ExternalSenderAddress] -= tx.Asset.RedeemedAmount
-		newExternalBal := senderAccount.EBalances[asset][tx.Asset.ExternalSenderAddress].Balance + tx.Asset.RedeemedAmount
-		newEBal := statedb.EBalance{
-			Address:         tx.Asset.ExternalSenderAddress,
-			Balance:         newExternalBal,
-			LastBlockHeight: senderAccount.EBalances[asset][tx.Asset.ExternalSenderAddress].LastBlockHeight,
-			Nonce:           senderAccount.EBalances[asset][tx.Asset.ExternalSenderAddress].Nonce,
+		actbz, err := cdc.MarshalJSON(item.Account)
+		if err != nil || !bytes.Equal(actbz, update.account) {
+			continue
 		}
-		senderAccount.EBalances[asset][tx.Asset.ExternalSenderAddress] = newEBal
-	}
-	senderAccount.Nonce = tx.Asset.Nonce
-	deposit(senderAccount, asset, tx.Asset.ExternalSenderAddress, tx.Asset.RedeemedAmount)
-	log.Printf("Redeemed Account: %v+\n", *senderAccount)
-	return senderAccount
-}
-func updateAccount(senderAccount *statedb.Account, tx *pluginproto.Tx) *statedb.Account {
-	if strings.EqualFold(strings.ToUpper(tx.Asset.Symbol), "HER") &&
-		len(senderAccount.Address) == 0 {
-		senderAccount.Address = tx.SenderAddress
-		senderAccount.Balance = 0
-		senderAccount.Nonce = 0
-		senderAccount.PublicKey = tx.SenderPubkey
-		log.Println("Account register", tx)
-		senderAccount.Erc20Address = tx.Asset.ExternalSenderAddress
-		senderAccount.FirstExternalAddress = make(map[string]string)
-	} else if strings.EqualFold(strings.ToUpper(tx.Asset.Symbol), "HER") &&
-		tx.SenderAddress == senderAccount.Address {
-		senderAccount.Balance += tx.Asset.Value
-		senderAccount.Nonce = tx.Asset.Nonce
-	} else if !strings.EqualFold(strings.ToUpper(tx.Asset.Symbol), "HER") &&
-		tx.SenderAddress == senderAccount.Address {
-
-		// Update account's Nonce
-		senderAccount.Nonce = tx.Asset.Nonce
-
-		// Register External Asset Addresses if not exist
-		if assetEBalance, ok := senderAccount.EBalances[tx.Asset.Symbol]; ok {
-			if _, ok := assetEBalance[tx.Asset.ExternalSenderAddress]; !ok {
-				eBalance := statedb.EBalance{}
-				eBalance.Address = tx.Asset.ExternalSenderAddress
-				eBalance.Balance = 0
-				eBalance.LastBlockHeight = 0
-				eBalance.Nonce = 0
-				eBalances := senderAccount.EBalances
-				eBalances[tx.Asset.Symbol][tx.Asset.ExternalSenderAddress] = eBalance
-				senderAccount.EBalances = eBalances
-			}
-		} else {
-			eBalance := statedb.EBalance{}
-			eBalance.Address = tx.Asset.ExternalSenderAddress
-			eBalance.Balance = 0
-			eBalance.LastBlockHeight = 0
-			eBalance.Nonce = 0
-			eBalances := senderAccount.EBalances
-			if len(eBalances) == 0 {
-				eBalances = make(map[string]map[string]statedb.EBalance)
-			}
-			if len(eBalances[tx.Asset.Symbol]) == 0 {
-				eBalances[tx.Asset.Symbol] = make(map[string]statedb.EBalance)
+		for _, key := range update.firstEntries {
+			if item.IsFirstEntry != nil {
+				item.IsFirstEntry[key] = false
 			}
-			eBalances[tx.Asset.Symbol][tx.Asset.ExternalSenderAddress] = eBalance
-			senderAccount.EBalances = eBalances
-			if senderAccount.FirstExternalAddress == nil {
-				senderAccount.FirstExternalAddress = make(map[string]string)
+		}
+		for _, key := range update.newAmounts {
+			if item.IsNewAmountUpdate != nil {
+				item.IsNewAmountUpdate[key] = false
 			}
-			senderAccount.FirstExternalAddress[tx.Asset.Symbol] = tx.Asset.ExternalSenderAddress
 		}
+		if update.firstHER {
+			item.IsFirstHEREntry = false
+		}
+		if update.newHER {
+			item.IsNewHERAmountUpdate = false
+		}
+		accountStorage.Set(update.address, item)
 	}
-	return senderAccount
 }
 
-// Debit Sender's Account
-func withdraw(senderAccount *statedb.Account, assetSymbol, assetExtAddress string, txValue uint64) {
-	if strings.EqualFold(assetSymbol, "HER") {
-		balance := senderAccount.Balance
-		if balance >= txValue {
-			senderAccount.Balance -= txValue
-		}
-	} else {
-		// Get balance of the required external asset
-		eBalance := senderAccount.EBalances[strings.ToUpper(assetSymbol)][assetExtAddress]
-		if eBalance.Balance >= txValue {
-			eBalance.Balance -= txValue
-			senderAccount.EBalances[strings.ToUpper(assetSymbol)][assetExtAddress] = eBalance
-		}
+// accountUpdates returns the accounts written for updates, to be recorded in
+// the block so that its state can be replayed
+func accountUpdates(updates []balanceUpdate) []*protobuf.AccountUpdate {
+	if len(updates) == 0 {
+		return nil
+	}
+	accounts := make([]*protobuf.AccountUpdate, 0, len(updates))
+	for _, update := range updates {
+		accounts = append(accounts, &protobuf.AccountUpdate{Address: update.address, Account: update.account})
 	}
+	return accounts
 }
 
-// Credit Receiver's Account
-func deposit(receiverAccount *statedb.Account, assetSymbol, assetExtAddress string, txValue uint64) {
-	if strings.EqualFold(assetSymbol, "HER") {
-		receiverAccount.Balance += txValue
-	} else {
-		// Get balance of the required external asset
-		eBalance := receiverAccount.EBalances[strings.ToUpper(assetSymbol)][assetExtAddress]
-		eBalance.Balance += txValue
-		receiverAccount.EBalances[strings.ToUpper(assetSymbol)][assetExtAddress] = eBalance
+// creditFees credits the fees collected from the txs to the reward account
+func (s *Supervisor) creditFees(stateTrie statedb.Trie, fees uint64) error {
+	if fees == 0 {
+		return nil
+	}
+	if len(s.rewardAddress) == 0 {
+		return fmt.Errorf("no reward account to credit %d fees to", fees)
+	}
+	var rewardAccount statedb.Account
+	actbz, err := stateTrie.TryGet([]byte(s.rewardAddress))
+	if err != nil {
+		return fmt.Errorf("failed to retrieve reward account: %v", err)
+	}
+	if len(actbz) > 0 {
+		if err := cdc.UnmarshalJSON(actbz, &rewardAccount); err != nil {
+			return fmt.Errorf("failed to unmarshal reward account: %v", err)
+		}
+	}
+	rewardAccount.Address = s.rewardAddress
+	balance, err := transition.AddBalance(rewardAccount.Balance, fees)
+	if err != nil {
+		return fmt.Errorf("failed to credit fees to reward account: %v", err)
 	}
+	rewardAccount.Balance = balance
+	actbz, err = cdc.MarshalJSON(rewardAccount)
+	if err != nil {
+		return fmt.Errorf("failed to marshal reward account: %v", err)
+	}
+	if err := stateTrie.TryUpdate([]byte(s.rewardAddress), actbz); err != nil {
+		return fmt.Errorf("failed to store reward account in state db: %v", err)
+	}
+	return nil
 }
 
 func (s *Supervisor) validatorAddresses() []string {
@@ -701,467 +757,525 @@ func (s *Supervisor) validatorAddresses() []string {
 	return addresses
 }
 
-func (s *Supervisor) validatorGroups(numGroup int) [][]string {
-	s.writerMutex.Lock()
-	defer s.writerMutex.Unlock()
+// numGroups returns the number of groups numValds validators are split into,
+// each of noOfPeersInGroup validators
+func (s *Supervisor) numGroups(numValds int) int {
+	peers := s.noOfPeersInGroup
+	if peers <= 0 {
+		peers = defaultPeersInGroup
+	}
+	return (numValds + peers - 1) / peers
+}
 
-	numValds := len(s.Validator)
-	validators := make([]string, 0)
-	for address := range s.Validator {
-		validators = append(validators, address)
+// validatorGroups splits the validators into numGroup groups. The validators
+// are drawn in an order weighted by their staking power and seeded by seed,
+// each joining the group with the least staking power so far, so that anyone
+// knowing the seed and the validators can recompute the groups.
+func (s *Supervisor) validatorGroups(seed []byte, numGroup int) [][]string {
+	s.writerMutex.Lock()
+	validators := make([]*protobuf.Validator, 0, len(s.Validator))
+	for _, validator := range s.Validator {
+		validators = append(validators, validator)
 	}
+	s.writerMutex.Unlock()
 
-	var groups [][]string
+	numValds := len(validators)
+	if numValds == 0 {
+		return nil
+	}
 	if numGroup <= 0 {
 		numGroup = 1
 	}
 	groupSize := (numValds + numGroup - 1) / numGroup
+	numGroup = (numValds + groupSize - 1) / groupSize
 
-	for start := 0; start < numValds; start += groupSize {
-		end := start + groupSize
-		if end > numValds {
-			end = numValds
+	keys := make(map[string]float64, numValds)
+	for _, validator := range validators {
+		keys[validator.GetAddress()] = drawKey(seed, validator)
+	}
+	sort.Slice(validators, func(i, j int) bool {
+		ki, kj := keys[validators[i].GetAddress()], keys[validators[j].GetAddress()]
+		if ki != kj {
+			return ki > kj
+		}
+		return validators[i].GetAddress() < validators[j].GetAddress()
+	})
+
+	groups := make([][]string, numGroup)
+	stakes := make([]int64, numGroup)
+	for _, validator := range validators {
+		group := -1
+		for i := range groups {
+			capacity := groupSize
+			if i == numGroup-1 {
+				capacity = numValds - groupSize*(numGroup-1)
+			}
+			if len(groups[i]) < capacity && (group < 0 || stakes[i] < stakes[group]) {
+				group = i
+			}
+		}
+		groups[group] = append(groups[group], validator.GetAddress())
+		if validator.GetStakingpower() > 0 {
+			stakes[group] += validator.GetStakingpower()
 		}
-		groups = append(groups, validators[start:end])
 	}
 
 	return groups
 }
 
+// drawKey returns the key validators are ordered by, in decreasing order, to
+// draw them weighted by staking power: log(u)/stake, u being uniform in (0, 1]
+// and derived from seed and the address of the validator. Validators without
+// stake are drawn last.
+func drawKey(seed []byte, validator *protobuf.Validator) float64 {
+	stake := validator.GetStakingpower()
+	if stake <= 0 {
+		return math.Inf(-1)
+	}
+	h := sha256.Sum256(append(append([]byte{}, seed...), validator.GetAddress()...))
+	u := float64(binary.BigEndian.Uint64(h[:8])>>11+1) / (1 << 53)
+	return math.Log(u) / float64(stake)
+}
+
+// txsGroups splits the txs into at most numGroup groups touching disjoint
+// accounts, so that the child block of each group can be verified against the
+// state before the block independently of the others.
 func (s *Supervisor) txsGroups(txList *transaction.TxList, numGroup int) [][]*transaction.Tx {
 	txs := txList.Transactions
-
 	var groups [][]*transaction.Tx
-	groupSize := (len(txs) + numGroup - 1) / numGroup
-
-	for start := 0; start < len(txs); start += groupSize {
-		end := start + groupSize
-		if end > len(txs) {
-			end = len(txs)
+	for _, indexes := range txGroupIndexes(txs, numGroup) {
+		group := make([]*transaction.Tx, 0, len(indexes))
+		for _, i := range indexes {
+			group = append(group, txs[i])
 		}
-		groups = append(groups, txs[start:end])
+		groups = append(groups, group)
+	}
+	return groups
+}
+
+// txGroupIndexes returns the indexes of the txs in each of the groups created
+// by txsGroups. The largest partitions go first to the groups with the fewest
+// txs, and each group keeps its txs in the order they were applied.
+func txGroupIndexes(txs []*transaction.Tx, numGroup int) [][]int {
+	partitions := txPartitions(txs)
+	if len(partitions) == 0 {
+		return nil
+	}
+	if numGroup <= 0 {
+		numGroup = 1
+	}
+	if numGroup > len(partitions) {
+		numGroup = len(partitions)
 	}
 
+	sort.SliceStable(partitions, func(i, j int) bool {
+		return len(partitions[i]) > len(partitions[j])
+	})
+	groups := make([][]int, numGroup)
+	for _, partition := range partitions {
+		group := 0
+		for i := range groups {
+			if len(groups[i]) < len(groups[group]) {
+				group = i
+			}
+		}
+		groups[group] = append(groups[group], partition...)
+	}
+	for i := range groups {
+		sort.Ints(groups[i])
+	}
 	return groups
 }
 
+// txPartitions returns the indexes of txs partitioned by the accounts they
+// touch, in the order of their first tx. A tx touching the accounts of two
+// partitions, like a transfer between them, joins them into one, so no
+// account is touched by two partitions.
+func txPartitions(txs []*transaction.Tx) [][]int {
+	// Union-find over the txs, the root of a set being its first tx
+	parent := make([]int, len(txs))
+	for i := range parent {
+		parent[i] = i
+	}
+	find := func(i int) int {
+		for parent[i] != i {
+			parent[i] = parent[parent[i]]
+			i = parent[i]
+		}
+		return i
+	}
+
+	// first holds the first tx touching each account
+	first := make(map[string]int)
+	for i, tx := range txs {
+		for _, address := range []string{tx.SenderAddress, tx.ReceiverAddress} {
+			if len(address) == 0 {
+				continue
+			}
+			j, ok := first[address]
+			if !ok {
+				first[address] = i
+				continue
+			}
+			ri, rj := find(i), find(j)
+			if ri < rj {
+				parent[rj] = ri
+			} else {
+				parent[ri] = rj
+			}
+		}
+	}
+
+	var partitions [][]int
+	partition := make(map[int]int)
+	for i := range txs {
+		root := find(i)
+		p, ok := partition[root]
+		if !ok {
+			p = len(partitions)
+			partition[root] = p
+			partitions = append(partitions, nil)
+		}
+		partitions[p] = append(partitions[p], i)
+	}
+	return partitions
+}
+
 // ShardToValidators distributes a series of childblocks to a series of validators
 func (s *Supervisor) ShardToValidators(lastBlock *protobuf.BaseBlock, txs txbyte.Txs, net *network.Network, stateRoot []byte) (*protobuf.BaseBlock, error) {
+	baseBlock, _, err := s.shardToValidators(lastBlock, txs, net, stateRoot)
+	return baseBlock, err
+}
+
+// shardToValidators distributes the txs in child blocks to the validator
+// groups and creates a base block of the child blocks a quorum of their group
+// voted for. It returns the indexes of the txs in the base block, the txs of
+// the other child blocks being left out of the block and the state. No base
+// block is created if no child block is committed.
+func (s *Supervisor) shardToValidators(lastBlock *protobuf.BaseBlock, txs txbyte.Txs, net *network.Network, stateRoot []byte) (*protobuf.BaseBlock, []int, error) {
 	numValds := len(s.Validator)
 	if numValds == 0 {
-		return nil, fmt.Errorf("not enough validators in pool to shard, # validators: %v", numValds)
+		return nil, nil, fmt.Errorf("not enough validators in pool to shard, # validators: %v", numValds)
 	}
 	numTxs := len(txs)
-	// TODO: Make number of groups configurable.
-	numGroup := 5
-	vGroups := s.validatorGroups(numGroup)
-	fmt.Printf("Number of txs (%v), groups (%v), validators (%v)\n", numTxs, vGroups, numValds)
+	height := lastBlock.GetHeader().GetHeight() + 1
+
+	// A base block holds the child blocks and votes of its own round only
+	mx := s.GetMutex()
+	mx.Lock()
+	s.ChildBlock = make([]*protobuf.ChildBlock, 0)
+	s.VoteInfoData = make(map[string][]*protobuf.VoteInfo)
+	s.ValidatorChildblock = make(map[string]*protobuf.BlockID)
+	mx.Unlock()
+
+	// The groups are seeded by the last block so that they change every block
+	seed := lastBlock.GetHeader().GetBlock_ID().GetBlockHash()
+	vGroups := s.validatorGroups(seed, s.numGroups(numValds))
+	log.Printf("Number of txs (%v), groups (%v), validators (%v)", numTxs, len(vGroups), numValds)
 
 	if len(stateRoot) == 0 {
-		return nil, fmt.Errorf("cannot process an empty stateRoot for the trie")
+		return nil, nil, fmt.Errorf("cannot process an empty stateRoot for the trie")
 	}
 	stateTrie, err := statedb.NewTrie(common.BytesToHash(stateRoot))
 	if err != nil {
-		return nil, fmt.Errorf("error attempting to retrieve state db trie from stateRoot: %v", err)
+		return nil, nil, fmt.Errorf("error attempting to retrieve state db trie from stateRoot: %v", err)
 	}
+	var updates []balanceUpdate
 	if accountStorage != nil {
-		stateTrie = updateStateWithNewExternalBalance(stateTrie)
+		updates = updateStateWithNewExternalBalance(stateTrie)
 	}
-	txList, err := s.updateStateForTxs(&txs, stateTrie)
+
+	// Validators verify the txs against the state before they are applied,
+	// so the touched accounts are proved before the state gets updated. The
+	// state is staged so that it can be reloaded to leave out the txs of
+	// the child blocks which aren't committed.
+	preStateRoot, err := stateTrie.Stage()
 	if err != nil {
-		return nil, fmt.Errorf("failed to update state for txs: %v", err)
+		return nil, nil, fmt.Errorf("failed to stage state before txs: %v", err)
+	}
+	defer statedb.Discard(preStateRoot)
+	proofs, err := accountProofs(txs, stateTrie)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create account proofs: %v", err)
+	}
+	txList, err := s.updateStateForTxs(&txs, stateTrie, height)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to update state for txs: %v", err)
 	}
 
-	txsGroups := s.txsGroups(txList, numGroup)
+	txsIndexes := txGroupIndexes(txList.Transactions, len(vGroups))
+	s.groups = nil
+	var committed []int
 	previousBlockHash := make([]byte, 0)
-	var voteCount = 0
-	for i := range txsGroups {
-		cb := s.CreateChildBlock(net, &transaction.TxList{Transactions: txsGroups[i]}, int64(len(txsGroups[i])), previousBlockHash)
-		previousBlockHash = cb.GetHeader().GetBlockID().BlockHash
-		if i > 0 {
-			cb.GetHeader().GetLastBlockID().BlockHash = previousBlockHash
+	for i := range txsIndexes {
+		groupTxs := make([]*transaction.Tx, 0, len(txsIndexes[i]))
+		for _, j := range txsIndexes[i] {
+			groupTxs = append(groupTxs, txList.Transactions[j])
+		}
+		// Validators apply the txs by the rules of the base block's height
+		cb := s.createChildBlock(net, &transaction.TxList{Transactions: groupTxs}, height, previousBlockHash, preStateRoot)
+		var cbhash cmn.HexBytes = cb.GetHeader().GetBlockID().GetBlockHash()
+		previousBlockHash = cbhash
+		cbmsg := &protobuf.ChildBlockMessage{
+			ChildBlock:    cb,
+			AccountProofs: groupAccountProofs(groupTxs, proofs),
 		}
-		cbmsg := &protobuf.ChildBlockMessage{ChildBlock: cb}
 		log.Println("Broadcasting child block to Validator Group:", vGroups[i])
+		votes := s.collectVotes(net, vGroups[i], cbmsg)
+
+		group := &protobuf.ValidatorGroup{Validators: vGroups[i], ChildBlockHash: cbhash}
+		s.groups = append(s.groups, group)
+		if !s.hasQuorum(vGroups[i], votes) {
+			log.Printf("Child block %v not committed, %d of %d validators voted", cbhash, len(votes), len(vGroups[i]))
+			continue
+		}
+		group.Committed = true
+		committed = append(committed, txsIndexes[i]...)
+
+		mx.Lock()
+		s.ChildBlock = append(s.ChildBlock, cb)
 		for _, address := range vGroups[i] {
-			validator, err := net.Client(address)
-			if err != nil {
-				return nil, fmt.Errorf("failed to create validator peer client: %v", err)
-			}
-			if validator.Address == "" {
-				log.Println("Empty validator node:", validator)
-				return nil, fmt.Errorf("Empty validator node:: %v", validator)
-			}
-			ctx := network.WithSignMessage(context.Background(), true)
-			response, err := validator.Request(ctx, cbmsg)
-			if err != nil {
-				return nil, fmt.Errorf(fmt.Sprintf("Failed to find block due to: %v", err))
-			}
-			switch msg := response.(type) {
-			case *protobuf.ChildBlockMessage:
-				mcb := msg
-				vote := mcb.GetVote()
-				if vote != nil {
-					// Increment the vote count of validator group
-					voteCount++
-
-					var cbhash cmn.HexBytes
-					cbhash = mcb.GetChildBlock().GetHeader().GetBlockID().GetBlockHash()
-					voteinfo := s.VoteInfoData[cbhash.String()]
-					voteinfo = append(voteinfo, vote)
-					s.VoteInfoData[cbhash.String()] = voteinfo
-
-					sign := vote.GetSignature()
-					var pubKey cryptokey.PubKey
-
-					cdc.UnmarshalBinaryBare(vote.GetValidator().GetPubKey(), &pubKey)
-
-					isVerified := pubKey.VerifyBytes(vote.GetValidator().GetPubKey(), sign)
-
-					isChildBlockSigned := mcb.GetVote().GetSignedCurrentBlock()
-
-					// Check whether Childblock is verified and signed by the validator
-					if isChildBlockSigned && isVerified {
-						mx := s.GetMutex()
-						mx.Lock()
-						s.ValidatorChildblock[address] = mcb.GetChildBlock().GetHeader().GetBlockID()
-						mx.Unlock()
-						log.Printf("<%s> Validator verified and signed the child block: %v", address, isVerified)
-						s.ChildBlock = append(s.ChildBlock, mcb.GetChildBlock())
-					}
-				}
+			if vote, ok := votes[address]; ok {
+				s.VoteInfoData[cbhash.String()] = append(s.VoteInfoData[cbhash.String()], vote)
+				s.ValidatorChildblock[address] = cb.GetHeader().GetBlockID()
 			}
 		}
+		mx.Unlock()
+	}
+	if len(committed) == 0 {
+		return nil, nil, nil
 	}
 
-	if voteCount == len(s.Validator) {
-		baseBlock, err := s.CreateBaseBlock(lastBlock)
+	// The txs of the child blocks which aren't committed are left out of
+	// the state, to be included in a later block
+	if len(committed) < len(txs) {
+		sort.Ints(committed)
+		stateTrie, err = statedb.NewTrie(common.BytesToHash(preStateRoot))
 		if err != nil {
-			return nil, err
+			return nil, nil, fmt.Errorf("failed to reload state before txs: %v", err)
 		}
-		return baseBlock, nil
+		committedTxs := make(txbyte.Txs, 0, len(committed))
+		for _, i := range committed {
+			committedTxs = append(committedTxs, txs[i])
+		}
+		if _, err := s.updateStateForTxs(&committedTxs, stateTrie, height); err != nil {
+			return nil, nil, fmt.Errorf("failed to update state for committed txs: %v", err)
+		}
+	}
+	if err := s.stageState(stateTrie); err != nil {
+		return nil, nil, err
 	}
+	s.balanceUpdates = updates
 
-	return nil, nil
+	baseBlock, err := s.CreateBaseBlock(lastBlock)
+	if err != nil {
+		return nil, nil, err
+	}
+	return baseBlock, committed, nil
 }
 
-func (s *Supervisor) updateStateForTxs(txs *txbyte.Txs, stateTrie statedb.Trie) (*transaction.TxList, error) {
-	txStr := transaction.Tx{}
-	txlist := &transaction.TxList{}
-	tx := pluginproto.Tx{}
-	for i, txbz := range *txs {
-		err := cdc.UnmarshalJSON(txbz, &txStr)
-		if err != nil {
-			return nil, fmt.Errorf("unable to unmarshal tx: %v", err)
-		}
-
-		err = cdc.UnmarshalJSON(txbz, &tx)
-		if err != nil {
-			log.Printf("Failed to Unmarshal tx: %v", err)
-			continue
-		}
+// collectVotes sends a child block to the validators of a group and returns
+// the valid votes for it by validator address
+func (s *Supervisor) collectVotes(net *network.Network, validators []string, cbmsg *protobuf.ChildBlockMessage) map[string]*protobuf.VoteInfo {
+	var cbhash cmn.HexBytes = cbmsg.GetChildBlock().GetHeader().GetBlockID().GetBlockHash()
+	ctx := network.WithRetryPolicy(network.WithSignMessage(context.Background(), true), childBlockRetryPolicy)
+	ctx, cancel := context.WithTimeout(ctx, childBlockTimeout)
+	defer cancel()
+	responses, err := net.RequestAll(ctx, validators, cbmsg, 0)
+	if err != nil {
+		log.Printf("Not every validator of the group responded to the child block %v: %v", cbhash, err)
+	}
 
-		// Get the public key of the sender
-		senderAddress := tx.GetSenderAddress()
-		pubKeyS, err := b64.StdEncoding.DecodeString(tx.GetSenderPubkey())
-		if err != nil {
-			log.Printf("Failed to decode sender public key: %v", err)
-			plog.Error().Msgf("Failed to decode sender public key: %v", err)
-			tx.Status = "failed"
-			txbz, err = cdc.MarshalJSON(&tx)
-			(*txs)[i] = txbz
-			txStr.Status = tx.Status
-			txlist.Transactions = append(txlist.Transactions, &txStr)
-			if err != nil {
-				log.Printf("Failed to encode failed tx: %v", err)
-				plog.Error().Msgf("Failed to encode failed tx: %v", err)
-			}
+	votes := make(map[string]*protobuf.VoteInfo)
+	for _, res := range responses {
+		address := res.Address
+		if res.Err != nil {
+			log.Printf("<%s> Failed to send the child block %v: %v", address, cbhash, res.Err)
 			continue
 		}
-
-		var pubKey secp256k1.PubKeySecp256k1
-		copy(pubKey[:], pubKeyS)
-
-		// Verify the signature
-		// if verification failed update the tx status as failed tx.
-		//Recreate the TX
-		asset := &pluginproto.Asset{
-			Category:              tx.Asset.Category,
-			Symbol:                tx.Asset.Symbol,
-			Network:               tx.Asset.Network,
-			Value:                 tx.Asset.Value,
-			Fee:                   tx.Asset.Fee,
-			Nonce:                 tx.Asset.Nonce,
-			ExternalSenderAddress: tx.Asset.ExternalSenderAddress,
-			LockedAmount:          tx.Asset.LockedAmount,
-			RedeemedAmount:        tx.Asset.RedeemedAmount,
-		}
-		verifiableTx := pluginproto.Tx{
-			SenderAddress:   tx.SenderAddress,
-			SenderPubkey:    tx.SenderPubkey,
-			RecieverAddress: tx.RecieverAddress,
-			Asset:           asset,
-			Message:         tx.Message,
-			Type:            tx.Type,
-		}
-
-		txbBeforeSign, err := json.Marshal(verifiableTx)
-
-		if err != nil {
-			plog.Error().Msgf("Failed to marshal the transaction to verify sign: %v", err)
-			log.Printf("Failed to marshal the transaction to verify sign: %v", err)
+		msg, ok := res.Message.(*protobuf.ChildBlockMessage)
+		if !ok {
 			continue
 		}
-
-		decodedSig, err := b64.StdEncoding.DecodeString(tx.Sign)
-
-		if err != nil {
-			plog.Error().Msgf("Failed to decode the base64 sign to verify sign: %v", err)
-			log.Printf("Failed to decode the base64 sign to verify sign: %v", err)
+		vote := msg.GetVote()
+		if vote == nil || !vote.GetSignedCurrentBlock() {
+			log.Printf("<%s> Validator rejected the child block: %v", address, cbhash)
 			continue
 		}
-
-		signVerificationRes := pubKey.VerifyBytes(txbBeforeSign, decodedSig)
-		if !signVerificationRes {
-			plog.Error().Msgf("Signature Verification Failed: %v", signVerificationRes)
-			log.Printf("Signature Verification Failed: %v", signVerificationRes)
-			tx.Status = "failed"
-			txbz, err = cdc.MarshalJSON(&tx)
-			(*txs)[i] = txbz
-			txStr.Status = tx.Status
-			txlist.Transactions = append(txlist.Transactions, &txStr)
-			if err != nil {
-				plog.Error().Msgf("Failed to encode failed tx: %v", err)
-				log.Printf("Failed to encode failed tx: %v", err)
+		if err := s.verifyVote(address, cbhash, vote); err != nil {
+			log.Printf("<%s> Invalid vote for the child block %v: %v", address, cbhash, err)
+			if validator, err := net.Client(address); err == nil {
+				net.ReportMisbehavior(validator.RemotePublicKey(), address, network.InvalidVote)
 			}
 			continue
 		}
-		var senderAccount statedb.Account
-		senderAddressBytes := []byte(senderAddress)
+		votes[address] = vote
+		log.Printf("<%s> Validator verified and signed the child block: %v", address, cbhash)
+	}
+	return votes
+}
 
-		// Get account details from state trie
-		senderActbz, err := stateTrie.TryGet(senderAddressBytes)
-		if err != nil {
-			plog.Error().Msgf("Failed to retrieve account detail: %v", err)
-			log.Printf("Failed to retrieve account detail: %v", err)
+// hasQuorum returns true if the validators who voted hold over 2/3 of the
+// staking power of the group
+func (s *Supervisor) hasQuorum(validators []string, votes map[string]*protobuf.VoteInfo) bool {
+	s.writerMutex.Lock()
+	defer s.writerMutex.Unlock()
+	var total, signed int64
+	for _, address := range validators {
+		stake := s.Validator[address].GetStakingpower()
+		if stake <= 0 {
 			continue
 		}
-
-		if len(senderActbz) > 0 {
-			err = cdc.UnmarshalJSON(senderActbz, &senderAccount)
-			if err != nil {
-				log.Printf("Failed to Unmarshal account: %v", err)
-				plog.Error().Msgf("Failed to Unmarshal account: %v", err)
-				continue
-			}
+		total += stake
+		if _, ok := votes[address]; ok {
+			signed += stake
 		}
+	}
+	return total > 0 && 3*signed > 2*total
+}
 
-		// Check if tx is of type account update
-		if strings.EqualFold(tx.Type, "External") {
-			symbol := tx.Asset.Symbol
-			if symbol != "BTC" && symbol != "ETH" && symbol != "HBTC" && symbol != "XTZ" {
-				log.Printf("Unsupported external asset symbol: %v", symbol)
-				plog.Error().Msgf("Unsupported external asset symbol: %v", symbol)
-				continue
-			}
-
-			// By default each of the new accounts will have HER token (with balance 0)
-			// added to the map object balances
-			balance := senderAccount.EBalances[symbol][tx.Asset.ExternalSenderAddress]
-			if balance == (statedb.EBalance{}) {
-				plog.Error().Msgf("Sender has no assets for the given symbol: %v", symbol)
-				log.Printf("Sender has no assets for the given symbol: %v", symbol)
-				continue
-			}
-			if balance.Balance < tx.Asset.Value {
-				plog.Error().Msgf("Sender does not have enough assets in account (%d) to send transaction amount (%d)", balance.Balance, tx.Asset.Value)
-				log.Printf("Sender does not have enough assets in account (%d) to send transaction amount (%d)", balance.Balance, tx.Asset.Value)
-				continue
-			}
-			balance.Balance -= tx.Asset.Value
-
-			senderAccount.Nonce = tx.Asset.Nonce
-			senderAccount.EBalances[symbol][tx.Asset.ExternalSenderAddress] = balance
-
-			sactbz, err := cdc.MarshalJSON(senderAccount)
-			if err != nil {
-				log.Printf("Failed to Marshal sender's account: %v", err)
-				plog.Error().Msgf("Failed to Marshal sender's account: %v", err)
-				continue
-			}
-			addressBytes := []byte(pubKey.GetAddress())
-			err = stateTrie.TryUpdate(addressBytes, sactbz)
-			if err != nil {
-				log.Printf("Failed to store account in state db: %v", err)
-				plog.Error().Msgf("Failed to store account in state db: %v", err)
-				tx.Status = "failed"
-				txbz, err = cdc.MarshalJSON(&tx)
-				(*txs)[i] = txbz
-				if err != nil {
-					log.Printf("Failed to encode failed tx: %v", err)
-					plog.Error().Msgf("Failed to encode failed tx: %v", err)
-				}
-			}
-			tx.Status = "success"
-			txbz, err = cdc.MarshalJSON(&tx)
-			txStr.Status = tx.Status
-			txlist.Transactions = append(txlist.Transactions, &txStr)
-			(*txs)[i] = txbz
-			if err != nil {
-				log.Printf("Failed to encode failed tx: %v", err)
-				plog.Error().Msgf("Failed to encode failed tx: %v", err)
-			}
-
-			continue
-
-		} else if strings.EqualFold(tx.Type, "Update") ||
-			strings.EqualFold(tx.Type, "Lock") ||
-			strings.EqualFold(tx.Type, "Redeem") {
-
-			switch txType := strings.ToUpper(tx.Type); txType {
-			case "UPDATE":
-				senderAccount = *(updateAccount(&senderAccount, &tx))
-			case "LOCK":
-				senderAccount = *(updateAccountLockedBalance(&senderAccount, &tx))
-			case "REDEEM":
-				senderAccount = *(updateRedeemAccountLockedBalance(&senderAccount, &tx))
-			}
+func (s *Supervisor) verifyVote(address string, blockHash []byte, vote *protobuf.VoteInfo) error {
+	s.writerMutex.Lock()
+	validator, ok := s.Validator[address]
+	s.writerMutex.Unlock()
+	if !ok {
+		return fmt.Errorf("unknown validator: %v", address)
+	}
+	if !bytes.Equal(validator.GetPubKey(), vote.GetValidator().GetPubKey()) {
+		return fmt.Errorf("vote is not from the validator's key")
+	}
+	var pubKey cryptokey.PubKey
+	if err := cdc.UnmarshalBinaryBare(vote.GetValidator().GetPubKey(), &pubKey); err != nil {
+		return fmt.Errorf("failed to decode validator public key: %v", err)
+	}
+	if !pubKey.VerifyBytes(blockHash, vote.GetSignature()) {
+		return fmt.Errorf("vote signature verification failed")
+	}
+	return nil
+}
 
-			sactbz, err := cdc.MarshalJSON(senderAccount)
-			if err != nil {
-				log.Printf("Failed to Marshal sender's account: %v", err)
-				plog.Error().Msgf("Failed to Marshal sender's account: %v", err)
-				continue
-			}
-			addressBytes := []byte(pubKey.GetAddress())
-			err = stateTrie.TryUpdate(addressBytes, sactbz)
-			if err != nil {
-				log.Printf("Failed to store account in state db: %v", err)
-				plog.Error().Msgf("Failed to store account in state db: %v", err)
-				tx.Status = "failed"
-				txbz, err = cdc.MarshalJSON(&tx)
-				(*txs)[i] = txbz
-				txStr.Status = tx.Status
-				txlist.Transactions = append(txlist.Transactions, &txStr)
-				if err != nil {
-					log.Printf("Failed to encode failed tx: %v", err)
-					plog.Error().Msgf("Failed to encode failed tx: %v", err)
-				}
-			}
-			tx.Status = "success"
-			txbz, err = cdc.MarshalJSON(&tx)
-			(*txs)[i] = txbz
-			txStr.Status = tx.Status
-			txlist.Transactions = append(txlist.Transactions, &txStr)
-			if err != nil {
-				log.Printf("Failed to encode failed tx: %v", err)
-				plog.Error().Msgf("Failed to encode failed tx: %v", err)
-			}
+// accountProofs creates merkle proofs of all the sender and receiver accounts of txs
+func accountProofs(txs txbyte.Txs, stateTrie statedb.Trie) (map[string]*protobuf.AccountProof, error) {
+	proofs := make(map[string]*protobuf.AccountProof)
+	for _, txbz := range txs {
+		tx := pluginproto.Tx{}
+		if err := cdc.UnmarshalJSON(txbz, &tx); err != nil {
 			continue
 		}
-
-		if strings.EqualFold(tx.Asset.Network, "Herdius") {
-
-			// Verify if sender has an address for corresponding external asset
-			if !strings.EqualFold(tx.Asset.Symbol, "HER") &&
-				!isExternalAssetAddressExist(&senderAccount, tx.Asset.Symbol, tx.Asset.ExternalSenderAddress) {
-				tx.Status = "failed"
-				txbz, err = cdc.MarshalJSON(&tx)
-				(*txs)[i] = txbz
-				txStr.Status = tx.Status
-				txlist.Transactions = append(txlist.Transactions, &txStr)
-				if err != nil {
-					log.Printf("Failed to encode failed tx: %v", err)
-					plog.Error().Msgf("Failed to encode failed tx: %v", err)
-				}
+		for _, address := range []string{tx.GetSenderAddress(), tx.GetRecieverAddress()} {
+			if _, ok := proofs[address]; ok || len(address) == 0 {
 				continue
 			}
-
-			// Get Reciever's Account
-			rcvrAddressBytes := []byte(tx.RecieverAddress)
-			rcvrActbz, _ := stateTrie.TryGet(rcvrAddressBytes)
-
-			var rcvrAccount statedb.Account
-
-			err = cdc.UnmarshalJSON(rcvrActbz, &rcvrAccount)
-
+			proof, err := stateTrie.Prove([]byte(address))
 			if err != nil {
-				log.Printf("Failed to Unmarshal receiver's account: %v", err)
-				plog.Error().Msgf("Failed to Unmarshal receiver's account: %v", err)
-				continue
+				return nil, fmt.Errorf("failed to prove account %v: %v", address, err)
 			}
+			proofs[address] = &protobuf.AccountProof{Address: address, Proof: proof}
+		}
+	}
+	return proofs, nil
+}
 
-			// Verify if Receiver has an address for corresponding external asset
-			if !strings.EqualFold(tx.Asset.Symbol, "HER") && len(rcvrAccount.EBalances[tx.Asset.Symbol]) == 0 {
-				tx.Status = "failed"
-				txbz, err = cdc.MarshalJSON(&tx)
-				(*txs)[i] = txbz
-				txStr.Status = tx.Status
-				txlist.Transactions = append(txlist.Transactions, &txStr)
-				if err != nil {
-					log.Printf("Failed to encode failed tx: %v", err)
-					plog.Error().Msgf("Failed to encode failed tx: %v", err)
-				}
+// groupAccountProofs picks the proofs of the accounts touched by txs
+func groupAccountProofs(txs []*transaction.Tx, proofs map[string]*protobuf.AccountProof) []*protobuf.AccountProof {
+	groupProofs := make([]*protobuf.AccountProof, 0)
+	added := make(map[string]bool)
+	for _, tx := range txs {
+		for _, address := range []string{tx.SenderAddress, tx.ReceiverAddress} {
+			proof, ok := proofs[address]
+			if !ok || added[address] {
 				continue
 			}
+			added[address] = true
+			groupProofs = append(groupProofs, proof)
+		}
+	}
+	return groupProofs
+}
 
-			// TODO: Deduct Fee from Sender's Account when HER Fee is applied
-			//Withdraw fund from Sender Account
-			withdraw(&senderAccount, tx.Asset.Symbol, tx.Asset.ExternalSenderAddress, tx.Asset.Value)
-
-			// Credit Reciever's Account
-			// If credit to external address, pick first account
-			// TODO: Should we consider tx.Asset.ExternalRecieverAddress?
-			deposit(&rcvrAccount, tx.Asset.Symbol, rcvrAccount.FirstExternalAddress[tx.Asset.Symbol], tx.Asset.Value)
-
-			senderAccount.Nonce = tx.Asset.Nonce
-			updatedSenderAccount, err := cdc.MarshalJSON(senderAccount)
-			if err != nil {
-				log.Printf("Failed to Marshal sender's account: %v", err)
-				plog.Error().Msgf("Failed to Marshal sender's account: %v", err)
-			}
-
-			err = stateTrie.TryUpdate(senderAddressBytes, updatedSenderAccount)
-			if err != nil {
-				log.Printf("Failed to update sender's account in state db: %v", err)
-				plog.Error().Msgf("Failed to update sender's account in state db: %v", err)
-			}
-
-			updatedRcvrAccount, err := cdc.MarshalJSON(rcvrAccount)
-
-			if err != nil {
-				log.Printf("Failed to Marshal receiver's account: %v", err)
-				plog.Error().Msgf("Failed to Marshal receiver's account: %v", err)
-			}
-
-			err = stateTrie.TryUpdate(rcvrAddressBytes, updatedRcvrAccount)
-			if err != nil {
-				log.Printf("Failed to update receiver's account in state db: %v", err)
-				plog.Error().Msgf("Failed to update receiver's account in state db: %v", err)
-			}
+// updateStateForTxs applies txs to stateTrie as the txs of the block at
+// height, marks each tx with its status and records its receipt. The
+// returned list holds a tx for each of txs. The state is left to be staged by
+// the caller.
+func (s *Supervisor) updateStateForTxs(txs *txbyte.Txs, stateTrie statedb.Trie, height int64) (*transaction.TxList, error) {
+	txlist := &transaction.TxList{}
+	fees := uint64(0)
+	receipts := make([][]byte, 0, len(*txs))
+	for i, txbz := range *txs {
+		txStr := transaction.Tx{}
+		tx := pluginproto.Tx{}
+		err := cdc.UnmarshalJSON(txbz, &txStr)
+		if err != nil {
+			return nil, fmt.Errorf("unable to unmarshal tx: %v", err)
+		}
 
-			// TODO: Fee should be credit to intended recipient
+		receipt := &pluginproto.Receipt{}
+		err = cdc.UnmarshalJSON(txbz, &tx)
+		if err != nil {
+			log.Printf("Failed to Unmarshal tx: %v", err)
+			receipt.Status = transition.StatusFailed
+			receipt.Code = transition.CodeInvalidTx
+			receipt.Message = fmt.Sprintf("failed to decode tx: %v", err)
+			receipt.StateRoot = stateTrie.Hash()
+			receipts = append(receipts, encodeReceipt(receipt))
+			txStr.Status = transition.StatusFailed
+			txlist.Transactions = append(txlist.Transactions, &txStr)
+			continue
 		}
 
-		// Mark the tx as success and
-		// add the updated tx to batch that will finally be added to singular block
-		tx.Status = "success"
+		result, err := applyBlockTx(stateTrie, &tx, height, fees)
+		if err != nil {
+			log.Printf("Failed to apply tx: %v", err)
+			plog.Error().Msgf("Failed to apply tx: %v", err)
+		}
+		if fees, err = transition.AddBalance(fees, result.Fee); err != nil {
+			return nil, fmt.Errorf("failed to add up fees: %v", err)
+		}
+		receipt.TxId = blockchain.TxIDWithoutStatus(&tx)
+		receipt.Status = result.Status
+		receipt.Code = result.Code
+		receipt.Message = result.Message
+		receipt.Fee = result.Fee
+		receipt.StateRoot = stateTrie.Hash()
+		receipts = append(receipts, encodeReceipt(receipt))
+
+		// Add the tx marked with its status to batch that will finally be added to the block
+		tx.Status = result.Status
 		txbz, err = cdc.MarshalJSON(&tx)
-		(*txs)[i] = txbz
-		txStr.Status = tx.Status
-		txlist.Transactions = append(txlist.Transactions, &txStr)
 		if err != nil {
-			log.Printf("Failed to encode failed tx: %v", err)
-			plog.Error().Msgf("Failed to encode failed tx: %v", err)
+			log.Printf("Failed to encode tx: %v", err)
+			plog.Error().Msgf("Failed to encode tx: %v", err)
+		} else {
+			(*txs)[i] = txbz
 		}
+		txStr.Status = tx.Status
+		txlist.Transactions = append(txlist.Transactions, &txStr)
+	}
 
+	if err := s.creditFees(stateTrie, fees); err != nil {
+		return nil, fmt.Errorf("failed to credit fees: %v", err)
 	}
+	s.fees = fees
+	s.receipts = receipts
+	return txlist, nil
+}
 
-	root, err := stateTrie.Commit(nil)
+// applyBlockTx applies tx to stateTrie as a tx of the block at height, whose
+// txs so far collected fees. A tx whose fee would overflow the fees of the
+// block is rejected before it is applied, as its fee couldn't be credited.
+func applyBlockTx(stateTrie statedb.Trie, tx *pluginproto.Tx, height int64, fees uint64) (transition.Receipt, error) {
+	if _, err := transition.AddBalance(fees, tx.GetAsset().GetFee()); err != nil {
+		err = fmt.Errorf("fees of the block: %w", err)
+		return transition.Receipt{Status: transition.StatusFailed, Code: transition.ErrorCode(err), Message: err.Error()}, err
+	}
+	return transition.ApplyTx(stateTrie, tx, height)
+}
+
+// encodeReceipt encodes receipt to be stored in the block
+func encodeReceipt(receipt *pluginproto.Receipt) []byte {
+	receiptbz, err := cdc.MarshalJSON(receipt)
 	if err != nil {
-		log.Println("Failed to commit to state trie:", err)
-		plog.Error().Msgf("Failed to commit to state trie: %v", err)
+		plog.Error().Msgf("Failed to encode receipt: %v", err)
 	}
-	s.SetStateRoot(root)
-	return txlist, nil
+	return receiptbz
 }
@@ -1,6 +1,7 @@
 package service
 
 import (
+	"context"
 	"fmt"
 	"io/ioutil"
 	"math"
@@ -8,255 +9,33 @@ import (
 	"os"
 	"testing"
 
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/herdius/herdius-core/blockchain"
 	"github.com/herdius/herdius-core/blockchain/protobuf"
+	cmn "github.com/herdius/herdius-core/libs/common"
+	cryptokeys "github.com/herdius/herdius-core/p2p/crypto"
+	"github.com/herdius/herdius-core/p2p/network"
+	"github.com/herdius/herdius-core/p2p/network/transport"
+	"github.com/herdius/herdius-core/p2p/types/opcode"
 	"github.com/herdius/herdius-core/storage/db"
 	"github.com/herdius/herdius-core/storage/state/statedb"
+	"github.com/herdius/herdius-core/types"
+	val "github.com/herdius/herdius-core/validator/service"
 
 	ed25519 "github.com/herdius/herdius-core/crypto/ed"
 	pluginproto "github.com/herdius/herdius-core/hbi/protobuf"
 
 	"github.com/herdius/herdius-core/crypto/secp256k1"
 	"github.com/herdius/herdius-core/supervisor/transaction"
+	"github.com/herdius/herdius-core/transition"
 
 	external "github.com/herdius/herdius-core/storage/exbalance"
 
 	txbyte "github.com/herdius/herdius-core/tx"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
-func TestRegisterNewHERAddress(t *testing.T) {
-	asset := &pluginproto.Asset{
-		Symbol: "HER",
-	}
-	tx := &pluginproto.Tx{
-		SenderAddress: "HHy1CuT3UxCGJ3BHydLEvR5ut6HRy2qUvm",
-		Asset:         asset,
-		Type:          "update",
-	}
-	account := &statedb.Account{}
-	account = updateAccount(account, tx)
-	assert.Equal(t, tx.SenderAddress, account.Address)
-}
-
-func TestUpdateHERAccountBalance(t *testing.T) {
-	asset := &pluginproto.Asset{
-		Symbol: "HER",
-	}
-	tx := &pluginproto.Tx{
-		SenderAddress: "HHy1CuT3UxCGJ3BHydLEvR5ut6HRy2qUvm",
-		Asset:         asset,
-		Type:          "update",
-	}
-	account := &statedb.Account{}
-	account = updateAccount(account, tx)
-	assert.Equal(t, tx.SenderAddress, account.Address)
-	assert.Equal(t, account.Balance, uint64(0))
-
-	// Update 10 HER tokens to existing HER Account
-	asset = &pluginproto.Asset{
-		Symbol: "HER",
-		Value:  10,
-		Nonce:  2,
-	}
-	tx = &pluginproto.Tx{
-		SenderAddress: "HHy1CuT3UxCGJ3BHydLEvR5ut6HRy2qUvm",
-		Asset:         asset,
-		Type:          "update",
-	}
-	account = updateAccount(account, tx)
-	assert.Equal(t, tx.SenderAddress, account.Address)
-	assert.Equal(t, account.Balance, uint64(10))
-	assert.Equal(t, account.Nonce, uint64(2))
-}
-
-func TestRegisterNewETHAddress(t *testing.T) {
-	symbol := "ETH"
-	extSenderAddress := "0xD8f647855876549d2623f52126CE40D053a2ef6A"
-	asset := &pluginproto.Asset{
-		Symbol:                symbol,
-		ExternalSenderAddress: extSenderAddress,
-		Nonce:                 1,
-		Network:               "Herdius",
-	}
-	tx := &pluginproto.Tx{
-		SenderAddress: "HHy1CuT3UxCGJ3BHydLEvR5ut6HRy2qUvm",
-		Asset:         asset,
-		Type:          "update",
-	}
-	account := &statedb.Account{
-		Address:              "HHy1CuT3UxCGJ3BHydLEvR5ut6HRy2qUvm",
-		FirstExternalAddress: make(map[string]string),
-	}
-	account = updateAccount(account, tx)
-	assert.True(t, len(account.EBalances) > 0)
-	assert.Equal(t, tx.Asset.ExternalSenderAddress, account.EBalances[symbol][extSenderAddress].Address)
-	assert.Equal(t, extSenderAddress, account.FirstExternalAddress[symbol])
-}
-
-func TestRegisterMultipleExternalAssets(t *testing.T) {
-	symbol := "ETH"
-	extSenderAddress := " 0xD8f647855876549d2623f52126CE40D053a2ef6A"
-	// First add ETH
-	asset := &pluginproto.Asset{
-		Symbol:                symbol,
-		ExternalSenderAddress: extSenderAddress,
-		Nonce:                 1,
-		Network:               "Herdius",
-	}
-	tx := &pluginproto.Tx{
-		SenderAddress: "HHy1CuT3UxCGJ3BHydLEvR5ut6HRy2qUvm",
-		Asset:         asset,
-		Type:          "update",
-	}
-	account := &statedb.Account{
-		Address:              "HHy1CuT3UxCGJ3BHydLEvR5ut6HRy2qUvm",
-		FirstExternalAddress: make(map[string]string),
-	}
-	account = updateAccount(account, tx)
-	assert.True(t, len(account.EBalances) == 1)
-	assert.True(t, len(account.EBalances[symbol]) == 1)
-	assert.Equal(t, tx.Asset.ExternalSenderAddress, account.EBalances[symbol][extSenderAddress].Address)
-	assert.Equal(t, extSenderAddress, account.FirstExternalAddress[symbol])
-
-	newSymbol := "BTC"
-	newExtSenderAddress := "Bitcoin-Address"
-	// Second add BTC
-	asset = &pluginproto.Asset{
-		Symbol:                newSymbol,
-		ExternalSenderAddress: newExtSenderAddress,
-		Nonce:                 2,
-		Network:               "Herdius",
-	}
-	tx = &pluginproto.Tx{
-		SenderAddress: "HHy1CuT3UxCGJ3BHydLEvR5ut6HRy2qUvm",
-		Asset:         asset,
-		Type:          "update",
-	}
-
-	account = updateAccount(account, tx)
-	assert.True(t, len(account.EBalances) == 2)
-	assert.True(t, len(account.EBalances[newSymbol]) == 1)
-	assert.Equal(t, tx.Asset.ExternalSenderAddress, account.EBalances[newSymbol][newExtSenderAddress].Address)
-	assert.Equal(t, newExtSenderAddress, account.FirstExternalAddress[newSymbol])
-
-	// Tezos support
-	newXTZSymbol := "XTZ"
-	newTezosExtSenderAddress := "Tezos-Address"
-	// Third add XTZ
-	asset = &pluginproto.Asset{
-		Symbol:                newXTZSymbol,
-		ExternalSenderAddress: newTezosExtSenderAddress,
-		Nonce:                 3,
-		Network:               "Herdius",
-	}
-	tx = &pluginproto.Tx{
-		SenderAddress: "HHy1CuT3UxCGJ3BHydLEvR5ut6HRy2qUvm",
-		Asset:         asset,
-		Type:          "update",
-	}
-
-	account = updateAccount(account, tx)
-	assert.True(t, len(account.EBalances) == 3)
-	assert.True(t, len(account.EBalances[newXTZSymbol]) == 1)
-	assert.Equal(t, tx.Asset.ExternalSenderAddress, account.EBalances[newXTZSymbol][newTezosExtSenderAddress].Address)
-	assert.Equal(t, newTezosExtSenderAddress, account.FirstExternalAddress[newXTZSymbol])
-}
-
-func TestUpdateExternalAccountBalance(t *testing.T) {
-	symbol := "ETH"
-	extSenderAddress := "0xD8f647855876549d2623f52126CE40D053a2ef6A"
-	asset := &pluginproto.Asset{
-		Symbol:                symbol,
-		ExternalSenderAddress: extSenderAddress,
-		Nonce:                 1,
-		Network:               "Herdius",
-	}
-	tx := &pluginproto.Tx{
-		SenderAddress: "HHy1CuT3UxCGJ3BHydLEvR5ut6HRy2qUvm",
-		Asset:         asset,
-		Type:          "update",
-	}
-	account := &statedb.Account{
-		Address:              "HHy1CuT3UxCGJ3BHydLEvR5ut6HRy2qUvm",
-		FirstExternalAddress: make(map[string]string),
-	}
-	account = updateAccount(account, tx)
-	assert.True(t, len(account.EBalances) > 0)
-	assert.Equal(t, extSenderAddress, account.FirstExternalAddress[symbol])
-	assert.Equal(t, tx.Asset.ExternalSenderAddress, account.EBalances[symbol][extSenderAddress].Address)
-	assert.Equal(t, tx.Asset.ExternalSenderAddress, account.FirstExternalAddress[symbol])
-
-	asset = &pluginproto.Asset{
-		Symbol:                symbol,
-		ExternalSenderAddress: extSenderAddress,
-		Nonce:                 2,
-		Network:               "Herdius",
-		Value:                 15,
-	}
-	tx = &pluginproto.Tx{
-		SenderAddress: "HHy1CuT3UxCGJ3BHydLEvR5ut6HRy2qUvm",
-		Asset:         asset,
-		Type:          "update",
-	}
-
-	account = updateAccount(account, tx)
-	assert.True(t, len(account.EBalances) > 0)
-	assert.Equal(t, tx.Asset.ExternalSenderAddress, account.EBalances[symbol][extSenderAddress].Address)
-	assert.Equal(t, uint64(0), account.EBalances[symbol][extSenderAddress].Balance)
-	assert.Equal(t, tx.Asset.ExternalSenderAddress, account.FirstExternalAddress[symbol])
-
-}
-
-func TestIsExternalAssetAddressExistTrue(t *testing.T) {
-	addr := "0xD8f647855876549d2623f52126CE40D053a2ef6A"
-	eBal := statedb.EBalance{Address: addr}
-	eBals := make(map[string]map[string]statedb.EBalance)
-	eBals["ETH"] = make(map[string]statedb.EBalance)
-	eBals["ETH"][addr] = eBal
-	account := &statedb.Account{
-		Address:   "HHy1CuT3UxCGJ3BHydLEvR5ut6HRy2qUvm",
-		EBalances: eBals,
-	}
-	assert.True(t, isExternalAssetAddressExist(account, "ETH", addr))
-}
-func TestIsExternalAssetAddressExistFalse(t *testing.T) {
-	addr := "0xD8f647855876549d2623f52126CE40D053a2ef6A"
-	eBals := make(map[string]map[string]statedb.EBalance)
-	account := &statedb.Account{
-		Address:   "HHy1CuT3UxCGJ3BHydLEvR5ut6HRy2qUvm",
-		EBalances: eBals,
-	}
-	assert.False(t, isExternalAssetAddressExist(account, "ETH", addr))
-}
-
-func TestExternalAssetWithdrawFromAnAccount(t *testing.T) {
-	addr := "0xD8f647855876549d2623f52126CE40D053a2ef6A"
-	eBal := statedb.EBalance{Balance: 10, Address: addr}
-	eBals := make(map[string]map[string]statedb.EBalance)
-	eBals["ETH"] = make(map[string]statedb.EBalance)
-	eBals["ETH"][addr] = eBal
-	account := &statedb.Account{
-		Address:   "HHy1CuT3UxCGJ3BHydLEvR5ut6HRy2qUvm",
-		EBalances: eBals,
-	}
-	withdraw(account, "ETH", addr, 5)
-	assert.Equal(t, uint64(5), account.EBalances["ETH"][addr].Balance)
-}
-
-func TestExternalAssetDepositToAnAccount(t *testing.T) {
-	addr := "0xD8f647855876549d2623f52126CE40D053a2ef6A"
-	eBal := statedb.EBalance{Balance: 10, Address: addr}
-	eBals := make(map[string]map[string]statedb.EBalance)
-	eBals["ETH"] = make(map[string]statedb.EBalance)
-	eBals["ETH"][addr] = eBal
-	account := &statedb.Account{
-		Address:   "HHy1CuT3UxCGJ3BHydLEvR5ut6HRy2qUvm",
-		EBalances: eBals,
-	}
-	deposit(account, "ETH", addr, 5)
-	assert.Equal(t, uint64(15), account.EBalances["ETH"][addr].Balance)
-}
-
 func TestRemoveValidator(t *testing.T) {
 	supsvc := &Supervisor{}
 	supsvc.SetWriteMutex()
@@ -421,6 +200,7 @@ func TestUpdateStateWithNewExternalBalance(t *testing.T) {
 	accountStorage = external.NewDB(badgerdb)
 	defer func() {
 		badgerdb.Close()
+		accountStorage = nil
 		os.RemoveAll("./test.syncdb")
 	}()
 	currentExternalBal := make(map[string]*big.Int)
@@ -430,7 +210,7 @@ func TestUpdateStateWithNewExternalBalance(t *testing.T) {
 	lastExternalBal[asset] = big.NewInt(int64(0))
 
 	isFirstEntry := make(map[string]bool)
-	isFirstEntry[asset] = true
+	isFirstEntry[storageKey] = true
 
 	eBalance.Balance = uint64(math.Pow10(18))
 	eBalances[asset][extAddr] = eBalance
@@ -443,145 +223,25 @@ func TestUpdateStateWithNewExternalBalance(t *testing.T) {
 	}
 	accountStorage.Set(extAddr, herCacheAccount)
 
-	updateStateWithNewExternalBalance(trie)
+	updates := updateStateWithNewExternalBalance(trie)
+	assert.Len(t, updates, 1)
+	actbz, err := trie.TryGet([]byte(extAddr))
+	assert.NoError(t, err)
+	assert.Equal(t, updates[0].account, actbz)
 
+	// The update stays flagged until the block is stored
 	res, ok := accountStorage.Get(extAddr)
 	assert.True(t, ok)
+	assert.True(t, res.IsFirstEntry[storageKey])
+
+	clearBalanceUpdates(updates)
+	res, ok = accountStorage.Get(extAddr)
+	assert.True(t, ok)
 	assert.False(t, res.IsFirstEntry[storageKey])
 
 	defer os.RemoveAll(dir)
 }
 
-func TestUpdateAccountLockedBalance(t *testing.T) {
-	symbol := "ETH"
-	lockedAmount := uint64(10)
-	extSenderAddress := "0xD8f647855876549d2623f52126CE40D053a2ef6A"
-	asset := &pluginproto.Asset{
-		Symbol:                symbol,
-		ExternalSenderAddress: extSenderAddress,
-		Nonce:                 1,
-		Network:               "Herdius",
-		LockedAmount:          lockedAmount,
-	}
-	tx := &pluginproto.Tx{
-		SenderAddress: "HHy1CuT3UxCGJ3BHydLEvR5ut6HRy2qUvm",
-		Asset:         asset,
-		Type:          "lock",
-	}
-
-	extAddr := "0xD8f647855876549d2623f52126CE40D053a2ef6A"
-
-	eBalance := statedb.EBalance{
-		Address: extAddr,
-		Balance: 0,
-	}
-	eBalances := make(map[string]map[string]statedb.EBalance)
-	eBalances[symbol] = make(map[string]statedb.EBalance)
-	eBalances[symbol][extAddr] = eBalance
-
-	account := &statedb.Account{
-		Address:              "HHy1CuT3UxCGJ3BHydLEvR5ut6HRy2qUvm",
-		FirstExternalAddress: make(map[string]string),
-		EBalances:            eBalances,
-	}
-	account = updateAccountLockedBalance(account, tx)
-	assert.Equal(t, lockedAmount, account.LockedBalance[symbol][extSenderAddress])
-}
-func TestUpdateAccountLockedBalanceMintHBTCFirst(t *testing.T) {
-	symbol := "BTC"
-	lockedAmount := uint64(10)
-	extSenderAddress := "0xD8f647855876549d2623f52126CE40D053a2ef6A"
-	asset := &pluginproto.Asset{
-		Symbol:                symbol,
-		ExternalSenderAddress: extSenderAddress,
-		Nonce:                 1,
-		Network:               "Herdius",
-		LockedAmount:          lockedAmount,
-		Value:                 1,
-	}
-	tx := &pluginproto.Tx{
-		SenderAddress: "HHy1CuT3UxCGJ3BHydLEvR5ut6HRy2qUvm",
-		Asset:         asset,
-		Type:          "lock",
-	}
-
-	extAddr := "0xD8f647855876549d2623f52126CE40D053a2ef6A"
-
-	eBalance := statedb.EBalance{
-		Address: extAddr,
-		Balance: 0,
-	}
-	eBalances := make(map[string]map[string]statedb.EBalance)
-	eBalances[symbol] = make(map[string]statedb.EBalance)
-	eBalances[symbol][extAddr] = eBalance
-
-	account := &statedb.Account{
-		Address:              "HHy1CuT3UxCGJ3BHydLEvR5ut6HRy2qUvm",
-		FirstExternalAddress: make(map[string]string),
-		EBalances:            eBalances,
-	}
-	account.FirstExternalAddress["ETH"] = "0xD8f647855876549d2623f52126CE40D053a2ef6A"
-	account = updateAccountLockedBalance(account, tx)
-	assert.Equal(t, lockedAmount, account.LockedBalance[symbol][extSenderAddress])
-	assert.Equal(t, uint64(0), account.EBalances["HBTC"]["0xD8f647855876549d2623f52126CE40D053a2ef6A"].Balance)
-
-}
-
-func TestUpdateRedeemAccountLockedBalance(t *testing.T) {
-	symbol := "ETH"
-	lockedAmount := uint64(10)
-	extSenderAddress := "0xD8f647855876549d2623f52126CE40D053a2ef6A"
-	asset := &pluginproto.Asset{
-		Symbol:                symbol,
-		ExternalSenderAddress: extSenderAddress,
-		Nonce:                 1,
-		Network:               "Herdius",
-		LockedAmount:          lockedAmount,
-	}
-	tx := &pluginproto.Tx{
-		SenderAddress: "HHy1CuT3UxCGJ3BHydLEvR5ut6HRy2qUvm",
-		Asset:         asset,
-		Type:          "lock",
-	}
-
-	extAddr := "0xD8f647855876549d2623f52126CE40D053a2ef6A"
-
-	eBalance := statedb.EBalance{
-		Address: extAddr,
-		Balance: 0,
-	}
-	eBalances := make(map[string]map[string]statedb.EBalance)
-	eBalances[symbol] = make(map[string]statedb.EBalance)
-	eBalances[symbol][extAddr] = eBalance
-	account := &statedb.Account{
-		Address:              "HHy1CuT3UxCGJ3BHydLEvR5ut6HRy2qUvm",
-		FirstExternalAddress: make(map[string]string),
-		EBalances:            eBalances,
-	}
-	account = updateAccountLockedBalance(account, tx)
-	assert.Equal(t, lockedAmount, account.LockedBalance[symbol][extSenderAddress])
-
-	// Redeem test
-	symbol = "ETH"
-	value := uint64(5)
-	extSenderAddress = "0xD8f647855876549d2623f52126CE40D053a2ef6A"
-	asset = &pluginproto.Asset{
-		Symbol:                symbol,
-		ExternalSenderAddress: extSenderAddress,
-		Nonce:                 2,
-		Network:               "Herdius",
-		RedeemedAmount:        value,
-	}
-	tx = &pluginproto.Tx{
-		SenderAddress: "HHy1CuT3UxCGJ3BHydLEvR5ut6HRy2qUvm",
-		Asset:         asset,
-		Type:          "redeem",
-	}
-
-	account = updateRedeemAccountLockedBalance(account, tx)
-	assert.Equal(t, value, account.LockedBalance[symbol][extSenderAddress])
-}
-
 func TestValidatorGroups(t *testing.T) {
 	tests := []struct {
 		name                             string
@@ -607,7 +267,7 @@ func TestValidatorGroups(t *testing.T) {
 			for i := 1; i <= tc.numValidators; i++ {
 				supsvc.AddValidator([]byte{1}, fmt.Sprintf("validator-%d", i))
 			}
-			groups := supsvc.validatorGroups(tc.desiredNumGroups)
+			groups := supsvc.validatorGroups([]byte("seed"), tc.desiredNumGroups)
 			if len(groups) != tc.expectedNumGroups {
 				t.Errorf("Unexpected number of groups, want: %d, got: %d", tc.expectedNumGroups, len(groups))
 			}
@@ -624,6 +284,69 @@ func TestValidatorGroups(t *testing.T) {
 	}
 }
 
+func TestValidatorGroupsSeeded(t *testing.T) {
+	supsvc := &Supervisor{}
+	supsvc.SetWriteMutex()
+	for i := 1; i <= 20; i++ {
+		supsvc.AddValidator([]byte{1}, fmt.Sprintf("validator-%d", i))
+	}
+
+	groups := supsvc.validatorGroups([]byte("block-1"), 4)
+	assert.Equal(t, groups, supsvc.validatorGroups([]byte("block-1"), 4), "the same seed should give the same groups")
+	assert.NotEqual(t, groups, supsvc.validatorGroups([]byte("block-2"), 4), "another seed should give other groups")
+}
+
+func TestValidatorGroupsStake(t *testing.T) {
+	supsvc := &Supervisor{}
+	supsvc.SetWriteMutex()
+	for i := 1; i <= 9; i++ {
+		supsvc.AddValidator([]byte{1}, fmt.Sprintf("validator-%d", i))
+	}
+	// The validators holding most of the stake end up in different groups
+	for i := 1; i <= 3; i++ {
+		supsvc.Validator[fmt.Sprintf("validator-%d", i)].Stakingpower = 10000
+	}
+
+	for _, seed := range []string{"block-1", "block-2", "block-3"} {
+		groups := supsvc.validatorGroups([]byte(seed), 3)
+		require.Len(t, groups, 3)
+		for _, group := range groups {
+			assert.Len(t, group, 3)
+			var stake int64
+			for _, address := range group {
+				stake += supsvc.Validator[address].GetStakingpower()
+			}
+			assert.Equal(t, int64(10200), stake)
+		}
+	}
+}
+
+func TestNumGroups(t *testing.T) {
+	supsvc := &Supervisor{}
+	assert.Equal(t, 4, supsvc.numGroups(10), "groups default to 3 validators")
+	supsvc.SetNoOfPeersInGroup(5)
+	assert.Equal(t, 2, supsvc.numGroups(10))
+	assert.Equal(t, 3, supsvc.numGroups(11))
+	assert.Equal(t, 1, supsvc.numGroups(1))
+}
+
+func TestHasQuorum(t *testing.T) {
+	supsvc := &Supervisor{}
+	supsvc.SetWriteMutex()
+	for _, address := range []string{"a", "b", "c", "d"} {
+		supsvc.AddValidator([]byte{1}, address)
+	}
+	supsvc.Validator["a"].Stakingpower = 300
+	group := []string{"a", "b", "c", "d"}
+	vote := &protobuf.VoteInfo{}
+
+	assert.True(t, supsvc.hasQuorum(group, map[string]*protobuf.VoteInfo{"a": vote, "b": vote, "c": vote}), "500 of 600")
+	assert.False(t, supsvc.hasQuorum(group, map[string]*protobuf.VoteInfo{"a": vote, "b": vote}), "exactly 2/3 isn't a quorum")
+	assert.False(t, supsvc.hasQuorum(group, map[string]*protobuf.VoteInfo{"b": vote, "c": vote, "d": vote}), "300 of 600")
+	assert.False(t, supsvc.hasQuorum(group, map[string]*protobuf.VoteInfo{"a": vote}), "300 of 600")
+	assert.False(t, supsvc.hasQuorum(group, nil))
+}
+
 func TestTxsGroups(t *testing.T) {
 	tests := []struct {
 		name              string
@@ -642,6 +365,9 @@ func TestTxsGroups(t *testing.T) {
 			t.Parallel()
 			supsvc := &Supervisor{}
 			txs := make([]*transaction.Tx, tc.numTxs)
+			for i := range txs {
+				txs[i] = &transaction.Tx{SenderAddress: fmt.Sprintf("sender-%d", i), ReceiverAddress: fmt.Sprintf("receiver-%d", i)}
+			}
 			txList := &transaction.TxList{Transactions: txs}
 
 			groups := supsvc.txsGroups(txList, tc.desiredNumGroups)
@@ -651,3 +377,556 @@ func TestTxsGroups(t *testing.T) {
 		})
 	}
 }
+
+func TestTxsGroupsByAccount(t *testing.T) {
+	transfer := func(sender, receiver string) *transaction.Tx {
+		return &transaction.Tx{SenderAddress: sender, ReceiverAddress: receiver}
+	}
+	txs := []*transaction.Tx{
+		transfer("a", "b"),
+		transfer("c", "d"),
+		transfer("e", "f"),
+		transfer("b", "g"),
+		transfer("h", ""),
+		// Joins the partitions of a and c
+		transfer("g", "d"),
+		transfer("a", "b"),
+	}
+	assert.Equal(t, [][]int{{0, 1, 3, 5, 6}, {2}, {4}}, txPartitions(txs))
+
+	supsvc := &Supervisor{}
+	groups := supsvc.txsGroups(&transaction.TxList{Transactions: txs}, 2)
+	require.Len(t, groups, 2)
+	assert.Equal(t, []*transaction.Tx{txs[0], txs[1], txs[3], txs[5], txs[6]}, groups[0], "txs keep their order")
+	assert.Equal(t, []*transaction.Tx{txs[2], txs[4]}, groups[1])
+
+	// No account is touched by two groups
+	groups = supsvc.txsGroups(&transaction.TxList{Transactions: txs}, 5)
+	require.Len(t, groups, 3, "there are only 3 partitions")
+	touched := make(map[string]int)
+	for i, group := range groups {
+		for _, tx := range group {
+			for _, address := range []string{tx.SenderAddress, tx.ReceiverAddress} {
+				if j, ok := touched[address]; ok && address != "" {
+					assert.Equal(t, j, i, "account %v touched by two groups", address)
+				}
+				touched[address] = i
+			}
+		}
+	}
+}
+
+func TestVerifyVote(t *testing.T) {
+	supsvc := &Supervisor{}
+	supsvc.SetWriteMutex()
+	privKey := secp256k1.GenPrivKey()
+	pubKey := privKey.PubKey()
+	supsvc.AddValidator(pubKey.Bytes(), "add-01")
+	supsvc.AddValidator(secp256k1.GenPrivKey().PubKey().Bytes(), "add-02")
+
+	blockHash := []byte("child-block-hash")
+	sign, err := privKey.Sign(blockHash)
+	assert.NoError(t, err)
+	vote := &protobuf.VoteInfo{
+		Validator:          &protobuf.Validator{Address: "add-01", PubKey: pubKey.Bytes()},
+		SignedCurrentBlock: true,
+		Signature:          sign,
+	}
+
+	assert.NoError(t, supsvc.verifyVote("add-01", blockHash, vote))
+	assert.Error(t, supsvc.verifyVote("add-01", []byte("other-block-hash"), vote))
+	assert.Error(t, supsvc.verifyVote("add-02", blockHash, vote))
+	assert.Error(t, supsvc.verifyVote("add-03", blockHash, vote))
+}
+
+func signedHERTx(t *testing.T, privKey secp256k1.PrivKeySecp256k1, receiver string, value, fee, nonce uint64) []byte {
+	tx := pluginproto.Tx{
+		RecieverAddress: receiver,
+		Asset: &pluginproto.Asset{
+			Category: "crypto",
+			Symbol:   "HER",
+			Network:  "Herdius",
+			Value:    value,
+			Fee:      fee,
+			Nonce:    nonce,
+		},
+		Message: "transfer",
+	}
+	require.NoError(t, txbyte.Sign(&tx, privKey, "herdius-test"))
+	txbz, err := cdc.MarshalJSON(&tx)
+	require.NoError(t, err)
+	return txbz
+}
+
+func getAccount(t *testing.T, stateTrie statedb.Trie, address string) statedb.Account {
+	var account statedb.Account
+	actbz, err := stateTrie.TryGet([]byte(address))
+	require.NoError(t, err)
+	require.NoError(t, cdc.UnmarshalJSON(actbz, &account))
+	return account
+}
+
+func TestUpdateStateForTxsChargesFees(t *testing.T) {
+	dir, err := ioutil.TempDir("", "fees")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	stateTrie := statedb.GetState(dir)
+
+	privKey := secp256k1.GenPrivKey()
+	sender := privKey.PubKey().GetAddress()
+	receiver := "HHy1CuT3UxCGJ3BHydLEvR5ut5TLFYAEKy"
+	reward := "HKFpxVGG7Zgo7P38kqe4cXbDkmn7v7a7Sb"
+	for _, account := range []statedb.Account{
+		{Address: sender, Balance: 100},
+		{Address: receiver},
+	} {
+		actbz, err := cdc.MarshalJSON(account)
+		require.NoError(t, err)
+		require.NoError(t, stateTrie.TryUpdate([]byte(account.Address), actbz))
+	}
+
+	supsvc := &Supervisor{}
+	supsvc.SetRewardAddress(reward)
+	txs := txbyte.Txs{
+		signedHERTx(t, privKey, receiver, 40, 5, 1),
+		// Sender is left with 55, not enough to cover value and fee
+		signedHERTx(t, privKey, receiver, 51, 5, 2),
+	}
+	txList, err := supsvc.updateStateForTxs(&txs, stateTrie, 1)
+	require.NoError(t, err)
+	require.Len(t, txList.Transactions, 2)
+	assert.Equal(t, "success", txList.Transactions[0].Status)
+	assert.Equal(t, "failed", txList.Transactions[1].Status)
+
+	assert.Equal(t, uint64(55), getAccount(t, stateTrie, sender).Balance)
+	assert.Equal(t, uint64(40), getAccount(t, stateTrie, receiver).Balance)
+	assert.Equal(t, uint64(5), getAccount(t, stateTrie, reward).Balance)
+	assert.Equal(t, uint64(5), supsvc.Fees())
+}
+
+func TestUpdateStateForTxsFeesOverflow(t *testing.T) {
+	stateTrie, err := statedb.NewMemTrie()
+	require.NoError(t, err)
+
+	alice, bob := secp256k1.GenPrivKey(), secp256k1.GenPrivKey()
+	receiver := "HHy1CuT3UxCGJ3BHydLEvR5ut5TLFYAEKy"
+	reward := "HKFpxVGG7Zgo7P38kqe4cXbDkmn7v7a7Sb"
+	for _, account := range []statedb.Account{
+		{Address: alice.PubKey().GetAddress(), Balance: math.MaxUint64},
+		{Address: bob.PubKey().GetAddress(), Balance: math.MaxUint64},
+		{Address: receiver},
+	} {
+		actbz, err := cdc.MarshalJSON(account)
+		require.NoError(t, err)
+		require.NoError(t, stateTrie.TryUpdate([]byte(account.Address), actbz))
+	}
+
+	supsvc := &Supervisor{}
+	supsvc.SetRewardAddress(reward)
+	fee := uint64(1 << 63)
+	txs := txbyte.Txs{
+		signedHERTx(t, alice, receiver, 1, fee, 1),
+		// Both fees add up to more than a balance holds
+		signedHERTx(t, bob, receiver, 1, fee, 1),
+	}
+	txList, err := supsvc.updateStateForTxs(&txs, stateTrie, 1)
+	require.NoError(t, err)
+	assert.Equal(t, "success", txList.Transactions[0].Status)
+	assert.Equal(t, "failed", txList.Transactions[1].Status)
+	var receipt pluginproto.Receipt
+	require.NoError(t, cdc.UnmarshalJSON(supsvc.receipts[1], &receipt))
+	assert.Equal(t, transition.CodeBalanceOverflow, receipt.Code)
+
+	// The rejected tx isn't applied and the fee of the other is credited
+	assert.Equal(t, uint64(math.MaxUint64), getAccount(t, stateTrie, bob.PubKey().GetAddress()).Balance)
+	assert.Equal(t, uint64(1), getAccount(t, stateTrie, receiver).Balance)
+	assert.Equal(t, fee, getAccount(t, stateTrie, reward).Balance)
+	assert.Equal(t, fee, supsvc.Fees())
+}
+
+func TestUpdateStateForTxsRecordsReceipts(t *testing.T) {
+	stateTrie, err := statedb.NewMemTrie()
+	require.NoError(t, err)
+
+	privKey := secp256k1.GenPrivKey()
+	sender := privKey.PubKey().GetAddress()
+	receiver := "HHy1CuT3UxCGJ3BHydLEvR5ut5TLFYAEKy"
+	for _, account := range []statedb.Account{
+		{Address: sender, Balance: 100},
+		{Address: receiver},
+	} {
+		actbz, err := cdc.MarshalJSON(account)
+		require.NoError(t, err)
+		require.NoError(t, stateTrie.TryUpdate([]byte(account.Address), actbz))
+	}
+	initialRoot := stateTrie.Hash()
+
+	supsvc := &Supervisor{}
+	supsvc.SetRewardAddress("HKFpxVGG7Zgo7P38kqe4cXbDkmn7v7a7Sb")
+	txs := txbyte.Txs{
+		signedHERTx(t, privKey, receiver, 40, 5, 1),
+		signedHERTx(t, privKey, receiver, 51, 5, 2),
+	}
+	_, err = supsvc.updateStateForTxs(&txs, stateTrie, 1)
+	require.NoError(t, err)
+	require.Len(t, supsvc.receipts, 2)
+
+	receipts := make([]pluginproto.Receipt, 2)
+	for i, receiptbz := range supsvc.receipts {
+		require.NoError(t, cdc.UnmarshalJSON(receiptbz, &receipts[i]))
+		var tx pluginproto.Tx
+		require.NoError(t, cdc.UnmarshalJSON(txs[i], &tx))
+		assert.Equal(t, blockchain.TxIDWithoutStatus(&tx), receipts[i].TxId)
+	}
+
+	assert.Equal(t, transition.StatusSuccess, receipts[0].Status)
+	assert.Equal(t, transition.CodeOK, receipts[0].Code)
+	assert.Equal(t, uint64(5), receipts[0].Fee)
+	assert.NotEqual(t, initialRoot, receipts[0].StateRoot)
+
+	assert.Equal(t, transition.StatusFailed, receipts[1].Status)
+	assert.Equal(t, transition.CodeInsufficientBalance, receipts[1].Code)
+	assert.Contains(t, receipts[1].Message, "insufficient balance")
+	assert.Equal(t, uint64(0), receipts[1].Fee)
+	// A failed tx leaves the state as it was
+	assert.Equal(t, receipts[0].StateRoot, receipts[1].StateRoot)
+}
+
+// validatorPlugin votes on the child blocks sent to a validator node
+type validatorPlugin struct {
+	*network.Plugin
+	validator *val.Validator
+}
+
+func (p *validatorPlugin) Receive(ctx *network.PluginContext) error {
+	if msg, ok := ctx.Message().(*protobuf.ChildBlockMessage); ok {
+		return ctx.Reply(network.WithSignMessage(context.Background(), true), p.validator.ProcessChildBlock(msg))
+	}
+	return nil
+}
+
+// newMemoryNode creates a node with secp256k1 node keys listening on port of
+// the in-memory network mem
+func newMemoryNode(t *testing.T, mem *transport.MemoryNetwork, port uint16, plugin func(*cryptokeys.KeyPair, string) network.PluginInterface) *network.Network {
+	privKey := secp256k1.GenPrivKey()
+	keys := &cryptokeys.KeyPair{
+		PublicKey:  privKey.PubKey().Bytes(),
+		PrivateKey: privKey.Bytes(),
+		PrivKey:    privKey,
+		PubKey:     privKey.PubKey(),
+	}
+	address := network.FormatAddress("tcp", "127.0.0.1", port)
+	builder := network.NewBuilder()
+	builder.ClearTransportLayers()
+	builder.RegisterTransportLayer("tcp", mem.Transport())
+	builder.SetKeys(keys)
+	builder.SetAddress(address)
+	if plugin != nil {
+		require.NoError(t, builder.AddPlugin(plugin(keys, address)))
+	}
+	node, err := builder.Build()
+	require.NoError(t, err)
+	go node.Listen()
+	node.BlockUntilListening()
+	return node
+}
+
+func TestShardToValidatorsOverNetwork(t *testing.T) {
+	opcode.RegisterMessageType(types.OpcodeChildBlockMessage, &protobuf.ChildBlockMessage{})
+
+	dir, err := ioutil.TempDir("", "shard")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	stateTrie := statedb.GetState(dir)
+	// The txs touch disjoint accounts, so each goes to its own child block
+	txs := txbyte.Txs{}
+	var senders []string
+	for i := 0; i < 3; i++ {
+		receiver := secp256k1.GenPrivKey().PubKey().GetAddress()
+		actbz, err := cdc.MarshalJSON(statedb.Account{Address: receiver})
+		require.NoError(t, err)
+		require.NoError(t, stateTrie.TryUpdate([]byte(receiver), actbz))
+		privKey := secp256k1.GenPrivKey()
+		actbz, err = cdc.MarshalJSON(statedb.Account{Address: privKey.PubKey().GetAddress(), Balance: 100})
+		require.NoError(t, err)
+		require.NoError(t, stateTrie.TryUpdate([]byte(privKey.PubKey().GetAddress()), actbz))
+		txs = append(txs, signedHERTx(t, privKey, receiver, 10, 1, 1))
+		senders = append(senders, privKey.PubKey().GetAddress())
+	}
+	// The txs paying the same receiver all go to the same child block
+	receiver := secp256k1.GenPrivKey().PubKey().GetAddress()
+	sharedTxs := txbyte.Txs{}
+	for i := 0; i < 3; i++ {
+		privKey := secp256k1.GenPrivKey()
+		actbz, err := cdc.MarshalJSON(statedb.Account{Address: privKey.PubKey().GetAddress(), Balance: 100})
+		require.NoError(t, err)
+		require.NoError(t, stateTrie.TryUpdate([]byte(privKey.PubKey().GetAddress()), actbz))
+		sharedTxs = append(sharedTxs, signedHERTx(t, privKey, receiver, 10, 1, 1))
+	}
+	root, err := stateTrie.Commit(nil)
+	require.NoError(t, err)
+
+	mem := transport.NewMemoryNetwork(1)
+	supervisor := newMemoryNode(t, mem, 3000, nil)
+	defer supervisor.Close()
+	validators := make(map[string][]byte)
+	for i := 0; i < 3; i++ {
+		node := newMemoryNode(t, mem, uint16(3001+i), func(keys *cryptokeys.KeyPair, address string) network.PluginInterface {
+			return &validatorPlugin{validator: val.NewValidator(keys, address)}
+		})
+		defer node.Close()
+		validators[node.Address] = node.GetKeys().PubKey.Bytes()
+	}
+	newSupervisor := func() *Supervisor {
+		supsvc := &Supervisor{}
+		supsvc.SetWriteMutex()
+		supsvc.SetRewardAddress("HKFpxVGG7Zgo7P38kqe4cXbDkmn7v7a7Sb")
+		supsvc.SetNoOfPeersInGroup(1)
+		for address, pubKey := range validators {
+			supsvc.AddValidator(pubKey, address)
+		}
+		return supsvc
+	}
+
+	// A validator cut off from the supervisor doesn't vote, so its child
+	// block isn't committed and its tx is left out of the base block
+	mem.Partition([]int{3003})
+	supsvc := newSupervisor()
+	baseBlock, committed, err := supsvc.shardToValidators(&protobuf.BaseBlock{}, append(txbyte.Txs{}, txs...), supervisor, root)
+	require.NoError(t, err)
+	require.NotNil(t, baseBlock)
+	assert.Len(t, supsvc.ChildBlock, 2)
+	require.Len(t, committed, 2)
+	numCommitted := 0
+	for _, group := range baseBlock.GetValidatorGroups() {
+		if group.GetCommitted() {
+			numCommitted++
+		}
+	}
+	assert.Equal(t, 2, numCommitted)
+	state, err := statedb.NewTrie(common.BytesToHash(baseBlock.GetHeader().GetStateRoot()))
+	require.NoError(t, err)
+	for i, sender := range senders {
+		balance := uint64(100)
+		if i == committed[0] || i == committed[1] {
+			balance = 89
+		}
+		assert.Equal(t, balance, getAccount(t, state, sender).Balance)
+	}
+	assert.Equal(t, uint64(2), baseBlock.GetHeader().GetFees())
+
+	// The same supervisor creates the next base block once the validator
+	// is back
+	mem.Heal()
+	baseBlock, err = supsvc.ShardToValidators(&protobuf.BaseBlock{}, txs, supervisor, root)
+	require.NoError(t, err)
+	require.NotNil(t, baseBlock, "every validator should vote for its child block")
+	assert.Equal(t, int64(1), baseBlock.GetHeader().GetHeight())
+	require.Len(t, supsvc.ChildBlock, 3)
+	for _, cb := range supsvc.ChildBlock {
+		var cbhash cmn.HexBytes = cb.GetHeader().GetBlockID().GetBlockHash()
+		assert.Len(t, supsvc.VoteInfoData[cbhash.String()], 1)
+	}
+	assert.Len(t, supsvc.ValidatorChildblock, 3)
+
+	// The base block records which validators verified which child block
+	require.Len(t, baseBlock.GetValidatorGroups(), 3)
+	for i, group := range baseBlock.GetValidatorGroups() {
+		assert.True(t, group.GetCommitted())
+		assert.Len(t, group.GetValidators(), 1)
+		assert.Equal(t, []byte(supsvc.ChildBlock[i].GetHeader().GetBlockID().GetBlockHash()), group.GetChildBlockHash())
+	}
+	groupsHash, err := blockchain.ValidatorGroupsHash(baseBlock.GetValidatorGroups())
+	require.NoError(t, err)
+	assert.Equal(t, groupsHash, baseBlock.GetHeader().GetValidatorGroupsHash())
+
+	assertBlockRound(t, baseBlock, 3)
+
+	// The validators left without a child block don't hold the base block
+	// back. The next base block holds the child blocks of its own round only.
+	baseBlock, err = supsvc.ShardToValidators(baseBlock, sharedTxs, supervisor, root)
+	require.NoError(t, err)
+	require.NotNil(t, baseBlock)
+	require.Len(t, supsvc.ChildBlock, 1)
+	assert.Equal(t, int64(3), supsvc.ChildBlock[0].GetHeader().GetNumTxs())
+	assert.Len(t, baseBlock.GetValidatorGroups(), 1)
+	assert.Len(t, supsvc.ValidatorChildblock, 1)
+	assertBlockRound(t, baseBlock, 1)
+}
+
+// assertBlockRound checks baseBlock holds numChildBlocks child blocks, each
+// with the votes of its group
+func assertBlockRound(t *testing.T, baseBlock *protobuf.BaseBlock, numChildBlocks int) {
+	var cbs []*protobuf.ChildBlock
+	require.NoError(t, cdc.UnmarshalJSON(baseBlock.GetChildBlock(), &cbs))
+	assert.Len(t, cbs, numChildBlocks)
+	var voteCommits []protobuf.VoteCommit
+	require.NoError(t, cdc.UnmarshalJSON(baseBlock.GetVoteCommits(), &voteCommits))
+	require.Len(t, voteCommits, numChildBlocks)
+	for i, voteCommit := range voteCommits {
+		assert.Equal(t, cbs[i].GetHeader().GetBlockID().GetBlockHash(), voteCommit.BlockID.GetBlockHash())
+		assert.Len(t, voteCommit.Vote, 1)
+	}
+}
+
+// testChain stores the blocks added to it in memory, or fails to if err is set
+type testChain struct {
+	blockchain.ServiceI
+	blocks []*protobuf.BaseBlock
+	err    error
+}
+
+func (c *testChain) AddBaseBlock(bb *protobuf.BaseBlock) error {
+	if c.err != nil {
+		return c.err
+	}
+	c.blocks = append(c.blocks, bb)
+	return nil
+}
+
+func (c *testChain) GetLastBlock() *protobuf.BaseBlock {
+	return c.blocks[len(c.blocks)-1]
+}
+
+func (c *testChain) GetBlockByHeight(height int64) (*protobuf.BaseBlock, error) {
+	if height < 0 || height >= int64(len(c.blocks)) {
+		return nil, fmt.Errorf("no block at height %d", height)
+	}
+	return c.blocks[height], nil
+}
+
+// newTestChain returns a chain holding a genesis block whose state holds
+// balance for each of addresses
+func newTestChain(t *testing.T, balance uint64, addresses ...string) *testChain {
+	dir, err := ioutil.TempDir("", "chain")
+	require.NoError(t, err)
+	t.Cleanup(func() { os.RemoveAll(dir) })
+	statedb.GetState(dir)
+	stateTrie, err := statedb.NewTrie(common.Hash{})
+	require.NoError(t, err)
+	for _, address := range addresses {
+		actbz, err := cdc.MarshalJSON(statedb.Account{Address: address, Balance: balance})
+		require.NoError(t, err)
+		require.NoError(t, stateTrie.TryUpdate([]byte(address), actbz))
+	}
+	root, err := stateTrie.Commit(nil)
+	require.NoError(t, err)
+	genesis := &protobuf.BaseBlock{Header: &protobuf.BaseHeader{
+		Block_ID:  &protobuf.BlockID{BlockHash: []byte("genesis")},
+		StateRoot: root,
+	}}
+	return &testChain{blocks: []*protobuf.BaseBlock{genesis}}
+}
+
+func TestCommitBlock(t *testing.T) {
+	privKey := secp256k1.GenPrivKey()
+	sender := privKey.PubKey().GetAddress()
+	receiver := secp256k1.GenPrivKey().PubKey().GetAddress()
+	chain := newTestChain(t, 100, sender, receiver)
+	genesis := chain.GetLastBlock()
+
+	supsvc := &Supervisor{}
+	supsvc.SetWriteMutex()
+	supsvc.SetRewardAddress("HKFpxVGG7Zgo7P38kqe4cXbDkmn7v7a7Sb")
+	supsvc.SetStateRoot(genesis.GetHeader().GetStateRoot())
+	txs := txbyte.Txs{signedHERTx(t, privKey, receiver, 40, 5, 1)}
+
+	// A block which fails to be stored leaves the state as it was
+	baseBlock, err := supsvc.createSingularBlock(genesis, nil, append(txbyte.Txs{}, txs...), nil, supsvc.StateRoot())
+	require.NoError(t, err)
+	assert.NotEqual(t, genesis.GetHeader().GetStateRoot(), baseBlock.GetHeader().GetStateRoot())
+	chain.err = fmt.Errorf("disk full")
+	assert.Error(t, supsvc.CommitBlock(genesis, baseBlock, chain))
+	assert.Equal(t, genesis.GetHeader().GetStateRoot(), supsvc.StateRoot())
+	assert.Len(t, chain.blocks, 1)
+
+	// A block whose state isn't the staged one isn't stored
+	baseBlock, err = supsvc.createSingularBlock(genesis, nil, append(txbyte.Txs{}, txs...), nil, supsvc.StateRoot())
+	require.NoError(t, err)
+	chain.err = nil
+	tampered := *baseBlock.GetHeader()
+	tampered.StateRoot = genesis.GetHeader().GetStateRoot()
+	assert.Error(t, supsvc.CommitBlock(genesis, &protobuf.BaseBlock{Header: &tampered}, chain))
+	assert.Len(t, chain.blocks, 1)
+
+	baseBlock, err = supsvc.createSingularBlock(genesis, nil, append(txbyte.Txs{}, txs...), nil, supsvc.StateRoot())
+	require.NoError(t, err)
+	require.NoError(t, supsvc.CommitBlock(genesis, baseBlock, chain))
+	assert.Equal(t, baseBlock.GetHeader().GetStateRoot(), supsvc.StateRoot())
+	assert.Len(t, chain.blocks, 2)
+	require.NoError(t, statedb.VerifyState(supsvc.StateRoot()))
+	state, err := statedb.NewTrie(common.BytesToHash(supsvc.StateRoot()))
+	require.NoError(t, err)
+	assert.Equal(t, uint64(55), getAccount(t, state, sender).Balance)
+	assert.Equal(t, uint64(140), getAccount(t, state, receiver).Balance)
+}
+
+// newTestAccountStorage sets up the account storage of the syncer, holding
+// account flagged with a first HER balance
+func newTestAccountStorage(t *testing.T, account statedb.Account) {
+	dir, err := ioutil.TempDir("", "syncdb")
+	require.NoError(t, err)
+	badgerdb := db.NewDB("test.syncdb", db.GoBadgerBackend, dir)
+	accountStorage = external.NewDB(badgerdb)
+	t.Cleanup(func() {
+		badgerdb.Close()
+		accountStorage = nil
+		os.RemoveAll(dir)
+	})
+	accountStorage.Set(account.Address, external.AccountCache{Account: account, IsFirstHEREntry: true})
+}
+
+func TestCommitBlockClearsBalanceUpdates(t *testing.T) {
+	privKey := secp256k1.GenPrivKey()
+	sender := privKey.PubKey().GetAddress()
+	receiver := secp256k1.GenPrivKey().PubKey().GetAddress()
+	chain := newTestChain(t, 100, sender, receiver)
+	genesis := chain.GetLastBlock()
+	extAccount := statedb.Account{
+		Address:      secp256k1.GenPrivKey().PubKey().GetAddress(),
+		Balance:      7,
+		Erc20Address: "0xd7a4d3f7c4b5b4d8d5a4b1e3c2a1f0e9d8c7b6a5",
+	}
+	newTestAccountStorage(t, extAccount)
+	flagged := func() bool {
+		item, ok := accountStorage.Get(extAccount.Address)
+		require.True(t, ok)
+		return item.IsFirstHEREntry
+	}
+
+	supsvc := &Supervisor{}
+	supsvc.SetWriteMutex()
+	supsvc.SetRewardAddress("HKFpxVGG7Zgo7P38kqe4cXbDkmn7v7a7Sb")
+	supsvc.SetStateRoot(genesis.GetHeader().GetStateRoot())
+
+	// The update of a block which fails to be stored is written with the next
+	baseBlock, err := supsvc.createSingularBlock(genesis, nil, txbyte.Txs{}, nil, supsvc.StateRoot())
+	require.NoError(t, err)
+	require.Len(t, baseBlock.GetAccountUpdates(), 1)
+	assert.Equal(t, extAccount.Address, baseBlock.GetAccountUpdates()[0].GetAddress())
+	chain.err = fmt.Errorf("disk full")
+	assert.Error(t, supsvc.CommitBlock(genesis, baseBlock, chain))
+	assert.True(t, flagged())
+
+	// An account the syncer updated again since it was written stays flagged
+	chain.err = nil
+	baseBlock, err = supsvc.createSingularBlock(genesis, nil, txbyte.Txs{}, nil, supsvc.StateRoot())
+	require.NoError(t, err)
+	updated := extAccount
+	updated.Balance = 9
+	accountStorage.Set(updated.Address, external.AccountCache{Account: updated, IsFirstHEREntry: true})
+	require.NoError(t, supsvc.CommitBlock(genesis, baseBlock, chain))
+	assert.True(t, flagged())
+	state, err := statedb.NewTrie(common.BytesToHash(supsvc.StateRoot()))
+	require.NoError(t, err)
+	assert.Equal(t, uint64(7), getAccount(t, state, extAccount.Address).Balance)
+
+	lastBlock := chain.GetLastBlock()
+	baseBlock, err = supsvc.createSingularBlock(lastBlock, nil, txbyte.Txs{}, nil, supsvc.StateRoot())
+	require.NoError(t, err)
+	require.NoError(t, supsvc.CommitBlock(lastBlock, baseBlock, chain))
+	assert.False(t, flagged())
+	state, err = statedb.NewTrie(common.BytesToHash(supsvc.StateRoot()))
+	require.NoError(t, err)
+	assert.Equal(t, uint64(9), getAccount(t, state, extAccount.Address).Balance)
+}
@@ -3,6 +3,10 @@ package transaction
 import (
 	"bytes"
 	"encoding/json"
+	"fmt"
+	"strconv"
+
+	pluginproto "github.com/herdius/herdius-core/hbi/protobuf"
 )
 
 const (
@@ -30,12 +34,14 @@ type Asset struct {
 type Tx struct {
 	SenderAddress   string `json:"sender_address"`
 	SenderPubKey    string `json:"sender_pubkey"`
-	ReceiverAddress string `json:"receiver_address"`
+	ReceiverAddress string `json:"reciever_address"`
 	Asset           Asset  `json:"asset"`
 	Message         string `json:"message"`
 	Signature       string `json:"sign"`
 	Type            string `json:"type"`
 	Status          string `json:"status"`
+	SignVersion     uint32 `json:"sign_version"`
+	ChainID         string `json:"chain_id"`
 }
 
 // TxList : List of Transactions
@@ -56,3 +62,56 @@ func PrettyPrint(tx Tx) (string, error) {
 	}
 	return buffer.String(), nil
 }
+
+// ToProto converts tx to its protobuf representation
+func ToProto(txValue Tx) (tx pluginproto.Tx, err error) {
+	val := uint64(0)
+	if txValue.Asset.Value != "" {
+		val, err = strconv.ParseUint(txValue.Asset.Value, 10, 64)
+		if err != nil {
+			err = fmt.Errorf("Failed to parse transaction value: %v", err)
+			return
+		}
+	}
+	fee := uint64(0)
+	if txValue.Asset.Fee != "" {
+		fee, err = strconv.ParseUint(txValue.Asset.Fee, 10, 64)
+		if err != nil {
+			err = fmt.Errorf("Failed to parse transaction fee: %v", err)
+			return
+		}
+	}
+	nonc := uint64(0)
+	if txValue.Asset.Nonce != "" {
+		nonc, err = strconv.ParseUint(txValue.Asset.Nonce, 10, 64)
+		if err != nil {
+			err = fmt.Errorf("Failed to parse transaction nonce: %v", err)
+			return
+		}
+	}
+	asset := &pluginproto.Asset{
+		Category:              txValue.Asset.Category,
+		Symbol:                txValue.Asset.Symbol,
+		Network:               txValue.Asset.Network,
+		Value:                 val,
+		Fee:                   fee,
+		Nonce:                 nonc,
+		ExternalSenderAddress: txValue.Asset.ExternalSenderAddress,
+		LockedAmount:          txValue.Asset.LockedAmount,
+		RedeemedAmount:        txValue.Asset.RedeemedAmount,
+	}
+	tx = pluginproto.Tx{
+		SenderAddress:   txValue.SenderAddress,
+		SenderPubkey:    txValue.SenderPubKey,
+		RecieverAddress: txValue.ReceiverAddress,
+		Asset:           asset,
+		Message:         txValue.Message,
+		Type:            txValue.Type,
+		Sign:            txValue.Signature,
+		Status:          txValue.Status,
+		SignVersion:     txValue.SignVersion,
+		ChainId:         txValue.ChainID,
+	}
+
+	return
+}
@@ -9,6 +9,7 @@ import (
 	ethtrie "github.com/ethereum/go-ethereum/trie"
 	"github.com/spf13/viper"
 
+	"github.com/herdius/herdius-core/asset"
 	"github.com/herdius/herdius-core/blockchain"
 	"github.com/herdius/herdius-core/p2p/log"
 	external "github.com/herdius/herdius-core/storage/exbalance"
@@ -104,44 +105,7 @@ func sync(exBal external.BalanceStorage, rpc apiEndponts) {
 				}
 			}
 		}
-		var syncers []Syncer
-
-		// ETH syncer
-		ethSyncer := newEthSyncer()
-		ethSyncer.RPC = rpc.ethRPC
-		ethSyncer.syncer.Account = senderAccount
-		ethSyncer.syncer.Storage = exBal
-		syncers = append(syncers, ethSyncer)
-
-		// BTC syncer
-		btcSyncer := newBTCSyncer()
-		btcSyncer.RPC = rpc.btcRPC
-		btcSyncer.syncer.Account = senderAccount
-		btcSyncer.syncer.Storage = exBal
-		syncers = append(syncers, btcSyncer)
-
-		// HBTC syncer
-		hbtcSyncer := newHBTCSyncer()
-		hbtcSyncer.RPC = rpc.hbtcRPC
-		hbtcSyncer.syncer.Account = senderAccount
-		hbtcSyncer.syncer.Storage = exBal
-		syncers = append(syncers, hbtcSyncer)
-
-		// HBTC testnetsyncer
-		hbtctestSyncer := newBTCTestNetSyncer()
-		hbtctestSyncer.Account = senderAccount
-		hbtctestSyncer.Storage = exBal
-		syncers = append(syncers, hbtctestSyncer)
-
-		// HERDIUS syncer
-		syncers = append(syncers, &HERToken{Account: senderAccount, Storage: exBal, RPC: rpc.ethRPC, TokenContractAddress: rpc.herTokenAddress})
-
-		// TEZOS syncer
-		tezosSyncer := newTezosSyncer()
-		tezosSyncer.RPC = rpc.tezosRPC
-		tezosSyncer.syncer.Account = senderAccount
-		tezosSyncer.syncer.Storage = exBal
-		syncers = append(syncers, tezosSyncer)
+		syncers := newSyncers(senderAccount, exBal, rpc)
 
 		wg.Add(1)
 		go func() {
@@ -166,3 +130,51 @@ func sync(exBal external.BalanceStorage, rpc apiEndponts) {
 	wg.Wait()
 	log.Debug().Msg("Sync account end")
 }
+
+// newSyncers creates the syncers of account for the registered assets
+func newSyncers(account statedb.Account, exBal external.BalanceStorage, rpc apiEndponts) []Syncer {
+	var syncers []Syncer
+	for _, a := range asset.GetRegistry().Assets() {
+		for _, name := range a.Syncers {
+			switch name {
+			case asset.SyncerETH:
+				ethSyncer := newEthSyncer()
+				ethSyncer.RPC = rpc.ethRPC
+				ethSyncer.syncer.Account = account
+				ethSyncer.syncer.Storage = exBal
+				syncers = append(syncers, ethSyncer)
+			case asset.SyncerBTC:
+				btcSyncer := newBTCSyncer()
+				btcSyncer.RPC = rpc.btcRPC
+				btcSyncer.syncer.Account = account
+				btcSyncer.syncer.Storage = exBal
+				syncers = append(syncers, btcSyncer)
+			case asset.SyncerBTCTestnet:
+				btcTestSyncer := newBTCTestNetSyncer()
+				btcTestSyncer.Account = account
+				btcTestSyncer.Storage = exBal
+				syncers = append(syncers, btcTestSyncer)
+			case asset.SyncerHBTC:
+				// The wrapped asset is held at the first address of its host
+				hbtcSyncer := newHBTCSyncer()
+				hbtcSyncer.symbol, hbtcSyncer.ethSymbol = a.Symbol, a.Host
+				hbtcSyncer.syncer.assetSymbol = a.Symbol
+				hbtcSyncer.RPC = rpc.hbtcRPC
+				hbtcSyncer.syncer.Account = account
+				hbtcSyncer.syncer.Storage = exBal
+				syncers = append(syncers, hbtcSyncer)
+			case asset.SyncerHERToken:
+				syncers = append(syncers, &HERToken{Account: account, Storage: exBal, RPC: rpc.ethRPC, TokenContractAddress: rpc.herTokenAddress})
+			case asset.SyncerTezos:
+				tezosSyncer := newTezosSyncer()
+				tezosSyncer.RPC = rpc.tezosRPC
+				tezosSyncer.syncer.Account = account
+				tezosSyncer.syncer.Storage = exBal
+				syncers = append(syncers, tezosSyncer)
+			default:
+				log.Warn().Msgf("Unknown syncer %v of asset %v", name, a.Symbol)
+			}
+		}
+	}
+	return syncers
+}
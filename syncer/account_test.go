@@ -0,0 +1,33 @@
+package sync
+
+import (
+	"testing"
+
+	"github.com/herdius/herdius-core/asset"
+	"github.com/herdius/herdius-core/storage/state/statedb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewSyncers(t *testing.T) {
+	defer asset.SetRegistry(asset.GetRegistry())
+
+	syncers := newSyncers(statedb.Account{}, nil, apiEndponts{})
+	assert.Len(t, syncers, 6)
+
+	registry, err := asset.NewRegistry(
+		asset.Asset{Symbol: "ETH", Syncers: []string{asset.SyncerETH}},
+		asset.Asset{Symbol: "BTC"},
+		asset.Asset{Symbol: "WBTC", Parent: "BTC", Host: "ETH", Syncers: []string{asset.SyncerHBTC}},
+	)
+	require.NoError(t, err)
+	asset.SetRegistry(registry)
+
+	syncers = newSyncers(statedb.Account{}, nil, apiEndponts{})
+	require.Len(t, syncers, 2)
+	wrapped, ok := syncers[1].(*HBTCSyncer)
+	require.True(t, ok)
+	assert.Equal(t, "WBTC", wrapped.symbol)
+	assert.Equal(t, "ETH", wrapped.ethSymbol)
+	assert.Equal(t, "WBTC", wrapped.syncer.assetSymbol)
+}
@@ -0,0 +1,199 @@
+package transition
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/herdius/herdius-core/asset"
+	pluginproto "github.com/herdius/herdius-core/hbi/protobuf"
+	"github.com/herdius/herdius-core/storage/state/statedb"
+)
+
+func isExternalAssetAddressExist(account *statedb.Account, assetSymbol, assetAddress string) bool {
+	if account == nil || account.EBalances == nil {
+		return false
+	}
+	if len(account.EBalances[assetSymbol][assetAddress].Address) > 0 {
+		return true
+	}
+	return false
+}
+
+func updateAccountLockedBalance(senderAccount *statedb.Account, tx *pluginproto.Tx) (*statedb.Account, error) {
+	if senderAccount.LockedBalance == nil {
+		senderAccount.LockedBalance = make(map[string]map[string]uint64)
+	}
+	symbol := strings.ToUpper(tx.Asset.Symbol)
+	if senderAccount.LockedBalance[symbol] == nil {
+		senderAccount.LockedBalance[symbol] = make(map[string]uint64)
+	}
+
+	if tx.SenderAddress == senderAccount.Address {
+		locked, err := AddBalance(senderAccount.LockedBalance[symbol][tx.Asset.ExternalSenderAddress], tx.Asset.LockedAmount)
+		if err != nil {
+			return nil, fmt.Errorf("locked %v: %w", symbol, err)
+		}
+		senderAccount.LockedBalance[symbol][tx.Asset.ExternalSenderAddress] = locked
+	}
+	if err := withdraw(senderAccount, tx.Asset.Symbol, tx.Asset.ExternalSenderAddress, tx.Asset.LockedAmount); err != nil {
+		return nil, err
+	}
+	senderAccount.Nonce = tx.Asset.Nonce
+	// Locking an asset opens a balance of the asset wrapping it at the
+	// first address of the wrapped asset's host, e.g. HBTC for BTC
+	if wrapped, ok := asset.GetRegistry().Wrapped(symbol); ok {
+		if _, ok := senderAccount.EBalances[wrapped.Symbol]; !ok {
+			eBalance := statedb.EBalance{}
+			eBalance.Address = senderAccount.FirstExternalAddress[wrapped.Host]
+			eBalance.Balance = 0
+			eBalance.LastBlockHeight = 0
+			eBalance.Nonce = 1
+			eBalances := senderAccount.EBalances
+			eBalances[wrapped.Symbol] = make(map[string]statedb.EBalance)
+			eBalances[wrapped.Symbol][eBalance.Address] = eBalance
+			senderAccount.EBalances = eBalances
+		}
+	}
+	return senderAccount, nil
+}
+
+// updateRedeemAccountLockedBalance credits the redeemed amount back to the external
+// balance of the asset. A locked asset is debited from its locked balance, a wrapped
+// asset from the balance of the wrapped asset.
+func updateRedeemAccountLockedBalance(senderAccount *statedb.Account, tx *pluginproto.Tx) (*statedb.Account, error) {
+	if senderAccount.LockedBalance == nil {
+		return senderAccount, nil
+	}
+	wrapped, isWrapped := asset.GetRegistry().Get(tx.Asset.Symbol)
+	isWrapped = isWrapped && len(wrapped.Parent) > 0
+	backing := asset.GetRegistry().Backing(tx.Asset.Symbol)
+	if senderAccount.LockedBalance[backing] == nil {
+		if !isWrapped {
+			return senderAccount, nil
+		}
+		if err := withdraw(senderAccount, tx.Asset.Symbol, tx.Asset.ExternalSenderAddress, tx.Asset.RedeemedAmount); err != nil {
+			return nil, err
+		}
+	} else if tx.SenderAddress == senderAccount.Address {
+		locked, err := SubBalance(senderAccount.LockedBalance[backing][tx.Asset.ExternalSenderAddress], tx.Asset.RedeemedAmount)
+		if err != nil {
+			return nil, fmt.Errorf("locked %v: %w", backing, err)
+		}
+		senderAccount.LockedBalance[backing][tx.Asset.ExternalSenderAddress] = locked
+	}
+	senderAccount.Nonce = tx.Asset.Nonce
+	if err := deposit(senderAccount, backing, tx.Asset.ExternalSenderAddress, tx.Asset.RedeemedAmount); err != nil {
+		return nil, err
+	}
+	return senderAccount, nil
+}
+
+func updateAccount(senderAccount *statedb.Account, tx *pluginproto.Tx) (*statedb.Account, error) {
+	if strings.EqualFold(strings.ToUpper(tx.Asset.Symbol), "HER") &&
+		len(senderAccount.Address) == 0 {
+		senderAccount.Address = tx.SenderAddress
+		senderAccount.Balance = 0
+		senderAccount.Nonce = 0
+		senderAccount.PublicKey = tx.SenderPubkey
+		senderAccount.Erc20Address = tx.Asset.ExternalSenderAddress
+		senderAccount.FirstExternalAddress = make(map[string]string)
+	} else if strings.EqualFold(strings.ToUpper(tx.Asset.Symbol), "HER") &&
+		tx.SenderAddress == senderAccount.Address {
+		balance, err := AddBalance(senderAccount.Balance, tx.Asset.Value)
+		if err != nil {
+			return nil, fmt.Errorf("HER: %w", err)
+		}
+		senderAccount.Balance = balance
+		senderAccount.Nonce = tx.Asset.Nonce
+	} else if !strings.EqualFold(strings.ToUpper(tx.Asset.Symbol), "HER") &&
+		tx.SenderAddress == senderAccount.Address {
+
+		// Update account's Nonce
+		senderAccount.Nonce = tx.Asset.Nonce
+
+		// Register External Asset Addresses if not exist
+		if assetEBalance, ok := senderAccount.EBalances[tx.Asset.Symbol]; ok {
+			if _, ok := assetEBalance[tx.Asset.ExternalSenderAddress]; !ok {
+				eBalance := statedb.EBalance{}
+				eBalance.Address = tx.Asset.ExternalSenderAddress
+				eBalance.Balance = 0
+				eBalance.LastBlockHeight = 0
+				eBalance.Nonce = 0
+				eBalances := senderAccount.EBalances
+				eBalances[tx.Asset.Symbol][tx.Asset.ExternalSenderAddress] = eBalance
+				senderAccount.EBalances = eBalances
+			}
+		} else {
+			eBalance := statedb.EBalance{}
+			eBalance.Address = tx.Asset.ExternalSenderAddress
+			eBalance.Balance = 0
+			eBalance.LastBlockHeight = 0
+			eBalance.Nonce = 0
+			eBalances := senderAccount.EBalances
+			if len(eBalances) == 0 {
+				eBalances = make(map[string]map[string]statedb.EBalance)
+			}
+			if len(eBalances[tx.Asset.Symbol]) == 0 {
+				eBalances[tx.Asset.Symbol] = make(map[string]statedb.EBalance)
+			}
+			eBalances[tx.Asset.Symbol][tx.Asset.ExternalSenderAddress] = eBalance
+			senderAccount.EBalances = eBalances
+			if senderAccount.FirstExternalAddress == nil {
+				senderAccount.FirstExternalAddress = make(map[string]string)
+			}
+			senderAccount.FirstExternalAddress[tx.Asset.Symbol] = tx.Asset.ExternalSenderAddress
+		}
+	}
+	return senderAccount, nil
+}
+
+// withdraw debits txValue from the sender's balance of the asset at assetExtAddress
+func withdraw(senderAccount *statedb.Account, assetSymbol, assetExtAddress string, txValue uint64) error {
+	if strings.EqualFold(assetSymbol, "HER") {
+		balance, err := SubBalance(senderAccount.Balance, txValue)
+		if err != nil {
+			return fmt.Errorf("HER: %w", err)
+		}
+		senderAccount.Balance = balance
+		return nil
+	}
+	// Get balance of the required external asset
+	symbol := strings.ToUpper(assetSymbol)
+	eBalance, ok := senderAccount.EBalances[symbol][assetExtAddress]
+	if !ok {
+		return fmt.Errorf("%w: %v address %v", ErrNoExternalAddress, symbol, assetExtAddress)
+	}
+	balance, err := SubBalance(eBalance.Balance, txValue)
+	if err != nil {
+		return fmt.Errorf("%v: %w", symbol, err)
+	}
+	eBalance.Balance = balance
+	senderAccount.EBalances[symbol][assetExtAddress] = eBalance
+	return nil
+}
+
+// deposit credits txValue to the receiver's balance of the asset at assetExtAddress
+func deposit(receiverAccount *statedb.Account, assetSymbol, assetExtAddress string, txValue uint64) error {
+	if strings.EqualFold(assetSymbol, "HER") {
+		balance, err := AddBalance(receiverAccount.Balance, txValue)
+		if err != nil {
+			return fmt.Errorf("HER: %w", err)
+		}
+		receiverAccount.Balance = balance
+		return nil
+	}
+	// Get balance of the required external asset
+	symbol := strings.ToUpper(assetSymbol)
+	eBalances, ok := receiverAccount.EBalances[symbol]
+	if !ok {
+		return fmt.Errorf("%w: %v", ErrNoExternalAddress, symbol)
+	}
+	eBalance := eBalances[assetExtAddress]
+	balance, err := AddBalance(eBalance.Balance, txValue)
+	if err != nil {
+		return fmt.Errorf("%v: %w", symbol, err)
+	}
+	eBalance.Balance = balance
+	eBalances[assetExtAddress] = eBalance
+	return nil
+}
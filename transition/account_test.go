@@ -0,0 +1,405 @@
+package transition
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	pluginproto "github.com/herdius/herdius-core/hbi/protobuf"
+	"github.com/herdius/herdius-core/storage/state/statedb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegisterNewHERAddress(t *testing.T) {
+	asset := &pluginproto.Asset{
+		Symbol: "HER",
+	}
+	tx := &pluginproto.Tx{
+		SenderAddress: "HHy1CuT3UxCGJ3BHydLEvR5ut6HRy2qUvm",
+		Asset:         asset,
+		Type:          "update",
+	}
+	account := &statedb.Account{}
+	account, err := updateAccount(account, tx)
+	require.NoError(t, err)
+	assert.Equal(t, tx.SenderAddress, account.Address)
+}
+
+func TestUpdateHERAccountBalance(t *testing.T) {
+	asset := &pluginproto.Asset{
+		Symbol: "HER",
+	}
+	tx := &pluginproto.Tx{
+		SenderAddress: "HHy1CuT3UxCGJ3BHydLEvR5ut6HRy2qUvm",
+		Asset:         asset,
+		Type:          "update",
+	}
+	account := &statedb.Account{}
+	account, err := updateAccount(account, tx)
+	require.NoError(t, err)
+	assert.Equal(t, tx.SenderAddress, account.Address)
+	assert.Equal(t, account.Balance, uint64(0))
+
+	// Update 10 HER tokens to existing HER Account
+	asset = &pluginproto.Asset{
+		Symbol: "HER",
+		Value:  10,
+		Nonce:  2,
+	}
+	tx = &pluginproto.Tx{
+		SenderAddress: "HHy1CuT3UxCGJ3BHydLEvR5ut6HRy2qUvm",
+		Asset:         asset,
+		Type:          "update",
+	}
+	account, err = updateAccount(account, tx)
+	require.NoError(t, err)
+	assert.Equal(t, tx.SenderAddress, account.Address)
+	assert.Equal(t, account.Balance, uint64(10))
+	assert.Equal(t, account.Nonce, uint64(2))
+}
+
+func TestRegisterNewETHAddress(t *testing.T) {
+	symbol := "ETH"
+	extSenderAddress := "0xD8f647855876549d2623f52126CE40D053a2ef6A"
+	asset := &pluginproto.Asset{
+		Symbol:                symbol,
+		ExternalSenderAddress: extSenderAddress,
+		Nonce:                 1,
+		Network:               "Herdius",
+	}
+	tx := &pluginproto.Tx{
+		SenderAddress: "HHy1CuT3UxCGJ3BHydLEvR5ut6HRy2qUvm",
+		Asset:         asset,
+		Type:          "update",
+	}
+	account := &statedb.Account{
+		Address:              "HHy1CuT3UxCGJ3BHydLEvR5ut6HRy2qUvm",
+		FirstExternalAddress: make(map[string]string),
+	}
+	account, err := updateAccount(account, tx)
+	require.NoError(t, err)
+	assert.True(t, len(account.EBalances) > 0)
+	assert.Equal(t, tx.Asset.ExternalSenderAddress, account.EBalances[symbol][extSenderAddress].Address)
+	assert.Equal(t, extSenderAddress, account.FirstExternalAddress[symbol])
+}
+
+func TestRegisterMultipleExternalAssets(t *testing.T) {
+	symbol := "ETH"
+	extSenderAddress := " 0xD8f647855876549d2623f52126CE40D053a2ef6A"
+	// First add ETH
+	asset := &pluginproto.Asset{
+		Symbol:                symbol,
+		ExternalSenderAddress: extSenderAddress,
+		Nonce:                 1,
+		Network:               "Herdius",
+	}
+	tx := &pluginproto.Tx{
+		SenderAddress: "HHy1CuT3UxCGJ3BHydLEvR5ut6HRy2qUvm",
+		Asset:         asset,
+		Type:          "update",
+	}
+	account := &statedb.Account{
+		Address:              "HHy1CuT3UxCGJ3BHydLEvR5ut6HRy2qUvm",
+		FirstExternalAddress: make(map[string]string),
+	}
+	account, err := updateAccount(account, tx)
+	require.NoError(t, err)
+	assert.True(t, len(account.EBalances) == 1)
+	assert.True(t, len(account.EBalances[symbol]) == 1)
+	assert.Equal(t, tx.Asset.ExternalSenderAddress, account.EBalances[symbol][extSenderAddress].Address)
+	assert.Equal(t, extSenderAddress, account.FirstExternalAddress[symbol])
+
+	newSymbol := "BTC"
+	newExtSenderAddress := "Bitcoin-Address"
+	// Second add BTC
+	asset = &pluginproto.Asset{
+		Symbol:                newSymbol,
+		ExternalSenderAddress: newExtSenderAddress,
+		Nonce:                 2,
+		Network:               "Herdius",
+	}
+	tx = &pluginproto.Tx{
+		SenderAddress: "HHy1CuT3UxCGJ3BHydLEvR5ut6HRy2qUvm",
+		Asset:         asset,
+		Type:          "update",
+	}
+
+	account, err = updateAccount(account, tx)
+	require.NoError(t, err)
+	assert.True(t, len(account.EBalances) == 2)
+	assert.True(t, len(account.EBalances[newSymbol]) == 1)
+	assert.Equal(t, tx.Asset.ExternalSenderAddress, account.EBalances[newSymbol][newExtSenderAddress].Address)
+	assert.Equal(t, newExtSenderAddress, account.FirstExternalAddress[newSymbol])
+
+	// Tezos support
+	newXTZSymbol := "XTZ"
+	newTezosExtSenderAddress := "Tezos-Address"
+	// Third add XTZ
+	asset = &pluginproto.Asset{
+		Symbol:                newXTZSymbol,
+		ExternalSenderAddress: newTezosExtSenderAddress,
+		Nonce:                 3,
+		Network:               "Herdius",
+	}
+	tx = &pluginproto.Tx{
+		SenderAddress: "HHy1CuT3UxCGJ3BHydLEvR5ut6HRy2qUvm",
+		Asset:         asset,
+		Type:          "update",
+	}
+
+	account, err = updateAccount(account, tx)
+	require.NoError(t, err)
+	assert.True(t, len(account.EBalances) == 3)
+	assert.True(t, len(account.EBalances[newXTZSymbol]) == 1)
+	assert.Equal(t, tx.Asset.ExternalSenderAddress, account.EBalances[newXTZSymbol][newTezosExtSenderAddress].Address)
+	assert.Equal(t, newTezosExtSenderAddress, account.FirstExternalAddress[newXTZSymbol])
+}
+
+func TestUpdateExternalAccountBalance(t *testing.T) {
+	symbol := "ETH"
+	extSenderAddress := "0xD8f647855876549d2623f52126CE40D053a2ef6A"
+	asset := &pluginproto.Asset{
+		Symbol:                symbol,
+		ExternalSenderAddress: extSenderAddress,
+		Nonce:                 1,
+		Network:               "Herdius",
+	}
+	tx := &pluginproto.Tx{
+		SenderAddress: "HHy1CuT3UxCGJ3BHydLEvR5ut6HRy2qUvm",
+		Asset:         asset,
+		Type:          "update",
+	}
+	account := &statedb.Account{
+		Address:              "HHy1CuT3UxCGJ3BHydLEvR5ut6HRy2qUvm",
+		FirstExternalAddress: make(map[string]string),
+	}
+	account, err := updateAccount(account, tx)
+	require.NoError(t, err)
+	assert.True(t, len(account.EBalances) > 0)
+	assert.Equal(t, extSenderAddress, account.FirstExternalAddress[symbol])
+	assert.Equal(t, tx.Asset.ExternalSenderAddress, account.EBalances[symbol][extSenderAddress].Address)
+	assert.Equal(t, tx.Asset.ExternalSenderAddress, account.FirstExternalAddress[symbol])
+
+	asset = &pluginproto.Asset{
+		Symbol:                symbol,
+		ExternalSenderAddress: extSenderAddress,
+		Nonce:                 2,
+		Network:               "Herdius",
+		Value:                 15,
+	}
+	tx = &pluginproto.Tx{
+		SenderAddress: "HHy1CuT3UxCGJ3BHydLEvR5ut6HRy2qUvm",
+		Asset:         asset,
+		Type:          "update",
+	}
+
+	account, err = updateAccount(account, tx)
+	require.NoError(t, err)
+	assert.True(t, len(account.EBalances) > 0)
+	assert.Equal(t, tx.Asset.ExternalSenderAddress, account.EBalances[symbol][extSenderAddress].Address)
+	assert.Equal(t, uint64(0), account.EBalances[symbol][extSenderAddress].Balance)
+	assert.Equal(t, tx.Asset.ExternalSenderAddress, account.FirstExternalAddress[symbol])
+
+}
+
+func TestIsExternalAssetAddressExistTrue(t *testing.T) {
+	addr := "0xD8f647855876549d2623f52126CE40D053a2ef6A"
+	eBal := statedb.EBalance{Address: addr}
+	eBals := make(map[string]map[string]statedb.EBalance)
+	eBals["ETH"] = make(map[string]statedb.EBalance)
+	eBals["ETH"][addr] = eBal
+	account := &statedb.Account{
+		Address:   "HHy1CuT3UxCGJ3BHydLEvR5ut6HRy2qUvm",
+		EBalances: eBals,
+	}
+	assert.True(t, isExternalAssetAddressExist(account, "ETH", addr))
+}
+func TestIsExternalAssetAddressExistFalse(t *testing.T) {
+	addr := "0xD8f647855876549d2623f52126CE40D053a2ef6A"
+	eBals := make(map[string]map[string]statedb.EBalance)
+	account := &statedb.Account{
+		Address:   "HHy1CuT3UxCGJ3BHydLEvR5ut6HRy2qUvm",
+		EBalances: eBals,
+	}
+	assert.False(t, isExternalAssetAddressExist(account, "ETH", addr))
+}
+
+func TestExternalAssetWithdrawFromAnAccount(t *testing.T) {
+	addr := "0xD8f647855876549d2623f52126CE40D053a2ef6A"
+	eBal := statedb.EBalance{Balance: 10, Address: addr}
+	eBals := make(map[string]map[string]statedb.EBalance)
+	eBals["ETH"] = make(map[string]statedb.EBalance)
+	eBals["ETH"][addr] = eBal
+	account := &statedb.Account{
+		Address:   "HHy1CuT3UxCGJ3BHydLEvR5ut6HRy2qUvm",
+		EBalances: eBals,
+	}
+	require.NoError(t, withdraw(account, "ETH", addr, 5))
+	assert.Equal(t, uint64(5), account.EBalances["ETH"][addr].Balance)
+
+	assert.True(t, errors.Is(withdraw(account, "ETH", addr, 6), ErrInsufficientBalance))
+	assert.Equal(t, uint64(5), account.EBalances["ETH"][addr].Balance)
+}
+
+func TestExternalAssetDepositToAnAccount(t *testing.T) {
+	addr := "0xD8f647855876549d2623f52126CE40D053a2ef6A"
+	eBal := statedb.EBalance{Balance: 10, Address: addr}
+	eBals := make(map[string]map[string]statedb.EBalance)
+	eBals["ETH"] = make(map[string]statedb.EBalance)
+	eBals["ETH"][addr] = eBal
+	account := &statedb.Account{
+		Address:   "HHy1CuT3UxCGJ3BHydLEvR5ut6HRy2qUvm",
+		EBalances: eBals,
+	}
+	require.NoError(t, deposit(account, "ETH", addr, 5))
+	assert.Equal(t, uint64(15), account.EBalances["ETH"][addr].Balance)
+
+	assert.True(t, errors.Is(deposit(account, "ETH", addr, math.MaxUint64), ErrBalanceOverflow))
+	assert.Equal(t, uint64(15), account.EBalances["ETH"][addr].Balance)
+}
+
+func TestUpdateAccountLockedBalance(t *testing.T) {
+	symbol := "ETH"
+	lockedAmount := uint64(10)
+	extSenderAddress := "0xD8f647855876549d2623f52126CE40D053a2ef6A"
+	asset := &pluginproto.Asset{
+		Symbol:                symbol,
+		ExternalSenderAddress: extSenderAddress,
+		Nonce:                 1,
+		Network:               "Herdius",
+		LockedAmount:          lockedAmount,
+	}
+	tx := &pluginproto.Tx{
+		SenderAddress: "HHy1CuT3UxCGJ3BHydLEvR5ut6HRy2qUvm",
+		Asset:         asset,
+		Type:          "lock",
+	}
+
+	extAddr := "0xD8f647855876549d2623f52126CE40D053a2ef6A"
+
+	eBalance := statedb.EBalance{
+		Address: extAddr,
+		Balance: lockedAmount,
+	}
+	eBalances := make(map[string]map[string]statedb.EBalance)
+	eBalances[symbol] = make(map[string]statedb.EBalance)
+	eBalances[symbol][extAddr] = eBalance
+
+	account := &statedb.Account{
+		Address:              "HHy1CuT3UxCGJ3BHydLEvR5ut6HRy2qUvm",
+		FirstExternalAddress: make(map[string]string),
+		EBalances:            eBalances,
+	}
+	account, err := updateAccountLockedBalance(account, tx)
+	require.NoError(t, err)
+	assert.Equal(t, lockedAmount, account.LockedBalance[symbol][extSenderAddress])
+	assert.Equal(t, uint64(0), account.EBalances[symbol][extAddr].Balance)
+
+	// The locked amount has to be covered by the balance
+	_, err = updateAccountLockedBalance(account, tx)
+	assert.True(t, errors.Is(err, ErrInsufficientBalance))
+}
+func TestUpdateAccountLockedBalanceMintHBTCFirst(t *testing.T) {
+	symbol := "BTC"
+	lockedAmount := uint64(10)
+	extSenderAddress := "0xD8f647855876549d2623f52126CE40D053a2ef6A"
+	asset := &pluginproto.Asset{
+		Symbol:                symbol,
+		ExternalSenderAddress: extSenderAddress,
+		Nonce:                 1,
+		Network:               "Herdius",
+		LockedAmount:          lockedAmount,
+		Value:                 1,
+	}
+	tx := &pluginproto.Tx{
+		SenderAddress: "HHy1CuT3UxCGJ3BHydLEvR5ut6HRy2qUvm",
+		Asset:         asset,
+		Type:          "lock",
+	}
+
+	extAddr := "0xD8f647855876549d2623f52126CE40D053a2ef6A"
+
+	eBalance := statedb.EBalance{
+		Address: extAddr,
+		Balance: lockedAmount,
+	}
+	eBalances := make(map[string]map[string]statedb.EBalance)
+	eBalances[symbol] = make(map[string]statedb.EBalance)
+	eBalances[symbol][extAddr] = eBalance
+
+	account := &statedb.Account{
+		Address:              "HHy1CuT3UxCGJ3BHydLEvR5ut6HRy2qUvm",
+		FirstExternalAddress: make(map[string]string),
+		EBalances:            eBalances,
+	}
+	account.FirstExternalAddress["ETH"] = "0xD8f647855876549d2623f52126CE40D053a2ef6A"
+	account, err := updateAccountLockedBalance(account, tx)
+	require.NoError(t, err)
+	assert.Equal(t, lockedAmount, account.LockedBalance[symbol][extSenderAddress])
+	assert.Equal(t, uint64(0), account.EBalances["HBTC"]["0xD8f647855876549d2623f52126CE40D053a2ef6A"].Balance)
+
+}
+
+func TestUpdateRedeemAccountLockedBalance(t *testing.T) {
+	symbol := "ETH"
+	lockedAmount := uint64(10)
+	extSenderAddress := "0xD8f647855876549d2623f52126CE40D053a2ef6A"
+	asset := &pluginproto.Asset{
+		Symbol:                symbol,
+		ExternalSenderAddress: extSenderAddress,
+		Nonce:                 1,
+		Network:               "Herdius",
+		LockedAmount:          lockedAmount,
+	}
+	tx := &pluginproto.Tx{
+		SenderAddress: "HHy1CuT3UxCGJ3BHydLEvR5ut6HRy2qUvm",
+		Asset:         asset,
+		Type:          "lock",
+	}
+
+	extAddr := "0xD8f647855876549d2623f52126CE40D053a2ef6A"
+
+	eBalance := statedb.EBalance{
+		Address: extAddr,
+		Balance: lockedAmount,
+	}
+	eBalances := make(map[string]map[string]statedb.EBalance)
+	eBalances[symbol] = make(map[string]statedb.EBalance)
+	eBalances[symbol][extAddr] = eBalance
+	account := &statedb.Account{
+		Address:              "HHy1CuT3UxCGJ3BHydLEvR5ut6HRy2qUvm",
+		FirstExternalAddress: make(map[string]string),
+		EBalances:            eBalances,
+	}
+	account, err := updateAccountLockedBalance(account, tx)
+	require.NoError(t, err)
+	assert.Equal(t, lockedAmount, account.LockedBalance[symbol][extSenderAddress])
+
+	// Redeem test
+	symbol = "ETH"
+	value := uint64(5)
+	extSenderAddress = "0xD8f647855876549d2623f52126CE40D053a2ef6A"
+	asset = &pluginproto.Asset{
+		Symbol:                symbol,
+		ExternalSenderAddress: extSenderAddress,
+		Nonce:                 2,
+		Network:               "Herdius",
+		RedeemedAmount:        value,
+	}
+	tx = &pluginproto.Tx{
+		SenderAddress: "HHy1CuT3UxCGJ3BHydLEvR5ut6HRy2qUvm",
+		Asset:         asset,
+		Type:          "redeem",
+	}
+
+	account, err = updateRedeemAccountLockedBalance(account, tx)
+	require.NoError(t, err)
+	assert.Equal(t, value, account.LockedBalance[symbol][extSenderAddress])
+	// The redeemed amount is credited once
+	assert.Equal(t, value, account.EBalances[symbol][extAddr].Balance)
+
+	tx.Asset.RedeemedAmount = value + 1
+	_, err = updateRedeemAccountLockedBalance(account, tx)
+	assert.True(t, errors.Is(err, ErrInsufficientBalance))
+}
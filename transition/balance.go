@@ -0,0 +1,24 @@
+package transition
+
+import (
+	"fmt"
+	"math"
+)
+
+// AddBalance returns balance credited with amount, or ErrBalanceOverflow if
+// the result doesn't fit a balance
+func AddBalance(balance, amount uint64) (uint64, error) {
+	if balance > math.MaxUint64-amount {
+		return balance, fmt.Errorf("%w: %d + %d", ErrBalanceOverflow, balance, amount)
+	}
+	return balance + amount, nil
+}
+
+// SubBalance returns balance debited with amount, or ErrInsufficientBalance if
+// balance can't cover amount
+func SubBalance(balance, amount uint64) (uint64, error) {
+	if balance < amount {
+		return balance, fmt.Errorf("%w: balance (%d) can't cover %d", ErrInsufficientBalance, balance, amount)
+	}
+	return balance - amount, nil
+}
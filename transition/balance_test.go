@@ -0,0 +1,153 @@
+package transition
+
+import (
+	"bytes"
+	"errors"
+	"math"
+	"math/big"
+	"math/rand"
+	"testing"
+	"testing/quick"
+
+	"github.com/herdius/herdius-core/asset"
+	"github.com/herdius/herdius-core/crypto/secp256k1"
+	"github.com/herdius/herdius-core/storage/state/statedb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBalanceArithmetic(t *testing.T) {
+	balance, err := AddBalance(math.MaxUint64-1, 1)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(math.MaxUint64), balance)
+	_, err = AddBalance(math.MaxUint64, 1)
+	assert.True(t, errors.Is(err, ErrBalanceOverflow))
+
+	balance, err = SubBalance(1, 1)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(0), balance)
+	_, err = SubBalance(0, 1)
+	assert.True(t, errors.Is(err, ErrInsufficientBalance))
+}
+
+// supplies sums the balances of each backing asset over accounts. HER includes
+// the fees, which are credited to the reward account once the block is created.
+func supplies(t *testing.T, state statedb.Trie, addresses []string, fees uint64) map[string]string {
+	total := map[string]*big.Int{"HER": new(big.Int).SetUint64(fees)}
+	add := func(symbol string, balance uint64) {
+		symbol = asset.GetRegistry().Backing(symbol)
+		if total[symbol] == nil {
+			total[symbol] = new(big.Int)
+		}
+		total[symbol].Add(total[symbol], new(big.Int).SetUint64(balance))
+	}
+	for _, address := range addresses {
+		acc := account(t, state, address)
+		add("HER", acc.Balance)
+		for symbol, eBalances := range acc.EBalances {
+			for _, eBalance := range eBalances {
+				add(symbol, eBalance.Balance)
+			}
+		}
+		for symbol, locked := range acc.LockedBalance {
+			for _, balance := range locked {
+				add(symbol, balance)
+			}
+		}
+	}
+	sums := make(map[string]string, len(total))
+	for symbol, sum := range total {
+		sums[symbol] = sum.String()
+	}
+	return sums
+}
+
+// TestSupplyConservation applies random batches of transfers, locks and redeems,
+// with amounts that often exceed or overflow the balances, and checks no tx
+// mints or burns any asset and that rejected txs don't change the state.
+func TestSupplyConservation(t *testing.T) {
+	const numAccounts, numTxs = 4, 30
+	keys := make([]secp256k1.PrivKeySecp256k1, numAccounts)
+	addresses := make([]string, numAccounts)
+	ethAddresses := []string{
+		"0xD8f647855876549d2623f52126CE40D053a2ef6A",
+		"0x2a65Aca4D5fC5B5C859090a6c34d164135398226",
+		"0x0a1D2aB6E4b2B4A9F5e1c3B6c0E1D4f7A8b9C0d1",
+		"0x7E5F4552091A69125d5DfCb7b8C2659029395Bdf",
+	}
+	for i := range keys {
+		keys[i] = secp256k1.GenPrivKey()
+		addresses[i] = keys[i].PubKey().GetAddress()
+	}
+
+	property := func(seed int64) bool {
+		r := rand.New(rand.NewSource(seed))
+		// Balances are small, large or close to overflowing
+		balance := func() uint64 {
+			switch r.Intn(3) {
+			case 0:
+				return uint64(r.Intn(100))
+			case 1:
+				return uint64(r.Int63())
+			}
+			return math.MaxUint64 - uint64(r.Intn(100))
+		}
+		accounts := make([]*statedb.Account, numAccounts)
+		for i := range accounts {
+			btcAddress := "btc-" + addresses[i]
+			accounts[i] = &statedb.Account{
+				Address: addresses[i],
+				Balance: balance(),
+				EBalances: map[string]map[string]statedb.EBalance{
+					"ETH":  {ethAddresses[i]: {Address: ethAddresses[i], Balance: balance() / 2}},
+					"BTC":  {btcAddress: {Address: btcAddress, Balance: balance() / 4}},
+					"HBTC": {ethAddresses[i]: {Address: ethAddresses[i], Balance: balance() / 4}},
+				},
+				FirstExternalAddress: map[string]string{"ETH": ethAddresses[i], "BTC": btcAddress},
+			}
+			if r.Intn(2) == 0 {
+				accounts[i].LockedBalance = map[string]map[string]uint64{"ETH": {ethAddresses[i]: uint64(r.Intn(100))}}
+			}
+		}
+		state := newState(t, accounts...)
+
+		fees := uint64(0)
+		before := supplies(t, state, addresses, fees)
+		for n := 0; n < numTxs; n++ {
+			i := r.Intn(numAccounts)
+			amount := balance()
+			tx := herTransfer(addresses[r.Intn(numAccounts)], 0, uint64(r.Intn(3)), account(t, state, addresses[i]).Nonce+1)
+			symbol := []string{"ETH", "BTC", "HBTC"}[r.Intn(3)]
+			address := accounts[i].FirstExternalAddress[symbol]
+			if symbol == "HBTC" {
+				address = ethAddresses[i]
+			}
+			switch r.Intn(4) {
+			case 0:
+				tx.Asset.Value = amount
+			case 1:
+				tx.Asset.Symbol, tx.Asset.ExternalSenderAddress, tx.Asset.Value = symbol, address, amount
+			case 2:
+				tx.Type, tx.RecieverAddress = "Lock", "Hx00000000000000000000000000000000"
+				tx.Asset.Symbol, tx.Asset.ExternalSenderAddress, tx.Asset.LockedAmount = symbol, address, amount%200
+			case 3:
+				tx.Type, tx.RecieverAddress = "Redeem", ""
+				tx.Asset.Symbol, tx.Asset.ExternalSenderAddress, tx.Asset.RedeemedAmount = symbol, address, amount%200
+			}
+
+			root := state.Hash()
+			receipt, err := ApplyTx(state, sign(t, keys[i], tx), 1)
+			if err != nil && !bytes.Equal(root, state.Hash()) {
+				t.Logf("seed %d: rejected tx changed the state: %v", seed, err)
+				return false
+			}
+			fees += receipt.Fee
+		}
+		after := supplies(t, state, addresses, fees)
+		if !assert.Equal(t, before, after, "seed %d", seed) {
+			return false
+		}
+		return true
+	}
+	require.NoError(t, quick.Check(property, &quick.Config{MaxCount: 50}))
+}
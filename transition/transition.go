@@ -0,0 +1,442 @@
+// Package transition applies transactions to the account state.
+// ApplyTx depends on nothing but the state, the tx and the height of its
+// block, so the supervisor, the validators and block replays all apply txs by
+// the same rules.
+package transition
+
+import (
+	b64 "encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/herdius/herdius-core/asset"
+	"github.com/herdius/herdius-core/crypto/secp256k1"
+	pluginproto "github.com/herdius/herdius-core/hbi/protobuf"
+	"github.com/herdius/herdius-core/storage/state/statedb"
+	txbyte "github.com/herdius/herdius-core/tx"
+)
+
+const (
+	// StatusSuccess is the status of a tx applied to the state
+	StatusSuccess = "success"
+	// StatusFailed is the status of a tx the state transition rejected
+	StatusFailed = "failed"
+)
+
+var (
+	// ErrInvalidTx is returned when the tx misses required fields
+	ErrInvalidTx = errors.New("invalid tx")
+	// ErrInvalidSignature is returned when the tx is not signed by the sender's key
+	ErrInvalidSignature = errors.New("invalid tx signature")
+	// ErrSenderMismatch is returned when the sender address doesn't belong to the sender's key
+	ErrSenderMismatch = errors.New("sender address does not match sender public key")
+	// ErrNonceTooLow is returned when the tx nonce was already used by the sender
+	ErrNonceTooLow = errors.New("tx nonce too low")
+	// ErrUnknownAccount is returned when the tx requires an account that is not registered
+	ErrUnknownAccount = errors.New("account does not exist")
+	// ErrUnsupportedAsset is returned when the tx asset can't be used for the tx type
+	ErrUnsupportedAsset = errors.New("unsupported asset")
+	// ErrNoExternalAddress is returned when the account has no address for the tx asset
+	ErrNoExternalAddress = errors.New("account has no external address for asset")
+	// ErrInsufficientBalance is returned when the account can't cover the tx amounts
+	ErrInsufficientBalance = errors.New("insufficient balance")
+	// ErrWrongChain is returned when the tx is not signed for the chain it is applied to
+	ErrWrongChain = errors.New("tx signed for another chain")
+	// ErrBalanceOverflow is returned when a credit overflows a balance
+	ErrBalanceOverflow = errors.New("balance overflow")
+)
+
+// Codes of the reasons a tx failed, recorded in its receipt
+const (
+	CodeOK uint32 = iota
+	CodeInvalidTx
+	CodeInvalidSignature
+	CodeSenderMismatch
+	CodeNonceTooLow
+	CodeUnknownAccount
+	CodeUnsupportedAsset
+	CodeNoExternalAddress
+	CodeInsufficientBalance
+	// CodeInternal is the code of failures not caused by the tx, e.g. state db errors
+	CodeInternal
+	CodeWrongChain
+	CodeBalanceOverflow
+)
+
+var errorCodes = []struct {
+	err  error
+	code uint32
+}{
+	{ErrInvalidTx, CodeInvalidTx},
+	{ErrInvalidSignature, CodeInvalidSignature},
+	{ErrSenderMismatch, CodeSenderMismatch},
+	{ErrNonceTooLow, CodeNonceTooLow},
+	{ErrUnknownAccount, CodeUnknownAccount},
+	{ErrUnsupportedAsset, CodeUnsupportedAsset},
+	{ErrNoExternalAddress, CodeNoExternalAddress},
+	{ErrInsufficientBalance, CodeInsufficientBalance},
+	{ErrWrongChain, CodeWrongChain},
+	{ErrBalanceOverflow, CodeBalanceOverflow},
+}
+
+// ErrorCode returns the receipt code of err
+func ErrorCode(err error) uint32 {
+	if err == nil {
+		return CodeOK
+	}
+	for _, ec := range errorCodes {
+		if errors.Is(err, ec.err) {
+			return ec.code
+		}
+	}
+	return CodeInternal
+}
+
+var (
+	chainIDMutex     sync.RWMutex
+	chainID          string
+	legacySignHeight int64
+)
+
+// SetChainID sets the ID of the chain txs are applied to. Once it is set,
+// txs signed in the canonical sign format have to be signed for the chain.
+func SetChainID(id string) {
+	chainIDMutex.Lock()
+	defer chainIDMutex.Unlock()
+	chainID = id
+}
+
+// ChainID returns the ID of the chain txs are applied to
+func ChainID() string {
+	chainIDMutex.RLock()
+	defer chainIDMutex.RUnlock()
+	return chainID
+}
+
+// SetLegacySignHeight sets the height of the first block whose txs have to
+// be signed for the chain ID. Txs signed with the legacy sign version, which
+// doesn't sign the chain ID, are only accepted in the blocks below it, so
+// that a chain holding them can move to the canonical sign version. 0
+// rejects them in every block.
+func SetLegacySignHeight(height int64) {
+	chainIDMutex.Lock()
+	defer chainIDMutex.Unlock()
+	legacySignHeight = height
+}
+
+// LegacySignHeight returns the height from which legacy signed txs are
+// rejected, 0 if they are rejected in every block
+func LegacySignHeight() int64 {
+	chainIDMutex.RLock()
+	defer chainIDMutex.RUnlock()
+	return legacySignHeight
+}
+
+// Configure sets the chain txs are applied to, as configured for the node.
+// Txs signed for other chains are rejected, as are legacy signed txs from
+// the block at legacyHeight on. Every node of the chain has to configure the
+// same.
+func Configure(id string, legacyHeight int64) {
+	chainIDMutex.Lock()
+	defer chainIDMutex.Unlock()
+	chainID = id
+	legacySignHeight = legacyHeight
+}
+
+// Receipt is the outcome of applying a tx to the state
+type Receipt struct {
+	Status  string
+	Code    uint32 // why the tx failed, CodeOK if it succeeded
+	Message string // error message of a failed tx
+	Fee     uint64 // HER fee debited from the sender
+}
+
+// failedReceipt returns the receipt of a tx rejected with err
+func failedReceipt(err error) Receipt {
+	return Receipt{Status: StatusFailed, Code: ErrorCode(err), Message: err.Error()}
+}
+
+// ApplyTx verifies tx against state and applies it to state, as a tx of the
+// block at height. Transfers, External, Update, Lock and Redeem txs are
+// supported. If the tx is rejected the error says why and the receipt has
+// StatusFailed.
+func ApplyTx(state statedb.Trie, tx *pluginproto.Tx, height int64) (Receipt, error) {
+	receipt, err := applyTx(state, tx, height)
+	if err != nil {
+		return failedReceipt(err), err
+	}
+	return receipt, nil
+}
+
+func applyTx(state statedb.Trie, tx *pluginproto.Tx, height int64) (Receipt, error) {
+	if tx == nil || tx.Asset == nil {
+		return Receipt{}, fmt.Errorf("%w: tx has no asset", ErrInvalidTx)
+	}
+	if err := VerifySign(tx, height); err != nil {
+		return Receipt{}, err
+	}
+	sender, err := getAccount(state, tx.SenderAddress)
+	if err != nil {
+		return Receipt{}, err
+	}
+	if len(sender.Address) > 0 && tx.Asset.Nonce <= sender.Nonce {
+		return Receipt{}, fmt.Errorf("%w: tx nonce %d should be greater than account nonce %d", ErrNonceTooLow, tx.Asset.Nonce, sender.Nonce)
+	}
+
+	receipt := Receipt{Status: StatusSuccess}
+	switch strings.ToUpper(tx.Type) {
+	case "UPDATE":
+		err = applyUpdate(state, sender, tx)
+	case "EXTERNAL":
+		err = applyExternal(state, sender, tx)
+	case "LOCK":
+		err = applyLock(state, sender, tx)
+	case "REDEEM":
+		err = applyRedeem(state, sender, tx)
+	default:
+		receipt.Fee, err = applyTransfer(state, sender, tx)
+	}
+	if err != nil {
+		return Receipt{}, err
+	}
+	return receipt, nil
+}
+
+// applyUpdate registers the sender's account or adds an external address to it
+func applyUpdate(state statedb.Trie, sender *statedb.Account, tx *pluginproto.Tx) error {
+	if len(sender.Address) == 0 && !strings.EqualFold(tx.Asset.Symbol, "HER") {
+		return fmt.Errorf("%w: %v must be registered before adding %v addresses", ErrUnknownAccount, tx.SenderAddress, tx.Asset.Symbol)
+	}
+	sender, err := updateAccount(sender, tx)
+	if err != nil {
+		return err
+	}
+	return putAccount(state, tx.SenderAddress, sender)
+}
+
+// applyExternal debits the tx value from the sender's external asset balance
+func applyExternal(state statedb.Trie, sender *statedb.Account, tx *pluginproto.Tx) error {
+	symbol := strings.ToUpper(tx.Asset.Symbol)
+	if !asset.GetRegistry().IsExternal(symbol) {
+		return fmt.Errorf("%w: external asset symbol %v", ErrUnsupportedAsset, tx.Asset.Symbol)
+	}
+	eBalance, err := externalBalance(sender, symbol, tx.Asset.ExternalSenderAddress)
+	if err != nil {
+		return err
+	}
+	if eBalance.Balance < tx.Asset.Value {
+		return fmt.Errorf("%w: %v balance (%d) can't cover %d", ErrInsufficientBalance, symbol, eBalance.Balance, tx.Asset.Value)
+	}
+	if err := withdraw(sender, symbol, tx.Asset.ExternalSenderAddress, tx.Asset.Value); err != nil {
+		return err
+	}
+	sender.Nonce = tx.Asset.Nonce
+	return putAccount(state, tx.SenderAddress, sender)
+}
+
+// applyLock moves the locked amount of the sender's external asset balance to its locked balance
+func applyLock(state statedb.Trie, sender *statedb.Account, tx *pluginproto.Tx) error {
+	symbol := strings.ToUpper(tx.Asset.Symbol)
+	eBalance, err := externalBalance(sender, symbol, tx.Asset.ExternalSenderAddress)
+	if err != nil {
+		return err
+	}
+	if eBalance.Balance < tx.Asset.LockedAmount {
+		return fmt.Errorf("%w: %v balance (%d) can't cover locked amount %d", ErrInsufficientBalance, symbol, eBalance.Balance, tx.Asset.LockedAmount)
+	}
+	sender, err = updateAccountLockedBalance(sender, tx)
+	if err != nil {
+		return err
+	}
+	return putAccount(state, tx.SenderAddress, sender)
+}
+
+// applyRedeem moves the redeemed amount of the sender's locked balance back to its external asset balance
+func applyRedeem(state statedb.Trie, sender *statedb.Account, tx *pluginproto.Tx) error {
+	if sender.LockedBalance == nil {
+		return fmt.Errorf("%w: %v has no locked balance to redeem", ErrInsufficientBalance, tx.SenderAddress)
+	}
+	symbol := strings.ToUpper(tx.Asset.Symbol)
+	backing := asset.GetRegistry().Backing(symbol)
+	extAddress := tx.Asset.ExternalSenderAddress
+	if len(sender.EBalances[backing]) == 0 {
+		return fmt.Errorf("%w: %v", ErrNoExternalAddress, backing)
+	}
+	if locked, ok := sender.LockedBalance[backing]; ok {
+		if locked[extAddress] < tx.Asset.RedeemedAmount {
+			return fmt.Errorf("%w: %v locked balance (%d) can't cover redeemed amount %d", ErrInsufficientBalance, backing, locked[extAddress], tx.Asset.RedeemedAmount)
+		}
+	} else if backing != symbol {
+		eBalance, err := externalBalance(sender, symbol, extAddress)
+		if err != nil {
+			return err
+		}
+		if eBalance.Balance < tx.Asset.RedeemedAmount {
+			return fmt.Errorf("%w: %v balance (%d) can't cover redeemed amount %d", ErrInsufficientBalance, symbol, eBalance.Balance, tx.Asset.RedeemedAmount)
+		}
+	} else {
+		return fmt.Errorf("%w: %v has no locked %v to redeem", ErrInsufficientBalance, tx.SenderAddress, backing)
+	}
+	sender, err := updateRedeemAccountLockedBalance(sender, tx)
+	if err != nil {
+		return err
+	}
+	return putAccount(state, tx.SenderAddress, sender)
+}
+
+// applyTransfer moves the tx value from the sender to the receiver account and debits
+// the HER fee from the sender. It returns the fee. Txs of other networks don't change the state.
+func applyTransfer(state statedb.Trie, sender *statedb.Account, tx *pluginproto.Tx) (uint64, error) {
+	if !strings.EqualFold(tx.Asset.Network, "Herdius") {
+		return 0, nil
+	}
+	if len(sender.Address) == 0 {
+		return 0, fmt.Errorf("%w: sender %v", ErrUnknownAccount, tx.SenderAddress)
+	}
+	isHER := strings.EqualFold(tx.Asset.Symbol, "HER")
+	if !isHER && !isExternalAssetAddressExist(sender, tx.Asset.Symbol, tx.Asset.ExternalSenderAddress) {
+		return 0, fmt.Errorf("%w: sender has no %v address %v", ErrNoExternalAddress, tx.Asset.Symbol, tx.Asset.ExternalSenderAddress)
+	}
+
+	receiver := sender
+	if tx.RecieverAddress != tx.SenderAddress {
+		var err error
+		receiver, err = getAccount(state, tx.RecieverAddress)
+		if err != nil {
+			return 0, err
+		}
+		if len(receiver.Address) == 0 {
+			return 0, fmt.Errorf("%w: receiver %v", ErrUnknownAccount, tx.RecieverAddress)
+		}
+	}
+	if !isHER && len(receiver.EBalances[tx.Asset.Symbol]) == 0 {
+		return 0, fmt.Errorf("%w: receiver has no %v address", ErrNoExternalAddress, tx.Asset.Symbol)
+	}
+	if err := verifyBalance(sender, tx); err != nil {
+		return 0, err
+	}
+
+	// Nothing is stored unless all balances are updated, so a failed tx doesn't change the state
+	if err := withdraw(sender, "HER", "", tx.Asset.Fee); err != nil {
+		return 0, err
+	}
+	if err := withdraw(sender, tx.Asset.Symbol, tx.Asset.ExternalSenderAddress, tx.Asset.Value); err != nil {
+		return 0, err
+	}
+	// If credit to external address, pick first account
+	// TODO: Should we consider tx.Asset.ExternalRecieverAddress?
+	if err := deposit(receiver, tx.Asset.Symbol, receiver.FirstExternalAddress[tx.Asset.Symbol], tx.Asset.Value); err != nil {
+		return 0, err
+	}
+	sender.Nonce = tx.Asset.Nonce
+
+	if err := putAccount(state, tx.SenderAddress, sender); err != nil {
+		return 0, err
+	}
+	if receiver != sender {
+		if err := putAccount(state, tx.RecieverAddress, receiver); err != nil {
+			return 0, err
+		}
+	}
+	return tx.Asset.Fee, nil
+}
+
+// getAccount reads the account at address from state.
+// An empty account is returned if address is not registered.
+func getAccount(state statedb.Trie, address string) (*statedb.Account, error) {
+	account := &statedb.Account{}
+	actbz, err := state.TryGet([]byte(address))
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve account %v: %v", address, err)
+	}
+	if len(actbz) > 0 {
+		if err := cdc.UnmarshalJSON(actbz, account); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal account %v: %v", address, err)
+		}
+	}
+	return account, nil
+}
+
+// putAccount stores account at address in state
+func putAccount(state statedb.Trie, address string, account *statedb.Account) error {
+	actbz, err := cdc.MarshalJSON(account)
+	if err != nil {
+		return fmt.Errorf("failed to marshal account %v: %v", address, err)
+	}
+	if err := state.TryUpdate([]byte(address), actbz); err != nil {
+		return fmt.Errorf("failed to store account %v in state db: %v", address, err)
+	}
+	return nil
+}
+
+// VerifySign verifies the tx is signed by the sender's key for the chain, as
+// required of the txs of the block at height, and the sender address belongs
+// to that key
+func VerifySign(tx *pluginproto.Tx, height int64) error {
+	pubKeyS, err := b64.StdEncoding.DecodeString(tx.GetSenderPubkey())
+	if err != nil {
+		return fmt.Errorf("%w: failed to decode sender public key: %v", ErrInvalidSignature, err)
+	}
+	var pubKey secp256k1.PubKeySecp256k1
+	copy(pubKey[:], pubKeyS)
+	if pubKey.GetAddress() != tx.GetSenderAddress() {
+		return fmt.Errorf("%w: %v", ErrSenderMismatch, tx.GetSenderAddress())
+	}
+
+	signBytes, err := txbyte.SignBytes(tx)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidSignature, err)
+	}
+	decodedSig, err := b64.StdEncoding.DecodeString(tx.Sign)
+	if err != nil {
+		return fmt.Errorf("%w: failed to decode the base64 sign: %v", ErrInvalidSignature, err)
+	}
+	if !pubKey.VerifyBytes(signBytes, decodedSig) {
+		return ErrInvalidSignature
+	}
+
+	// Txs signed for another chain, or for no chain at all, could be replayed
+	if id := ChainID(); len(id) > 0 {
+		if tx.SignVersion == txbyte.SignVersionLegacy {
+			if height < LegacySignHeight() {
+				return nil
+			}
+			return fmt.Errorf("%w: txs of chain %v have to be signed with sign version %d", ErrWrongChain, id, txbyte.SignVersionCanonical)
+		}
+		if tx.ChainId != id {
+			return fmt.Errorf("%w: tx of chain %q applied to chain %q", ErrWrongChain, tx.ChainId, id)
+		}
+	}
+	return nil
+}
+
+// verifyBalance checks the sender's account covers the tx value and the HER fee
+func verifyBalance(senderAccount *statedb.Account, tx *pluginproto.Tx) error {
+	value, fee := tx.GetAsset().GetValue(), tx.GetAsset().GetFee()
+	if strings.EqualFold(tx.GetAsset().GetSymbol(), "HER") {
+		if value+fee < value || senderAccount.Balance < value+fee {
+			return fmt.Errorf("%w: HER balance (%d) can't cover %d with fee %d", ErrInsufficientBalance, senderAccount.Balance, value, fee)
+		}
+		return nil
+	}
+	if senderAccount.Balance < fee {
+		return fmt.Errorf("%w: HER balance (%d) can't cover fee %d", ErrInsufficientBalance, senderAccount.Balance, fee)
+	}
+	symbol := strings.ToUpper(tx.GetAsset().GetSymbol())
+	eBalance := senderAccount.EBalances[symbol][tx.GetAsset().GetExternalSenderAddress()]
+	if eBalance.Balance < value {
+		return fmt.Errorf("%w: %v balance (%d) can't cover %d", ErrInsufficientBalance, symbol, eBalance.Balance, value)
+	}
+	return nil
+}
+
+// externalBalance returns the account balance of the asset at the external address
+func externalBalance(account *statedb.Account, symbol, address string) (statedb.EBalance, error) {
+	eBalance, ok := account.EBalances[symbol][address]
+	if !ok {
+		return statedb.EBalance{}, fmt.Errorf("%w: %v address %v", ErrNoExternalAddress, symbol, address)
+	}
+	return eBalance, nil
+}
@@ -0,0 +1,288 @@
+package transition
+
+import (
+	b64 "encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/herdius/herdius-core/crypto/secp256k1"
+	pluginproto "github.com/herdius/herdius-core/hbi/protobuf"
+	"github.com/herdius/herdius-core/storage/state/statedb"
+	txbyte "github.com/herdius/herdius-core/tx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const ethAddress = "0xD8f647855876549d2623f52126CE40D053a2ef6A"
+
+// sign signs tx with privKey the way clients do, filling in the sender fields
+func sign(t *testing.T, privKey secp256k1.PrivKeySecp256k1, tx *pluginproto.Tx) *pluginproto.Tx {
+	pubKey := privKey.PubKey().(secp256k1.PubKeySecp256k1)
+	tx.SenderAddress = pubKey.GetAddress()
+	tx.SenderPubkey = b64.StdEncoding.EncodeToString(pubKey[:])
+	tx.Sign = ""
+	txbz, err := json.Marshal(tx)
+	require.NoError(t, err)
+	sig, err := privKey.Sign(txbz)
+	require.NoError(t, err)
+	tx.Sign = b64.StdEncoding.EncodeToString(sig)
+	return tx
+}
+
+func newState(t *testing.T, accounts ...*statedb.Account) statedb.Trie {
+	state, err := statedb.NewMemTrie()
+	require.NoError(t, err)
+	for _, account := range accounts {
+		require.NoError(t, putAccount(state, account.Address, account))
+	}
+	return state
+}
+
+func account(t *testing.T, state statedb.Trie, address string) *statedb.Account {
+	account, err := getAccount(state, address)
+	require.NoError(t, err)
+	return account
+}
+
+func herTransfer(receiver string, value, fee, nonce uint64) *pluginproto.Tx {
+	return &pluginproto.Tx{
+		RecieverAddress: receiver,
+		Asset: &pluginproto.Asset{
+			Category: "crypto",
+			Symbol:   "HER",
+			Network:  "Herdius",
+			Value:    value,
+			Fee:      fee,
+			Nonce:    nonce,
+		},
+		Message: "transfer",
+	}
+}
+
+func TestApplyTxTransfer(t *testing.T) {
+	privKey := secp256k1.GenPrivKey()
+	sender := privKey.PubKey().GetAddress()
+	receiver := "HHy1CuT3UxCGJ3BHydLEvR5ut6HRy2qUvm"
+	state := newState(t,
+		&statedb.Account{Address: sender, Balance: 100, Nonce: 1},
+		&statedb.Account{Address: receiver},
+	)
+
+	receipt, err := ApplyTx(state, sign(t, privKey, herTransfer(receiver, 40, 2, 2)), 1)
+	require.NoError(t, err)
+	assert.Equal(t, Receipt{Status: StatusSuccess, Fee: 2}, receipt)
+	assert.Equal(t, uint64(58), account(t, state, sender).Balance)
+	assert.Equal(t, uint64(2), account(t, state, sender).Nonce)
+	assert.Equal(t, uint64(40), account(t, state, receiver).Balance)
+
+	// Sending to itself only costs the fee
+	receipt, err = ApplyTx(state, sign(t, privKey, herTransfer(sender, 10, 2, 3)), 1)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(56), account(t, state, sender).Balance)
+}
+
+func TestVerifySignChain(t *testing.T) {
+	privKey := secp256k1.GenPrivKey()
+	receiver := "HHy1CuT3UxCGJ3BHydLEvR5ut6HRy2qUvm"
+	canonical := func(chainID string) *pluginproto.Tx {
+		tx := herTransfer(receiver, 10, 1, 2)
+		require.NoError(t, txbyte.Sign(tx, privKey, chainID))
+		return tx
+	}
+
+	// Without a chain ID legacy and canonical signs are accepted
+	assert.NoError(t, VerifySign(sign(t, privKey, herTransfer(receiver, 10, 1, 2)), 1))
+	assert.NoError(t, VerifySign(canonical("herdius-dev"), 1))
+
+	SetChainID("herdius-dev")
+	defer SetChainID("")
+	assert.NoError(t, VerifySign(canonical("herdius-dev"), 1))
+	assert.True(t, errors.Is(VerifySign(canonical("herdius-staging"), 1), ErrWrongChain))
+
+	// Legacy signs are rejected unless accepted below a legacy sign height
+	legacy := sign(t, privKey, herTransfer(receiver, 10, 1, 2))
+	assert.True(t, errors.Is(VerifySign(legacy, 1), ErrWrongChain))
+	Configure("herdius-dev", 10)
+	defer SetLegacySignHeight(0)
+	assert.NoError(t, VerifySign(legacy, 9))
+	assert.True(t, errors.Is(VerifySign(legacy, 10), ErrWrongChain))
+	assert.NoError(t, VerifySign(canonical("herdius-dev"), 10))
+
+	// The chain ID is signed
+	tx := canonical("herdius-staging")
+	tx.ChainId = "herdius-dev"
+	assert.True(t, errors.Is(VerifySign(tx, 1), ErrInvalidSignature))
+}
+
+func TestApplyTxRejects(t *testing.T) {
+	privKey, other := secp256k1.GenPrivKey(), secp256k1.GenPrivKey()
+	sender := privKey.PubKey().GetAddress()
+	receiver := "HHy1CuT3UxCGJ3BHydLEvR5ut6HRy2qUvm"
+	rich := secp256k1.GenPrivKey().PubKey().GetAddress()
+	accounts := func() []*statedb.Account {
+		return []*statedb.Account{
+			{
+				Address: sender,
+				Balance: 100,
+				Nonce:   1,
+				EBalances: map[string]map[string]statedb.EBalance{
+					"ETH": {ethAddress: {Address: ethAddress, Balance: 10}},
+				},
+				FirstExternalAddress: map[string]string{"ETH": ethAddress},
+			},
+			{Address: receiver},
+			{Address: rich, Balance: math.MaxUint64},
+		}
+	}
+
+	tests := []struct {
+		name string
+		tx   func() *pluginproto.Tx
+		err  error
+	}{
+		{"insufficient balance", func() *pluginproto.Tx {
+			return sign(t, privKey, herTransfer(receiver, 100, 1, 2))
+		}, ErrInsufficientBalance},
+		{"value and fee overflow", func() *pluginproto.Tx {
+			return sign(t, privKey, herTransfer(receiver, ^uint64(0), 1, 2))
+		}, ErrInsufficientBalance},
+		{"receiver balance overflow", func() *pluginproto.Tx {
+			return sign(t, privKey, herTransfer(rich, 10, 1, 2))
+		}, ErrBalanceOverflow},
+		{"stale nonce", func() *pluginproto.Tx {
+			return sign(t, privKey, herTransfer(receiver, 10, 0, 1))
+		}, ErrNonceTooLow},
+		{"unknown receiver", func() *pluginproto.Tx {
+			return sign(t, privKey, herTransfer("HKFpxVGG7Zgo7P38kqe4cXbDkmn7v7a7Sb", 10, 0, 2))
+		}, ErrUnknownAccount},
+		{"unknown sender", func() *pluginproto.Tx {
+			return sign(t, other, herTransfer(receiver, 0, 0, 1))
+		}, ErrUnknownAccount},
+		{"tampered tx", func() *pluginproto.Tx {
+			tx := sign(t, privKey, herTransfer(receiver, 10, 0, 2))
+			tx.Asset.Value = 20
+			return tx
+		}, ErrInvalidSignature},
+		{"forged sender", func() *pluginproto.Tx {
+			tx := sign(t, other, herTransfer(receiver, 10, 0, 2))
+			tx.SenderAddress = sender
+			return tx
+		}, ErrSenderMismatch},
+		{"receiver without external address", func() *pluginproto.Tx {
+			tx := herTransfer(receiver, 5, 0, 2)
+			tx.Asset.Symbol = "ETH"
+			tx.Asset.ExternalSenderAddress = ethAddress
+			return sign(t, privKey, tx)
+		}, ErrNoExternalAddress},
+		{"unsupported external asset", func() *pluginproto.Tx {
+			tx := herTransfer(receiver, 5, 0, 2)
+			tx.Type = "External"
+			tx.Asset.Symbol = "DOGE"
+			return sign(t, privKey, tx)
+		}, ErrUnsupportedAsset},
+		{"external without address", func() *pluginproto.Tx {
+			tx := herTransfer(receiver, 5, 0, 2)
+			tx.Type = "External"
+			tx.Asset.Symbol = "BTC"
+			return sign(t, privKey, tx)
+		}, ErrNoExternalAddress},
+		{"lock more than balance", func() *pluginproto.Tx {
+			tx := herTransfer(receiver, 0, 0, 2)
+			tx.Type = "Lock"
+			tx.Asset.Symbol = "ETH"
+			tx.Asset.ExternalSenderAddress = ethAddress
+			tx.Asset.LockedAmount = 11
+			return sign(t, privKey, tx)
+		}, ErrInsufficientBalance},
+		{"redeem without lock", func() *pluginproto.Tx {
+			tx := herTransfer(receiver, 0, 0, 2)
+			tx.Type = "Redeem"
+			tx.Asset.Symbol = "ETH"
+			tx.Asset.ExternalSenderAddress = ethAddress
+			tx.Asset.RedeemedAmount = 1
+			return sign(t, privKey, tx)
+		}, ErrInsufficientBalance},
+		{"external address of unregistered account", func() *pluginproto.Tx {
+			tx := herTransfer("", 0, 0, 1)
+			tx.Type = "Update"
+			tx.Asset.Symbol = "ETH"
+			tx.Asset.ExternalSenderAddress = ethAddress
+			return sign(t, other, tx)
+		}, ErrUnknownAccount},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			state := newState(t, accounts()...)
+			root := state.Hash()
+			receipt, err := ApplyTx(state, tt.tx(), 1)
+			assert.True(t, errors.Is(err, tt.err), "unexpected error: %v", err)
+			assert.Equal(t, StatusFailed, receipt.Status)
+			assert.Equal(t, ErrorCode(tt.err), receipt.Code)
+			assert.Equal(t, err.Error(), receipt.Message)
+			assert.Equal(t, root, state.Hash(), "rejected tx changed the state")
+		})
+	}
+}
+
+func TestErrorCode(t *testing.T) {
+	assert.Equal(t, CodeOK, ErrorCode(nil))
+	assert.Equal(t, CodeNonceTooLow, ErrorCode(fmt.Errorf("%w: nonce 1", ErrNonceTooLow)))
+	assert.Equal(t, CodeInternal, ErrorCode(errors.New("state db failure")))
+}
+
+func TestApplyTxUpdateLockRedeem(t *testing.T) {
+	privKey := secp256k1.GenPrivKey()
+	sender := privKey.PubKey().GetAddress()
+	state := newState(t)
+
+	// Register the account, then its ETH address
+	register := herTransfer("", 0, 0, 0)
+	register.Type = "Update"
+	_, err := ApplyTx(state, sign(t, privKey, register), 1)
+	require.NoError(t, err)
+	assert.Equal(t, sender, account(t, state, sender).Address)
+
+	update := herTransfer("", 0, 0, 1)
+	update.Type = "Update"
+	update.Asset.Symbol = "ETH"
+	update.Asset.ExternalSenderAddress = ethAddress
+	_, err = ApplyTx(state, sign(t, privKey, update), 1)
+	require.NoError(t, err)
+	acc := account(t, state, sender)
+	assert.Equal(t, ethAddress, acc.FirstExternalAddress["ETH"])
+
+	// Fund the ETH address as the external balance sync does
+	eBalance := acc.EBalances["ETH"][ethAddress]
+	eBalance.Balance = 10
+	acc.EBalances["ETH"][ethAddress] = eBalance
+	require.NoError(t, putAccount(state, sender, acc))
+
+	lock := herTransfer("", 0, 0, 2)
+	lock.Type = "Lock"
+	lock.Asset.Symbol = "ETH"
+	lock.Asset.ExternalSenderAddress = ethAddress
+	lock.Asset.LockedAmount = 4
+	_, err = ApplyTx(state, sign(t, privKey, lock), 1)
+	require.NoError(t, err)
+	acc = account(t, state, sender)
+	assert.Equal(t, uint64(6), acc.EBalances["ETH"][ethAddress].Balance)
+	assert.Equal(t, uint64(4), acc.LockedBalance["ETH"][ethAddress])
+
+	redeem := herTransfer("", 0, 0, 3)
+	redeem.Type = "Redeem"
+	redeem.Asset.Symbol = "ETH"
+	redeem.Asset.ExternalSenderAddress = ethAddress
+	redeem.Asset.RedeemedAmount = 5
+	_, err = ApplyTx(state, sign(t, privKey, redeem), 1)
+	assert.True(t, errors.Is(err, ErrInsufficientBalance))
+
+	redeem.Asset.RedeemedAmount = 3
+	_, err = ApplyTx(state, sign(t, privKey, redeem), 1)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(1), account(t, state, sender).LockedBalance["ETH"][ethAddress])
+	assert.Equal(t, uint64(9), account(t, state, sender).EBalances["ETH"][ethAddress].Balance)
+}
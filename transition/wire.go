@@ -0,0 +1,12 @@
+package transition
+
+import (
+	cryptoAmino "github.com/herdius/herdius-core/crypto/encoding/amino"
+	amino "github.com/tendermint/go-amino"
+)
+
+var cdc = amino.NewCodec()
+
+func init() {
+	cryptoAmino.RegisterAmino(cdc)
+}
@@ -0,0 +1,133 @@
+package tx
+
+import (
+	b64 "encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+
+	"github.com/herdius/herdius-core/crypto/secp256k1"
+	pluginproto "github.com/herdius/herdius-core/hbi/protobuf"
+)
+
+// Versions of the bytes a tx sender signs
+const (
+	// SignVersionLegacy signs the JSON encoding of the tx. It depends on the
+	// field order of the Go JSON encoder and signs no chain ID, so a tx of one
+	// chain is valid on every other chain.
+	SignVersionLegacy uint32 = 0
+	// SignVersionCanonical signs the canonical encoding of SignBytes, which
+	// includes the chain ID and the tx type
+	SignVersionCanonical uint32 = 1
+)
+
+// signPrefix starts the canonical sign bytes, so they can't be mistaken for
+// any other message signed by the same key
+const signPrefix = "herdius-tx"
+
+// SignBytes returns the bytes the sender of tx signs, in the format of
+// tx.SignVersion.
+//
+// The canonical format (version 1) is the ASCII prefix "herdius-tx", the
+// version as a 4 byte big endian integer and then the fields below in order.
+// Strings are encoded as their 4 byte big endian length followed by their
+// UTF-8 bytes, integers as 8 byte big endian integers.
+//
+//	chain_id, type, sender_address, sender_pubkey, reciever_address, message,
+//	asset.category, asset.symbol, asset.network, asset.value, asset.fee,
+//	asset.nonce, asset.external_sender_address, asset.locked_amount,
+//	asset.redeemed_amount
+//
+// The signature is the secp256k1 signature of the SHA-256 hash of these bytes.
+func SignBytes(tx *pluginproto.Tx) ([]byte, error) {
+	if tx == nil || tx.Asset == nil {
+		return nil, fmt.Errorf("tx has no asset")
+	}
+	switch tx.SignVersion {
+	case SignVersionLegacy:
+		return legacySignBytes(tx)
+	case SignVersionCanonical:
+		return canonicalSignBytes(tx), nil
+	}
+	return nil, fmt.Errorf("unsupported sign version %d", tx.SignVersion)
+}
+
+func canonicalSignBytes(tx *pluginproto.Tx) []byte {
+	b := []byte(signPrefix)
+	b = appendUint32(b, tx.SignVersion)
+	for _, s := range []string{tx.ChainId, tx.Type, tx.SenderAddress, tx.SenderPubkey, tx.RecieverAddress, tx.Message,
+		tx.Asset.Category, tx.Asset.Symbol, tx.Asset.Network} {
+		b = appendString(b, s)
+	}
+	b = appendUint64(b, tx.Asset.Value)
+	b = appendUint64(b, tx.Asset.Fee)
+	b = appendUint64(b, tx.Asset.Nonce)
+	b = appendString(b, tx.Asset.ExternalSenderAddress)
+	b = appendUint64(b, tx.Asset.LockedAmount)
+	b = appendUint64(b, tx.Asset.RedeemedAmount)
+	return b
+}
+
+func appendUint32(b []byte, v uint32) []byte {
+	var buf [4]byte
+	binary.BigEndian.PutUint32(buf[:], v)
+	return append(b, buf[:]...)
+}
+
+func appendUint64(b []byte, v uint64) []byte {
+	var buf [8]byte
+	binary.BigEndian.PutUint64(buf[:], v)
+	return append(b, buf[:]...)
+}
+
+func appendString(b []byte, s string) []byte {
+	return append(appendUint32(b, uint32(len(s))), s...)
+}
+
+// legacySignBytes recreates the JSON encoding legacy wallets sign
+func legacySignBytes(tx *pluginproto.Tx) ([]byte, error) {
+	asset := &pluginproto.Asset{
+		Category:              tx.Asset.Category,
+		Symbol:                tx.Asset.Symbol,
+		Network:               tx.Asset.Network,
+		Value:                 tx.Asset.Value,
+		Fee:                   tx.Asset.Fee,
+		Nonce:                 tx.Asset.Nonce,
+		ExternalSenderAddress: tx.Asset.ExternalSenderAddress,
+		LockedAmount:          tx.Asset.LockedAmount,
+		RedeemedAmount:        tx.Asset.RedeemedAmount,
+	}
+	verifiableTx := pluginproto.Tx{
+		SenderAddress:   tx.SenderAddress,
+		SenderPubkey:    tx.SenderPubkey,
+		RecieverAddress: tx.RecieverAddress,
+		Asset:           asset,
+		Message:         tx.Message,
+		Type:            tx.Type,
+	}
+	txbz, err := json.Marshal(verifiableTx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal the transaction: %v", err)
+	}
+	return txbz, nil
+}
+
+// Sign signs tx for the chain of chainID with privKey in the canonical
+// format. It sets the sender address and public key of tx to those of privKey.
+func Sign(tx *pluginproto.Tx, privKey secp256k1.PrivKeySecp256k1, chainID string) error {
+	pubKey := privKey.PubKey().(secp256k1.PubKeySecp256k1)
+	tx.SenderAddress = pubKey.GetAddress()
+	tx.SenderPubkey = b64.StdEncoding.EncodeToString(pubKey[:])
+	tx.SignVersion = SignVersionCanonical
+	tx.ChainId = chainID
+	signBytes, err := SignBytes(tx)
+	if err != nil {
+		return err
+	}
+	sig, err := privKey.Sign(signBytes)
+	if err != nil {
+		return fmt.Errorf("failed to sign the transaction: %v", err)
+	}
+	tx.Sign = b64.StdEncoding.EncodeToString(sig)
+	return nil
+}
@@ -0,0 +1,93 @@
+package tx
+
+import (
+	b64 "encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"testing"
+
+	"github.com/herdius/herdius-core/crypto/secp256k1"
+	pluginproto "github.com/herdius/herdius-core/hbi/protobuf"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// signVector is a test vector of testdata/sign_vectors.json, which wallets
+// can check their canonical sign bytes and signatures against
+type signVector struct {
+	Description   string          `json:"description"`
+	PrivateKey    string          `json:"private_key"` // hex
+	ChainID       string          `json:"chain_id"`
+	Tx            *pluginproto.Tx `json:"tx"` // the tx before signing
+	SenderAddress string          `json:"sender_address"`
+	SenderPubkey  string          `json:"sender_pubkey"` // base64
+	SignBytes     string          `json:"sign_bytes"`    // hex
+	Sign          string          `json:"sign"`          // base64
+}
+
+func TestSignVectors(t *testing.T) {
+	data, err := ioutil.ReadFile("testdata/sign_vectors.json")
+	require.NoError(t, err)
+	var vectors []signVector
+	require.NoError(t, json.Unmarshal(data, &vectors))
+	require.NotEmpty(t, vectors)
+
+	for _, v := range vectors {
+		keyBytes, err := hex.DecodeString(v.PrivateKey)
+		require.NoError(t, err)
+		var privKey secp256k1.PrivKeySecp256k1
+		copy(privKey[:], keyBytes)
+
+		tx := v.Tx
+		require.NoError(t, Sign(tx, privKey, v.ChainID), v.Description)
+		assert.Equal(t, v.SenderAddress, tx.SenderAddress, v.Description)
+		assert.Equal(t, v.SenderPubkey, tx.SenderPubkey, v.Description)
+		signBytes, err := SignBytes(tx)
+		require.NoError(t, err)
+		assert.Equal(t, v.SignBytes, hex.EncodeToString(signBytes), v.Description)
+		assert.Equal(t, v.Sign, tx.Sign, v.Description)
+	}
+}
+
+func TestSignBytes(t *testing.T) {
+	privKey := secp256k1.GenPrivKey()
+	tx := &pluginproto.Tx{Type: "Transfer", Asset: &pluginproto.Asset{Symbol: "HER", Value: 10, Nonce: 1}}
+	require.NoError(t, Sign(tx, privKey, "herdius-dev"))
+	signBytes, err := SignBytes(tx)
+	require.NoError(t, err)
+
+	sig, err := b64.StdEncoding.DecodeString(tx.Sign)
+	require.NoError(t, err)
+	assert.True(t, privKey.PubKey().VerifyBytes(signBytes, sig))
+
+	// Every signed field changes the sign bytes
+	changes := []func(tx *pluginproto.Tx){
+		func(tx *pluginproto.Tx) { tx.ChainId = "herdius-staging" },
+		func(tx *pluginproto.Tx) { tx.Type = "External" },
+		func(tx *pluginproto.Tx) { tx.Asset.Value = 11 },
+		func(tx *pluginproto.Tx) { tx.Message = "memo" },
+		// Moving bytes between fields keeps the concatenation but not the encoding
+		func(tx *pluginproto.Tx) { tx.ChainId, tx.Type = "herdius-devT", "ransfer" },
+	}
+	for i, change := range changes {
+		changed := *tx
+		asset := *tx.Asset
+		changed.Asset = &asset
+		change(&changed)
+		changedBytes, err := SignBytes(&changed)
+		require.NoError(t, err)
+		assert.NotEqual(t, signBytes, changedBytes, "change %d", i)
+	}
+
+	// Unsigned fields don't
+	status := *tx
+	status.Status = "success"
+	statusBytes, err := SignBytes(&status)
+	require.NoError(t, err)
+	assert.Equal(t, signBytes, statusBytes)
+
+	tx.SignVersion = 2
+	_, err = SignBytes(tx)
+	assert.Error(t, err)
+}
@@ -28,4 +28,11 @@ const (
 	OpcodeTxRedeemResponse            = opcode.Opcode(1132)
 	OpcodeTxsByBlockHeightRequest     = opcode.Opcode(1133)
 	OpcodeLastBlockRequest            = opcode.Opcode(1134)
+	OpcodeSubscribeRequest            = opcode.Opcode(1135)
+	OpcodeSubscribeResponse           = opcode.Opcode(1136)
+	OpcodeUnsubscribeRequest          = opcode.Opcode(1137)
+	OpcodeNotification                = opcode.Opcode(1138)
+	OpcodeTxAnnouncement              = opcode.Opcode(1139)
+	OpcodeTxFetchRequest              = opcode.Opcode(1140)
+	OpcodeTxFetchResponse             = opcode.Opcode(1141)
 )
@@ -0,0 +1,198 @@
+package service
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/herdius/herdius-core/blockchain/protobuf"
+	hehash "github.com/herdius/herdius-core/crypto/herhash"
+	cryptokeys "github.com/herdius/herdius-core/p2p/crypto"
+	"github.com/herdius/herdius-core/storage/state/statedb"
+	"github.com/herdius/herdius-core/supervisor/transaction"
+	"github.com/herdius/herdius-core/transition"
+	txbyte "github.com/herdius/herdius-core/tx"
+)
+
+// ValidatorI is an interface
+type ValidatorI interface {
+	VerifyChildBlock(cb *protobuf.ChildBlock, proofs []*protobuf.AccountProof) error
+	Vote(cb *protobuf.ChildBlock) (*protobuf.VoteInfo, error)
+	ProcessChildBlock(msg *protobuf.ChildBlockMessage) *protobuf.ChildBlockMessage
+}
+
+var (
+	_ ValidatorI = (*Validator)(nil)
+)
+
+// Validator re-verifies the child blocks sent by the supervisor and votes on them
+type Validator struct {
+	keys         *cryptokeys.KeyPair
+	address      string
+	stakingpower int64
+}
+
+// NewValidator creates a validator signing its votes with keys
+func NewValidator(keys *cryptokeys.KeyPair, address string) *Validator {
+	return &Validator{
+		keys:         keys,
+		address:      address,
+		stakingpower: 100,
+	}
+}
+
+// Address returns validator's network address
+func (v *Validator) Address() string {
+	return v.address
+}
+
+// ProcessChildBlock verifies the child block of msg and returns the reply to the supervisor.
+// The reply carries a signed vote if the child block is valid, and an unsigned one otherwise.
+func (v *Validator) ProcessChildBlock(msg *protobuf.ChildBlockMessage) *protobuf.ChildBlockMessage {
+	cb := msg.GetChildBlock()
+	reply := &protobuf.ChildBlockMessage{
+		ChildBlock: cb,
+		Vote: &protobuf.VoteInfo{
+			Validator: v.validator(),
+		},
+	}
+	if err := v.VerifyChildBlock(cb, msg.GetAccountProofs()); err != nil {
+		log.Printf("Child block verification failed: %v", err)
+		return reply
+	}
+	vote, err := v.Vote(cb)
+	if err != nil {
+		log.Printf("Failed to vote for child block: %v", err)
+		return reply
+	}
+	reply.Vote = vote
+	return reply
+}
+
+// Vote signs the child block hash
+func (v *Validator) Vote(cb *protobuf.ChildBlock) (*protobuf.VoteInfo, error) {
+	blockHash := cb.GetHeader().GetBlockID().GetBlockHash()
+	if len(blockHash) == 0 {
+		return nil, fmt.Errorf("child block has no hash")
+	}
+	sign, err := v.keys.PrivKey.Sign(blockHash)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign child block: %v", err)
+	}
+	return &protobuf.VoteInfo{
+		Validator:          v.validator(),
+		SignedCurrentBlock: true,
+		Signature:          sign,
+	}, nil
+}
+
+func (v *Validator) validator() *protobuf.Validator {
+	return &protobuf.Validator{
+		Address:      v.address,
+		PubKey:       v.keys.PubKey.Bytes(),
+		Stakingpower: v.stakingpower,
+	}
+}
+
+// VerifyChildBlock checks the child block header commits to its txs and
+// re-verifies each successful tx against the accounts proved under the header's state root
+func (v *Validator) VerifyChildBlock(cb *protobuf.ChildBlock, proofs []*protobuf.AccountProof) error {
+	header := cb.GetHeader()
+	if header == nil {
+		return fmt.Errorf("child block has no header")
+	}
+	if err := verifyBlockHash(header); err != nil {
+		return err
+	}
+
+	txs := txbyte.Txs(cb.GetTxsData().GetTx())
+	if int64(len(txs)) != header.GetNumTxs() {
+		return fmt.Errorf("child block has %d txs, header claims %d", len(txs), header.GetNumTxs())
+	}
+	if !bytes.Equal(txs.MerkleHash(), header.GetRootHash()) {
+		return fmt.Errorf("txs root hash mismatch")
+	}
+
+	state, proved, err := verifyAccountProofs(header.GetStateRoot(), proofs)
+	if err != nil {
+		return err
+	}
+	for i, txbz := range txs {
+		if err := verifyTx(txbz, state, proved, header.GetHeight()); err != nil {
+			return fmt.Errorf("tx %d: %v", i, err)
+		}
+	}
+	return nil
+}
+
+// verifyBlockHash recomputes the child block hash the way the supervisor creates it
+func verifyBlockHash(header *protobuf.Header) error {
+	blockID := header.GetBlockID()
+	if blockID == nil {
+		return fmt.Errorf("child block has no block ID")
+	}
+	h := *header
+	h.BlockID = nil
+	hbz, err := cdc.MarshalJSON(&h)
+	if err != nil {
+		return fmt.Errorf("failed to marshal child block header: %v", err)
+	}
+	if !bytes.Equal(hehash.Sum(hbz), blockID.GetBlockHash()) {
+		return fmt.Errorf("child block hash mismatch")
+	}
+	return nil
+}
+
+// verifyAccountProofs checks each proof against stateRoot and returns an in-memory
+// state holding the proved accounts, along with the set of proved addresses.
+// Accounts proved absent from the state are left out of the returned state.
+func verifyAccountProofs(stateRoot []byte, proofs []*protobuf.AccountProof) (statedb.Trie, map[string]bool, error) {
+	if len(stateRoot) == 0 {
+		return nil, nil, fmt.Errorf("child block has no state root")
+	}
+	state, err := statedb.NewMemTrie()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create state trie: %v", err)
+	}
+	proved := make(map[string]bool)
+	for _, proof := range proofs {
+		actbz, err := statedb.VerifyProof(stateRoot, []byte(proof.GetAddress()), proof.GetProof())
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid proof for account %v: %v", proof.GetAddress(), err)
+		}
+		if len(actbz) > 0 {
+			if err := state.TryUpdate([]byte(proof.GetAddress()), actbz); err != nil {
+				return nil, nil, fmt.Errorf("failed to store account %v: %v", proof.GetAddress(), err)
+			}
+		}
+		proved[proof.GetAddress()] = true
+	}
+	return state, proved, nil
+}
+
+// verifyTx verifies a tx of the child block at height and applies it to
+// state, so that later txs of the same account are checked against the
+// updated account.
+func verifyTx(txbz []byte, state statedb.Trie, proved map[string]bool, height int64) error {
+	txValue := transaction.Tx{}
+	if err := cdc.UnmarshalJSON(txbz, &txValue); err != nil {
+		return fmt.Errorf("failed to unmarshal tx: %v", err)
+	}
+	// Txs rejected by the supervisor don't change the state
+	if !strings.EqualFold(txValue.Status, transition.StatusSuccess) {
+		return nil
+	}
+	tx, err := transaction.ToProto(txValue)
+	if err != nil {
+		return err
+	}
+	if !proved[tx.SenderAddress] {
+		return fmt.Errorf("no proof for sender account %v", tx.SenderAddress)
+	}
+	if len(tx.RecieverAddress) > 0 && !proved[tx.RecieverAddress] {
+		return fmt.Errorf("no proof for receiver account %v", tx.RecieverAddress)
+	}
+	_, err = transition.ApplyTx(state, &tx, height)
+	return err
+}
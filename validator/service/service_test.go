@@ -0,0 +1,221 @@
+package service
+
+import (
+	b64 "encoding/base64"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"strconv"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/herdius/herdius-core/blockchain/protobuf"
+	cryptokey "github.com/herdius/herdius-core/crypto"
+	hehash "github.com/herdius/herdius-core/crypto/herhash"
+	"github.com/herdius/herdius-core/crypto/secp256k1"
+	pluginproto "github.com/herdius/herdius-core/hbi/protobuf"
+	cryptokeys "github.com/herdius/herdius-core/p2p/crypto"
+	"github.com/herdius/herdius-core/storage/state/statedb"
+	"github.com/herdius/herdius-core/supervisor/transaction"
+	txbyte "github.com/herdius/herdius-core/tx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testAccount struct {
+	privKey secp256k1.PrivKeySecp256k1
+	pubKey  secp256k1.PubKeySecp256k1
+	address string
+}
+
+func newTestAccount() testAccount {
+	privKey := secp256k1.GenPrivKey()
+	pubKey := privKey.PubKey().(secp256k1.PubKeySecp256k1)
+	return testAccount{privKey: privKey, pubKey: pubKey, address: pubKey.GetAddress()}
+}
+
+func newTestValidator() *Validator {
+	privKey := secp256k1.GenPrivKey()
+	pubKey := privKey.PubKey()
+	keys := &cryptokeys.KeyPair{
+		PublicKey:  pubKey.Bytes(),
+		PrivateKey: privKey.Bytes(),
+		PrivKey:    privKey,
+		PubKey:     pubKey,
+	}
+	return NewValidator(keys, "tcp://127.0.0.1:3001")
+}
+
+// newTestState stores accounts in a fresh state trie and returns the trie
+func newTestState(t *testing.T, accounts ...statedb.Account) statedb.Trie {
+	dir, err := ioutil.TempDir("", "validator-state")
+	require.NoError(t, err)
+	t.Cleanup(func() { os.RemoveAll(dir) })
+
+	statedb.GetState(dir)
+	stateTrie, err := statedb.NewTrie(common.Hash{})
+	require.NoError(t, err)
+	for _, account := range accounts {
+		actbz, err := cdc.MarshalJSON(account)
+		require.NoError(t, err)
+		require.NoError(t, stateTrie.TryUpdate([]byte(account.Address), actbz))
+	}
+	return stateTrie
+}
+
+func signedTx(t *testing.T, sender testAccount, receiver string, value, fee, nonce uint64) *transaction.Tx {
+	tx := pluginproto.Tx{
+		SenderAddress:   sender.address,
+		SenderPubkey:    b64.StdEncoding.EncodeToString(sender.pubKey[:]),
+		RecieverAddress: receiver,
+		Asset: &pluginproto.Asset{
+			Category: "crypto",
+			Symbol:   "HER",
+			Network:  "Herdius",
+			Value:    value,
+			Fee:      fee,
+			Nonce:    nonce,
+		},
+		Message: "transfer",
+	}
+	txbz, err := json.Marshal(tx)
+	require.NoError(t, err)
+	sign, err := sender.privKey.Sign(txbz)
+	require.NoError(t, err)
+
+	return &transaction.Tx{
+		SenderAddress:   tx.SenderAddress,
+		SenderPubKey:    tx.SenderPubkey,
+		ReceiverAddress: tx.RecieverAddress,
+		Asset: transaction.Asset{
+			Category: tx.Asset.Category,
+			Symbol:   tx.Asset.Symbol,
+			Network:  tx.Asset.Network,
+			Value:    strconv.FormatUint(value, 10),
+			Fee:      strconv.FormatUint(fee, 10),
+			Nonce:    strconv.FormatUint(nonce, 10),
+		},
+		Message:   tx.Message,
+		Signature: b64.StdEncoding.EncodeToString(sign),
+		Status:    "success",
+	}
+}
+
+// childBlockMessage creates the child block message the way the supervisor does
+func childBlockMessage(t *testing.T, stateTrie statedb.Trie, txs ...*transaction.Tx) *protobuf.ChildBlockMessage {
+	txbzs := make([][]byte, 0)
+	addresses := make([]string, 0)
+	for _, tx := range txs {
+		txbz, err := cdc.MarshalJSON(*tx)
+		require.NoError(t, err)
+		txbzs = append(txbzs, txbz)
+		addresses = append(addresses, tx.SenderAddress, tx.ReceiverAddress)
+	}
+	txList := txbyte.Txs(txbzs)
+
+	header := &protobuf.Header{
+		NumTxs:    int64(len(txbzs)),
+		TotalTxs:  int64(len(txbzs)),
+		RootHash:  txList.MerkleHash(),
+		StateRoot: stateTrie.Hash(),
+	}
+	hbz, err := cdc.MarshalJSON(header)
+	require.NoError(t, err)
+	header.BlockID = &protobuf.BlockID{BlockHash: hehash.Sum(hbz)}
+
+	proofs := make([]*protobuf.AccountProof, 0)
+	for _, address := range addresses {
+		proof, err := stateTrie.Prove([]byte(address))
+		require.NoError(t, err)
+		proofs = append(proofs, &protobuf.AccountProof{Address: address, Proof: proof})
+	}
+	return &protobuf.ChildBlockMessage{
+		ChildBlock: &protobuf.ChildBlock{
+			Header:  header,
+			TxsData: &protobuf.TxsData{Tx: txbzs},
+		},
+		AccountProofs: proofs,
+	}
+}
+
+func TestProcessChildBlockVotes(t *testing.T) {
+	sender, receiver := newTestAccount(), newTestAccount()
+	stateTrie := newTestState(t,
+		statedb.Account{Address: sender.address, Balance: 100, Nonce: 1},
+		statedb.Account{Address: receiver.address},
+	)
+	msg := childBlockMessage(t, stateTrie,
+		signedTx(t, sender, receiver.address, 40, 1, 2),
+		signedTx(t, sender, receiver.address, 58, 1, 3),
+	)
+
+	v := newTestValidator()
+	reply := v.ProcessChildBlock(msg)
+	vote := reply.GetVote()
+	assert.True(t, vote.GetSignedCurrentBlock())
+	assert.Equal(t, v.Address(), vote.GetValidator().GetAddress())
+
+	var pubKey cryptokey.PubKey
+	require.NoError(t, cdc.UnmarshalBinaryBare(vote.GetValidator().GetPubKey(), &pubKey))
+	blockHash := msg.GetChildBlock().GetHeader().GetBlockID().GetBlockHash()
+	assert.True(t, pubKey.VerifyBytes(blockHash, vote.GetSignature()))
+}
+
+func TestProcessChildBlockRejects(t *testing.T) {
+	sender, receiver, other := newTestAccount(), newTestAccount(), newTestAccount()
+	stateTrie := newTestState(t,
+		statedb.Account{Address: sender.address, Balance: 100, Nonce: 1},
+		statedb.Account{Address: receiver.address},
+	)
+
+	forged := signedTx(t, other, receiver.address, 10, 0, 2)
+	forged.SenderAddress = sender.address
+
+	tests := []struct {
+		name string
+		msg  func() *protobuf.ChildBlockMessage
+	}{
+		{"insufficient balance", func() *protobuf.ChildBlockMessage {
+			return childBlockMessage(t, stateTrie, signedTx(t, sender, receiver.address, 101, 0, 2))
+		}},
+		{"insufficient balance for fee", func() *protobuf.ChildBlockMessage {
+			return childBlockMessage(t, stateTrie, signedTx(t, sender, receiver.address, 96, 5, 2))
+		}},
+		{"insufficient balance across txs", func() *protobuf.ChildBlockMessage {
+			return childBlockMessage(t, stateTrie,
+				signedTx(t, sender, receiver.address, 60, 0, 2),
+				signedTx(t, sender, receiver.address, 60, 0, 3),
+			)
+		}},
+		{"stale nonce", func() *protobuf.ChildBlockMessage {
+			return childBlockMessage(t, stateTrie, signedTx(t, sender, receiver.address, 10, 0, 1))
+		}},
+		{"forged sender", func() *protobuf.ChildBlockMessage {
+			return childBlockMessage(t, stateTrie, forged)
+		}},
+		{"tampered tx", func() *protobuf.ChildBlockMessage {
+			msg := childBlockMessage(t, stateTrie, signedTx(t, sender, receiver.address, 10, 0, 2))
+			msg.ChildBlock.TxsData.Tx[0] = []byte("{}")
+			return msg
+		}},
+		{"tampered state root", func() *protobuf.ChildBlockMessage {
+			msg := childBlockMessage(t, stateTrie, signedTx(t, sender, receiver.address, 10, 0, 2))
+			msg.ChildBlock.Header.StateRoot = make([]byte, 32)
+			return msg
+		}},
+		{"missing proofs", func() *protobuf.ChildBlockMessage {
+			msg := childBlockMessage(t, stateTrie, signedTx(t, sender, receiver.address, 10, 0, 2))
+			msg.AccountProofs = nil
+			return msg
+		}},
+	}
+
+	v := newTestValidator()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reply := v.ProcessChildBlock(tt.msg())
+			assert.False(t, reply.GetVote().GetSignedCurrentBlock())
+			assert.Empty(t, reply.GetVote().GetSignature())
+		})
+	}
+}